@@ -1,8 +1,7 @@
 package main
 
 import (
-	"one-lab-final/internal/app"
-	"one-lab-final/internal/config"
+	"os"
 )
 
 // @title           Library API
@@ -19,13 +18,8 @@ import (
 // @in header
 // @name Authorization
 func main() {
-	cfg, err := config.ParseConfig("configs/config.yaml")
+	err := newRootCommand().Execute()
 	if err != nil {
-		panic(err)
-	}
-
-	err = app.Run(cfg)
-	if err != nil {
-		panic(err)
+		os.Exit(1)
 	}
 }
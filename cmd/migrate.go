@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"one-lab-final/internal/app"
+	"one-lab-final/internal/config"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/spf13/cobra"
+)
+
+var migrationsPath string
+
+func newMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply or revert database migrations",
+	}
+
+	cmd.PersistentFlags().StringVar(&migrationsPath, "path", "migrations", "directory with migrations")
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "up [N]",
+			Short: "Apply all or N pending migrations",
+			Args:  cobra.MaximumNArgs(1),
+			RunE: withMigrate(func(cmd *cobra.Command, m *migrate.Migrate, steps int) error {
+				if steps == 0 {
+					return m.Up()
+				}
+
+				return m.Steps(steps)
+			}),
+		},
+		&cobra.Command{
+			Use:   "down [N]",
+			Short: "Revert N applied migrations, one by default",
+			Args:  cobra.MaximumNArgs(1),
+			RunE: withMigrate(func(cmd *cobra.Command, m *migrate.Migrate, steps int) error {
+				if steps == 0 {
+					steps = 1
+				}
+
+				return m.Steps(-steps)
+			}),
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "Print version of the database schema",
+			Args:  cobra.NoArgs,
+			RunE: withMigrate(func(cmd *cobra.Command, m *migrate.Migrate, _ int) error {
+				version, dirty, err := m.Version()
+				if errors.Is(err, migrate.ErrNilVersion) {
+					cmd.Println("no migrations are applied")
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+
+				cmd.Printf("version: %d, dirty: %t\n", version, dirty)
+				return nil
+			}),
+		},
+	)
+
+	return cmd
+}
+
+// withMigrate parses optional number of steps and opens migrations. Having
+// nothing to apply is not an error
+func withMigrate(run func(cmd *cobra.Command, m *migrate.Migrate, steps int) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		var steps int
+
+		if len(args) == 1 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				return fmt.Errorf("number of migrations must be a positive integer, got %q", args[0])
+			}
+
+			steps = n
+		}
+
+		cfg, err := config.ParseConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		m, err := app.NewMigrate(cfg, migrationsPath)
+		if err != nil {
+			return err
+		}
+
+		defer m.Close()
+
+		err = run(cmd, m, steps)
+		if errors.Is(err, migrate.ErrNoChange) {
+			cmd.Println("no change")
+			return nil
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"one-lab-final/internal/app"
+	"one-lab-final/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var configPath string
+
+func newRootCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "main",
+		Short:        "Library API server and administration tools",
+		SilenceUsage: true,
+	}
+
+	cmd.PersistentFlags().StringVar(&configPath, "config", "configs/config.yaml", "path to configuration file")
+
+	cmd.AddCommand(
+		newServeCommand(),
+		newMigrateCommand(),
+		newUserCommand(),
+		newTokensCommand(),
+		newRatingsCommand(),
+		newSeedCommand(),
+	)
+
+	return cmd
+}
+
+// withApp parses configuration and connects to the database before calling
+// run, connection is closed afterwards
+func withApp(run func(cmd *cobra.Command, args []string, app *app.App) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.ParseConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		a, err := app.New(cfg)
+		if err != nil {
+			return err
+		}
+
+		defer a.Close()
+
+		return run(cmd, args, a)
+	}
+}
+
+func newServeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Start HTTP server with scheduled tasks",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.ParseConfig(configPath)
+			if err != nil {
+				return err
+			}
+
+			return app.Run(cfg)
+		},
+	}
+}
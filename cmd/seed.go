@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"one-lab-final/internal/app"
+	"one-lab-final/internal/config"
+	"one-lab-final/internal/entity"
+	"one-lab-final/pkg/util"
+
+	"github.com/spf13/cobra"
+)
+
+type seedBook struct {
+	Title       string
+	Author      string
+	Description string
+	Tags        []string
+	Year        int64
+}
+
+var seedBooks = []seedBook{
+	{
+		Title:       "Pride and Prejudice",
+		Author:      "Jane Austen",
+		Description: "Elizabeth Bennet navigates manners, upbringing and marriage in Regency England.",
+		Tags:        []string{"classic", "romance"},
+		Year:        1813,
+	},
+	{
+		Title:       "Crime and Punishment",
+		Author:      "Fyodor Dostoevsky",
+		Description: "A former student commits a murder and struggles with its moral consequences.",
+		Tags:        []string{"classic", "psychological"},
+		Year:        1866,
+	},
+	{
+		Title:       "The Master and Margarita",
+		Author:      "Mikhail Bulgakov",
+		Description: "The devil visits Soviet Moscow while a writer and his beloved face their fate.",
+		Tags:        []string{"classic", "fantasy", "satire"},
+		Year:        1967,
+	},
+	{
+		Title:       "Nineteen Eighty-Four",
+		Author:      "George Orwell",
+		Description: "A clerk of the Ministry of Truth rebels against the totalitarian Party.",
+		Tags:        []string{"dystopia", "political"},
+		Year:        1949,
+	},
+	{
+		Title:       "The Hobbit",
+		Author:      "J. R. R. Tolkien",
+		Description: "Bilbo Baggins joins thirteen dwarves on a quest to reclaim their mountain home.",
+		Tags:        []string{"fantasy", "adventure"},
+		Year:        1937,
+	},
+}
+
+func newSeedCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "seed",
+		Short: "Create admin from configuration and sample books. Steps are skipped when admins or books already exist",
+		Args:  cobra.NoArgs,
+		RunE: withApp(func(cmd *cobra.Command, args []string, a *app.App) error {
+			err := seedAdmin(cmd, a, a.Config.ADMIN)
+			if err != nil {
+				return err
+			}
+
+			return seedCatalog(cmd, a)
+		}),
+	}
+}
+
+func seedAdmin(cmd *cobra.Command, a *app.App, cfg config.AdminConfig) error {
+	adminRole := entity.ADMIN
+
+	_, admins, err := a.Services.GetUsers(cmd.Context(), entity.UserSearch{Role: &adminRole}, util.NewFilter(1, 1, "id"))
+	if err != nil {
+		return err
+	}
+
+	if admins.TotalRecords > 0 {
+		cmd.Println("admins already exist, skipping admin")
+		return nil
+	}
+
+	if cfg.Password == "" {
+		return errors.New("ADMIN_PASSWORD must be set to create admin")
+	}
+
+	admin := &entity.User{
+		Username:  &cfg.Username,
+		Email:     &cfg.Email,
+		FirstName: &cfg.FirstName,
+		LastName:  &cfg.LastName,
+		Password: entity.Password{
+			Plaintext: &cfg.Password,
+		},
+		Role:      entity.USER,
+		Activated: true,
+	}
+
+	err = a.Services.CreateUser(cmd.Context(), admin)
+	if err != nil {
+		return err
+	}
+
+	return grantRole(cmd, a, admin.ID, entity.ADMIN, "created by seed")
+}
+
+func seedCatalog(cmd *cobra.Command, a *app.App) error {
+	_, books, err := a.Services.GetBooks(cmd.Context(), nil, nil, nil, util.NewFilter(1, 1, "created_at"))
+	if err != nil {
+		return err
+	}
+
+	if books.TotalRecords > 0 {
+		cmd.Println("books already exist, skipping catalog")
+		return nil
+	}
+
+	for _, seed := range seedBooks {
+		seed := seed
+		book := &entity.Book{
+			Title:       &seed.Title,
+			Author:      &seed.Author,
+			Description: &seed.Description,
+			Tags:        &seed.Tags,
+			Year:        seed.Year,
+		}
+
+		err := a.Services.CreateBook(cmd.Context(), book)
+		if err != nil {
+			return err
+		}
+	}
+
+	cmd.Printf("%d books were created\n", len(seedBooks))
+	return nil
+}
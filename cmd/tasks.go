@@ -0,0 +1,62 @@
+package main
+
+import (
+	"one-lab-final/internal/app"
+
+	"github.com/spf13/cobra"
+)
+
+func newTokensCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tokens",
+		Short: "Manage tokens",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "purge",
+		Short: "Delete expired tokens",
+		Args:  cobra.NoArgs,
+		RunE: withApp(func(cmd *cobra.Command, args []string, a *app.App) error {
+			err := a.Services.DeleteExpiredTokens(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			cmd.Println("expired tokens are deleted")
+			return nil
+		}),
+	})
+
+	return cmd
+}
+
+func newRatingsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ratings",
+		Short: "Manage precomputed ratings",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "refresh",
+		Short: "Refresh ratings of books and statistics of reviewers",
+		Args:  cobra.NoArgs,
+		RunE: withApp(func(cmd *cobra.Command, args []string, a *app.App) error {
+			err := a.Services.RefreshBooksRating(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			cmd.Println("ratings of books are refreshed")
+
+			err = a.Services.RefreshUserStats(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			cmd.Println("statistics of reviewers are refreshed")
+			return nil
+		}),
+	})
+
+	return cmd
+}
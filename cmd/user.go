@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"one-lab-final/internal/app"
+	"one-lab-final/internal/entity"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newUserCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage user accounts",
+	}
+
+	cmd.AddCommand(
+		newUserCreateCommand(),
+		newUserGrantRoleCommand(),
+		newUserResetPasswordCommand(),
+	)
+
+	return cmd
+}
+
+func newUserCreateCommand() *cobra.Command {
+	var email, firstName, lastName, password, role string
+
+	cmd := &cobra.Command{
+		Use:   "create <username>",
+		Short: "Create activated user, password is read from stdin when not set",
+		Args:  cobra.ExactArgs(1),
+		RunE: withApp(func(cmd *cobra.Command, args []string, a *app.App) error {
+			newRole, ok := entity.StringToRole(role)
+			if !ok {
+				return fmt.Errorf("role %q is not valid", role)
+			}
+
+			if password == "" {
+				var err error
+				password, err = readPassword(cmd)
+				if err != nil {
+					return err
+				}
+			}
+
+			user := &entity.User{
+				Username:  &args[0],
+				Email:     &email,
+				FirstName: &firstName,
+				LastName:  &lastName,
+				Password: entity.Password{
+					Plaintext: &password,
+				},
+				Role:      entity.USER,
+				Activated: true,
+			}
+
+			err := a.Services.CreateUser(cmd.Context(), user)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("user %s was created with id %d\n", *user.Username, user.ID)
+
+			if newRole == entity.USER {
+				return nil
+			}
+
+			return grantRole(cmd, a, user.ID, newRole, "created from command line")
+		}),
+	}
+
+	cmd.Flags().StringVar(&email, "email", "", "email of the user")
+	cmd.Flags().StringVar(&firstName, "first-name", "", "first name of the user")
+	cmd.Flags().StringVar(&lastName, "last-name", "", "last name of the user")
+	cmd.Flags().StringVar(&password, "password", "", "password of the user")
+	cmd.Flags().StringVar(&role, "role", entity.USER.String(), "role of the user")
+	cmd.MarkFlagRequired("email")
+
+	return cmd
+}
+
+func newUserGrantRoleCommand() *cobra.Command {
+	var reason string
+
+	cmd := &cobra.Command{
+		Use:   "grant-role <username or email> <role>",
+		Short: "Change role of the user, the change is recorded without an actor",
+		Args:  cobra.ExactArgs(2),
+		RunE: withApp(func(cmd *cobra.Command, args []string, a *app.App) error {
+			role, ok := entity.StringToRole(args[1])
+			if !ok {
+				return fmt.Errorf("role %q is not valid", args[1])
+			}
+
+			user, err := a.Services.GetUserByCredentials(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("user %s: %w", args[0], err)
+			}
+
+			return grantRole(cmd, a, user.ID, role, reason)
+		}),
+	}
+
+	cmd.Flags().StringVar(&reason, "reason", "", "why the role is changed")
+
+	return cmd
+}
+
+func newUserResetPasswordCommand() *cobra.Command {
+	var password string
+
+	cmd := &cobra.Command{
+		Use:   "reset-password <username or email>",
+		Short: "Set new password and end all sessions of the user, password is read from stdin when not set",
+		Args:  cobra.ExactArgs(1),
+		RunE: withApp(func(cmd *cobra.Command, args []string, a *app.App) error {
+			user, err := a.Services.GetUserByCredentials(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("user %s: %w", args[0], err)
+			}
+
+			if password == "" {
+				password, err = readPassword(cmd)
+				if err != nil {
+					return err
+				}
+			}
+
+			user.Password = entity.Password{
+				Plaintext: &password,
+			}
+
+			err = a.Services.UpdateUser(cmd.Context(), user, "")
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("password of %s was reset\n", *user.Username)
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(&password, "password", "", "new password")
+
+	return cmd
+}
+
+func grantRole(cmd *cobra.Command, a *app.App, userID int64, role entity.Role, reason string) error {
+	change := &entity.RoleChange{
+		UserID:  userID,
+		NewRole: role,
+	}
+
+	if reason != "" {
+		change.Reason = &reason
+	}
+
+	err := a.Services.GrantRoleToUser(cmd.Context(), change)
+	if err != nil {
+		return err
+	}
+
+	cmd.Printf("role of user %d was changed from %s to %s\n", userID, change.OldRole, role)
+	return nil
+}
+
+func readPassword(cmd *cobra.Command) (string, error) {
+	cmd.Print("Password: ")
+
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -87,7 +87,7 @@ const docTemplate = `{
                 "tags": [
                     "Books"
                 ],
-                "summary": "Delete book by ID. Requires MODERATOR role or higher",
+                "summary": "Delete book by ID. Requires \"books.delete\" permission",
                 "parameters": [
                     {
                         "type": "integer",
@@ -135,7 +135,7 @@ const docTemplate = `{
                 "tags": [
                     "Books"
                 ],
-                "summary": "Create new book. Requires MODERATOR role or higher",
+                "summary": "Create new book. Requires \"books.create\" permission",
                 "parameters": [
                     {
                         "description": "Request body",
@@ -185,7 +185,7 @@ const docTemplate = `{
                 "tags": [
                     "Books"
                 ],
-                "summary": "Update book by id. Requires MODERATOR role or higher",
+                "summary": "Update book by id. Requires \"books.update\" permission",
                 "parameters": [
                     {
                         "type": "integer",
@@ -337,24 +337,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/healthcheck": {
+        "/feed": {
             "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Healthcheck"
+                    "Users"
+                ],
+                "summary": "Get activity of followed users, newest first. Suspended and deleted users are skipped",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "MTcwMDAwMDAwMDAwMDAwMDAwMDo0Mg",
+                        "description": "Value of \"next_cursor\" from the previous page, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of activities inside of one page. Can range between 1-100",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Check if server is running",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.DefaultResponse"
+                            "$ref": "#/definitions/api.GetFeedResponse"
                         }
                     },
-                    "494": {
-                        "description": "",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -368,50 +391,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/mod/roles/{id}": {
-            "patch": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "consumes": [
-                    "application/json"
-                ],
+        "/healthcheck": {
+            "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Moderation"
-                ],
-                "summary": "Update role of user by his ID. Requires ADMIN role",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Request body",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.GrantRoleToUser"
-                        }
-                    }
+                    "Healthcheck"
                 ],
+                "summary": "Check if server is running",
                 "responses": {
                     "200": {
-                        "description": "role of user was successfully updated",
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/api.DefaultResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "494": {
+                        "description": "",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
@@ -425,45 +422,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/mod/suspensions/new": {
-            "post": {
+        "/mod/content-rules": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Moderation"
                 ],
-                "summary": "Suspend user for some time. Requires MODERATOR role or higher",
-                "parameters": [
-                    {
-                        "description": "Request body",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.CreateSuspensionRequest"
-                        }
-                    }
-                ],
+                "summary": "List content rules screening review text. Requires \"reviews.moderate\" permission",
                 "responses": {
                     "200": {
-                        "description": "User was successfully suspended",
-                        "schema": {
-                            "$ref": "#/definitions/api.DefaultResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
+                            "$ref": "#/definitions/api.GetContentRulesResponse"
                         }
                     },
                     "500": {
@@ -473,10 +450,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/mod/suspensions/update/{id}": {
-            "patch": {
+            },
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -491,30 +466,23 @@ const docTemplate = `{
                 "tags": [
                     "Moderation"
                 ],
-                "summary": "Modify suspension of user. Requires MODERATOR role or higher",
+                "summary": "Create content rule. Term rules require pattern, links and repeat rules require threshold. Requires \"reviews.moderate\" permission",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Suspension ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "description": "Request body",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.UpdateSuspensionRequest"
+                            "$ref": "#/definitions/api.CreateContentRuleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "suspension was successfully updated",
+                    "201": {
+                        "description": "Content rule succesfully created",
                         "schema": {
-                            "$ref": "#/definitions/api.DefaultResponse"
+                            "$ref": "#/definitions/api.ContentRuleResponse"
                         }
                     },
                     "400": {
@@ -532,27 +500,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/reviews/delete/{id}": {
+        "/mod/content-rules/{id}": {
             "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Moderation"
                 ],
-                "summary": "Delete review by ID",
+                "summary": "Delete content rule. Requires \"reviews.moderate\" permission",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Review ID",
+                        "description": "Content rule ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -571,6 +536,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -578,10 +549,8 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/reviews/new": {
-            "post": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -594,25 +563,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Moderation"
                 ],
-                "summary": "Create new review",
+                "summary": "Change pattern, threshold, severity or state of content rule. Requires \"reviews.moderate\" permission",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Content rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "Request body",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.CreateReviewRequest"
+                            "$ref": "#/definitions/api.UpdateContentRuleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Review succesfully created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.DefaultResponse"
+                            "$ref": "#/definitions/api.ContentRuleResponse"
                         }
                     },
                     "400": {
@@ -621,6 +597,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -630,46 +612,87 @@ const docTemplate = `{
                 }
             }
         },
-        "/reviews/update/{id}": {
-            "patch": {
+        "/mod/reports": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "consumes": [
-                    "application/json"
-                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Moderation"
                 ],
-                "summary": "Update review by ID",
+                "summary": "Moderation queue, reports are grouped by review. Requires \"reviews.moderate\" permission",
                 "parameters": [
+                    {
+                        "enum": [
+                            "spam",
+                            "harassment",
+                            "hate_speech",
+                            "spoiler",
+                            "off_topic",
+                            "other"
+                        ],
+                        "type": "string",
+                        "example": "spam",
+                        "x-enum-varnames": [
+                            "ReportSpam",
+                            "ReportHarassment",
+                            "ReportHateSpeech",
+                            "ReportSpoiler",
+                            "ReportOffTopic",
+                            "ReportOther"
+                        ],
+                        "name": "category",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Review ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "default": 1,
+                        "name": "page",
+                        "in": "query"
                     },
                     {
-                        "description": "Request body",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.UpdateReviewRequest"
-                        }
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of books inside of one page. Can range between 1-100",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "The field that is used for sorting. Add prefix \"-\" to change direction",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "open",
+                            "dismissed",
+                            "actioned"
+                        ],
+                        "type": "string",
+                        "default": "open",
+                        "x-enum-varnames": [
+                            "ReportOpen",
+                            "ReportDismissed",
+                            "ReportActioned"
+                        ],
+                        "name": "state",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.DefaultResponse"
+                            "$ref": "#/definitions/api.GetReportedReviewsResponse"
                         }
                     },
                     "400": {
@@ -687,8 +710,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/delete": {
-            "delete": {
+        "/mod/reports/{id}": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -698,14 +721,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Moderation"
+                ],
+                "summary": "List every report of the review, resolved included. Requires \"reviews.moderate\" permission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Delete user",
                 "responses": {
                     "200": {
-                        "description": "User succesfully deleted",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.DefaultResponse"
+                            "$ref": "#/definitions/api.GetReviewReportsResponse"
                         }
                     },
                     "400": {
@@ -714,6 +746,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -723,8 +761,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/login": {
+        "/mod/reports/{id}/resolve": {
             "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Action dismiss closes reports as dismissed. Hide, delete and suspend close them as actioned, the author is suspended in the same step when suspend_for is set",
                 "consumes": [
                     "application/json"
                 ],
@@ -732,25 +776,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Moderation"
                 ],
-                "summary": "Authenticated user and return access token",
+                "summary": "Resolve open reports of the review. Requires \"reviews.moderate\" permission, suspending the author also requires \"users.suspend\"",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "Request body",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.LoginRequest"
+                            "$ref": "#/definitions/api.ResolveReportsRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Token succesfully created",
+                    "200": {
+                        "description": "reports were successfully resolved",
                         "schema": {
-                            "$ref": "#/definitions/api.LoginResponse"
+                            "$ref": "#/definitions/api.ResolveReportsResponse"
                         }
                     },
                     "400": {
@@ -759,6 +810,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -768,34 +837,139 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/register": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/mod/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Moderation"
                 ],
-                "summary": "Register new user",
+                "summary": "List reviews of any visibility, e.g. pending reviews quarantined by content filter. Requires \"reviews.moderate\" permission",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "book_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of books inside of one page. Can range between 1-100",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "The field that is used for sorting. Add prefix \"-\" to change direction",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "visible",
+                            "hidden",
+                            "deleted",
+                            "pending",
+                            "removed"
+                        ],
+                        "type": "string",
+                        "example": "pending",
+                        "x-enum-varnames": [
+                            "ReviewVisible",
+                            "ReviewHidden",
+                            "ReviewDeleted",
+                            "ReviewPending",
+                            "ReviewRemoved"
+                        ],
+                        "description": "One of visible, pending, hidden, deleted, removed",
+                        "name": "visibility",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetModerationReviewsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mod/reviews/{id}/hide": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Hide review from book reviews and ratings. Requires \"reviews.moderate\" permission",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "Request body",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.CreateUserRequest"
+                            "$ref": "#/definitions/api.HideReviewRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "User succesfully created",
+                    "200": {
+                        "description": "review was successfully hidden",
                         "schema": {
-                            "$ref": "#/definitions/api.DefaultResponse"
+                            "$ref": "#/definitions/api.ReviewResponse"
                         }
                     },
                     "400": {
@@ -804,6 +978,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -813,22 +999,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/suspensions/{id}": {
-            "get": {
-                "consumes": [
-                    "application/json"
+        "/mod/reviews/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Moderation"
                 ],
-                "summary": "Check if user has suspensions",
+                "summary": "Make hidden review visible again. Requires \"reviews.moderate\" permission",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "User ID",
+                        "description": "Review ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -836,9 +1024,99 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "ok",
+                        "description": "review was successfully restored",
                         "schema": {
-                            "$ref": "#/definitions/api.CheckSuspensionResponse"
+                            "$ref": "#/definitions/api.ReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mod/roles": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "List built-in and custom roles with their permissions. Requires \"roles.manage\" permission",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetRolesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Create custom role from set of permissions the user has. Requires \"roles.manage\" permission",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Role succesfully created",
+                        "schema": {
+                            "$ref": "#/definitions/api.RoleResponse"
                         }
                     },
                     "400": {
@@ -847,6 +1125,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -856,34 +1146,44 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/update": {
+        "/mod/roles/{id}": {
             "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Moderation"
                 ],
-                "summary": "Update user info",
+                "summary": "Update role of user by his ID and record it in role history. Requires \"roles.grant\" permission and every permission of current and new role of the user. The last admin can't be demoted",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "description": "Request body",
                         "name": "data",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.UpdateUserRequest"
+                            "$ref": "#/definitions/api.GrantRoleToUser"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "User succesfully updated",
+                        "description": "role of user was successfully updated",
                         "schema": {
                             "$ref": "#/definitions/api.DefaultResponse"
                         }
@@ -894,6 +1194,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -903,32 +1221,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{username}": {
+        "/mod/roles/{name}": {
             "get": {
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Moderation"
                 ],
-                "summary": "Get user by his username",
+                "summary": "Get role with its permissions. Requires \"roles.manage\" permission",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Username of user",
-                        "name": "username",
+                        "description": "Name of role",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Ok",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.GetUserByUsernameResponse"
+                            "$ref": "#/definitions/api.RoleResponse"
                         }
                     },
                     "400": {
@@ -950,17 +1270,3594 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Replace description and permissions of custom role within permissions the user has. Built-in roles can not be changed. Requires \"roles.manage\" permission",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of role",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.RoleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Delete custom role which is not assigned to any user. Requires \"roles.manage\" permission",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of role",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mod/suspensions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "List suspensions, expired and lifted included. Requires \"users.suspend\" permission",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-01-01",
+                        "description": "Creation date range, both days included",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "moderator_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of books inside of one page. Can range between 1-100",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "The field that is used for sorting. Add prefix \"-\" to change direction",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-12-31",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "example": 21,
+                        "name": "user_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetSuspensionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mod/suspensions/new": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Suspend user for some time. Requires \"users.suspend\" permission",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateSuspensionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User was successfully suspended",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuspensionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mod/suspensions/update/{id}": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Change reason or duration of active suspension. Requires \"users.suspend\" permission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Suspension ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateSuspensionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "suspension was successfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuspensionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mod/suspensions/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Get suspension. Requires \"users.suspend\" permission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Suspension ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuspensionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mod/suspensions/{id}/lift": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "End active suspension before it expires. Requires \"users.suspend\" permission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Suspension ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.LiftSuspensionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "suspension was successfully lifted",
+                        "schema": {
+                            "$ref": "#/definitions/api.SuspensionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mod/users": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "List users with filters and prefix search. Requires \"users.view\" permission",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "example": true,
+                        "name": "activated",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of books inside of one page. Can range between 1-100",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-01-01",
+                        "description": "Registration date range, both days included",
+                        "name": "registered_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "2023-12-31",
+                        "name": "registered_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "MODERATOR",
+                        "description": "Allowed values: \"user\", \"moderator\", \"admin\"",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 100,
+                        "type": "string",
+                        "example": "joh",
+                        "description": "Prefix of username, email, first or last name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "The field that is used for sorting. Add prefix \"-\" to change direction",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "example": false,
+                        "name": "suspended",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetUsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mod/users/{id}/roles/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "List role changes of user. Requires \"roles.grant\" permission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of books inside of one page. Can range between 1-100",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "The field that is used for sorting. Add prefix \"-\" to change direction",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetRoleHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/delete/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Delete review by ID. Users with \"reviews.moderate\" permission can delete any review",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/mine": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Get reviews of current user. Hidden reviews are included with a notice",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetReviewsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/new": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Create new review",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Review succesfully created",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Review is waiting for moderator approval",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/update/{id}": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Update review by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Review is waiting for moderator approval",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/report": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Report abusive review to moderators",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ReportReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "review was successfully reported",
+                        "schema": {
+                            "$ref": "#/definitions/api.ReviewReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/2fa": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Disable 2FA. Not allowed if 2FA is required for role of the user",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "2FA succesfully disabled",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/2fa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Enable 2FA with the first code. Returns recovery codes, they are shown only once",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "2FA succesfully enabled",
+                        "schema": {
+                            "$ref": "#/definitions/api.ConfirmTwoFactorResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/2fa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Generate TOTP secret. 2FA is enabled after confirmation with the first code",
+                "responses": {
+                    "201": {
+                        "description": "Secret succesfully generated",
+                        "schema": {
+                            "$ref": "#/definitions/api.EnrollTwoFactorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/activate": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Activate account with token sent to email",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ActivateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User succesfully activated",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/activate/resend": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Send new activation token to email of current user",
+                "responses": {
+                    "200": {
+                        "description": "Activation token was sent",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Get API keys of current user",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetAPIKeysResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Create API key for machine clients. Key is shown only once",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "API key succesfully created",
+                        "schema": {
+                            "$ref": "#/definitions/api.APIKeyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/api-keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Revoke API key of current user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "API key was successfully revoked",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/avatar": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Upload avatar of current user. Image is cropped to square and resized to 64, 128 and 256 pixels",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "JPEG, PNG or GIF image",
+                        "name": "avatar",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Avatar succesfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/api.AvatarResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete avatar of current user",
+                "responses": {
+                    "200": {
+                        "description": "Avatar succesfully deleted",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/delete": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete user. Logging in during grace period restores the account, after it the profile is anonymized",
+                "responses": {
+                    "200": {
+                        "description": "User succesfully deleted",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/email": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Request email change. Token is sent to the new email, notice to the current one",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ChangeEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Confirmation token was sent",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ValidationErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/email/cancel": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Cancel email change with token sent to the previous email, confirmed change is reverted until it expires. Logs out all sessions",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.EmailChangeTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email change was cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/email/confirm": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Confirm email change with token sent to the new email",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.EmailChangeTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Email succesfully changed",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/export": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Request export of all personal data. Archive is built in background, poll its status with returned ID",
+                "responses": {
+                    "202": {
+                        "description": "Export succesfully queued",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataExportResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/export/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get status of data export. Ready export contains time-limited download URL",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.DataExportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/export/{id}/download": {
+            "get": {
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Download archive of data export. The URL is returned by export status endpoint",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 1700000000,
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "3f8a...",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ZIP archive with JSON files",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Authenticated user and return access token",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Token succesfully created",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Password accepted, partial token must be exchanged with two-factor code",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "423": {
+                        "description": "Too many failed attempts, see Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Attempted too early after failed one, see Retry-After header",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login/2fa": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Exchange partial token returned by login for access token",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginTwoFactorRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Token succesfully created",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/logout": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Revoke token used for this request",
+                "responses": {
+                    "200": {
+                        "description": "Logged out successfully",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/oidc/{provider}/callback": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Finish login with external identity provider and return access token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of provider from config",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State from login request",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Token succesfully created",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Partial token must be exchanged with two-factor code",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/oidc/{provider}/login": {
+            "get": {
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Redirect to external identity provider to log in",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name of provider from config",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to identity provider"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Set new password with token sent to email. Logs out all sessions",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password succesfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ValidationErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/password-reset": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Send password reset token to email of the account",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.PasswordResetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Token was sent if account exists",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/register": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Register new user",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User succesfully created",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Get active sessions of current user",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetSessionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Revoke all sessions of current user, including this one",
+                "responses": {
+                    "200": {
+                        "description": "Logged out everywhere",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Revoke session of current user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session was successfully revoked",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/suspensions/{id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Check if user has suspensions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/api.CheckSuspensionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/token/refresh": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Authentication"
+                ],
+                "summary": "Exchange refresh token for a new pair of tokens",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Token succesfully refreshed",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/update": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update user info. Changing password logs out all other sessions",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User succesfully updated",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ValidationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/username": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Change username. Old username redirects to the new one",
+                "parameters": [
+                    {
+                        "description": "Request body",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ChangeUsernameRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Username succesfully changed",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ValidationErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by his username",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username of user",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Ok",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetUserByUsernameResponse"
+                        }
+                    },
+                    "301": {
+                        "description": "User was renamed, redirect to the current username"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/follow": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Follow user, their activity appears in the feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "308": {
+                        "description": "User was renamed, redirect to the current username"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Unfollow user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.DefaultResponse"
+                        }
+                    },
+                    "308": {
+                        "description": "User was renamed, redirect to the current username"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/followers": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List users following the user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of books inside of one page. Can range between 1-100",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "The field that is used for sorting. Add prefix \"-\" to change direction",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetFollowsResponse"
+                        }
+                    },
+                    "301": {
+                        "description": "User was renamed, redirect to the current username"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/following": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List users followed by the user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Number of books inside of one page. Can range between 1-100",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "The field that is used for sorting. Add prefix \"-\" to change direction",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetFollowsResponse"
+                        }
+                    },
+                    "301": {
+                        "description": "User was renamed, redirect to the current username"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/stats": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get reviewer statistics of the user. Statistics are refreshed every hour",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.GetUserStatsResponse"
+                        }
+                    },
+                    "301": {
+                        "description": "User was renamed, redirect to the current username"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/entity.APIKey"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ActivateUserRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "MFRGGZDFMZTWQ2LKNNWG23TPOBYXE43UOV3HO6DZPIZTENBV"
+                }
+            }
+        },
+        "api.AvatarResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/entity.Avatar"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ChangeEmailRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "example@gmail.com"
+                }
+            }
+        },
+        "api.ChangeUsernameRequest": {
+            "type": "object",
+            "required": [
+                "username"
+            ],
+            "properties": {
+                "username": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 5,
+                    "example": "username"
+                }
+            }
+        },
+        "api.CheckSuspensionResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Suspension"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ConfirmTwoFactorResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ContentRuleResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/entity.ContentRule"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expiry": {
+                    "description": "Key never expires when omitted",
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "ingestion"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "books:write"
+                    ]
+                }
+            }
+        },
+        "api.CreateBookRequest": {
+            "type": "object",
+            "required": [
+                "author",
+                "description",
+                "tags",
+                "title",
+                "year"
+            ],
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 5,
+                    "example": "Robert W. Chambers"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua."
+                },
+                "tags": {
+                    "type": "array",
+                    "maxItems": 5,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "horror",
+                        "mystery"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 5,
+                    "example": "The King in Yellow"
+                },
+                "year": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 1994
+                }
+            }
+        },
+        "api.CreateContentRuleRequest": {
+            "type": "object",
+            "required": [
+                "kind",
+                "severity"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "default": true
+                },
+                "kind": {
+                    "enum": [
+                        "term",
+                        "links",
+                        "repeat"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.ContentRuleKind"
+                        }
+                    ],
+                    "example": "term"
+                },
+                "pattern": {
+                    "description": "Banned word or phrase of term rule",
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "buy now"
+                },
+                "severity": {
+                    "enum": [
+                        "reject",
+                        "quarantine"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.ContentRuleSeverity"
+                        }
+                    ],
+                    "example": "reject"
+                },
+                "threshold": {
+                    "description": "Allowed number of links or repeated characters",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "api.CreateReviewRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "rating"
+            ],
+            "properties": {
+                "book_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "content": {
+                    "type": "string",
+                    "example": "Awesome book"
+                },
+                "rating": {
+                    "type": "integer",
+                    "default": 0,
+                    "example": 100
+                }
+            }
+        },
+        "api.CreateRoleRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "permissions"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Edits books"
+                },
+                "name": {
+                    "description": "Uppercase letters, digits and underscores",
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "LIBRARIAN"
+                },
+                "permissions": {
+                    "description": "Allowed values: \"books.create\", \"books.update\", \"books.delete\", \"reviews.moderate\", \"users.view\", \"users.suspend\", \"roles.grant\", \"roles.manage\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "books.create",
+                        "books.update"
+                    ]
+                }
+            }
+        },
+        "api.CreateSuspensionRequest": {
+            "type": "object",
+            "required": [
+                "expires_in",
+                "reason",
+                "user_id"
+            ],
+            "properties": {
+                "expires_in": {
+                    "description": "Time in minutes",
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Bad behaviour"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 21
+                }
+            }
+        },
+        "api.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "first_name",
+                "last_name",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "example@gmail.com"
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2,
+                    "example": "John"
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2,
+                    "example": "Snow"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "password"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 5,
+                    "example": "username"
+                }
+            }
+        },
+        "api.DataExportResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/entity.DataExport"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.DefaultResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.EmailChangeTokenRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "MFRGGZDFMZTWQ2LKNNWG23TPOBYXE43UOV3HO6DZPIZTENBV"
+                }
+            }
+        },
+        "api.EnrollTwoFactorResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/entity.TwoFactor"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.GetAPIKeysResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.APIKey"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.GetBookByIDResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/entity.Book"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.GetBooksResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Book"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/util.Metadata"
+                }
+            }
+        },
+        "api.GetContentRulesResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ContentRule"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.GetFeedResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Activity"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "next_cursor": {
+                    "description": "Cursor of the next page, omitted on the last page",
+                    "type": "string"
+                }
+            }
+        },
+        "api.GetFollowsResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Follow"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/util.Metadata"
+                }
+            }
+        },
+        "api.GetModerationReviewsResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Review"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/util.Metadata"
+                }
+            }
+        },
+        "api.GetReportedReviewsResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ReportedReview"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/util.Metadata"
+                }
+            }
+        },
+        "api.GetReviewReportsResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ReviewReport"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.GetReviewsByBookIDResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Review"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/util.Metadata"
+                }
+            }
+        },
+        "api.GetReviewsResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Review"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.GetRoleHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.RoleChange"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/util.Metadata"
+                }
+            }
+        },
+        "api.GetRolesResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.RoleDefinition"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.GetSessionsResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Session"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.GetSuspensionsResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Suspension"
+                    }
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/util.Metadata"
+                }
+            }
+        },
+        "api.GetUserByUsernameResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/entity.User"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
             }
-        }
-    },
-    "definitions": {
-        "api.CheckSuspensionResponse": {
+        },
+        "api.GetUserStatsResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/entity.UserStats"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.GetUsersResponse": {
             "type": "object",
             "properties": {
                 "body": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.Suspension"
+                        "$ref": "#/definitions/entity.UserOverview"
                     }
                 },
                 "code": {
@@ -968,18 +4865,297 @@ const docTemplate = `{
                 },
                 "message": {
                     "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/util.Metadata"
                 }
             }
         },
-        "api.CreateBookRequest": {
+        "api.GrantRoleToUser": {
             "type": "object",
             "required": [
-                "author",
-                "description",
-                "tags",
-                "title",
-                "year"
+                "role"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "Why the role is changed, stored in role history",
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Joined moderation team"
+                },
+                "role": {
+                    "description": "Name of built-in (\"user\", \"moderator\", \"admin\") or custom role",
+                    "type": "string",
+                    "example": "ADMIN"
+                }
+            }
+        },
+        "api.HideReviewRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "description": "Shown to the author of the review",
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Spoilers"
+                }
+            }
+        },
+        "api.LiftSuspensionRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Appeal accepted"
+                }
+            }
+        },
+        "api.LoginRequest": {
+            "type": "object",
+            "required": [
+                "credentials",
+                "password"
+            ],
+            "properties": {
+                "credentials": {
+                    "description": "Can be username or email",
+                    "type": "string",
+                    "example": "username"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password"
+                }
+            }
+        },
+        "api.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/entity.Token"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.LoginTwoFactorRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "token"
+            ],
+            "properties": {
+                "code": {
+                    "description": "TOTP code or one of recovery codes",
+                    "type": "string",
+                    "example": "123456"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "MFRGGZDFMZTWQ2LKNNWG23TPOBYXE43UOV3HO6DZPIZTENBV"
+                }
+            }
+        },
+        "api.PasswordResetRequest": {
+            "type": "object",
+            "required": [
+                "credentials"
+            ],
+            "properties": {
+                "credentials": {
+                    "description": "Can be username or email",
+                    "type": "string",
+                    "example": "username"
+                }
+            }
+        },
+        "api.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string",
+                    "example": "MFRGGZDFMZTWQ2LKNNWG23TPOBYXE43UOV3HO6DZPIZTENBV"
+                }
+            }
+        },
+        "api.ReportReviewRequest": {
+            "type": "object",
+            "required": [
+                "category"
+            ],
+            "properties": {
+                "category": {
+                    "description": "One of spam, harassment, hate_speech, spoiler, off_topic, other",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.ReportCategory"
+                        }
+                    ],
+                    "example": "spam"
+                },
+                "comment": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Link to a shop"
+                }
+            }
+        },
+        "api.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "maxLength": 128,
+                    "example": "password"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "MFRGGZDFMZTWQ2LKNNWG23TPOBYXE43UOV3HO6DZPIZTENBV"
+                }
+            }
+        },
+        "api.ResolveReportsRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "reason"
+            ],
+            "properties": {
+                "action": {
+                    "enum": [
+                        "dismiss",
+                        "hide",
+                        "delete",
+                        "suspend"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.ReportAction"
+                        }
+                    ],
+                    "example": "hide"
+                },
+                "reason": {
+                    "description": "Shown to the author of hidden review and used as suspension reason",
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Advertisement"
+                },
+                "suspend_for": {
+                    "description": "Suspends the author for given number of minutes, required by suspend action",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "api.ResolveReportsResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "suspension": {
+                    "description": "Suspension of the author, omitted when the author was not suspended",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.Suspension"
+                        }
+                    ]
+                }
+            }
+        },
+        "api.ReviewReportResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/entity.ReviewReport"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ReviewResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/entity.Review"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.RoleResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/entity.RoleDefinition"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.SuspensionResponse": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "$ref": "#/definitions/entity.Suspension"
+                },
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.TwoFactorCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
             ],
+            "properties": {
+                "code": {
+                    "description": "TOTP code or one of recovery codes",
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "api.UpdateBookRequest": {
+            "type": "object",
             "properties": {
                 "author": {
                     "type": "string",
@@ -1016,17 +5192,38 @@ const docTemplate = `{
                 }
             }
         },
-        "api.CreateReviewRequest": {
+        "api.UpdateContentRuleRequest": {
             "type": "object",
-            "required": [
-                "content",
-                "rating"
-            ],
             "properties": {
-                "book_id": {
-                    "type": "integer",
-                    "example": 1
+                "enabled": {
+                    "type": "boolean"
+                },
+                "pattern": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "buy now"
+                },
+                "severity": {
+                    "enum": [
+                        "reject",
+                        "quarantine"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/entity.ContentRuleSeverity"
+                        }
+                    ],
+                    "example": "quarantine"
                 },
+                "threshold": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "api.UpdateReviewRequest": {
+            "type": "object",
+            "properties": {
                 "content": {
                     "type": "string",
                     "example": "Awesome book"
@@ -1038,42 +5235,52 @@ const docTemplate = `{
                 }
             }
         },
-        "api.CreateSuspensionRequest": {
+        "api.UpdateRoleRequest": {
             "type": "object",
             "required": [
-                "expires_in",
-                "reason",
-                "user_id"
+                "permissions"
             ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Edits books"
+                },
+                "permissions": {
+                    "description": "Allowed values: \"books.create\", \"books.update\", \"books.delete\", \"reviews.moderate\", \"users.view\", \"users.suspend\", \"roles.grant\", \"roles.manage\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "books.create",
+                        "books.update"
+                    ]
+                }
+            }
+        },
+        "api.UpdateSuspensionRequest": {
+            "type": "object",
             "properties": {
                 "expires_in": {
-                    "description": "Time in minutes",
+                    "description": "New duration in minutes counted from creation of suspension, can extend or shorten it",
                     "type": "integer",
                     "minimum": 1
                 },
                 "reason": {
                     "type": "string",
+                    "minLength": 1,
                     "example": "Bad behaviour"
-                },
-                "user_id": {
-                    "type": "integer",
-                    "example": 21
                 }
             }
         },
-        "api.CreateUserRequest": {
+        "api.UpdateUserRequest": {
             "type": "object",
-            "required": [
-                "email",
-                "first_name",
-                "last_name",
-                "password",
-                "username"
-            ],
             "properties": {
-                "email": {
+                "bio": {
                     "type": "string",
-                    "example": "example@gmail.com"
+                    "maxLength": 500,
+                    "example": "Reads mostly science fiction"
                 },
                 "first_name": {
                     "type": "string",
@@ -1087,286 +5294,442 @@ const docTemplate = `{
                     "minLength": 2,
                     "example": "Snow"
                 },
+                "location": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Winterfell"
+                },
                 "password": {
                     "type": "string",
-                    "maxLength": 32,
-                    "minLength": 6,
+                    "maxLength": 128,
                     "example": "password"
                 },
-                "username": {
+                "website": {
                     "type": "string",
-                    "maxLength": 50,
-                    "minLength": 5,
-                    "example": "username"
+                    "maxLength": 200,
+                    "example": "https://example.com"
                 }
             }
         },
-        "api.DefaultResponse": {
+        "api.ValidationErrorResponse": {
             "type": "object",
             "properties": {
                 "code": {
                     "type": "integer"
                 },
+                "fields": {
+                    "description": "Problem with each rejected field",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "message": {
                     "type": "string"
                 }
             }
         },
-        "api.ErrorResponse": {
+        "entity.APIKey": {
             "type": "object",
             "properties": {
-                "code": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expiry": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "message": {
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
                     "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.APIScope"
+                    }
                 }
             }
         },
-        "api.GetBookByIDResponse": {
+        "entity.APIScope": {
+            "type": "string",
+            "enum": [
+                "books:write",
+                "reviews:write",
+                "mod:suspend",
+                "mod:reviews",
+                "mod:roles"
+            ],
+            "x-enum-varnames": [
+                "APIScopeBooksWrite",
+                "APIScopeReviewsWrite",
+                "APIScopeModSuspend",
+                "APIScopeModReviews",
+                "APIScopeModRoles"
+            ]
+        },
+        "entity.Activity": {
             "type": "object",
             "properties": {
-                "body": {
-                    "$ref": "#/definitions/entity.Book"
+                "created_at": {
+                    "type": "string"
                 },
-                "code": {
+                "followed_username": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "message": {
+                "kind": {
+                    "type": "string"
+                },
+                "review": {
+                    "$ref": "#/definitions/entity.Review"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "api.GetBooksResponse": {
+        "entity.Avatar": {
             "type": "object",
             "properties": {
-                "body": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/entity.Book"
-                    }
-                },
-                "code": {
-                    "type": "integer"
+                "large": {
+                    "type": "string"
                 },
-                "message": {
+                "medium": {
                     "type": "string"
                 },
-                "meta": {
-                    "$ref": "#/definitions/util.Metadata"
+                "small": {
+                    "type": "string"
                 }
             }
         },
-        "api.GetReviewsByBookIDResponse": {
+        "entity.Book": {
             "type": "object",
             "properties": {
-                "body": {
+                "author": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "tags": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/entity.Review"
+                        "type": "string"
                     }
                 },
-                "code": {
-                    "type": "integer"
+                "title": {
+                    "type": "string"
                 },
-                "message": {
+                "updated_at": {
                     "type": "string"
                 },
-                "meta": {
-                    "$ref": "#/definitions/util.Metadata"
+                "year": {
+                    "type": "integer"
                 }
             }
         },
-        "api.GetUserByUsernameResponse": {
+        "entity.ContentRule": {
             "type": "object",
             "properties": {
-                "body": {
-                    "$ref": "#/definitions/entity.User"
+                "created_at": {
+                    "type": "string"
                 },
-                "code": {
+                "created_by": {
                     "type": "integer"
                 },
-                "message": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "$ref": "#/definitions/entity.ContentRuleKind"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "severity": {
+                    "$ref": "#/definitions/entity.ContentRuleSeverity"
+                },
+                "threshold": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "api.GrantRoleToUser": {
-            "type": "object",
-            "required": [
-                "role"
+        "entity.ContentRuleKind": {
+            "type": "string",
+            "enum": [
+                "term",
+                "links",
+                "repeat"
             ],
-            "properties": {
-                "role": {
-                    "description": "Allowed values: \"user\", \"moderator\", \"admin\"",
-                    "type": "string",
-                    "example": "ADMIN"
-                }
-            }
+            "x-enum-varnames": [
+                "ContentRuleTerm",
+                "ContentRuleLinks",
+                "ContentRuleRepeat"
+            ]
         },
-        "api.LoginRequest": {
-            "type": "object",
-            "required": [
-                "credentials",
-                "password"
+        "entity.ContentRuleSeverity": {
+            "type": "string",
+            "enum": [
+                "reject",
+                "quarantine"
             ],
-            "properties": {
-                "credentials": {
-                    "description": "Can be username or email",
-                    "type": "string",
-                    "example": "username"
-                },
-                "password": {
-                    "type": "string",
-                    "example": "password"
-                }
-            }
+            "x-enum-varnames": [
+                "ContentReject",
+                "ContentQuarantine"
+            ]
         },
-        "api.LoginResponse": {
+        "entity.DataExport": {
             "type": "object",
             "properties": {
-                "body": {
-                    "$ref": "#/definitions/entity.Token"
+                "created_at": {
+                    "type": "string"
                 },
-                "code": {
+                "download_expiry": {
+                    "type": "string"
+                },
+                "download_url": {
+                    "description": "Set only for ready exports, valid until DownloadExpiry",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "expiry": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "message": {
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "api.UpdateBookRequest": {
+        "entity.Follow": {
             "type": "object",
             "properties": {
-                "author": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 5,
-                    "example": "Robert W. Chambers"
+                "first_name": {
+                    "type": "string"
                 },
-                "description": {
-                    "type": "string",
-                    "example": "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua."
+                "followed_at": {
+                    "type": "string"
                 },
-                "tags": {
-                    "type": "array",
-                    "maxItems": 5,
-                    "minItems": 1,
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "horror",
-                        "mystery"
-                    ]
+                "last_name": {
+                    "type": "string"
                 },
-                "title": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 5,
-                    "example": "The King in Yellow"
+                "user_id": {
+                    "type": "integer"
                 },
-                "year": {
-                    "type": "integer",
-                    "minimum": 1,
-                    "example": 1994
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "api.UpdateReviewRequest": {
+        "entity.MonthCount": {
             "type": "object",
             "properties": {
-                "content": {
-                    "type": "string",
-                    "example": "Awesome book"
+                "count": {
+                    "type": "integer"
                 },
-                "rating": {
-                    "type": "integer",
-                    "default": 0,
-                    "example": 100
+                "month": {
+                    "description": "Formatted as \"2006-01\"",
+                    "type": "string"
                 }
             }
         },
-        "api.UpdateSuspensionRequest": {
+        "entity.Permission": {
+            "type": "string",
+            "enum": [
+                "books.create",
+                "books.update",
+                "books.delete",
+                "reviews.moderate",
+                "users.view",
+                "users.suspend",
+                "roles.grant",
+                "roles.manage"
+            ],
+            "x-enum-varnames": [
+                "PermissionBooksCreate",
+                "PermissionBooksUpdate",
+                "PermissionBooksDelete",
+                "PermissionReviewsModerate",
+                "PermissionUsersView",
+                "PermissionUsersSuspend",
+                "PermissionRolesGrant",
+                "PermissionRolesManage"
+            ]
+        },
+        "entity.RefreshToken": {
             "type": "object",
             "properties": {
-                "expires_in": {
-                    "description": "Time in minutes",
-                    "type": "integer",
-                    "minimum": 1
+                "expiry": {
+                    "type": "string"
                 },
-                "reason": {
-                    "type": "string",
-                    "example": "Bad behaviour"
+                "token": {
+                    "type": "string"
                 }
             }
         },
-        "api.UpdateUserRequest": {
+        "entity.ReportAction": {
+            "type": "string",
+            "enum": [
+                "dismiss",
+                "hide",
+                "delete",
+                "suspend"
+            ],
+            "x-enum-varnames": [
+                "ReportActionDismiss",
+                "ReportActionHide",
+                "ReportActionDelete",
+                "ReportActionSuspend"
+            ]
+        },
+        "entity.ReportCategory": {
+            "type": "string",
+            "enum": [
+                "spam",
+                "harassment",
+                "hate_speech",
+                "spoiler",
+                "off_topic",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "ReportSpam",
+                "ReportHarassment",
+                "ReportHateSpeech",
+                "ReportSpoiler",
+                "ReportOffTopic",
+                "ReportOther"
+            ]
+        },
+        "entity.ReportState": {
+            "type": "string",
+            "enum": [
+                "open",
+                "dismissed",
+                "actioned"
+            ],
+            "x-enum-varnames": [
+                "ReportOpen",
+                "ReportDismissed",
+                "ReportActioned"
+            ]
+        },
+        "entity.ReportedReview": {
             "type": "object",
             "properties": {
-                "first_name": {
-                    "type": "string",
-                    "maxLength": 50,
-                    "minLength": 2,
-                    "example": "John"
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.ReportCategory"
+                    }
                 },
-                "last_name": {
-                    "type": "string",
-                    "maxLength": 50,
-                    "minLength": 2,
-                    "example": "Snow"
+                "first_reported_at": {
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "maxLength": 32,
-                    "minLength": 6,
-                    "example": "password"
+                "last_reported_at": {
+                    "type": "string"
+                },
+                "reports": {
+                    "type": "integer"
+                },
+                "review": {
+                    "$ref": "#/definitions/entity.Review"
+                },
+                "state": {
+                    "$ref": "#/definitions/entity.ReportState"
                 }
             }
         },
-        "entity.Book": {
+        "entity.Review": {
             "type": "object",
             "properties": {
-                "author": {
+                "book_id": {
+                    "type": "integer"
+                },
+                "content": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "description": {
+                "deleted_at": {
                     "type": "string"
                 },
-                "id": {
+                "hidden_at": {
+                    "type": "string"
+                },
+                "hidden_by": {
                     "type": "integer"
                 },
-                "rating": {
-                    "type": "number"
+                "hidden_reason": {
+                    "type": "string"
                 },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "id": {
+                    "type": "integer"
                 },
-                "title": {
+                "notice": {
+                    "description": "Notice explains to the author why the review is not public",
                     "type": "string"
                 },
+                "rating": {
+                    "type": "integer"
+                },
                 "updated_at": {
                     "type": "string"
                 },
-                "year": {
+                "user_id": {
                     "type": "integer"
+                },
+                "visibility": {
+                    "$ref": "#/definitions/entity.ReviewVisibility"
                 }
             }
         },
-        "entity.Review": {
+        "entity.ReviewReport": {
             "type": "object",
             "properties": {
-                "book_id": {
-                    "type": "integer"
+                "category": {
+                    "$ref": "#/definitions/entity.ReportCategory"
                 },
-                "content": {
+                "comment": {
                     "type": "string"
                 },
                 "created_at": {
@@ -1375,23 +5738,49 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
-                "rating": {
+                "reporter_id": {
                     "type": "integer"
                 },
-                "updated_at": {
+                "resolution": {
+                    "$ref": "#/definitions/entity.ReportAction"
+                },
+                "resolved_at": {
                     "type": "string"
                 },
-                "user_id": {
+                "resolved_by": {
                     "type": "integer"
+                },
+                "review_id": {
+                    "type": "integer"
+                },
+                "state": {
+                    "$ref": "#/definitions/entity.ReportState"
                 }
             }
         },
+        "entity.ReviewVisibility": {
+            "type": "string",
+            "enum": [
+                "visible",
+                "hidden",
+                "deleted",
+                "pending",
+                "removed"
+            ],
+            "x-enum-varnames": [
+                "ReviewVisible",
+                "ReviewHidden",
+                "ReviewDeleted",
+                "ReviewPending",
+                "ReviewRemoved"
+            ]
+        },
         "entity.Role": {
-            "type": "integer",
+            "type": "string",
             "enum": [
-                0,
-                1,
-                2
+                "USER",
+                "MODERATOR",
+                "ADMIN"
             ],
             "x-enum-varnames": [
                 "USER",
@@ -1399,18 +5788,114 @@ const docTemplate = `{
                 "ADMIN"
             ]
         },
+        "entity.RoleChange": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "integer"
+                },
+                "actor_username": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_role": {
+                    "$ref": "#/definitions/entity.Role"
+                },
+                "old_role": {
+                    "$ref": "#/definitions/entity.Role"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "entity.RoleDefinition": {
+            "type": "object",
+            "properties": {
+                "built_in": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "$ref": "#/definitions/entity.Role"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.Permission"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.Session": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "expiry": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.Suspension": {
             "type": "object",
             "properties": {
+                "active": {
+                    "type": "boolean"
+                },
                 "created_at": {
                     "type": "string"
                 },
+                "expires_at": {
+                    "type": "string"
+                },
                 "expires_in": {
                     "type": "integer"
                 },
                 "id": {
                     "type": "integer"
                 },
+                "lift_reason": {
+                    "type": "string"
+                },
+                "lifted_at": {
+                    "type": "string"
+                },
+                "lifted_by": {
+                    "type": "integer"
+                },
                 "moderator_id": {
                     "type": "integer"
                 },
@@ -1420,25 +5905,62 @@ const docTemplate = `{
                 "updated_at": {
                     "type": "string"
                 },
+                "updated_by": {
+                    "type": "integer"
+                },
                 "user_id": {
                     "type": "integer"
                 }
             }
         },
+        "entity.TagCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "tag": {
+                    "type": "string"
+                }
+            }
+        },
         "entity.Token": {
             "type": "object",
             "properties": {
                 "expiry": {
                     "type": "string"
                 },
+                "refresh_token": {
+                    "$ref": "#/definitions/entity.RefreshToken"
+                },
                 "token": {
                     "type": "string"
+                },
+                "two_factor_required": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "entity.TwoFactor": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
+                },
+                "uri": {
+                    "type": "string"
                 }
             }
         },
         "entity.User": {
             "type": "object",
             "properties": {
+                "avatar": {
+                    "$ref": "#/definitions/entity.Avatar"
+                },
+                "bio": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -1451,6 +5973,9 @@ const docTemplate = `{
                 "last_name": {
                     "type": "string"
                 },
+                "location": {
+                    "type": "string"
+                },
                 "role": {
                     "$ref": "#/definitions/entity.Role"
                 },
@@ -1459,6 +5984,90 @@ const docTemplate = `{
                 },
                 "username": {
                     "type": "string"
+                },
+                "website": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.UserOverview": {
+            "type": "object",
+            "properties": {
+                "activated": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_login_at": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "review_count": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "suspended": {
+                    "type": "boolean"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "entity.UserStats": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "type": "number"
+                },
+                "first_review_at": {
+                    "type": "string"
+                },
+                "last_review_at": {
+                    "type": "string"
+                },
+                "monthly_reviews": {
+                    "description": "Reviews of the past 12 months and the current one, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.MonthCount"
+                    }
+                },
+                "rating_distribution": {
+                    "description": "Number of reviews by given rating",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "refreshed_at": {
+                    "type": "string"
+                },
+                "review_count": {
+                    "type": "integer"
+                },
+                "top_tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/entity.TagCount"
+                    }
                 }
             }
         },
@@ -2,40 +2,123 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"one-lab-final/internal/config"
-	"one-lab-final/internal/entity"
 	"one-lab-final/internal/handler"
 	"one-lab-final/internal/repository/pgrepo"
 	"one-lab-final/internal/service"
+	"one-lab-final/pkg/blob"
 	"one-lab-final/pkg/httpserver"
+	"one-lab-final/pkg/mailer"
+	"one-lab-final/pkg/oidc"
 	"one-lab-final/pkg/store/postgres"
+	"one-lab-final/pkg/util"
 	"os"
 	"os/signal"
 
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/procyon-projects/chrono"
 )
 
-func Run(cfg *config.Config) error {
-	db, err := postgres.ConnectDB(
-		postgres.WithHost(cfg.DB.Host),
-		postgres.WithPort(cfg.DB.Port),
-		postgres.WithDBName(cfg.DB.DBName),
-		postgres.WithUsername(cfg.DB.Username),
-		postgres.WithPassword(cfg.DB.Password),
-	)
+// App holds dependencies shared by the server and command line
+type App struct {
+	Config   *config.Config
+	DB       *pgxpool.Pool
+	Services service.Service
+}
+
+// New connects to the database and builds services from configuration
+func New(cfg *config.Config) (*App, error) {
+	db, err := postgres.ConnectDB(dbOptions(cfg.DB)...)
 	if err != nil {
 		log.Printf("connection to DB err: %s", err.Error())
-		return err
+		return nil, err
 	}
 
 	log.Println("connection success")
 
+	mailer, err := newMailer(cfg.MAIL)
+	if err != nil {
+		log.Printf("mailer setup err: %s", err.Error())
+		db.Close()
+		return nil, err
+	}
+
+	hasher, err := newPasswordHasher(cfg.AUTH.PasswordHash)
+	if err != nil {
+		log.Printf("password hasher setup err: %s", err.Error())
+		db.Close()
+		return nil, err
+	}
+
+	store, err := newBlobStore(cfg.BLOB)
+	if err != nil {
+		log.Printf("blob store setup err: %s", err.Error())
+		db.Close()
+		return nil, err
+	}
+
+	opts := []service.Option{
+		service.WithMailer(mailer),
+		service.WithPasswordHasher(hasher),
+		service.WithBlobStore(store),
+	}
+
+	if cfg.AUTH.PasswordPolicy.BreachedList != "" {
+		breached, err := util.LoadBreachedPasswordsFile(cfg.AUTH.PasswordPolicy.BreachedList)
+		if err != nil {
+			log.Printf("breached password list err: %s", err.Error())
+			db.Close()
+			return nil, err
+		}
+
+		log.Printf("loaded %d breached password hashes", breached.Len())
+		opts = append(opts, service.WithBreachedPasswords(breached))
+	}
+	for _, provider := range cfg.OIDC.Providers {
+		opts = append(opts, service.WithOIDCProvider(provider.Name, newOIDCProvider(provider), provider.TrustEmail))
+	}
+
 	repo := pgrepo.New(db, cfg)
-	services := service.New(repo, cfg)
+
+	return &App{
+		Config:   cfg,
+		DB:       db,
+		Services: service.New(repo, cfg, opts...),
+	}, nil
+}
+
+func (a *App) Close() {
+	a.DB.Close()
+}
+
+// NewMigrate prepares migrations from directory at path for the configured
+// database
+func NewMigrate(cfg *config.Config, path string) (*migrate.Migrate, error) {
+	return postgres.NewMigrate(path, dbOptions(cfg.DB)...)
+}
+
+// Run starts the server with scheduled tasks and blocks until interrupt
+func Run(cfg *config.Config) error {
+	app, err := New(cfg)
+	if err != nil {
+		return err
+	}
+
+	defer app.Close()
+
+	services := app.Services
 	handler := handler.New(services, cfg)
+
+	router, err := handler.InitRouter()
+	if err != nil {
+		return err
+	}
+
 	server := httpserver.New(
-		handler.InitRouter(),
+		router,
 		httpserver.WithPort(cfg.HTTP.Port),
 		httpserver.WithReadTimeout(cfg.HTTP.ReadTimeout),
 		httpserver.WithWriteTimeout(cfg.HTTP.WriteTimeout),
@@ -53,6 +136,42 @@ func Run(cfg *config.Config) error {
 		return err
 	}
 
+	//Delete stale failed login attempts every hour
+	_, err = taskScheduler.ScheduleWithCron(func(ctx context.Context) {
+		services.DeleteStaleLoginAttempts(ctx)
+		log.Println("stale login attempts are deleted")
+	}, "0 0 * * * *")
+	if err != nil {
+		log.Printf("scheduling task error: %s", err.Error())
+		return err
+	}
+
+	//Build queued data exports every minute
+	_, err = taskScheduler.ScheduleWithCron(func(ctx context.Context) {
+		err := services.ProcessDataExports(ctx)
+		if err != nil {
+			log.Printf("processing data exports err: %s", err.Error())
+		}
+	}, "0 * * * * *")
+	if err != nil {
+		log.Printf("scheduling task error: %s", err.Error())
+		return err
+	}
+
+	//Anonymize users deleted before grace period every day at 03:00
+	_, err = taskScheduler.ScheduleWithCron(func(ctx context.Context) {
+		err := services.PurgeDeletedUsers(ctx)
+		if err != nil {
+			log.Printf("purging deleted users err: %s", err.Error())
+			return
+		}
+		log.Println("deleted users are anonymized")
+	}, "0 0 3 * * *")
+	if err != nil {
+		log.Printf("scheduling task error: %s", err.Error())
+		return err
+	}
+
 	//Refresh rating of books every 3 hours
 	_, err = taskScheduler.ScheduleWithCron(func(ctx context.Context) {
 		services.RefreshBooksRating(ctx)
@@ -63,31 +182,18 @@ func Run(cfg *config.Config) error {
 		return err
 	}
 
-	//Create or update admin user
-	admin, err := services.GetUserByCredentials(context.Background(), cfg.ADMIN.Username)
-	if err != nil {
-		admin = &entity.User{
-			Username:  &cfg.ADMIN.Username,
-			Email:     &cfg.ADMIN.Email,
-			FirstName: &cfg.ADMIN.FirstName,
-			LastName:  &cfg.ADMIN.LastName,
-			Password: entity.Password{
-				Plaintext: &cfg.ADMIN.Password,
-			},
-			Role: entity.ADMIN,
-		}
-		log.Print("admin user does not exists, creating new one...")
-		services.CreateUser(context.Background(), admin)
-
-	} else {
-		admin.Email = &cfg.ADMIN.Email
-		admin.FirstName = &cfg.ADMIN.FirstName
-		admin.LastName = &cfg.ADMIN.LastName
-		admin.Password = entity.Password{
-			Plaintext: &cfg.ADMIN.Password,
+	//Refresh statistics of reviewers every hour
+	_, err = taskScheduler.ScheduleWithCron(func(ctx context.Context) {
+		err := services.RefreshUserStats(ctx)
+		if err != nil {
+			log.Printf("refreshing user stats err: %s", err.Error())
+			return
 		}
-		log.Print("admin user exists, updating info...")
-		services.UpdateUser(context.Background(), admin)
+		log.Println("statistics of reviewers are refreshed")
+	}, "0 30 * * * *")
+	if err != nil {
+		log.Printf("scheduling task error: %s", err.Error())
+		return err
 	}
 
 	server.Start()
@@ -110,3 +216,81 @@ func Run(cfg *config.Config) error {
 
 	return nil
 }
+
+func dbOptions(cfg config.DBConfig) []postgres.Option {
+	return []postgres.Option{
+		postgres.WithHost(cfg.Host),
+		postgres.WithPort(cfg.Port),
+		postgres.WithDBName(cfg.DBName),
+		postgres.WithUsername(cfg.Username),
+		postgres.WithPassword(cfg.Password),
+	}
+}
+
+func newMailer(cfg config.MailConfig) (mailer.Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return mailer.NewSMTPMailer(
+			mailer.WithHost(cfg.Host),
+			mailer.WithPort(cfg.Port),
+			mailer.WithCredentials(cfg.Username, cfg.Password),
+			mailer.WithSender(cfg.From),
+		), nil
+	case "file":
+		return mailer.NewFileMailer(cfg.Directory, cfg.From)
+	case "log", "":
+		return mailer.NewLogMailer(log.Default()), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", cfg.Driver)
+	}
+}
+
+func newBlobStore(cfg config.BlobConfig) (blob.Store, error) {
+	switch cfg.Driver {
+	case "s3":
+		opts := []blob.Option{
+			blob.WithRegion(cfg.Region),
+			blob.WithCredentials(cfg.AccessKey, cfg.SecretKey),
+		}
+
+		if cfg.PublicURL != "" {
+			opts = append(opts, blob.WithPublicURL(cfg.PublicURL))
+		}
+
+		return blob.NewS3Store(cfg.Endpoint, cfg.Bucket, opts...), nil
+	case "local", "":
+		return blob.NewLocalStore(cfg.Directory, cfg.BaseURL)
+	default:
+		return nil, fmt.Errorf("unknown blob driver %q", cfg.Driver)
+	}
+}
+
+func newPasswordHasher(cfg config.PasswordHashConfig) (util.PasswordHasher, error) {
+	switch cfg.Algorithm {
+	case "argon2id", "":
+		return &util.Argon2idHasher{
+			Memory:      cfg.Argon2Memory,
+			Iterations:  cfg.Argon2Iterations,
+			Parallelism: cfg.Argon2Parallelism,
+			SaltLength:  16,
+			KeyLength:   32,
+		}, nil
+	case "bcrypt":
+		return &util.BcryptHasher{Cost: cfg.BcryptCost}, nil
+	default:
+		return nil, fmt.Errorf("unknown password hash algorithm %q", cfg.Algorithm)
+	}
+}
+
+func newOIDCProvider(cfg config.OIDCProviderConfig) *oidc.Provider {
+	opts := []oidc.Option{
+		oidc.WithRedirectURL(cfg.RedirectURL),
+		oidc.WithClientSecret(os.Getenv(cfg.ClientSecretEnv)),
+	}
+
+	if len(cfg.Scopes) != 0 {
+		opts = append(opts, oidc.WithScopes(cfg.Scopes...))
+	}
+
+	return oidc.NewProvider(cfg.Issuer, cfg.ClientID, opts...)
+}
@@ -7,10 +7,15 @@ import (
 )
 
 type Config struct {
-	HTTP  ServerConfig `yaml:"http"`
-	DB    DBConfig     `yaml:"db"`
-	AUTH  AuthConfig   `yaml:"auth"`
-	ADMIN AdminConfig  `yaml:"admin"`
+	HTTP   ServerConfig `yaml:"http"`
+	DB     DBConfig     `yaml:"db"`
+	AUTH   AuthConfig   `yaml:"auth"`
+	ADMIN  AdminConfig  `yaml:"admin"`
+	MAIL   MailConfig   `yaml:"mail"`
+	OIDC   OIDCConfig   `yaml:"oidc"`
+	BLOB   BlobConfig   `yaml:"blob"`
+	AVATAR AvatarConfig `yaml:"avatar"`
+	EXPORT ExportConfig `yaml:"export"`
 }
 
 type ServerConfig struct {
@@ -19,11 +24,77 @@ type ServerConfig struct {
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
 	ReadTimeout     time.Duration `yaml:"read_timeout"`
 	WriteTimeout    time.Duration `yaml:"write_timeout"`
+
+	// Networks of reverse proxies whose X-Forwarded-For is used as client IP.
+	// When empty, client IP is always the remote address of the connection
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
 type AuthConfig struct {
-	TokenExpiration time.Duration `yaml:"token_expiration"`
-	SigningKey      string        `env:"AUTH_KEY" env-required:"true"`
+	// Either "opaque" or "jwt"
+	TokenFormat             string        `yaml:"token_format" env-default:"opaque"`
+	AccessTokenExpiration   time.Duration `yaml:"access_token_expiration" env-default:"15m"`
+	RefreshTokenExpiration  time.Duration `yaml:"refresh_token_expiration" env-default:"720h"`
+	ActivationExpiration    time.Duration `yaml:"activation_token_expiration"`
+	PasswordResetExpiration time.Duration `yaml:"password_reset_token_expiration"`
+
+	// Time given to confirm new email and minimal time between username changes
+	EmailChangeExpiration  time.Duration `yaml:"email_change_token_expiration"`
+	UsernameChangeCooldown time.Duration `yaml:"username_change_cooldown"`
+
+	// Time during which deleted account is restored by logging in. After it the
+	// profile is anonymized and reviews are either kept under "Deleted user"
+	// ("keep") or removed ("delete")
+	DeletionGracePeriod time.Duration `yaml:"deletion_grace_period"`
+	DeletedUserReviews  string        `yaml:"deleted_user_reviews" env-default:"keep"`
+
+	// Minimal time between two password reset requests for the same account
+	PasswordResetInterval time.Duration `yaml:"password_reset_interval"`
+
+	// Time given to enter TOTP code after password was accepted
+	TwoFactorExpiration time.Duration `yaml:"two_factor_token_expiration"`
+	TwoFactorIssuer     string        `yaml:"two_factor_issuer" env-default:"Library"`
+
+	// Failed logins allowed per account and per client IP before temporary lockout
+	LoginLockoutThreshold   int           `yaml:"login_lockout_threshold"`
+	LoginIPLockoutThreshold int           `yaml:"login_ip_lockout_threshold"`
+	LoginLockoutDuration    time.Duration `yaml:"login_lockout_duration"`
+
+	// Delay after the first failed login, doubled after every next one
+	LoginDelay time.Duration `yaml:"login_delay"`
+
+	// Hashes made with other algorithm or cost are replaced on successful login
+	PasswordHash PasswordHashConfig `yaml:"password_hash"`
+
+	// Checked on registration and on every password change
+	PasswordPolicy PasswordPolicyConfig `yaml:"password_policy"`
+
+	// Whether MODERATOR and ADMIN must enable 2FA to access moderation endpoints
+	RequireTwoFactor bool   `yaml:"require_two_factor"`
+	SigningKey       string `env:"AUTH_KEY" env-required:"true"`
+	SigningKeyID     string `env:"AUTH_KEY_ID" env-default:"default"`
+
+	// Keys that are no longer used for signing but still accepted, in format "kid1:key1,kid2:key2"
+	VerificationKeys map[string]string `env:"AUTH_VERIFICATION_KEYS"`
+}
+
+type PasswordHashConfig struct {
+	// Either "argon2id" or "bcrypt"
+	Algorithm  string `yaml:"algorithm" env-default:"argon2id"`
+	BcryptCost int    `yaml:"bcrypt_cost" env-default:"10"`
+
+	// Memory is set in KiB
+	Argon2Memory      uint32 `yaml:"argon2_memory" env-default:"65536"`
+	Argon2Iterations  uint32 `yaml:"argon2_iterations" env-default:"3"`
+	Argon2Parallelism uint8  `yaml:"argon2_parallelism" env-default:"2"`
+}
+
+type PasswordPolicyConfig struct {
+	MinLength int `yaml:"min_length" env-default:"8"`
+
+	// File with SHA-1 hashes of breached passwords, one "HASH:COUNT" per line.
+	// Screening is disabled when empty
+	BreachedList string `yaml:"breached_list"`
 }
 
 type DBConfig struct {
@@ -34,12 +105,80 @@ type DBConfig struct {
 	Password string `env:"DB_PASSWORD" env-required:"true"`
 }
 
+type MailConfig struct {
+	// Either "log", "file" or "smtp"
+	Driver string `yaml:"driver" env-default:"log"`
+	From   string `yaml:"from"`
+
+	// Used by "file" driver
+	Directory string `yaml:"directory"`
+
+	// Used by "smtp" driver
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Username string `env:"SMTP_USERNAME"`
+	Password string `env:"SMTP_PASSWORD"`
+}
+
+type BlobConfig struct {
+	// Either "local" or "s3"
+	Driver string `yaml:"driver" env-default:"local"`
+
+	// Used by "local" driver, files are served at BaseURL
+	Directory string `yaml:"directory" env-default:"media"`
+	BaseURL   string `yaml:"base_url" env-default:"/media"`
+
+	// Used by "s3" driver, any S3 compatible storage such as MinIO works
+	Endpoint  string `yaml:"endpoint"`
+	Bucket    string `yaml:"bucket"`
+	Region    string `yaml:"region" env-default:"us-east-1"`
+	AccessKey string `env:"S3_ACCESS_KEY"`
+	SecretKey string `env:"S3_SECRET_KEY"`
+
+	// Address objects are publicly available at, endpoint and bucket are used when empty
+	PublicURL string `yaml:"public_url"`
+}
+
+type AvatarConfig struct {
+	// Maximal size of uploaded file in bytes and maximal width or height in pixels
+	MaxSize      int64 `yaml:"max_size" env-default:"5242880"`
+	MaxDimension int   `yaml:"max_dimension" env-default:"4096"`
+}
+
+type ExportConfig struct {
+	// Time the archive is kept after it is built and validity of a single download URL
+	Expiration    time.Duration `yaml:"expiration"`
+	URLExpiration time.Duration `yaml:"url_expiration"`
+}
+
+type OIDCConfig struct {
+	// Time given to log in at provider
+	StateExpiration time.Duration        `yaml:"state_expiration"`
+	Providers       []OIDCProviderConfig `yaml:"providers"`
+}
+
+type OIDCProviderConfig struct {
+	// Used in login URL: /users/oidc/{name}/login
+	Name        string   `yaml:"name"`
+	Issuer      string   `yaml:"issuer"`
+	ClientID    string   `yaml:"client_id"`
+	RedirectURL string   `yaml:"redirect_url"`
+	Scopes      []string `yaml:"scopes"`
+
+	// Name of enviromental variable with client secret
+	ClientSecretEnv string `yaml:"client_secret_env"`
+
+	// Whether verified email from provider links identity to existing user with the same email
+	TrustEmail bool `yaml:"trust_email"`
+}
+
 type AdminConfig struct {
 	Username  string `yaml:"username"`
 	Email     string `yaml:"email"`
 	FirstName string `yaml:"first_name"`
 	LastName  string `yaml:"lasr_name"`
-	Password  string `env:"ADMIN_PASSWORD" env-required:"true"`
+	// Used only by "seed" command to create the first admin
+	Password string `env:"ADMIN_PASSWORD"`
 }
 
 func ParseConfig(path string) (*Config, error) {
@@ -0,0 +1,50 @@
+package entity
+
+import "time"
+
+// APIScope limits what API key can do. A key never exceeds role of its
+// owner, so every scope also requires one of its permissions
+type APIScope string
+
+const (
+	APIScopeBooksWrite   APIScope = "books:write"
+	APIScopeReviewsWrite APIScope = "reviews:write"
+	APIScopeModSuspend   APIScope = "mod:suspend"
+	APIScopeModReviews   APIScope = "mod:reviews"
+	APIScopeModRoles     APIScope = "mod:roles"
+)
+
+// APIScopePermissions lists permissions covered by the scope, scopes
+// without permissions are available to every user
+var APIScopePermissions = map[APIScope][]Permission{
+	APIScopeBooksWrite:   {PermissionBooksCreate, PermissionBooksUpdate, PermissionBooksDelete},
+	APIScopeReviewsWrite: {},
+	APIScopeModSuspend:   {PermissionUsersSuspend},
+	APIScopeModReviews:   {PermissionReviewsModerate},
+	APIScopeModRoles:     {PermissionUsersView, PermissionRolesGrant, PermissionRolesManage},
+}
+
+// APIKey is long-lived token for machine clients. Plaintext is returned
+// only once on creation, Prefix helps to tell keys apart later
+type APIKey struct {
+	ID         int64      `json:"id" db:"id"`
+	UserID     int64      `json:"-" db:"user_id"`
+	Name       string     `json:"name" db:"name"`
+	Plaintext  string     `json:"key,omitempty"`
+	Prefix     string     `json:"prefix" db:"prefix"`
+	Hash       []byte     `json:"-" db:"hash"`
+	Scopes     []APIScope `json:"scopes" db:"scopes"`
+	Expiry     *time.Time `json:"expiry" db:"expiry"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+func (k *APIKey) HasScope(scope APIScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "fmt"
+
+// Avatar images are square JPEGs stored under "<avatar key>/<size>.jpg"
+const (
+	AvatarSmall  = 64
+	AvatarMedium = 128
+	AvatarLarge  = 256
+)
+
+var AvatarSizes = []int{AvatarSmall, AvatarMedium, AvatarLarge}
+
+// Avatar holds public URLs of every avatar size
+type Avatar struct {
+	Small  string `json:"small"`
+	Medium string `json:"medium"`
+	Large  string `json:"large"`
+}
+
+func AvatarBlobKey(avatarKey string, size int) string {
+	return fmt.Sprintf("%s/%d.jpg", avatarKey, size)
+}
@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+// ContentRuleKind tells how review text is checked by the rule
+type ContentRuleKind string
+
+const (
+	// Pattern is a banned word or phrase, matched by whole words after
+	// leetspeak is normalized
+	ContentRuleTerm ContentRuleKind = "term"
+	// Threshold is the number of links allowed in the text
+	ContentRuleLinks ContentRuleKind = "links"
+	// Threshold is the longest allowed run of the same character
+	ContentRuleRepeat ContentRuleKind = "repeat"
+)
+
+// ContentRuleSeverity tells what happens to the review breaking the rule
+type ContentRuleSeverity string
+
+const (
+	ContentReject ContentRuleSeverity = "reject"
+	// Quarantined reviews are saved as pending until moderator approves them
+	ContentQuarantine ContentRuleSeverity = "quarantine"
+)
+
+func (s ContentRuleSeverity) Valid() bool {
+	return s == ContentReject || s == ContentQuarantine
+}
+
+// ContentRule is a rule of review screening, rules are edited by moderators
+// at runtime
+type ContentRule struct {
+	ID        int64               `json:"id" db:"id"`
+	Kind      ContentRuleKind     `json:"kind" db:"kind"`
+	Pattern   *string             `json:"pattern,omitempty" db:"pattern"`
+	Threshold *int                `json:"threshold,omitempty" db:"threshold"`
+	Severity  ContentRuleSeverity `json:"severity" db:"severity"`
+	Enabled   bool                `json:"enabled" db:"enabled"`
+	CreatedBy *int64              `json:"created_by" db:"created_by"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at" db:"updated_at"`
+}
+
+// ContentRuleUpdate holds changed fields of the rule, nil fields are kept
+type ContentRuleUpdate struct {
+	ID        int64
+	Pattern   *string
+	Threshold *int
+	Severity  *ContentRuleSeverity
+	Enabled   *bool
+}
@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+const (
+	ExportPending = "pending"
+	ExportRunning = "running"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+)
+
+// DataExport is an archive with every personal record of the user. It is
+// built in background and downloaded with a signed URL until Expiry
+type DataExport struct {
+	ID      int64      `json:"id" db:"id"`
+	UserID  int64      `json:"-" db:"user_id"`
+	Status  string     `json:"status" db:"status"`
+	BlobKey *string    `json:"-" db:"blob_key"`
+	Error   *string    `json:"error,omitempty" db:"error"`
+	Expiry  *time.Time `json:"expiry,omitempty" db:"expiry"`
+
+	// Set only for ready exports, valid until DownloadExpiry
+	DownloadURL    *string    `json:"download_url,omitempty"`
+	DownloadExpiry *time.Time `json:"download_expiry,omitempty"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
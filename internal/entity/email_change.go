@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// EmailChange is a pending change of email. It is applied with the token sent
+// to the new address, token sent to the old address cancels it. Confirmed
+// change is kept until expiry, so the old address can still revert it
+type EmailChange struct {
+	UserID      int64      `json:"user_id" db:"user_id"`
+	Email       string     `json:"email" db:"email"`
+	OldEmail    *string    `json:"old_email" db:"old_email"`
+	ConfirmHash []byte     `json:"-" db:"confirm_hash"`
+	CancelHash  []byte     `json:"-" db:"cancel_hash"`
+	Expiry      time.Time  `json:"expiry" db:"expiry"`
+	ConfirmedAt *time.Time `json:"confirmed_at" db:"confirmed_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
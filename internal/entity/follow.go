@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+const (
+	ActivityReviewCreated = "review_created"
+	ActivityReviewUpdated = "review_updated"
+	ActivityUserFollowed  = "user_followed"
+)
+
+// Follow is a row of follower or following list, the user is the other side
+// of the relation
+type Follow struct {
+	UserID     int64     `json:"user_id" db:"user_id"`
+	Username   *string   `json:"username" db:"username"`
+	FirstName  *string   `json:"first_name" db:"first_name"`
+	LastName   *string   `json:"last_name" db:"last_name"`
+	FollowedAt time.Time `json:"followed_at" db:"created_at"`
+}
+
+// Activity is an entry of the feed. Review is set for review activities,
+// FollowedUsername for "user_followed"
+type Activity struct {
+	ID               int64     `json:"id" db:"id"`
+	Kind             string    `json:"kind" db:"kind"`
+	UserID           int64     `json:"user_id" db:"user_id"`
+	Username         *string   `json:"username" db:"username"`
+	Review           *Review   `json:"review,omitempty"`
+	FollowedUsername *string   `json:"followed_username,omitempty"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+// FeedCursor points at the last activity of the page, next page starts
+// right after it
+type FeedCursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Identity links user to account at external OpenID Connect provider
+type Identity struct {
+	ID        int64     `json:"id" db:"id"`
+	UserID    int64     `json:"-" db:"user_id"`
+	Provider  string    `json:"provider" db:"provider"`
+	Subject   string    `json:"-" db:"subject"`
+	Email     *string   `json:"email" db:"email"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// OIDCState keeps parameters of login started with external provider
+// until the user is redirected back
+type OIDCState struct {
+	Hash         []byte    `db:"hash"`
+	Provider     string    `db:"provider"`
+	Nonce        string    `db:"nonce"`
+	CodeVerifier string    `db:"code_verifier"`
+	Expiry       time.Time `db:"expiry"`
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// LoginAttempt counts failed logins for an account or a client IP.
+// Counter is reset when no failures happened during the lockout window
+type LoginAttempt struct {
+	Key           string     `json:"key" db:"key"`
+	Failures      int        `json:"failures" db:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at" db:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until" db:"locked_until"`
+}
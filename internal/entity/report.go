@@ -0,0 +1,105 @@
+package entity
+
+import "time"
+
+// ReportCategory is a reason of the report chosen by reader
+type ReportCategory string
+
+const (
+	ReportSpam       ReportCategory = "spam"
+	ReportHarassment ReportCategory = "harassment"
+	ReportHateSpeech ReportCategory = "hate_speech"
+	ReportSpoiler    ReportCategory = "spoiler"
+	ReportOffTopic   ReportCategory = "off_topic"
+	ReportOther      ReportCategory = "other"
+)
+
+// ReportCategories lists every category the review can be reported for
+var ReportCategories = []ReportCategory{
+	ReportSpam,
+	ReportHarassment,
+	ReportHateSpeech,
+	ReportSpoiler,
+	ReportOffTopic,
+	ReportOther,
+}
+
+func (c ReportCategory) Valid() bool {
+	for _, category := range ReportCategories {
+		if c == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReportState moves from open to dismissed or actioned once moderator
+// resolves reports of the review
+type ReportState string
+
+const (
+	ReportOpen      ReportState = "open"
+	ReportDismissed ReportState = "dismissed"
+	ReportActioned  ReportState = "actioned"
+)
+
+// ReportAction is taken by moderator on reported review. Every action
+// except dismiss marks the reports as actioned
+type ReportAction string
+
+const (
+	ReportActionDismiss ReportAction = "dismiss"
+	ReportActionHide    ReportAction = "hide"
+	ReportActionDelete  ReportAction = "delete"
+	// Only the author is suspended, the review is kept
+	ReportActionSuspend ReportAction = "suspend"
+)
+
+func (a ReportAction) State() ReportState {
+	if a == ReportActionDismiss {
+		return ReportDismissed
+	}
+
+	return ReportActioned
+}
+
+type ReviewReport struct {
+	ID         int64          `json:"id" db:"id"`
+	ReviewID   int64          `json:"review_id" db:"review_id"`
+	ReporterID int64          `json:"reporter_id" db:"reporter_id"`
+	Category   ReportCategory `json:"category" db:"category"`
+	Comment    *string        `json:"comment" db:"comment"`
+	State      ReportState    `json:"state" db:"state"`
+	Resolution *ReportAction  `json:"resolution" db:"resolution"`
+	ResolvedBy *int64         `json:"resolved_by" db:"resolved_by"`
+	ResolvedAt *time.Time     `json:"resolved_at" db:"resolved_at"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+}
+
+// ReportedReview is an item of moderation queue, reports of the review in
+// the same state are grouped together
+type ReportedReview struct {
+	Review          Review           `json:"review"`
+	State           ReportState      `json:"state"`
+	Reports         int              `json:"reports"`
+	Categories      []ReportCategory `json:"categories"`
+	FirstReportedAt time.Time        `json:"first_reported_at"`
+	LastReportedAt  time.Time        `json:"last_reported_at"`
+}
+
+// ReportSearch holds filters of moderation queue
+type ReportSearch struct {
+	State    ReportState
+	Category *ReportCategory
+}
+
+// ReportResolution closes open reports of the review. The author is
+// suspended in the same step when Suspension is set
+type ReportResolution struct {
+	ReviewID    int64
+	ModeratorID int64
+	Action      ReportAction
+	Reason      string
+	Suspension  *Suspension
+}
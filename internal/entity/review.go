@@ -2,12 +2,53 @@ package entity
 
 import "time"
 
+// ReviewVisibility tells who can see the review
+type ReviewVisibility string
+
+const (
+	ReviewVisible ReviewVisibility = "visible"
+	// Hidden reviews are removed from public listings and ratings by
+	// moderator, only the author still sees them
+	ReviewHidden ReviewVisibility = "hidden"
+	// Deleted reviews are removed by the author and are not shown to anyone
+	ReviewDeleted ReviewVisibility = "deleted"
+	// Pending reviews were quarantined by content filter and wait for
+	// moderator approval, only the author sees them
+	ReviewPending ReviewVisibility = "pending"
+	// Removed reviews were deleted by moderator, the row with its reports,
+	// moderator and reason is kept for moderation history
+	ReviewRemoved ReviewVisibility = "removed"
+)
+
+func (v ReviewVisibility) Valid() bool {
+	return v == ReviewVisible || v == ReviewHidden || v == ReviewDeleted || v == ReviewPending || v == ReviewRemoved
+}
+
+// Deleted reports whether the review was deleted by the author or by moderator
+func (v ReviewVisibility) Deleted() bool {
+	return v == ReviewDeleted || v == ReviewRemoved
+}
+
 type Review struct {
-	ID        int64     `json:"id" db:"id"`
-	Content   *string   `json:"content" db:"content"`
-	Rating    *int64    `json:"rating" db:"rating"`
-	UserID    int64     `json:"user_id" db:"user_id"`
-	BookID    int64     `json:"book_id" db:"book_id"`
+	ID           int64            `json:"id" db:"id"`
+	Content      *string          `json:"content" db:"content"`
+	Rating       *int64           `json:"rating" db:"rating"`
+	UserID       int64            `json:"user_id" db:"user_id"`
+	BookID       int64            `json:"book_id" db:"book_id"`
+	Visibility   ReviewVisibility `json:"visibility" db:"visibility"`
+	HiddenBy     *int64           `json:"hidden_by,omitempty" db:"hidden_by"`
+	HiddenReason *string          `json:"hidden_reason,omitempty" db:"hidden_reason"`
+	HiddenAt     *time.Time       `json:"hidden_at,omitempty" db:"hidden_at"`
+	DeletedAt    *time.Time       `json:"deleted_at,omitempty" db:"deleted_at"`
+	// Notice explains to the author why the review is not public
+	Notice    string    `json:"notice,omitempty"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
+
+// ReviewSearch holds optional filters of review list
+type ReviewSearch struct {
+	Visibility *ReviewVisibility
+	BookID     *int64
+	UserID     *int64
+}
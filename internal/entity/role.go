@@ -1,32 +1,109 @@
 package entity
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+	"time"
+)
 
-type Role int
+// Role is a name of the role stored in database. Permissions of the role
+// are resolved on every request, so changes of the role apply immediately
+type Role string
 
+// Built-in roles, they can not be changed or deleted
 const (
-	USER Role = iota
-	MODERATOR
-	ADMIN
+	USER      Role = "USER"
+	MODERATOR Role = "MODERATOR"
+	ADMIN     Role = "ADMIN"
 )
 
-var RoleMap = map[string]Role{
-	"USER":      USER,
-	"MODERATOR": MODERATOR,
-	"ADMIN":     ADMIN,
-}
+var roleNameRX = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,49}$`)
 
+// StringToRole normalizes name of the role. Only the format is checked,
+// whether the role exists is known to database
 func StringToRole(str string) (Role, bool) {
-	c, ok := RoleMap[strings.ToUpper(str)]
-	return c, ok
+	name := strings.ToUpper(str)
+	if !roleNameRX.MatchString(name) {
+		return "", false
+	}
+
+	return Role(name), true
 }
 
 func (role Role) String() string {
-	for k, v := range RoleMap {
-		if v == role {
-			return k
+	return string(role)
+}
+
+func (role Role) BuiltIn() bool {
+	return role == USER || role == MODERATOR || role == ADMIN
+}
+
+type Permission string
+
+const (
+	PermissionBooksCreate     Permission = "books.create"
+	PermissionBooksUpdate     Permission = "books.update"
+	PermissionBooksDelete     Permission = "books.delete"
+	PermissionReviewsModerate Permission = "reviews.moderate"
+	PermissionUsersView       Permission = "users.view"
+	PermissionUsersSuspend    Permission = "users.suspend"
+	PermissionRolesGrant      Permission = "roles.grant"
+	PermissionRolesManage     Permission = "roles.manage"
+)
+
+// Permissions lists every permission which can be given to a role
+var Permissions = []Permission{
+	PermissionBooksCreate,
+	PermissionBooksUpdate,
+	PermissionBooksDelete,
+	PermissionReviewsModerate,
+	PermissionUsersView,
+	PermissionUsersSuspend,
+	PermissionRolesGrant,
+	PermissionRolesManage,
+}
+
+// RoleDefinition is a named set of permissions assigned to users
+type RoleDefinition struct {
+	Name        Role         `json:"name" db:"name"`
+	Description *string      `json:"description" db:"description"`
+	Permissions []Permission `json:"permissions" db:"permissions"`
+	BuiltIn     bool         `json:"built_in" db:"built_in"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// HasPermission reports whether any of permissions matches
+func HasPermission(permissions []Permission, permission Permission) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
 		}
 	}
 
-	return ""
+	return false
+}
+
+// HasPermissions reports whether owned contains every required permission
+func HasPermissions(owned []Permission, required []Permission) bool {
+	for _, p := range required {
+		if !HasPermission(owned, p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RoleChange is a record of role history of the user. ActorID is nil when
+// the role was changed by the system
+type RoleChange struct {
+	ID            int64     `json:"id" db:"id"`
+	UserID        int64     `json:"user_id" db:"user_id"`
+	ActorID       *int64    `json:"actor_id" db:"actor_id"`
+	ActorUsername *string   `json:"actor_username" db:"actor_username"`
+	OldRole       Role      `json:"old_role" db:"old_role"`
+	NewRole       Role      `json:"new_role" db:"new_role"`
+	Reason        *string   `json:"reason" db:"reason"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 }
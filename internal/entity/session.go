@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type Session struct {
+	ID         int64      `json:"id" db:"id"`
+	UserAgent  string     `json:"user_agent" db:"user_agent"`
+	IP         string     `json:"ip" db:"ip"`
+	Current    bool       `json:"current"`
+	Hash       []byte     `json:"-" db:"hash"`
+	Expiry     time.Time  `json:"expiry" db:"expiry"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
@@ -2,12 +2,29 @@ package entity
 
 import "time"
 
+// Suspension is active until it expires or is lifted. ExpiresIn is counted
+// from creation of the suspension
 type Suspension struct {
 	ID          int64          `json:"id" db:"id"`
 	Reason      *string        `json:"reason" db:"reason"`
 	UserID      int64          `json:"user_id" db:"user_id"`
 	ModeratorID int64          `json:"moderator_id" db:"moderator_id"`
 	ExpiresIn   *time.Duration `json:"expires_in" db:"expires_in" swaggertype:"primitive,integer"`
+	ExpiresAt   time.Time      `json:"expires_at" db:"expires_at"`
+	Active      bool           `json:"active" db:"active"`
+	UpdatedBy   *int64         `json:"updated_by" db:"updated_by"`
+	LiftedAt    *time.Time     `json:"lifted_at" db:"lifted_at"`
+	LiftedBy    *int64         `json:"lifted_by" db:"lifted_by"`
+	LiftReason  *string        `json:"lift_reason" db:"lift_reason"`
 	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
 }
+
+// SuspensionSearch holds optional filters of suspension list
+type SuspensionSearch struct {
+	Active        *bool
+	ModeratorID   *int64
+	UserID        *int64
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
@@ -2,9 +2,54 @@ package entity
 
 import "time"
 
+const (
+	ScopeAuthentication = "authentication"
+	ScopeActivation     = "activation"
+	ScopePasswordReset  = "password-reset"
+
+	// Issued after password check, exchanged for authentication token with TOTP or recovery code
+	ScopeTwoFactor = "2fa"
+)
+
 type Token struct {
+	ID        int64         `json:"-" db:"id"`
+	Plaintext string        `json:"token"`
+	Expiry    time.Time     `json:"expiry" db:"expiry"`
+	Refresh   *RefreshToken `json:"refresh_token,omitempty"`
+	Partial   bool          `json:"two_factor_required,omitempty"`
+	UserID    int64         `json:"-" db:"user_id"`
+	Hash      []byte        `json:"-" db:"token"`
+	JTI       *string       `json:"-" db:"jti"`
+	Scope     string        `json:"-" db:"scope"`
+	UserAgent string        `json:"-" db:"user_agent"`
+	IP        string        `json:"-" db:"ip"`
+	CreatedAt time.Time     `json:"-" db:"created_at"`
+}
+
+// RefreshToken is exchanged for a new access token. Every refresh token
+// belongs to a session (token family) and can be used only once
+type RefreshToken struct {
 	Plaintext string    `json:"token"`
 	Expiry    time.Time `json:"expiry" db:"expiry"`
+	SessionID int64     `json:"-" db:"session_id"`
 	UserID    int64     `json:"-" db:"user_id"`
-	Hash      []byte    `json:"-" db:"token"`
+	Used      bool      `json:"-" db:"used"`
+	Hash      []byte    `json:"-" db:"hash"`
+}
+
+// TokenStatus is checked for every request authenticated with JWT
+type TokenStatus struct {
+	Revoked   bool
+	Suspended bool
+	Activated bool
+	TwoFactor bool
+	// Current role of the user, it may differ from the role in claims
+	Role        Role
+	Permissions []Permission
+}
+
+// Client describes where a request for a new token came from
+type Client struct {
+	IP        string
+	UserAgent string
 }
@@ -0,0 +1,9 @@
+package entity
+
+// TwoFactor holds TOTP secret of the user. Secret is set on enrollment
+// and 2FA is enabled only after the first valid code is confirmed
+type TwoFactor struct {
+	Secret  string `json:"secret" db:"totp_secret"`
+	URI     string `json:"uri"`
+	Enabled bool   `json:"-" db:"totp_enabled"`
+}
@@ -3,14 +3,36 @@ package entity
 import "time"
 
 type User struct {
-	ID        int64     `json:"id" db:"id"`
-	Username  *string   `json:"username" db:"username"`
-	Email     *string   `json:"-" db:"email"`
-	FirstName *string   `json:"first_name" db:"first_name"`
-	LastName  *string   `json:"last_name" db:"last_name"`
-	Password  Password  `json:"-" db:"password_hash"`
-	Role      Role      `json:"role" db:"role"`
-	Suspended bool      `json:"-"`
+	ID        int64    `json:"id" db:"id"`
+	Username  *string  `json:"username" db:"username"`
+	Email     *string  `json:"-" db:"email"`
+	FirstName *string  `json:"first_name" db:"first_name"`
+	LastName  *string  `json:"last_name" db:"last_name"`
+	Password  Password `json:"-" db:"password_hash"`
+	Role      Role     `json:"role" db:"role"`
+	Suspended bool     `json:"-"`
+	Activated bool     `json:"-" db:"activated"`
+	TwoFactor bool     `json:"-" db:"totp_enabled"`
+
+	// Permissions of the role, loaded only for authorization
+	Permissions []Permission `json:"-"`
+
+	Bio      *string `json:"bio" db:"bio"`
+	Location *string `json:"location" db:"location"`
+	Website  *string `json:"website" db:"website"`
+
+	// Prefix of avatar blobs, URLs of every size are exposed in Avatar
+	AvatarKey *string `json:"-" db:"avatar_key"`
+	Avatar    *Avatar `json:"avatar"`
+
+	// Used to enforce cooldown between username changes
+	UsernameChangedAt *time.Time `json:"-" db:"username_changed_at"`
+
+	// Set while deletion of the account can be undone by logging in
+	DeletedAt *time.Time `json:"-" db:"deleted_at"`
+
+	// Set when user is authenticated with API key instead of session
+	APIKey    *APIKey   `json:"-"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
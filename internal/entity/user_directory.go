@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// UserSearch narrows admin user directory, nil fields are not applied.
+// Search matches prefix of username, email, first or last name
+type UserSearch struct {
+	Search        *string
+	Role          *Role
+	Suspended     *bool
+	Activated     *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+// UserOverview is a row of admin user directory
+type UserOverview struct {
+	ID          int64      `json:"id" db:"id"`
+	Username    *string    `json:"username" db:"username"`
+	Email       *string    `json:"email" db:"email"`
+	FirstName   *string    `json:"first_name" db:"first_name"`
+	LastName    *string    `json:"last_name" db:"last_name"`
+	Role        string     `json:"role" db:"role"`
+	Activated   bool       `json:"activated" db:"activated"`
+	Suspended   bool       `json:"suspended"`
+	ReviewCount int64      `json:"review_count"`
+	LastLoginAt *time.Time `json:"last_login_at" db:"last_login_at"`
+	DeletedAt   *time.Time `json:"deleted_at" db:"deleted_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
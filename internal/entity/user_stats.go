@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+// UserStats describes reviewing activity of the user. It is computed
+// periodically, so recent reviews may be missing until RefreshedAt moves
+type UserStats struct {
+	ReviewCount   int64    `json:"review_count"`
+	AverageRating *float64 `json:"average_rating"`
+	// Number of reviews by given rating
+	RatingDistribution map[int64]int64 `json:"rating_distribution"`
+	TopTags            []TagCount      `json:"top_tags"`
+	FirstReviewAt      *time.Time      `json:"first_review_at"`
+	LastReviewAt       *time.Time      `json:"last_review_at"`
+	// Reviews of the past 12 months and the current one, oldest first
+	MonthlyReviews []MonthCount `json:"monthly_reviews"`
+	RefreshedAt    *time.Time   `json:"refreshed_at"`
+}
+
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+type MonthCount struct {
+	// Formatted as "2006-01"
+	Month string `json:"month"`
+	Count int64  `json:"count"`
+}
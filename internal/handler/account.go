@@ -0,0 +1,244 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Request email change. Token is sent to the new email, notice to the current one
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param data body api.ChangeEmailRequest true "Request body"
+//
+// @Success      202 {object} api.DefaultResponse "Confirmation token was sent"
+// @Failure      400  {object}  api.ValidationErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/email [post]
+func (h *Handler) requestEmailChange(ctx *gin.Context) {
+	var req api.ChangeEmailRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.MustGet("userID").(int64)
+
+	err = h.Services.RequestEmailChange(ctx, userID, req.Email)
+	if err != nil {
+		var validationErr *service.ValidationError
+
+		switch {
+		case errors.As(err, &validationErr):
+			ctx.JSON(http.StatusBadRequest, &api.ValidationErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "validation failed",
+				Fields:  validationErr.Fields,
+			})
+			return
+		case errors.Is(err, service.ErrEmailTaken), errors.Is(err, service.ErrEmailChangeRevertible):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusAccepted, &api.DefaultResponse{
+		Code:    http.StatusAccepted,
+		Message: "confirmation token was sent to the new email",
+	})
+}
+
+// @Summary      Confirm email change with token sent to the new email
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param data body api.EmailChangeTokenRequest true "Request body"
+//
+// @Success      200 {object} api.DefaultResponse "Email succesfully changed"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/email/confirm [put]
+func (h *Handler) confirmEmailChange(ctx *gin.Context) {
+	var req api.EmailChangeTokenRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.Services.ConfirmEmailChange(ctx, req.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "email change token is invalid or expired",
+			})
+			return
+		case errors.Is(err, service.ErrEmailTaken):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "email succesfully changed",
+	})
+}
+
+// @Summary      Cancel email change with token sent to the previous email, confirmed change is reverted until it expires. Logs out all sessions
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param data body api.EmailChangeTokenRequest true "Request body"
+//
+// @Success      200 {object} api.DefaultResponse "Email change was cancelled"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/email/cancel [put]
+func (h *Handler) cancelEmailChange(ctx *gin.Context) {
+	var req api.EmailChangeTokenRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.Services.CancelEmailChange(ctx, req.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "email change does not exists or can no longer be cancelled",
+			})
+			return
+		case errors.Is(err, service.ErrEmailTaken):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "email change was cancelled",
+	})
+}
+
+// @Summary      Change username. Old username redirects to the new one
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param data body api.ChangeUsernameRequest true "Request body"
+//
+// @Success      200 {object} api.DefaultResponse "Username succesfully changed"
+// @Failure      400  {object}  api.ValidationErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      429  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/username [put]
+func (h *Handler) changeUsername(ctx *gin.Context) {
+	var req api.ChangeUsernameRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.MustGet("userID").(int64)
+
+	err = h.Services.ChangeUsername(ctx, userID, req.Username)
+	if err != nil {
+		var validationErr *service.ValidationError
+
+		switch {
+		case errors.As(err, &validationErr):
+			ctx.JSON(http.StatusBadRequest, &api.ValidationErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "validation failed",
+				Fields:  validationErr.Fields,
+			})
+			return
+		case errors.Is(err, service.ErrUsernameTaken):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrUsernameChangeCooldown):
+			ctx.JSON(http.StatusTooManyRequests, &api.ErrorResponse{
+				Code:    http.StatusTooManyRequests,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "username succesfully changed",
+	})
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/internal/service/mocks"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestEmailChange(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		RequestJSON  string
+		MockError    error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Token sent successfully",
+			RequestJSON:  `{"email": "new@gmail.com"}`,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusAccepted,
+		},
+		{
+			Name:         "Invalid email",
+			RequestJSON:  `{"email": "new"}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Email is the same",
+			RequestJSON:  `{"email": "new@gmail.com"}`,
+			MockError:    &service.ValidationError{Fields: map[string]string{"email": "must differ from current email"}},
+			ExpectMock:   true,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Email is taken",
+			RequestJSON:  `{"email": "new@gmail.com"}`,
+			MockError:    service.ErrEmailTaken,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/users/email", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			if test.ExpectMock {
+				services.On("RequestEmailChange", ctx, userID, "new@gmail.com").Return(test.MockError)
+			}
+
+			handler.requestEmailChange(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestConfirmEmailChange(t *testing.T) {
+	tests := []struct {
+		Name         string
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Email changed successfully",
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Invalid token",
+			MockError:    repository.ErrRecordNotFound,
+			ExpectedCode: http.StatusNotFound,
+		},
+		{
+			Name:         "Email was taken meanwhile",
+			MockError:    service.ErrEmailTaken,
+			ExpectedCode: http.StatusConflict,
+		},
+		{
+			Name:         "Error while changing email",
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("PUT", "/users/email/confirm", strings.NewReader(`{"token": "token"}`))
+			req.Header.Set("Content-Type", "application/json")
+			ctx.Request = req
+
+			services.On("ConfirmEmailChange", ctx, "token").Return(test.MockError)
+			handler.confirmEmailChange(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestChangeUsername(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		RequestJSON  string
+		MockError    error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Username changed successfully",
+			RequestJSON:  `{"username": "newname"}`,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Username is too short",
+			RequestJSON:  `{"username": "new"}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Username is taken",
+			RequestJSON:  `{"username": "newname"}`,
+			MockError:    service.ErrUsernameTaken,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusConflict,
+		},
+		{
+			Name:         "Username changed recently",
+			RequestJSON:  `{"username": "newname"}`,
+			MockError:    service.ErrUsernameChangeCooldown,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("PUT", "/users/username", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			if test.ExpectMock {
+				services.On("ChangeUsername", ctx, userID, "newname").Return(test.MockError)
+			}
+
+			handler.changeUsername(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
@@ -8,6 +8,10 @@ type Username struct {
 	Value string `uri:"username" binding:"required,min=5,max=50" example:"username"`
 }
 
+type Provider struct {
+	Value string `uri:"provider" binding:"required" example:"company"`
+}
+
 type Filter struct {
 	Page int `form:"page,default=1" default:"1"`
 
@@ -27,3 +31,10 @@ type ErrorResponse struct {
 	Code    int    `json:"code"`
 	Message string `json:"message"`
 }
+
+type ValidationErrorResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	// Problem with each rejected field
+	Fields map[string]string `json:"fields,omitempty"`
+}
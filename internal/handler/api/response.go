@@ -17,6 +17,12 @@ type GetUserByUsernameResponse struct {
 	Body    *entity.User `json:"body"`
 }
 
+type GetUserStatsResponse struct {
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Body    *entity.UserStats `json:"body"`
+}
+
 type GetBookByIDResponse struct {
 	Code    int          `json:"code"`
 	Message string       `json:"message"`
@@ -42,3 +48,156 @@ type CheckSuspensionResponse struct {
 	Message string               `json:"message"`
 	Body    []*entity.Suspension `json:"body"`
 }
+
+type SuspensionResponse struct {
+	Code    int                `json:"code"`
+	Message string             `json:"message"`
+	Body    *entity.Suspension `json:"body"`
+}
+
+type GetSuspensionsResponse struct {
+	Code    int                  `json:"code"`
+	Message string               `json:"message"`
+	Body    []*entity.Suspension `json:"body"`
+	Meta    util.Metadata        `json:"meta"`
+}
+
+type GetSessionsResponse struct {
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Body    []*entity.Session `json:"body"`
+}
+
+type EnrollTwoFactorResponse struct {
+	Code    int               `json:"code"`
+	Message string            `json:"message"`
+	Body    *entity.TwoFactor `json:"body"`
+}
+
+type ConfirmTwoFactorResponse struct {
+	Code    int      `json:"code"`
+	Message string   `json:"message"`
+	Body    []string `json:"body"`
+}
+
+type APIKeyResponse struct {
+	Code    int            `json:"code"`
+	Message string         `json:"message"`
+	Body    *entity.APIKey `json:"body"`
+}
+
+type GetAPIKeysResponse struct {
+	Code    int              `json:"code"`
+	Message string           `json:"message"`
+	Body    []*entity.APIKey `json:"body"`
+}
+
+type AvatarResponse struct {
+	Code    int            `json:"code"`
+	Message string         `json:"message"`
+	Body    *entity.Avatar `json:"body"`
+}
+
+type GetRolesResponse struct {
+	Code    int                      `json:"code"`
+	Message string                   `json:"message"`
+	Body    []*entity.RoleDefinition `json:"body"`
+}
+
+type GetRoleHistoryResponse struct {
+	Code    int                  `json:"code"`
+	Message string               `json:"message"`
+	Body    []*entity.RoleChange `json:"body"`
+	Meta    util.Metadata        `json:"meta"`
+}
+
+type RoleResponse struct {
+	Code    int                    `json:"code"`
+	Message string                 `json:"message"`
+	Body    *entity.RoleDefinition `json:"body"`
+}
+
+type DataExportResponse struct {
+	Code    int                `json:"code"`
+	Message string             `json:"message"`
+	Body    *entity.DataExport `json:"body"`
+}
+
+type GetFollowsResponse struct {
+	Code    int              `json:"code"`
+	Message string           `json:"message"`
+	Body    []*entity.Follow `json:"body"`
+	Meta    util.Metadata    `json:"meta"`
+}
+
+type GetFeedResponse struct {
+	Code    int                `json:"code"`
+	Message string             `json:"message"`
+	Body    []*entity.Activity `json:"body"`
+	// Cursor of the next page, omitted on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type GetUsersResponse struct {
+	Code    int                    `json:"code"`
+	Message string                 `json:"message"`
+	Body    []*entity.UserOverview `json:"body"`
+	Meta    util.Metadata          `json:"meta"`
+}
+
+type GetReviewsResponse struct {
+	Code    int              `json:"code"`
+	Message string           `json:"message"`
+	Body    []*entity.Review `json:"body"`
+}
+
+type GetModerationReviewsResponse struct {
+	Code    int              `json:"code"`
+	Message string           `json:"message"`
+	Body    []*entity.Review `json:"body"`
+	Meta    util.Metadata    `json:"meta"`
+}
+
+type ReviewResponse struct {
+	Code    int            `json:"code"`
+	Message string         `json:"message"`
+	Body    *entity.Review `json:"body"`
+}
+
+type ReviewReportResponse struct {
+	Code    int                  `json:"code"`
+	Message string               `json:"message"`
+	Body    *entity.ReviewReport `json:"body"`
+}
+
+type GetReviewReportsResponse struct {
+	Code    int                    `json:"code"`
+	Message string                 `json:"message"`
+	Body    []*entity.ReviewReport `json:"body"`
+}
+
+type GetReportedReviewsResponse struct {
+	Code    int                      `json:"code"`
+	Message string                   `json:"message"`
+	Body    []*entity.ReportedReview `json:"body"`
+	Meta    util.Metadata            `json:"meta"`
+}
+
+type ResolveReportsResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	// Suspension of the author, omitted when the author was not suspended
+	Suspension *entity.Suspension `json:"suspension,omitempty"`
+}
+
+type GetContentRulesResponse struct {
+	Code    int                   `json:"code"`
+	Message string                `json:"message"`
+	Body    []*entity.ContentRule `json:"body"`
+}
+
+type ContentRuleResponse struct {
+	Code    int                 `json:"code"`
+	Message string              `json:"message"`
+	Body    *entity.ContentRule `json:"body"`
+}
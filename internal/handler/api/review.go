@@ -21,5 +21,55 @@ type GetReviewsByBookIDRequest struct {
 
 type DeleteReviewRequest struct {
 	UserID int64
-	Role   entity.Role
+}
+
+type HideReviewRequest struct {
+	// Shown to the author of the review
+	Reason string `json:"reason" binding:"required,max=500" example:"Spoilers"`
+}
+
+type GetModerationReviewsRequest struct {
+	// One of visible, pending, hidden, deleted, removed
+	Visibility *entity.ReviewVisibility `form:"visibility" binding:"omitempty" example:"pending"`
+	BookID     *int64                   `form:"book_id" binding:"omitempty,min=1"`
+	UserID     *int64                   `form:"user_id" binding:"omitempty,min=1"`
+	Filter
+}
+
+type CreateContentRuleRequest struct {
+	Kind entity.ContentRuleKind `json:"kind" binding:"required,oneof=term links repeat" example:"term"`
+	// Banned word or phrase of term rule
+	Pattern *string `json:"pattern" binding:"omitempty,max=100" example:"buy now"`
+	// Allowed number of links or repeated characters
+	Threshold *int                       `json:"threshold" binding:"omitempty,min=0" swaggertype:"primitive,integer"`
+	Severity  entity.ContentRuleSeverity `json:"severity" binding:"required,oneof=reject quarantine" example:"reject"`
+	Enabled   *bool                      `json:"enabled" binding:"omitempty" default:"true"`
+}
+
+type UpdateContentRuleRequest struct {
+	Pattern   *string                     `json:"pattern" binding:"omitempty,max=100" example:"buy now"`
+	Threshold *int                        `json:"threshold" binding:"omitempty,min=0" swaggertype:"primitive,integer"`
+	Severity  *entity.ContentRuleSeverity `json:"severity" binding:"omitempty,oneof=reject quarantine" example:"quarantine"`
+	Enabled   *bool                       `json:"enabled" binding:"omitempty"`
+}
+
+type ReportReviewRequest struct {
+	// One of spam, harassment, hate_speech, spoiler, off_topic, other
+	Category entity.ReportCategory `json:"category" binding:"required" example:"spam"`
+	Comment  *string               `json:"comment" binding:"omitempty,max=500" example:"Link to a shop"`
+}
+
+type GetReportedReviewsRequest struct {
+	State    entity.ReportState     `form:"state,default=open" binding:"omitempty,oneof=open dismissed actioned" default:"open"`
+	Category *entity.ReportCategory `form:"category" binding:"omitempty" example:"spam"`
+	Filter
+}
+
+type ResolveReportsRequest struct {
+	Action entity.ReportAction `json:"action" binding:"required,oneof=dismiss hide delete suspend" example:"hide"`
+	// Shown to the author of hidden review and used as suspension reason
+	Reason string `json:"reason" binding:"required,max=500" example:"Advertisement"`
+
+	// Suspends the author for given number of minutes, required by suspend action
+	SuspendFor *int64 `json:"suspend_for" binding:"omitempty,min=1" swaggertype:"primitive,integer"`
 }
@@ -0,0 +1,19 @@
+package api
+
+type RoleName struct {
+	Value string `uri:"name" binding:"required,max=50" example:"LIBRARIAN"`
+}
+
+type CreateRoleRequest struct {
+	// Uppercase letters, digits and underscores
+	Name        string  `json:"name" binding:"required,max=50" example:"LIBRARIAN"`
+	Description *string `json:"description" binding:"omitempty,max=200" example:"Edits books"`
+	//Allowed values: "books.create", "books.update", "books.delete", "reviews.moderate", "users.view", "users.suspend", "roles.grant", "roles.manage"
+	Permissions []string `json:"permissions" binding:"required" example:"books.create,books.update"`
+}
+
+type UpdateRoleRequest struct {
+	Description *string `json:"description" binding:"omitempty,max=200" example:"Edits books"`
+	//Allowed values: "books.create", "books.update", "books.delete", "reviews.moderate", "users.view", "users.suspend", "roles.grant", "roles.manage"
+	Permissions []string `json:"permissions" binding:"required" example:"books.create,books.update"`
+}
@@ -1,5 +1,7 @@
 package api
 
+import "time"
+
 type CreateSuspensionRequest struct {
 	UserID int64  `json:"user_id" binding:"required" example:"21"`
 	Reason string `json:"reason" binding:"required" example:"Bad behaviour"`
@@ -10,8 +12,22 @@ type CreateSuspensionRequest struct {
 
 type UpdateSuspensionRequest struct {
 	ID
-	Reason string `json:"reason" binding:"omitempty" example:"Bad behaviour"`
+	Reason *string `json:"reason" binding:"omitempty,min=1" example:"Bad behaviour"`
 
-	// Time in minutes
-	ExpiresIn int64 `json:"expires_in" binding:"omitempty,min=1" swaggertype:"primitive,integer"`
+	// New duration in minutes counted from creation of suspension, can extend or shorten it
+	ExpiresIn *int64 `json:"expires_in" binding:"omitempty,min=1" swaggertype:"primitive,integer"`
+}
+
+type LiftSuspensionRequest struct {
+	Reason string `json:"reason" binding:"required,max=500" example:"Appeal accepted"`
+}
+
+type GetSuspensionsRequest struct {
+	Active      *bool  `form:"active" binding:"omitempty" example:"true"`
+	ModeratorID *int64 `form:"moderator_id" binding:"omitempty,min=1" example:"1"`
+	UserID      *int64 `form:"user_id" binding:"omitempty,min=1" example:"21"`
+	// Creation date range, both days included
+	From *time.Time `form:"from" time_format:"2006-01-02" binding:"omitempty" example:"2023-01-01"`
+	To   *time.Time `form:"to" time_format:"2006-01-02" binding:"omitempty" example:"2023-12-31"`
+	Filter
 }
@@ -1,17 +1,22 @@
 package api
 
+import "time"
+
 type CreateUserRequest struct {
 	Username  string `json:"username" binding:"required,min=5,max=50" example:"username"`
 	Email     string `json:"email" binding:"required,email" example:"example@gmail.com"`
-	Password  string `json:"password" binding:"required,min=6,max=32" example:"password"`
+	Password  string `json:"password" binding:"required,max=128" example:"password"`
 	FirstName string `json:"first_name" binding:"required,min=2,max=50" example:"John"`
 	LastName  string `json:"last_name" binding:"required,min=2,max=50" example:"Snow"`
 }
 
 type UpdateUserRequest struct {
-	Password  *string `json:"password" binding:"omitempty,min=6,max=32" example:"password"`
+	Password  *string `json:"password" binding:"omitempty,max=128" example:"password"`
 	FirstName *string `json:"first_name" binding:"omitempty,min=2,max=50" example:"John"`
 	LastName  *string `json:"last_name" binding:"omitempty,min=2,max=50" example:"Snow"`
+	Bio       *string `json:"bio" binding:"omitempty,max=500" example:"Reads mostly science fiction"`
+	Location  *string `json:"location" binding:"omitempty,max=100" example:"Winterfell"`
+	Website   *string `json:"website" binding:"omitempty,url,max=200" example:"https://example.com"`
 }
 
 type LoginRequest struct {
@@ -20,7 +25,91 @@ type LoginRequest struct {
 	Password    string `json:"password" binding:"required" example:"password"`
 }
 
-type GrantRoleToUser struct {
+type GetUsersRequest struct {
+	// Prefix of username, email, first or last name
+	Search *string `form:"search" binding:"omitempty,max=100" example:"joh"`
 	//Allowed values: "user", "moderator", "admin"
+	Role      *string `form:"role" binding:"omitempty" example:"MODERATOR"`
+	Suspended *bool   `form:"suspended" binding:"omitempty" example:"false"`
+	Activated *bool   `form:"activated" binding:"omitempty" example:"true"`
+	// Registration date range, both days included
+	RegisteredFrom *time.Time `form:"registered_from" time_format:"2006-01-02" binding:"omitempty" example:"2023-01-01"`
+	RegisteredTo   *time.Time `form:"registered_to" time_format:"2006-01-02" binding:"omitempty" example:"2023-12-31"`
+	Filter
+}
+
+type GetFeedRequest struct {
+	// Value of "next_cursor" from the previous page, empty for the first page
+	Cursor string `form:"cursor" example:"MTcwMDAwMDAwMDAwMDAwMDAwMDo0Mg"`
+	//Number of activities inside of one page. Can range between 1-100
+	Limit int `form:"limit,default=20" binding:"min=1,max=100" default:"20"`
+}
+
+type GrantRoleToUser struct {
+	//Name of built-in ("user", "moderator", "admin") or custom role
 	Role string `json:"role" binding:"required"  example:"ADMIN"`
+	//Why the role is changed, stored in role history
+	Reason *string `json:"reason" binding:"omitempty,max=500" example:"Joined moderation team"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"MFRGGZDFMZTWQ2LKNNWG23TPOBYXE43UOV3HO6DZPIZTENBV"`
+}
+
+type PasswordResetRequest struct {
+	// Can be username or email
+	Credentials string `json:"credentials" binding:"required" example:"username"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required" example:"MFRGGZDFMZTWQ2LKNNWG23TPOBYXE43UOV3HO6DZPIZTENBV"`
+	Password string `json:"password" binding:"required,max=128" example:"password"`
+}
+
+type LoginTwoFactorRequest struct {
+	Token string `json:"token" binding:"required" example:"MFRGGZDFMZTWQ2LKNNWG23TPOBYXE43UOV3HO6DZPIZTENBV"`
+	// TOTP code or one of recovery codes
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+type TwoFactorCodeRequest struct {
+	// TOTP code or one of recovery codes
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `form:"code"`
+	State string `form:"state" binding:"required"`
+
+	// Set by provider when user denied access
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+type ActivateUserRequest struct {
+	Token string `json:"token" binding:"required" example:"MFRGGZDFMZTWQ2LKNNWG23TPOBYXE43UOV3HO6DZPIZTENBV"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required,max=64" example:"ingestion"`
+	Scopes []string `json:"scopes" binding:"required,min=1" example:"books:write"`
+	// Key never expires when omitted
+	Expiry *time.Time `json:"expiry" example:"2030-01-01T00:00:00Z"`
+}
+
+type ChangeEmailRequest struct {
+	Email string `json:"email" binding:"required,email" example:"example@gmail.com"`
+}
+
+type EmailChangeTokenRequest struct {
+	Token string `json:"token" binding:"required" example:"MFRGGZDFMZTWQ2LKNNWG23TPOBYXE43UOV3HO6DZPIZTENBV"`
+}
+
+type ChangeUsernameRequest struct {
+	Username string `json:"username" binding:"required,min=5,max=50" example:"username"`
+}
+
+type DownloadDataExportRequest struct {
+	Expires   int64  `form:"expires" binding:"required" example:"1700000000"`
+	Signature string `form:"signature" binding:"required" example:"3f8a..."`
 }
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Create API key for machine clients. Key is shown only once
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param data body api.CreateAPIKeyRequest true "Request body"
+//
+// @Success      201 {object} api.APIKeyResponse "API key succesfully created"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      401  {object}  api.ErrorResponse
+// @Failure      403  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/api-keys [post]
+func (h *Handler) createAPIKey(ctx *gin.Context) {
+	var req api.CreateAPIKeyRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	scopes := make([]entity.APIScope, len(req.Scopes))
+	for i, scope := range req.Scopes {
+		scopes[i] = entity.APIScope(scope)
+	}
+
+	userID := ctx.MustGet("userID").(int64)
+
+	key, err := h.Services.CreateAPIKey(ctx, userID, req.Name, scopes, req.Expiry)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownScope), errors.Is(err, service.ErrInvalidExpiry):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrScopeNotAllowed):
+			ctx.JSON(http.StatusForbidden, &api.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, &api.APIKeyResponse{
+		Code:    http.StatusCreated,
+		Message: "api key succesfully created",
+		Body:    key,
+	})
+}
+
+// @Summary      Get API keys of current user
+// @Tags         Authentication
+// @Produce      json
+// @Security ApiKeyAuth
+//
+// @Success      200 {object} api.GetAPIKeysResponse "ok"
+// @Failure      401  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/api-keys [get]
+func (h *Handler) getAPIKeys(ctx *gin.Context) {
+	userID := ctx.MustGet("userID").(int64)
+
+	keys, err := h.Services.GetAPIKeys(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetAPIKeysResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    keys,
+	})
+}
+
+// @Summary      Revoke API key of current user by ID
+// @Tags         Authentication
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true  "API key ID"
+//
+// @Success      200 {object} api.DefaultResponse "API key was successfully revoked"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/api-keys/{id} [delete]
+func (h *Handler) deleteAPIKey(ctx *gin.Context) {
+	var id api.ID
+
+	err := ctx.ShouldBindUri(&id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.MustGet("userID").(int64)
+
+	err = h.Services.DeleteAPIKey(ctx, id.Value, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "api key does not exists",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "api key was successfully revoked",
+	})
+}
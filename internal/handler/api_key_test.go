@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/internal/service/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		Body         string
+		MockResult   any
+		MockError    error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Key created successfully",
+			Body:         `{"name":"ingestion","scopes":["books:write"]}`,
+			MockResult:   &entity.APIKey{ID: 1, Plaintext: "ak_token"},
+			ExpectMock:   true,
+			ExpectedCode: http.StatusCreated,
+		},
+		{
+			Name:         "Missing scopes",
+			Body:         `{"name":"ingestion","scopes":[]}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Unknown scope",
+			Body:         `{"name":"ingestion","scopes":["books:delete"]}`,
+			MockError:    service.ErrUnknownScope,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Scope exceeds role",
+			Body:         `{"name":"ingestion","scopes":["mod:roles"]}`,
+			MockError:    service.ErrScopeNotAllowed,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusForbidden,
+		},
+		{
+			Name:         "Error while creating key",
+			Body:         `{"name":"ingestion","scopes":["books:write"]}`,
+			MockError:    errors.New("critical error"),
+			ExpectMock:   true,
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			service := &mocks.Service{}
+			handler := New(service, nil)
+
+			req, _ := http.NewRequest("POST", "/users/api-keys", strings.NewReader(test.Body))
+			req.Header.Set("Content-Type", "application/json")
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			if test.ExpectMock {
+				service.On("CreateAPIKey", ctx, userID, "ingestion", mock.AnythingOfType("[]entity.APIScope"), (*time.Time)(nil)).Return(test.MockResult, test.MockError)
+			}
+
+			handler.createAPIKey(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestDeleteAPIKey(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		ID           string
+		MockError    error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Key revoked successfully",
+			ID:           "1",
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Invalid ID",
+			ID:           "key",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Key does not exist",
+			ID:           "1",
+			MockError:    repository.ErrRecordNotFound,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusNotFound,
+		},
+		{
+			Name:         "Error while revoking key",
+			ID:           "1",
+			MockError:    errors.New("critical error"),
+			ExpectMock:   true,
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			service := &mocks.Service{}
+			handler := New(service, nil)
+
+			req, _ := http.NewRequest("DELETE", fmt.Sprintf("/users/api-keys/%s", test.ID), strings.NewReader(""))
+			ctx.Request = req
+			ctx.Params = gin.Params{{Key: "id", Value: test.ID}}
+			ctx.Set("userID", userID)
+
+			if test.ExpectMock {
+				service.On("DeleteAPIKey", ctx, int64(1), userID).Return(test.MockError)
+			}
+
+			handler.deleteAPIKey(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Upload avatar of current user. Image is cropped to square and resized to 64, 128 and 256 pixels
+// @Tags         Users
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param avatar formData file true "JPEG, PNG or GIF image"
+//
+// @Success      200 {object} api.AvatarResponse "Avatar succesfully updated"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      413  {object}  api.ErrorResponse
+// @Failure      415  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/avatar [put]
+func (h *Handler) updateAvatar(ctx *gin.Context) {
+	header, err := ctx.FormFile("avatar")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	userID := ctx.MustGet("userID").(int64)
+
+	avatar, err := h.Services.UpdateAvatar(ctx, userID, file)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrImageTooLarge):
+			ctx.JSON(http.StatusRequestEntityTooLarge, &api.ErrorResponse{
+				Code:    http.StatusRequestEntityTooLarge,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrUnsupportedImage):
+			ctx.JSON(http.StatusUnsupportedMediaType, &api.ErrorResponse{
+				Code:    http.StatusUnsupportedMediaType,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.AvatarResponse{
+		Code:    http.StatusOK,
+		Message: "avatar succesfully updated",
+		Body:    avatar,
+	})
+}
+
+// @Summary      Delete avatar of current user
+// @Tags         Users
+// @Produce      json
+// @Security ApiKeyAuth
+//
+// @Success      200 {object} api.DefaultResponse "Avatar succesfully deleted"
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/avatar [delete]
+func (h *Handler) deleteAvatar(ctx *gin.Context) {
+	userID := ctx.MustGet("userID").(int64)
+
+	err := h.Services.DeleteAvatar(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "avatar succesfully deleted",
+	})
+}
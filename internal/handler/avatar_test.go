@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/service"
+	"one-lab-final/internal/service/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateAvatar(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		Field        string
+		MockError    error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Avatar updated successfully",
+			Field:        "avatar",
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "File is missing",
+			Field:        "image",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Image is too large",
+			Field:        "avatar",
+			MockError:    service.ErrImageTooLarge,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			Name:         "Unsupported image",
+			Field:        "avatar",
+			MockError:    service.ErrUnsupportedImage,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			Name:         "Error while storing avatar",
+			Field:        "avatar",
+			MockError:    errors.New("critical error"),
+			ExpectMock:   true,
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, _ := form.CreateFormFile(test.Field, "avatar.png")
+			part.Write([]byte("image"))
+			form.Close()
+
+			req, _ := http.NewRequest("PUT", "/users/avatar", &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			if test.ExpectMock {
+				var avatar *entity.Avatar
+				if test.MockError == nil {
+					avatar = &entity.Avatar{Small: "/media/avatars/123/abc/64.jpg"}
+				}
+				services.On("UpdateAvatar", ctx, userID, mock.Anything).Return(avatar, test.MockError)
+			}
+
+			handler.updateAvatar(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestDeleteAvatar(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Avatar deleted successfully",
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Error while deleting avatar",
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("DELETE", "/users/avatar", nil)
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			services.On("DeleteAvatar", ctx, userID).Return(test.MockError)
+
+			handler.deleteAvatar(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
@@ -17,7 +17,7 @@ var (
 	ErrEmptyTags = errors.New("tags must be present")
 )
 
-// @Summary      Create new book. Requires MODERATOR role or higher
+// @Summary      Create new book. Requires "books.create" permission
 // @Tags         Books
 // @Accept       json
 // @Produce      json
@@ -162,7 +162,7 @@ func (h *Handler) getBooks(ctx *gin.Context) {
 	})
 }
 
-// @Summary      Update book by id. Requires MODERATOR role or higher
+// @Summary      Update book by id. Requires "books.update" permission
 // @Tags         Books
 // @Accept       json
 // @Produce      json
@@ -232,7 +232,7 @@ func (h *Handler) updateBook(ctx *gin.Context) {
 
 }
 
-// @Summary      Delete book by ID. Requires MODERATOR role or higher
+// @Summary      Delete book by ID. Requires "books.delete" permission
 // @Tags         Books
 // @Produce      json
 // @Security ApiKeyAuth
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      List content rules screening review text. Requires "reviews.moderate" permission
+// @Tags         Moderation
+// @Produce      json
+// @Security ApiKeyAuth
+//
+// @Success      200 {object} api.GetContentRulesResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/content-rules [get]
+func (h *Handler) getContentRules(ctx *gin.Context) {
+	rules, err := h.Services.GetContentRules(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetContentRulesResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    rules,
+	})
+}
+
+// @Summary      Create content rule. Term rules require pattern, links and repeat rules require threshold. Requires "reviews.moderate" permission
+// @Tags         Moderation
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param data body api.CreateContentRuleRequest true "Request body"
+//
+// @Success      201 {object} api.ContentRuleResponse "Content rule succesfully created"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/content-rules [post]
+func (h *Handler) createContentRule(ctx *gin.Context) {
+	var req api.CreateContentRuleRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	moderatorID := ctx.MustGet("userID").(int64)
+
+	rule := &entity.ContentRule{
+		Kind:      req.Kind,
+		Pattern:   req.Pattern,
+		Threshold: req.Threshold,
+		Severity:  req.Severity,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+		CreatedBy: &moderatorID,
+	}
+
+	err = h.Services.CreateContentRule(ctx, rule)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownRuleKind), errors.Is(err, service.ErrUnknownSeverity), errors.Is(err, service.ErrInvalidContentRule):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, &api.ContentRuleResponse{
+		Code:    http.StatusCreated,
+		Message: "content rule succesfully created",
+		Body:    rule,
+	})
+}
+
+// @Summary      Change pattern, threshold, severity or state of content rule. Requires "reviews.moderate" permission
+// @Tags         Moderation
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true  "Content rule ID"
+// @Param data body api.UpdateContentRuleRequest true "Request body"
+//
+// @Success      200 {object} api.ContentRuleResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/content-rules/{id} [patch]
+func (h *Handler) updateContentRule(ctx *gin.Context) {
+	var req api.UpdateContentRuleRequest
+	var id api.ID
+
+	err := ctx.ShouldBindUri(&id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.Services.UpdateContentRule(ctx, entity.ContentRuleUpdate{
+		ID:        id.Value,
+		Pattern:   req.Pattern,
+		Threshold: req.Threshold,
+		Severity:  req.Severity,
+		Enabled:   req.Enabled,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownSeverity), errors.Is(err, service.ErrInvalidContentRule):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "content rule does not exist",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.ContentRuleResponse{
+		Code:    http.StatusOK,
+		Message: "content rule succesfully updated",
+		Body:    rule,
+	})
+}
+
+// @Summary      Delete content rule. Requires "reviews.moderate" permission
+// @Tags         Moderation
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true  "Content rule ID"
+//
+// @Success      200 {object} api.DefaultResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/content-rules/{id} [delete]
+func (h *Handler) deleteContentRule(ctx *gin.Context) {
+	var id api.ID
+
+	err := ctx.ShouldBindUri(&id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.Services.DeleteContentRule(ctx, id.Value)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "content rule does not exist",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "content rule succesfully deleted",
+	})
+}
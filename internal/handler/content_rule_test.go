@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/internal/service/mocks"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateContentRule(t *testing.T) {
+	var userID int64 = 123
+	pattern := "buy now"
+	tests := []struct {
+		Name         string
+		RequestJSON  string
+		MockResult   error
+		ExpectedRule *entity.ContentRule
+		ExpectedCode int
+	}{
+		{
+			Name:        "Create term rule successfully",
+			RequestJSON: `{"kind": "term", "pattern": "buy now", "severity": "reject"}`,
+			ExpectedRule: &entity.ContentRule{
+				Kind:      entity.ContentRuleTerm,
+				Pattern:   &pattern,
+				Severity:  entity.ContentReject,
+				Enabled:   true,
+				CreatedBy: &userID,
+			},
+			ExpectedCode: http.StatusCreated,
+		},
+		{
+			Name:         "Unknown kind",
+			RequestJSON:  `{"kind": "regex", "pattern": "buy now", "severity": "reject"}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:        "Term rule without pattern",
+			RequestJSON: `{"kind": "term", "severity": "quarantine", "enabled": false}`,
+			MockResult:  service.ErrInvalidContentRule,
+			ExpectedRule: &entity.ContentRule{
+				Kind:      entity.ContentRuleTerm,
+				Severity:  entity.ContentQuarantine,
+				Enabled:   false,
+				CreatedBy: &userID,
+			},
+			ExpectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/mod/content-rules", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			if test.ExpectedRule != nil {
+				services.On("CreateContentRule", ctx, test.ExpectedRule).Return(test.MockResult)
+			}
+
+			handler.createContentRule(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUpdateContentRule(t *testing.T) {
+	enabled := false
+	tests := []struct {
+		Name         string
+		RequestURI   string
+		RequestJSON  string
+		MockResult   error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Disable rule successfully",
+			RequestURI:   "2",
+			RequestJSON:  `{"enabled": false}`,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Rule does not exist",
+			RequestURI:   "2",
+			RequestJSON:  `{"enabled": false}`,
+			MockResult:   repository.ErrRecordNotFound,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusNotFound,
+		},
+		{
+			Name:         "Invalid ID",
+			RequestURI:   "abc",
+			RequestJSON:  `{"enabled": false}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("PATCH", "/mod/content-rules/"+test.RequestURI, strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx.Params = gin.Params{{Key: "id", Value: test.RequestURI}}
+			ctx.Request = req
+
+			if test.ExpectMock {
+				update := entity.ContentRuleUpdate{ID: 2, Enabled: &enabled}
+				services.On("UpdateContentRule", ctx, update).Return(&entity.ContentRule{ID: 2}, test.MockResult)
+			}
+
+			handler.updateContentRule(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
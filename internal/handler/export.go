@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Request export of all personal data. Archive is built in background, poll its status with returned ID
+// @Tags         Users
+// @Produce      json
+// @Security ApiKeyAuth
+//
+// @Success      202 {object} api.DataExportResponse "Export succesfully queued"
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/export [post]
+func (h *Handler) requestDataExport(ctx *gin.Context) {
+	userID := ctx.MustGet("userID").(int64)
+
+	export, err := h.Services.RequestDataExport(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrExportInProgress):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusAccepted, &api.DataExportResponse{
+		Code:    http.StatusAccepted,
+		Message: "export succesfully queued",
+		Body:    export,
+	})
+}
+
+// @Summary      Get status of data export. Ready export contains time-limited download URL
+// @Tags         Users
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true  "Export ID"
+//
+// @Success      200 {object} api.DataExportResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/export/{id} [get]
+func (h *Handler) getDataExport(ctx *gin.Context) {
+	var id api.ID
+
+	err := ctx.ShouldBindUri(&id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.MustGet("userID").(int64)
+
+	export, err := h.Services.GetDataExport(ctx, id.Value, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "export does not exist",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DataExportResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    export,
+	})
+}
+
+// @Summary      Download archive of data export. The URL is returned by export status endpoint
+// @Tags         Users
+// @Produce      application/zip
+// @Param        id   path      int  true  "Export ID"
+// @Param        query  query     api.DownloadDataExportRequest  true  "Signature of download URL"
+//
+// @Success      200 {file} file "ZIP archive with JSON files"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      403  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/export/{id}/download [get]
+func (h *Handler) downloadDataExport(ctx *gin.Context) {
+	var id api.ID
+	var req api.DownloadDataExportRequest
+
+	err := ctx.ShouldBindUri(&id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	archive, err := h.Services.DownloadDataExport(ctx, id.Value, req.Expires, req.Signature)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSignature):
+			ctx.JSON(http.StatusForbidden, &api.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "export does not exist",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+	defer archive.Close()
+
+	ctx.DataFromReader(http.StatusOK, -1, "application/zip", archive, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="export-%d.zip"`, id.Value),
+	})
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/internal/service/mocks"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestDataExport(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Export queued",
+			ExpectedCode: http.StatusAccepted,
+		},
+		{
+			Name:         "Previous export in progress",
+			MockError:    service.ErrExportInProgress,
+			ExpectedCode: http.StatusConflict,
+		},
+		{
+			Name:         "Error while queueing export",
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/users/export", nil)
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			var export *entity.DataExport
+			if test.MockError == nil {
+				export = &entity.DataExport{ID: 1, Status: entity.ExportPending}
+			}
+			services.On("RequestDataExport", ctx, userID).Return(export, test.MockError)
+
+			handler.requestDataExport(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestGetDataExport(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		ID           string
+		MockError    error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Export found",
+			ID:           "1",
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Non-valid ID",
+			ID:           "one",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Export does not exist",
+			ID:           "1",
+			MockError:    repository.ErrRecordNotFound,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("GET", "/users/export/"+test.ID, nil)
+			ctx.Request = req
+			ctx.Params = gin.Params{{Key: "id", Value: test.ID}}
+			ctx.Set("userID", userID)
+
+			if test.ExpectMock {
+				var export *entity.DataExport
+				if test.MockError == nil {
+					export = &entity.DataExport{ID: 1, Status: entity.ExportRunning}
+				}
+				services.On("GetDataExport", ctx, int64(1), userID).Return(export, test.MockError)
+			}
+
+			handler.getDataExport(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestDownloadDataExport(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Query        string
+		MockError    error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Archive downloaded",
+			Query:        "expires=1700000000&signature=abc",
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Signature is missing",
+			Query:        "expires=1700000000",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Signature is invalid",
+			Query:        "expires=1700000000&signature=abc",
+			MockError:    service.ErrInvalidSignature,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusForbidden,
+		},
+		{
+			Name:         "Export does not exist",
+			Query:        "expires=1700000000&signature=abc",
+			MockError:    repository.ErrRecordNotFound,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("GET", "/users/export/1/download?"+test.Query, nil)
+			ctx.Request = req
+			ctx.Params = gin.Params{{Key: "id", Value: "1"}}
+
+			if test.ExpectMock {
+				var archive io.ReadCloser
+				if test.MockError == nil {
+					archive = io.NopCloser(strings.NewReader("archive"))
+				}
+				services.On("DownloadDataExport", ctx, int64(1), int64(1700000000), "abc").Return(archive, test.MockError)
+			}
+
+			handler.downloadDataExport(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			if test.ExpectedCode == http.StatusOK {
+				assert.Equal(t, "archive", w.Body.String())
+				assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
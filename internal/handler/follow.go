@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/pkg/util"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Follow user, their activity appears in the feed
+// @Tags         Users
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        username   path      string  true  "Username"
+//
+// @Success      200 {object} api.DefaultResponse
+// @Success      308 "User was renamed, redirect to the current username"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/{username}/follow [post]
+func (h *Handler) followUser(ctx *gin.Context) {
+	var req api.Username
+
+	err := ctx.ShouldBindUri(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.MustGet("userID").(int64)
+
+	err = h.Services.FollowUser(ctx, userID, req.Value)
+	if err != nil {
+		var movedErr *service.UsernameMovedError
+
+		switch {
+		case errors.As(err, &movedErr):
+			ctx.Redirect(http.StatusPermanentRedirect, movedUsernamePath(ctx, req.Value, movedErr.Username))
+			return
+		case errors.Is(err, service.ErrCannotFollowSelf):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "user does not exists",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "user succesfully followed",
+	})
+}
+
+// @Summary      Unfollow user
+// @Tags         Users
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        username   path      string  true  "Username"
+//
+// @Success      200 {object} api.DefaultResponse
+// @Success      308 "User was renamed, redirect to the current username"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/{username}/follow [delete]
+func (h *Handler) unfollowUser(ctx *gin.Context) {
+	var req api.Username
+
+	err := ctx.ShouldBindUri(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.MustGet("userID").(int64)
+
+	err = h.Services.UnfollowUser(ctx, userID, req.Value)
+	if err != nil {
+		var movedErr *service.UsernameMovedError
+
+		switch {
+		case errors.As(err, &movedErr):
+			ctx.Redirect(http.StatusPermanentRedirect, movedUsernamePath(ctx, req.Value, movedErr.Username))
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "user does not exists",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "user succesfully unfollowed",
+	})
+}
+
+// @Summary      List users following the user
+// @Tags         Users
+// @Produce      json
+// @Param        username   path      string  true  "Username"
+// @Param        query  query     api.Filter  false  "Pagination, sort by username or created_at"
+//
+// @Success      200 {object} api.GetFollowsResponse
+// @Success      301 "User was renamed, redirect to the current username"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/{username}/followers [get]
+func (h *Handler) getFollowers(ctx *gin.Context) {
+	h.getFollows(ctx, h.Services.GetFollowers)
+}
+
+// @Summary      List users followed by the user
+// @Tags         Users
+// @Produce      json
+// @Param        username   path      string  true  "Username"
+// @Param        query  query     api.Filter  false  "Pagination, sort by username or created_at"
+//
+// @Success      200 {object} api.GetFollowsResponse
+// @Success      301 "User was renamed, redirect to the current username"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/{username}/following [get]
+func (h *Handler) getFollowing(ctx *gin.Context) {
+	h.getFollows(ctx, h.Services.GetFollowing)
+}
+
+func (h *Handler) getFollows(ctx *gin.Context, list func(context.Context, string, util.Filter) ([]*entity.Follow, *util.Metadata, error)) {
+	var req api.Username
+	var filter api.Filter
+
+	err := ctx.ShouldBindUri(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = ctx.ShouldBindQuery(&filter)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	follows, meta, err := list(ctx, req.Value, util.NewFilter(filter.Page, filter.PageSize, filter.Sort))
+	if err != nil {
+		var movedErr *service.UsernameMovedError
+
+		switch {
+		case errors.As(err, &movedErr):
+			ctx.Redirect(http.StatusMovedPermanently, movedUsernamePath(ctx, req.Value, movedErr.Username))
+			return
+		case errors.Is(err, service.ErrInvalidSortValue):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "user does not exists",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetFollowsResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    follows,
+		Meta:    *meta,
+	})
+}
+
+// @Summary      Get activity of followed users, newest first. Suspended and deleted users are skipped
+// @Tags         Users
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        query  query     api.GetFeedRequest  false  "Pagination"
+//
+// @Success      200 {object} api.GetFeedResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /feed [get]
+func (h *Handler) getFeed(ctx *gin.Context) {
+	var req api.GetFeedRequest
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.MustGet("userID").(int64)
+
+	activities, next, err := h.Services.GetFeed(ctx, userID, req.Cursor, req.Limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCursor):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetFeedResponse{
+		Code:       http.StatusOK,
+		Message:    "ok",
+		Body:       activities,
+		NextCursor: next,
+	})
+}
+
+// movedUsernamePath replaces previous username in request path with the
+// current one
+func movedUsernamePath(ctx *gin.Context, previous string, current string) string {
+	return strings.Replace(ctx.Request.URL.Path, "/"+url.PathEscape(previous)+"/", "/"+url.PathEscape(current)+"/", 1)
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/internal/service/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFollowUser(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name             string
+		Username         string
+		MockError        error
+		ExpectMock       bool
+		ExpectedCode     int
+		ExpectedLocation string
+	}{
+		{
+			Name:         "User followed",
+			Username:     "john_doe",
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Non-valid username",
+			Username:     "jd",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "User follows themselves",
+			Username:     "john_doe",
+			MockError:    service.ErrCannotFollowSelf,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "User does not exist",
+			Username:     "john_doe",
+			MockError:    repository.ErrRecordNotFound,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusNotFound,
+		},
+		{
+			Name:             "User was renamed",
+			Username:         "john_doe",
+			MockError:        &service.UsernameMovedError{Username: "john_smith"},
+			ExpectMock:       true,
+			ExpectedCode:     http.StatusPermanentRedirect,
+			ExpectedLocation: "/users/john_smith/follow",
+		},
+		{
+			Name:         "Error while following",
+			Username:     "john_doe",
+			MockError:    errors.New("critical error"),
+			ExpectMock:   true,
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/users/"+test.Username+"/follow", nil)
+			ctx.Request = req
+			ctx.Params = gin.Params{{Key: "username", Value: test.Username}}
+			ctx.Set("userID", userID)
+
+			if test.ExpectMock {
+				services.On("FollowUser", ctx, userID, test.Username).Return(test.MockError)
+			}
+
+			handler.followUser(ctx)
+			// Redirect does not write body, so the status is not flushed by itself
+			ctx.Writer.WriteHeaderNow()
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			if test.ExpectedLocation != "" {
+				assert.Equal(t, test.ExpectedLocation, w.Header().Get("Location"))
+			}
+			services.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetFeed(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		RequestQuery string
+		Cursor       string
+		Limit        int
+		MockError    error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "First page",
+			RequestQuery: "",
+			Limit:        20,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Next page",
+			RequestQuery: "cursor=abc&limit=5",
+			Cursor:       "abc",
+			Limit:        5,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Non-valid limit",
+			RequestQuery: "limit=1000",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Non-valid cursor",
+			RequestQuery: "cursor=abc",
+			Cursor:       "abc",
+			Limit:        20,
+			MockError:    service.ErrInvalidCursor,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Error while retrieving",
+			Limit:        20,
+			MockError:    errors.New("critical error"),
+			ExpectMock:   true,
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("GET", "/feed?"+test.RequestQuery, nil)
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			if test.ExpectMock {
+				var activities []*entity.Activity
+				if test.MockError == nil {
+					activities = []*entity.Activity{{ID: 1, Kind: entity.ActivityReviewCreated}}
+				}
+				services.On("GetFeed", ctx, userID, test.Cursor, test.Limit).Return(activities, "", test.MockError)
+			}
+
+			handler.getFeed(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
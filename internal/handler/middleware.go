@@ -11,17 +11,20 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func (h *Handler) requireRole(requiredRole entity.Role) gin.HandlerFunc {
+// requirePermission checks that role of the user has the permission.
+// Requests made with API key additionally need the scope, empty scope
+// allows only sessions
+func (h *Handler) requirePermission(permission entity.Permission, scope entity.APIScope) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		ok := h.authenticate(ctx)
+		ok := h.authenticate(ctx, scope)
 		if !ok {
 			return
 		}
 
-		value, _ := ctx.Get("role")
+		value, _ := ctx.Get("permissions")
 
-		role := value.(entity.Role)
-		if role < requiredRole {
+		permissions := value.([]entity.Permission)
+		if !entity.HasPermission(permissions, permission) {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, api.ErrorResponse{
 				Code:    http.StatusUnauthorized,
 				Message: "not enought rights",
@@ -29,22 +32,55 @@ func (h *Handler) requireRole(requiredRole entity.Role) gin.HandlerFunc {
 			return
 		}
 
+		if h.twoFactorRequired() && !ctx.GetBool("two_factor") {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, api.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "two-factor authentication must be enabled to access this resource",
+			})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func (h *Handler) requireAuthenticatedUser(scope entity.APIScope) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ok := h.authenticate(ctx, scope)
+		if !ok {
+			return
+		}
+
 		ctx.Next()
 	}
 }
 
-func (h *Handler) requireAuthenticatedUser() gin.HandlerFunc {
+func (h *Handler) requireActivatedUser(scope entity.APIScope) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		ok := h.authenticate(ctx)
+		ok := h.authenticate(ctx, scope)
 		if !ok {
 			return
 		}
 
+		if !ctx.GetBool("activated") {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, api.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "account must be activated to access this resource",
+			})
+			return
+		}
+
 		ctx.Next()
 	}
 }
 
-func (h *Handler) authenticate(ctx *gin.Context) bool {
+// twoFactorRequired reports whether privileged resources, i.e. the ones
+// requiring a permission, need two-factor authentication
+func (h *Handler) twoFactorRequired() bool {
+	return h.Config != nil && h.Config.AUTH.RequireTwoFactor
+}
+
+func (h *Handler) authenticate(ctx *gin.Context, scope entity.APIScope) bool {
 	authHeader := ctx.GetHeader("Authorization")
 
 	if authHeader == "" {
@@ -89,8 +125,43 @@ func (h *Handler) authenticate(ctx *gin.Context) bool {
 		})
 		return false
 	}
+
+	if user.APIKey != nil && (scope == "" || !user.APIKey.HasScope(scope)) {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, api.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "api key does not have required scope",
+		})
+		return false
+	}
+
 	ctx.Set("userID", user.ID)
 	ctx.Set("role", user.Role)
+	ctx.Set("permissions", user.Permissions)
+	ctx.Set("activated", user.Activated)
+	ctx.Set("two_factor", user.TwoFactor)
+	ctx.Set("token", token[1])
+	ctx.Set("apiKey", user.APIKey)
 
 	return true
 }
+
+// hasPermission reports whether authenticated user may also use the
+// permission inside of a route. It follows requirePermission, API keys need
+// the scope as well
+func (h *Handler) hasPermission(ctx *gin.Context, permission entity.Permission, scope entity.APIScope) bool {
+	value, _ := ctx.Get("permissions")
+
+	permissions, _ := value.([]entity.Permission)
+	if !entity.HasPermission(permissions, permission) {
+		return false
+	}
+
+	if h.twoFactorRequired() && !ctx.GetBool("two_factor") {
+		return false
+	}
+
+	value, _ = ctx.Get("apiKey")
+
+	key, _ := value.(*entity.APIKey)
+	return key == nil || key.HasScope(scope)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"one-lab-final/internal/config"
 	"one-lab-final/internal/entity"
 	"one-lab-final/internal/repository"
 	"one-lab-final/internal/service/mocks"
@@ -88,7 +89,7 @@ func TestAuthenticate(t *testing.T) {
 			ctx.Request = req
 
 			service.On("GetUserByToken", ctx, test.ExpectedToken).Return(test.MockResult, test.MockError)
-			status := handler.authenticate(ctx)
+			status := handler.authenticate(ctx, "")
 
 			assert.Equal(t, test.ExpectedStatus, status)
 			assert.Equal(t, test.ExpectedCode, w.Code)
@@ -96,7 +97,67 @@ func TestAuthenticate(t *testing.T) {
 	}
 }
 
-func TestRequireRole(t *testing.T) {
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		Name               string
+		MockResult         any
+		MockError          error
+		Token              string
+		ExpectedToken      string
+		ExpectedPermission entity.Permission
+		ExpectedCode       int
+	}{
+		{
+			Name: "Authenticated succesfully",
+			MockResult: &entity.User{
+				Role:        entity.ADMIN,
+				Permissions: []entity.Permission{entity.PermissionRolesGrant},
+			},
+			Token:              "Bearer token",
+			ExpectedToken:      "token",
+			ExpectedPermission: entity.PermissionRolesGrant,
+			ExpectedCode:       http.StatusOK,
+		},
+		{
+			Name:         "Non-valid token",
+			Token:        "Bearer",
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name: "Role does not have permission",
+			MockResult: &entity.User{
+				Role:        entity.Role("LIBRARIAN"),
+				Permissions: []entity.Permission{entity.PermissionBooksCreate},
+			},
+			Token:              "Bearer token",
+			ExpectedToken:      "token",
+			ExpectedPermission: entity.PermissionUsersSuspend,
+			ExpectedCode:       http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, e := gin.CreateTestContext(w)
+			service := &mocks.Service{}
+			handler := New(service, nil)
+
+			e.Use(handler.requirePermission(test.ExpectedPermission, "")).GET("/healthcheck", handler.healthcheck)
+			req, _ := http.NewRequest("GET", "/healthcheck", strings.NewReader(""))
+			req.Header.Set("Authorization", test.Token)
+			ctx.Request = req
+
+			service.On("GetUserByToken", mock.AnythingOfType("*gin.Context"), test.ExpectedToken).Return(test.MockResult, test.MockError)
+			e.ServeHTTP(w, req)
+
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+func TestRequireAuthenticatedUser(t *testing.T) {
 	tests := []struct {
 		Name          string
 		MockResult    any
@@ -121,16 +182,6 @@ func TestRequireRole(t *testing.T) {
 			Token:        "Bearer",
 			ExpectedCode: http.StatusUnauthorized,
 		},
-		{
-			Name: "Roles do not match",
-			MockResult: &entity.User{
-				Role: entity.USER,
-			},
-			Token:         "Bearer token",
-			ExpectedToken: "token",
-			ExpectedRole:  entity.ADMIN,
-			ExpectedCode:  http.StatusUnauthorized,
-		},
 	}
 
 	for _, test := range tests {
@@ -142,7 +193,7 @@ func TestRequireRole(t *testing.T) {
 			service := &mocks.Service{}
 			handler := New(service, nil)
 
-			e.Use(handler.requireRole(test.ExpectedRole)).GET("/healthcheck", handler.healthcheck)
+			e.Use(handler.requireAuthenticatedUser("")).GET("/healthcheck", handler.healthcheck)
 			req, _ := http.NewRequest("GET", "/healthcheck", strings.NewReader(""))
 			req.Header.Set("Authorization", test.Token)
 			ctx.Request = req
@@ -154,26 +205,32 @@ func TestRequireRole(t *testing.T) {
 		})
 	}
 }
-func TestRequireAuthenticatedUser(t *testing.T) {
+
+func TestRequireActivatedUser(t *testing.T) {
 	tests := []struct {
 		Name          string
 		MockResult    any
 		MockError     error
 		Token         string
 		ExpectedToken string
-		ExpectedRole  entity.Role
 		ExpectedCode  int
 	}{
 		{
-			Name: "Authenticated succesfully",
+			Name: "Activated user",
 			MockResult: &entity.User{
-				Role: entity.ADMIN,
+				Activated: true,
 			},
 			Token:         "Bearer token",
 			ExpectedToken: "token",
-			ExpectedRole:  entity.ADMIN,
 			ExpectedCode:  http.StatusOK,
 		},
+		{
+			Name:          "Not activated user",
+			MockResult:    &entity.User{},
+			Token:         "Bearer token",
+			ExpectedToken: "token",
+			ExpectedCode:  http.StatusForbidden,
+		},
 		{
 			Name:         "Non-valid token",
 			Token:        "Bearer",
@@ -190,7 +247,7 @@ func TestRequireAuthenticatedUser(t *testing.T) {
 			service := &mocks.Service{}
 			handler := New(service, nil)
 
-			e.Use(handler.requireAuthenticatedUser()).GET("/healthcheck", handler.healthcheck)
+			e.Use(handler.requireActivatedUser("")).GET("/healthcheck", handler.healthcheck)
 			req, _ := http.NewRequest("GET", "/healthcheck", strings.NewReader(""))
 			req.Header.Set("Authorization", test.Token)
 			ctx.Request = req
@@ -202,3 +259,140 @@ func TestRequireAuthenticatedUser(t *testing.T) {
 		})
 	}
 }
+
+func TestRequirePermissionTwoFactor(t *testing.T) {
+	tests := []struct {
+		Name         string
+		MockResult   any
+		ExpectedCode int
+	}{
+		{
+			Name: "Two-factor authentication is enabled",
+			MockResult: &entity.User{
+				Role:        entity.MODERATOR,
+				Permissions: []entity.Permission{entity.PermissionBooksCreate},
+				TwoFactor:   true,
+			},
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name: "Two-factor authentication is not enabled",
+			MockResult: &entity.User{
+				Role:        entity.MODERATOR,
+				Permissions: []entity.Permission{entity.PermissionBooksCreate},
+			},
+			ExpectedCode: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, e := gin.CreateTestContext(w)
+			service := &mocks.Service{}
+			handler := New(service, &config.Config{
+				AUTH: config.AuthConfig{
+					RequireTwoFactor: true,
+				},
+			})
+
+			e.Use(handler.requirePermission(entity.PermissionBooksCreate, "")).GET("/healthcheck", handler.healthcheck)
+			req, _ := http.NewRequest("GET", "/healthcheck", strings.NewReader(""))
+			req.Header.Set("Authorization", "Bearer token")
+			ctx.Request = req
+
+			service.On("GetUserByToken", mock.AnythingOfType("*gin.Context"), "token").Return(test.MockResult, nil)
+			e.ServeHTTP(w, req)
+
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestRequirePermissionAPIKey(t *testing.T) {
+	tests := []struct {
+		Name          string
+		MockResult    any
+		RequiredScope entity.APIScope
+		ExpectedCode  int
+	}{
+		{
+			Name: "Key has required scope",
+			MockResult: &entity.User{
+				Role:        entity.MODERATOR,
+				Permissions: []entity.Permission{entity.PermissionBooksCreate},
+				APIKey: &entity.APIKey{
+					Scopes: []entity.APIScope{entity.APIScopeBooksWrite},
+				},
+			},
+			RequiredScope: entity.APIScopeBooksWrite,
+			ExpectedCode:  http.StatusOK,
+		},
+		{
+			Name: "Key is missing required scope",
+			MockResult: &entity.User{
+				Role:        entity.MODERATOR,
+				Permissions: []entity.Permission{entity.PermissionBooksCreate},
+				APIKey: &entity.APIKey{
+					Scopes: []entity.APIScope{entity.APIScopeReviewsWrite},
+				},
+			},
+			RequiredScope: entity.APIScopeBooksWrite,
+			ExpectedCode:  http.StatusForbidden,
+		},
+		{
+			Name: "Owner role does not have permission",
+			MockResult: &entity.User{
+				Role: entity.USER,
+				APIKey: &entity.APIKey{
+					Scopes: []entity.APIScope{entity.APIScopeBooksWrite},
+				},
+			},
+			RequiredScope: entity.APIScopeBooksWrite,
+			ExpectedCode:  http.StatusUnauthorized,
+		},
+		{
+			Name: "Route is available only for sessions",
+			MockResult: &entity.User{
+				Role:        entity.MODERATOR,
+				Permissions: []entity.Permission{entity.PermissionBooksCreate},
+				APIKey: &entity.APIKey{
+					Scopes: []entity.APIScope{entity.APIScopeBooksWrite},
+				},
+			},
+			ExpectedCode: http.StatusForbidden,
+		},
+		{
+			Name: "Session is not limited by scopes",
+			MockResult: &entity.User{
+				Role:        entity.MODERATOR,
+				Permissions: []entity.Permission{entity.PermissionBooksCreate},
+			},
+			RequiredScope: entity.APIScopeBooksWrite,
+			ExpectedCode:  http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, e := gin.CreateTestContext(w)
+			service := &mocks.Service{}
+			handler := New(service, nil)
+
+			e.Use(handler.requirePermission(entity.PermissionBooksCreate, test.RequiredScope)).GET("/healthcheck", handler.healthcheck)
+			req, _ := http.NewRequest("GET", "/healthcheck", strings.NewReader(""))
+			req.Header.Set("Authorization", "Bearer ak_token")
+			ctx.Request = req
+
+			service.On("GetUserByToken", mock.AnythingOfType("*gin.Context"), "ak_token").Return(test.MockResult, nil)
+			e.ServeHTTP(w, req)
+
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/pkg/oidc"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Redirect to external identity provider to log in
+// @Tags         Authentication
+// @Param        provider   path      string  true  "Name of provider from config"
+//
+// @Success      302 "Redirect to identity provider"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/oidc/{provider}/login [get]
+func (h *Handler) oidcLogin(ctx *gin.Context) {
+	var req api.Provider
+
+	err := ctx.ShouldBindUri(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	url, err := h.Services.OIDCLoginURL(ctx, req.Value)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownProvider):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.Redirect(http.StatusFound, url)
+}
+
+// @Summary      Finish login with external identity provider and return access token
+// @Tags         Authentication
+// @Produce      json
+// @Param        provider   path      string  true  "Name of provider from config"
+// @Param        code   query      string  true  "Authorization code"
+// @Param        state   query      string  true  "State from login request"
+//
+// @Success      201 {object} api.LoginResponse "Token succesfully created"
+// @Success      202 {object} api.LoginResponse "Partial token must be exchanged with two-factor code"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      401  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/oidc/{provider}/callback [get]
+func (h *Handler) oidcCallback(ctx *gin.Context) {
+	var provider api.Provider
+	var req api.OIDCCallbackRequest
+
+	err := ctx.ShouldBindUri(&provider)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if req.Error != "" || req.Code == "" {
+		ctx.JSON(http.StatusUnauthorized, &api.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "identity provider did not authorize login: " + req.Error + " " + req.ErrorDescription,
+		})
+		return
+	}
+
+	token, err := h.Services.OIDCLogin(ctx, provider.Value, req.Code, req.State, entity.Client{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownProvider):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusUnauthorized, &api.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: "login request is invalid or expired",
+			})
+			return
+		case errors.Is(err, oidc.ErrExchangeFailed) || errors.Is(err, oidc.ErrInvalidIDToken):
+			ctx.JSON(http.StatusUnauthorized, &api.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrIdentityEmailTaken):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrOIDCEmailRequired):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	if token.Partial {
+		ctx.JSON(http.StatusAccepted, &api.LoginResponse{
+			Code:    http.StatusAccepted,
+			Message: "two-factor code is required, exchange token at /users/login/2fa",
+			Body:    token,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &api.LoginResponse{
+		Code:    http.StatusCreated,
+		Message: "token succesfully created",
+		Body:    token,
+	})
+}
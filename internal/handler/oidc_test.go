@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/internal/service/mocks"
+	"one-lab-final/pkg/oidc"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOIDCLogin(t *testing.T) {
+	tests := []struct {
+		Name             string
+		MockResult       string
+		MockError        error
+		ExpectedCode     int
+		ExpectedLocation string
+	}{
+		{
+			Name:             "Redirected to provider",
+			MockResult:       "https://sso.example.com/authorize?state=state",
+			ExpectedCode:     http.StatusFound,
+			ExpectedLocation: "https://sso.example.com/authorize?state=state",
+		},
+		{
+			Name:         "Unknown provider",
+			MockError:    service.ErrUnknownProvider,
+			ExpectedCode: http.StatusNotFound,
+		},
+		{
+			Name:         "Error while creating state",
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			_, e := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			e.GET("/users/oidc/:provider/login", handler.oidcLogin)
+			req, _ := http.NewRequest("GET", "/users/oidc/company/login", nil)
+
+			services.On("OIDCLoginURL", mock.AnythingOfType("*gin.Context"), "company").Return(test.MockResult, test.MockError)
+			e.ServeHTTP(w, req)
+
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			assert.Equal(t, test.ExpectedLocation, w.Header().Get("Location"))
+		})
+	}
+}
+
+func TestOIDCCallback(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Query        string
+		MockResult   *entity.Token
+		MockError    error
+		ExpectLogin  bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Logged in successfully",
+			Query:        "?code=code&state=state",
+			MockResult:   &entity.Token{},
+			ExpectLogin:  true,
+			ExpectedCode: http.StatusCreated,
+		},
+		{
+			Name:         "Two-factor code is required",
+			Query:        "?code=code&state=state",
+			MockResult:   &entity.Token{Partial: true},
+			ExpectLogin:  true,
+			ExpectedCode: http.StatusAccepted,
+		},
+		{
+			Name:         "Missing state",
+			Query:        "?code=code",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Access denied at provider",
+			Query:        "?error=access_denied&state=state",
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name:         "Invalid state",
+			Query:        "?code=code&state=state",
+			MockError:    repository.ErrRecordNotFound,
+			ExpectLogin:  true,
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name:         "Invalid ID token",
+			Query:        "?code=code&state=state",
+			MockError:    oidc.ErrInvalidIDToken,
+			ExpectLogin:  true,
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name:         "Email is taken",
+			Query:        "?code=code&state=state",
+			MockError:    service.ErrIdentityEmailTaken,
+			ExpectLogin:  true,
+			ExpectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			_, e := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			e.GET("/users/oidc/:provider/callback", handler.oidcCallback)
+			req, _ := http.NewRequest("GET", "/users/oidc/company/callback"+test.Query, nil)
+
+			if test.ExpectLogin {
+				services.On("OIDCLogin", mock.AnythingOfType("*gin.Context"), "company", "code", "state", mock.AnythingOfType("entity.Client")).Return(test.MockResult, test.MockError)
+			}
+			e.ServeHTTP(w, req)
+
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/pkg/util"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Report abusive review to moderators
+// @Tags         Reviews
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true  "Review ID"
+// @Param data body api.ReportReviewRequest true "Request body"
+//
+// @Success      201 {object} api.ReviewReportResponse "review was successfully reported"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /reviews/{id}/report [post]
+func (h *Handler) reportReview(ctx *gin.Context) {
+	var req api.ReportReviewRequest
+	var id api.ID
+
+	err := ctx.ShouldBindUri(&id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	report := &entity.ReviewReport{
+		ReviewID:   id.Value,
+		ReporterID: ctx.MustGet("userID").(int64),
+		Category:   req.Category,
+		Comment:    req.Comment,
+	}
+
+	err = h.Services.ReportReview(ctx, report)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownReportCategory), errors.Is(err, service.ErrCannotReportOwnReview):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "review does not exists",
+			})
+			return
+		case errors.Is(err, service.ErrAlreadyReported):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, &api.ReviewReportResponse{
+		Code:    http.StatusCreated,
+		Message: "review was successfully reported",
+		Body:    report,
+	})
+}
+
+// @Summary      Moderation queue, reports are grouped by review. Requires "reviews.moderate" permission
+// @Tags         Moderation
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        query  query     api.GetReportedReviewsRequest  false  "Filters, pagination, sort by reports, created_at (first report) or last_reported_at"
+//
+// @Success      200 {object} api.GetReportedReviewsResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/reports [get]
+func (h *Handler) getReportedReviews(ctx *gin.Context) {
+	var req api.GetReportedReviewsRequest
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	search := entity.ReportSearch{
+		State:    req.State,
+		Category: req.Category,
+	}
+
+	items, meta, err := h.Services.GetReportedReviews(ctx, search, util.NewFilter(req.Page, req.PageSize, req.Sort))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSortValue), errors.Is(err, service.ErrUnknownReportCategory):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetReportedReviewsResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    items,
+		Meta:    *meta,
+	})
+}
+
+// @Summary      List every report of the review, resolved included. Requires "reviews.moderate" permission
+// @Tags         Moderation
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true  "Review ID"
+//
+// @Success      200 {object} api.GetReviewReportsResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/reports/{id} [get]
+func (h *Handler) getReviewReports(ctx *gin.Context) {
+	var req api.ID
+
+	err := ctx.ShouldBindUri(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	reports, err := h.Services.GetReviewReports(ctx, req.Value)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "review does not exists",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetReviewReportsResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    reports,
+	})
+}
+
+// @Summary      Resolve open reports of the review. Requires "reviews.moderate" permission, suspending the author also requires "users.suspend"
+// @Description  Action dismiss closes reports as dismissed. Hide, delete and suspend close them as actioned, the author is suspended in the same step when suspend_for is set
+// @Tags         Moderation
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true  "Review ID"
+// @Param data body api.ResolveReportsRequest true "Request body"
+//
+// @Success      200 {object} api.ResolveReportsResponse "reports were successfully resolved"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      403  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/reports/{id}/resolve [post]
+func (h *Handler) resolveReports(ctx *gin.Context) {
+	var req api.ResolveReportsRequest
+	var id api.ID
+
+	err := ctx.ShouldBindUri(&id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	resolution := &entity.ReportResolution{
+		ReviewID:    id.Value,
+		ModeratorID: ctx.MustGet("userID").(int64),
+		Action:      req.Action,
+		Reason:      req.Reason,
+	}
+
+	if req.SuspendFor != nil {
+		if !h.hasPermission(ctx, entity.PermissionUsersSuspend, entity.APIScopeModSuspend) {
+			ctx.JSON(http.StatusForbidden, &api.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: fmt.Sprintf("suspending the author requires %q permission", entity.PermissionUsersSuspend),
+			})
+			return
+		}
+
+		expiresIn := time.Minute * time.Duration(*req.SuspendFor)
+		resolution.Suspension = &entity.Suspension{ExpiresIn: &expiresIn}
+	}
+
+	err = h.Services.ResolveReviewReports(ctx, resolution)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSuspensionRequired), errors.Is(err, service.ErrDismissWithSuspension):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "review does not exists",
+			})
+			return
+		case errors.Is(err, service.ErrNoOpenReports), errors.Is(err, service.ErrReviewHidden), errors.Is(err, repository.ErrEditConflict):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.ResolveReportsResponse{
+		Code:       http.StatusOK,
+		Message:    "reports were successfully resolved",
+		Suspension: resolution.Suspension,
+	})
+}
@@ -0,0 +1,325 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/internal/service/mocks"
+	"one-lab-final/pkg/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportReview(t *testing.T) {
+	var userID int64 = 123
+	comment := "Link to a shop"
+	tests := []struct {
+		Name         string
+		RequestURI   string
+		RequestJSON  string
+		MockResult   error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Report review successfully",
+			RequestURI:   "2",
+			RequestJSON:  `{"category": "spam", "comment": "Link to a shop"}`,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusCreated,
+		},
+		{
+			Name:         "Missing category",
+			RequestURI:   "2",
+			RequestJSON:  `{"comment": "Link to a shop"}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Non-valid id",
+			RequestURI:   "bleh",
+			RequestJSON:  `{"category": "spam", "comment": "Link to a shop"}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Own review",
+			RequestURI:   "2",
+			RequestJSON:  `{"category": "spam", "comment": "Link to a shop"}`,
+			MockResult:   service.ErrCannotReportOwnReview,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Review does not exist",
+			RequestURI:   "2",
+			RequestJSON:  `{"category": "spam", "comment": "Link to a shop"}`,
+			MockResult:   repository.ErrRecordNotFound,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusNotFound,
+		},
+		{
+			Name:         "Review already reported",
+			RequestURI:   "2",
+			RequestJSON:  `{"category": "spam", "comment": "Link to a shop"}`,
+			MockResult:   service.ErrAlreadyReported,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/reviews/"+test.RequestURI+"/report", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx.Params = gin.Params{{Key: "id", Value: test.RequestURI}}
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			if test.ExpectMock {
+				expected := &entity.ReviewReport{ReviewID: 2, ReporterID: userID, Category: entity.ReportSpam, Comment: &comment}
+				services.On("ReportReview", ctx, expected).Return(test.MockResult)
+			}
+
+			handler.reportReview(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetReportedReviews(t *testing.T) {
+	spam := entity.ReportSpam
+	tests := []struct {
+		Name           string
+		Query          string
+		MockError      error
+		ExpectMock     bool
+		ExpectedSearch entity.ReportSearch
+		ExpectedSort   string
+		ExpectedCode   int
+	}{
+		{
+			Name:           "Open reports by default",
+			Query:          "",
+			ExpectMock:     true,
+			ExpectedSearch: entity.ReportSearch{State: entity.ReportOpen},
+			ExpectedSort:   "created_at",
+			ExpectedCode:   http.StatusOK,
+		},
+		{
+			Name:           "Filtered by state and category",
+			Query:          "?state=dismissed&category=spam&sort=-reports",
+			ExpectMock:     true,
+			ExpectedSearch: entity.ReportSearch{State: entity.ReportDismissed, Category: &spam},
+			ExpectedSort:   "-reports",
+			ExpectedCode:   http.StatusOK,
+		},
+		{
+			Name:         "Unknown state",
+			Query:        "?state=closed",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:           "Invalid sort",
+			Query:          "?sort=content",
+			MockError:      service.ErrInvalidSortValue,
+			ExpectMock:     true,
+			ExpectedSearch: entity.ReportSearch{State: entity.ReportOpen},
+			ExpectedSort:   "content",
+			ExpectedCode:   http.StatusBadRequest,
+		},
+		{
+			Name:           "Some error ocurred while retrieving",
+			Query:          "",
+			MockError:      errors.New("critical error"),
+			ExpectMock:     true,
+			ExpectedSearch: entity.ReportSearch{State: entity.ReportOpen},
+			ExpectedSort:   "created_at",
+			ExpectedCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("GET", "/mod/reports"+test.Query, nil)
+			ctx.Request = req
+
+			if test.ExpectMock {
+				var meta *util.Metadata
+				if test.MockError == nil {
+					meta = &util.Metadata{}
+				}
+
+				services.On("GetReportedReviews", ctx, test.ExpectedSearch, util.NewFilter(1, 50, test.ExpectedSort)).
+					Return([]*entity.ReportedReview{}, meta, test.MockError)
+			}
+
+			handler.getReportedReviews(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
+
+func TestResolveReports(t *testing.T) {
+	var userID int64 = 123
+	suspendFor := time.Minute * 60
+	tests := []struct {
+		Name               string
+		RequestURI         string
+		RequestJSON        string
+		Permissions        []entity.Permission
+		MockResult         error
+		ExpectedResolution *entity.ReportResolution
+		ExpectedCode       int
+	}{
+		{
+			Name:        "Hide review",
+			RequestURI:  "2",
+			RequestJSON: `{"action": "hide", "reason": "Advertisement"}`,
+			Permissions: []entity.Permission{entity.PermissionReviewsModerate},
+			ExpectedResolution: &entity.ReportResolution{
+				ReviewID:    2,
+				ModeratorID: userID,
+				Action:      entity.ReportActionHide,
+				Reason:      "Advertisement",
+			},
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:        "Delete review and suspend author",
+			RequestURI:  "2",
+			RequestJSON: `{"action": "delete", "reason": "Advertisement", "suspend_for": 60}`,
+			Permissions: []entity.Permission{entity.PermissionReviewsModerate, entity.PermissionUsersSuspend},
+			ExpectedResolution: &entity.ReportResolution{
+				ReviewID:    2,
+				ModeratorID: userID,
+				Action:      entity.ReportActionDelete,
+				Reason:      "Advertisement",
+				Suspension:  &entity.Suspension{ExpiresIn: &suspendFor},
+			},
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Suspending author without permission",
+			RequestURI:   "2",
+			RequestJSON:  `{"action": "delete", "reason": "Advertisement", "suspend_for": 60}`,
+			Permissions:  []entity.Permission{entity.PermissionReviewsModerate},
+			ExpectedCode: http.StatusForbidden,
+		},
+		{
+			Name:         "Unknown action",
+			RequestURI:   "2",
+			RequestJSON:  `{"action": "ban", "reason": "Advertisement"}`,
+			Permissions:  []entity.Permission{entity.PermissionReviewsModerate},
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:        "Suspend action without duration",
+			RequestURI:  "2",
+			RequestJSON: `{"action": "suspend", "reason": "Advertisement"}`,
+			Permissions: []entity.Permission{entity.PermissionReviewsModerate},
+			MockResult:  service.ErrSuspensionRequired,
+			ExpectedResolution: &entity.ReportResolution{
+				ReviewID:    2,
+				ModeratorID: userID,
+				Action:      entity.ReportActionSuspend,
+				Reason:      "Advertisement",
+			},
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:        "No open reports",
+			RequestURI:  "2",
+			RequestJSON: `{"action": "dismiss", "reason": "Not a spam"}`,
+			Permissions: []entity.Permission{entity.PermissionReviewsModerate},
+			MockResult:  service.ErrNoOpenReports,
+			ExpectedResolution: &entity.ReportResolution{
+				ReviewID:    2,
+				ModeratorID: userID,
+				Action:      entity.ReportActionDismiss,
+				Reason:      "Not a spam",
+			},
+			ExpectedCode: http.StatusConflict,
+		},
+		{
+			Name:        "Review already hidden",
+			RequestURI:  "2",
+			RequestJSON: `{"action": "hide", "reason": "Spoilers"}`,
+			Permissions: []entity.Permission{entity.PermissionReviewsModerate},
+			MockResult:  service.ErrReviewHidden,
+			ExpectedResolution: &entity.ReportResolution{
+				ReviewID:    2,
+				ModeratorID: userID,
+				Action:      entity.ReportActionHide,
+				Reason:      "Spoilers",
+			},
+			ExpectedCode: http.StatusConflict,
+		},
+		{
+			Name:        "Review does not exist",
+			RequestURI:  "2",
+			RequestJSON: `{"action": "dismiss", "reason": "Not a spam"}`,
+			Permissions: []entity.Permission{entity.PermissionReviewsModerate},
+			MockResult:  repository.ErrRecordNotFound,
+			ExpectedResolution: &entity.ReportResolution{
+				ReviewID:    2,
+				ModeratorID: userID,
+				Action:      entity.ReportActionDismiss,
+				Reason:      "Not a spam",
+			},
+			ExpectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/mod/reports/"+test.RequestURI+"/resolve", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx.Params = gin.Params{{Key: "id", Value: test.RequestURI}}
+			ctx.Request = req
+			ctx.Set("userID", userID)
+			ctx.Set("permissions", test.Permissions)
+
+			if test.ExpectedResolution != nil {
+				services.On("ResolveReviewReports", ctx, test.ExpectedResolution).Return(test.MockResult)
+			}
+
+			handler.resolveReports(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
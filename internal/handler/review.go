@@ -7,6 +7,7 @@ import (
 	"one-lab-final/internal/entity"
 	"one-lab-final/internal/handler/api"
 	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
 	"one-lab-final/pkg/util"
 
 	"github.com/gin-gonic/gin"
@@ -20,7 +21,8 @@ import (
 // @Param data body api.CreateReviewRequest true "Request body"
 //
 // @Success      201 {object} api.DefaultResponse "Review succesfully created"
-// @Failure      400  {object}  api.ErrorResponse
+// @Success      202 {object} api.DefaultResponse "Review is waiting for moderator approval"
+// @Failure      400  {object}  api.ValidationErrorResponse
 // @Failure      500  {object}  api.ErrorResponse
 // @Router       /reviews/new [post]
 func (h *Handler) createReview(ctx *gin.Context) {
@@ -47,15 +49,35 @@ func (h *Handler) createReview(ctx *gin.Context) {
 	err = h.Services.CreateReview(ctx, review)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		})
-		return
+		var validationErr *service.ValidationError
+
+		switch {
+		case errors.As(err, &validationErr):
+			ctx.JSON(http.StatusBadRequest, &api.ValidationErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "review was rejected by content filter",
+				Fields:  validationErr.Fields,
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
 	}
 
 	ctx.Header("Locations", fmt.Sprintf("/reviews/%d", review.ID))
 
+	if review.Visibility == entity.ReviewPending {
+		ctx.JSON(http.StatusAccepted, &api.DefaultResponse{
+			Code:    http.StatusAccepted,
+			Message: pendingReviewMessage(review),
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusCreated, &api.DefaultResponse{
 		Code:    http.StatusCreated,
 		Message: "review succesfully created",
@@ -122,6 +144,31 @@ func (h *Handler) getReviewsByBookID(ctx *gin.Context) {
 	})
 }
 
+// @Summary      Get reviews of current user. Hidden reviews are included with a notice
+// @Tags         Reviews
+// @Produce      json
+// @Security ApiKeyAuth
+//
+// @Success      200 {object} api.GetReviewsResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /reviews/mine [get]
+func (h *Handler) getOwnReviews(ctx *gin.Context) {
+	reviews, err := h.Services.GetOwnReviews(ctx, ctx.MustGet("userID").(int64))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetReviewsResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    reviews,
+	})
+}
+
 // @Summary      Update review by ID
 // @Tags         Reviews
 // @Accept       json
@@ -131,7 +178,8 @@ func (h *Handler) getReviewsByBookID(ctx *gin.Context) {
 // @Param data body api.UpdateReviewRequest true "Request body"
 //
 // @Success      200 {object} api.DefaultResponse
-// @Failure      400  {object}  api.ErrorResponse
+// @Success      202 {object} api.DefaultResponse "Review is waiting for moderator approval"
+// @Failure      400  {object}  api.ValidationErrorResponse
 // @Failure      500  {object}  api.ErrorResponse
 // @Router       /reviews/update/{id} [patch]
 func (h *Handler) updateReview(ctx *gin.Context) {
@@ -159,16 +207,38 @@ func (h *Handler) updateReview(ctx *gin.Context) {
 
 	req.UserID = ctx.MustGet("userID").(int64)
 
-	err = h.Services.UpdateReview(ctx, &entity.Review{
+	review := &entity.Review{
 		ID:      id.Value,
 		Content: req.Content,
 		Rating:  req.Rating,
 		UserID:  req.UserID,
-	})
+	}
+
+	err = h.Services.UpdateReview(ctx, review)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
+		var validationErr *service.ValidationError
+
+		switch {
+		case errors.As(err, &validationErr):
+			ctx.JSON(http.StatusBadRequest, &api.ValidationErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "review was rejected by content filter",
+				Fields:  validationErr.Fields,
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	if review.Visibility == entity.ReviewPending {
+		ctx.JSON(http.StatusAccepted, &api.DefaultResponse{
+			Code:    http.StatusAccepted,
+			Message: pendingReviewMessage(review),
 		})
 		return
 	}
@@ -180,7 +250,7 @@ func (h *Handler) updateReview(ctx *gin.Context) {
 
 }
 
-// @Summary      Delete review by ID
+// @Summary      Delete review by ID. Users with "reviews.moderate" permission can delete any review
 // @Tags         Reviews
 // @Accept       json
 // @Produce      json
@@ -189,6 +259,7 @@ func (h *Handler) updateReview(ctx *gin.Context) {
 //
 // @Success      200 {object} api.DefaultResponse
 // @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
 // @Failure      500  {object}  api.ErrorResponse
 // @Router       /reviews/delete/{id} [delete]
 func (h *Handler) deleteReview(ctx *gin.Context) {
@@ -206,21 +277,227 @@ func (h *Handler) deleteReview(ctx *gin.Context) {
 
 	req.UserID = ctx.MustGet("userID").(int64)
 
-	err = h.Services.DeleteReview(ctx, id.Value, req.UserID)
+	// Moderators can delete reviews of other users
+	if h.hasPermission(ctx, entity.PermissionReviewsModerate, entity.APIScopeModReviews) {
+		err = h.Services.RemoveReview(ctx, id.Value, req.UserID)
+	} else {
+		err = h.Services.DeleteReview(ctx, id.Value, req.UserID)
+	}
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
-			Code:    http.StatusInternalServerError,
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "review does not exists",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "review succesfully deleted",
+	})
+}
+
+// @Summary      Hide review from book reviews and ratings. Requires "reviews.moderate" permission
+// @Tags         Moderation
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true  "Review ID"
+// @Param data body api.HideReviewRequest true "Request body"
+//
+// @Success      200 {object} api.ReviewResponse "review was successfully hidden"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/reviews/{id}/hide [post]
+func (h *Handler) hideReview(ctx *gin.Context) {
+	var req api.HideReviewRequest
+	var id api.ID
+
+	err := ctx.ShouldBindUri(&id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
 			Message: err.Error(),
 		})
 		return
 	}
 
-	// if req.Role >= entity.MODERATOR {
-	// 	log.Println()
-	// }
+	moderatorID := ctx.MustGet("userID").(int64)
 
-	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+	review := &entity.Review{
+		ID:           id.Value,
+		HiddenBy:     &moderatorID,
+		HiddenReason: &req.Reason,
+	}
+
+	err = h.Services.HideReview(ctx, review)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "review does not exists",
+			})
+			return
+		case errors.Is(err, service.ErrReviewHidden), errors.Is(err, repository.ErrEditConflict):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.ReviewResponse{
 		Code:    http.StatusOK,
-		Message: "review succesfully deleted",
+		Message: "review was successfully hidden",
+		Body:    review,
+	})
+}
+
+// @Summary      Make hidden review visible again. Requires "reviews.moderate" permission
+// @Tags         Moderation
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true  "Review ID"
+//
+// @Success      200 {object} api.ReviewResponse "review was successfully restored"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/reviews/{id}/restore [post]
+func (h *Handler) restoreReview(ctx *gin.Context) {
+	var id api.ID
+
+	err := ctx.ShouldBindUri(&id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	review := &entity.Review{ID: id.Value}
+
+	err = h.Services.RestoreReview(ctx, review)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "review does not exists",
+			})
+			return
+		case errors.Is(err, service.ErrReviewNotHidden), errors.Is(err, repository.ErrEditConflict):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.ReviewResponse{
+		Code:    http.StatusOK,
+		Message: "review was successfully restored",
+		Body:    review,
+	})
+}
+
+// @Summary      List reviews of any visibility, e.g. pending reviews quarantined by content filter. Requires "reviews.moderate" permission
+// @Tags         Moderation
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        query  query     api.GetModerationReviewsRequest  false  "Filters, pagination, sort by id, rating, created_at or updated_at"
+//
+// @Success      200 {object} api.GetModerationReviewsResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/reviews [get]
+func (h *Handler) getModerationReviews(ctx *gin.Context) {
+	var req api.GetModerationReviewsRequest
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	search := entity.ReviewSearch{
+		Visibility: req.Visibility,
+		BookID:     req.BookID,
+		UserID:     req.UserID,
+	}
+
+	reviews, meta, err := h.Services.GetReviews(ctx, search, util.NewFilter(req.Page, req.PageSize, req.Sort))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSortValue), errors.Is(err, service.ErrUnknownVisibility):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetModerationReviewsResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    reviews,
+		Meta:    *meta,
 	})
 }
+
+// pendingReviewMessage tells the author why the review was quarantined
+func pendingReviewMessage(review *entity.Review) string {
+	message := "review is waiting for moderator approval"
+	if review.HiddenReason != nil {
+		message = fmt.Sprintf("%s, it %s", message, *review.HiddenReason)
+	}
+
+	return message
+}
@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"one-lab-final/internal/entity"
 	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
 	"one-lab-final/internal/service/mocks"
 	"one-lab-final/pkg/util"
 	"strings"
@@ -25,6 +26,7 @@ func TestCreateReview(t *testing.T) {
 		RequestJSON    string
 		MockResult     any
 		ExpectedReview entity.Review
+		Quarantined    bool
 		ExpectedCode   int
 	}{
 		{
@@ -71,6 +73,41 @@ func TestCreateReview(t *testing.T) {
 			},
 			ExpectedCode: http.StatusInternalServerError,
 		},
+		{
+			Name: "Review rejected by content filter",
+			RequestJSON: `
+			{
+				"content": "This is a review", 
+				"rating": 5, 
+				"book_id": 123
+			}`,
+			MockResult: &service.ValidationError{Fields: map[string]string{"content": "contains banned words"}},
+			ExpectedReview: entity.Review{
+				Content: util.StringToPointer("This is a review"),
+				Rating:  util.IntToPointer(5),
+				BookID:  bookID,
+				UserID:  userID,
+			},
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name: "Review quarantined by content filter",
+			RequestJSON: `
+			{
+				"content": "This is a review", 
+				"rating": 5, 
+				"book_id": 123
+			}`,
+			MockResult: nil,
+			ExpectedReview: entity.Review{
+				Content: util.StringToPointer("This is a review"),
+				Rating:  util.IntToPointer(5),
+				BookID:  bookID,
+				UserID:  userID,
+			},
+			Quarantined:  true,
+			ExpectedCode: http.StatusAccepted,
+		},
 	}
 
 	for _, test := range tests {
@@ -89,7 +126,11 @@ func TestCreateReview(t *testing.T) {
 			ctx.Request = req
 			ctx.Set("userID", userID)
 
-			service.On("CreateReview", ctx, &test.ExpectedReview).Return(test.MockResult)
+			service.On("CreateReview", ctx, &test.ExpectedReview).Return(test.MockResult).Run(func(args mock.Arguments) {
+				if test.Quarantined {
+					args.Get(1).(*entity.Review).Visibility = entity.ReviewPending
+				}
+			})
 			handler.createReview(ctx)
 			assert.Equal(t, test.ExpectedCode, w.Code)
 		})
@@ -298,6 +339,7 @@ func TestDeleteReview(t *testing.T) {
 		RequestURI   string
 		MockResult   any
 		ExpectedID   int64
+		Permissions  []entity.Permission
 		ExpectedCode int
 	}{
 		{
@@ -319,6 +361,21 @@ func TestDeleteReview(t *testing.T) {
 			ExpectedID:   reviewID,
 			ExpectedCode: http.StatusInternalServerError,
 		},
+		{
+			Name:         "Review does not exist",
+			RequestURI:   fmt.Sprintf("%d", reviewID),
+			MockResult:   repository.ErrRecordNotFound,
+			ExpectedID:   reviewID,
+			ExpectedCode: http.StatusNotFound,
+		},
+		{
+			Name:         "Moderator deletes review of other user",
+			RequestURI:   fmt.Sprintf("%d", reviewID),
+			MockResult:   nil,
+			ExpectedID:   reviewID,
+			Permissions:  []entity.Permission{entity.PermissionReviewsModerate},
+			ExpectedCode: http.StatusOK,
+		},
 	}
 
 	for _, test := range tests {
@@ -336,12 +393,143 @@ func TestDeleteReview(t *testing.T) {
 
 			param := gin.Param{Key: "id", Value: test.RequestURI}
 			ctx.Set("userID", userID)
+			ctx.Set("permissions", test.Permissions)
 			ctx.Params = append(ctx.Params, param)
 			ctx.Request = req
 
-			service.On("DeleteReview", ctx, test.ExpectedID, userID).Return(test.MockResult)
+			if test.Permissions != nil {
+				service.On("RemoveReview", ctx, test.ExpectedID, userID).Return(test.MockResult)
+			} else {
+				service.On("DeleteReview", ctx, test.ExpectedID, userID).Return(test.MockResult)
+			}
+
 			handler.deleteReview(ctx)
 			assert.Equal(t, test.ExpectedCode, w.Code)
 		})
 	}
 }
+
+func TestHideReview(t *testing.T) {
+	var userID int64 = 123
+	reason := "Spoilers"
+	tests := []struct {
+		Name         string
+		RequestURI   string
+		RequestJSON  string
+		MockResult   error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Hide review successfully",
+			RequestURI:   "2",
+			RequestJSON:  `{"reason": "Spoilers"}`,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Missing reason",
+			RequestURI:   "2",
+			RequestJSON:  `{}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Review does not exist",
+			RequestURI:   "2",
+			RequestJSON:  `{"reason": "Spoilers"}`,
+			MockResult:   repository.ErrRecordNotFound,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusNotFound,
+		},
+		{
+			Name:         "Review already hidden",
+			RequestURI:   "2",
+			RequestJSON:  `{"reason": "Spoilers"}`,
+			MockResult:   service.ErrReviewHidden,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/mod/reviews/"+test.RequestURI+"/hide", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx.Params = gin.Params{{Key: "id", Value: test.RequestURI}}
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			if test.ExpectMock {
+				expected := &entity.Review{ID: 2, HiddenBy: &userID, HiddenReason: &reason}
+				services.On("HideReview", ctx, expected).Return(test.MockResult)
+			}
+
+			handler.hideReview(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRestoreReview(t *testing.T) {
+	tests := []struct {
+		Name         string
+		RequestURI   string
+		MockResult   error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Restore review successfully",
+			RequestURI:   "2",
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Non-valid id",
+			RequestURI:   "bleh",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Review is not hidden",
+			RequestURI:   "2",
+			MockResult:   service.ErrReviewNotHidden,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/mod/reviews/"+test.RequestURI+"/restore", nil)
+
+			ctx.Params = gin.Params{{Key: "id", Value: test.RequestURI}}
+			ctx.Request = req
+
+			if test.ExpectMock {
+				services.On("RestoreReview", ctx, &entity.Review{ID: 2}).Return(test.MockResult)
+			}
+
+			handler.restoreReview(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,400 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/pkg/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      List built-in and custom roles with their permissions. Requires "roles.manage" permission
+// @Tags         Moderation
+// @Produce      json
+// @Security ApiKeyAuth
+//
+// @Success      200 {object} api.GetRolesResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/roles [get]
+func (h *Handler) getRoles(ctx *gin.Context) {
+	roles, err := h.Services.GetRoles(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetRolesResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    roles,
+	})
+}
+
+// @Summary      Get role with its permissions. Requires "roles.manage" permission
+// @Tags         Moderation
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        name   path      string  true  "Name of role"
+//
+// @Success      200 {object} api.RoleResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/roles/{name} [get]
+func (h *Handler) getRole(ctx *gin.Context) {
+	name, ok := bindRoleName(ctx)
+	if !ok {
+		return
+	}
+
+	role, err := h.Services.GetRole(ctx, name)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "role does not exist",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.RoleResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    role,
+	})
+}
+
+// @Summary      Create custom role from set of permissions the user has. Requires "roles.manage" permission
+// @Tags         Moderation
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param data body api.CreateRoleRequest true "Request body"
+//
+// @Success      201 {object} api.RoleResponse "Role succesfully created"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      403  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/roles [post]
+func (h *Handler) createRole(ctx *gin.Context) {
+	var req api.CreateRoleRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	name, ok := entity.StringToRole(req.Name)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "role name may contain only letters, digits and underscores",
+		})
+		return
+	}
+
+	role := &entity.RoleDefinition{
+		Name:        name,
+		Description: req.Description,
+		Permissions: toPermissions(req.Permissions),
+	}
+
+	actorID := ctx.MustGet("userID").(int64)
+
+	err = h.Services.CreateRole(ctx, actorID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRoleAboveOwn):
+			ctx.JSON(http.StatusForbidden, &api.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrUnknownPermission):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrRoleExists):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, &api.RoleResponse{
+		Code:    http.StatusCreated,
+		Message: "role succesfully created",
+		Body:    role,
+	})
+}
+
+// @Summary      Replace description and permissions of custom role within permissions the user has. Built-in roles can not be changed. Requires "roles.manage" permission
+// @Tags         Moderation
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        name   path      string  true  "Name of role"
+// @Param data body api.UpdateRoleRequest true "Request body"
+//
+// @Success      200 {object} api.RoleResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      403  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/roles/{name} [put]
+func (h *Handler) updateRole(ctx *gin.Context) {
+	var req api.UpdateRoleRequest
+
+	name, ok := bindRoleName(ctx)
+	if !ok {
+		return
+	}
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	role := &entity.RoleDefinition{
+		Name:        name,
+		Description: req.Description,
+		Permissions: toPermissions(req.Permissions),
+	}
+
+	actorID := ctx.MustGet("userID").(int64)
+
+	err = h.Services.UpdateRole(ctx, actorID, role)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRoleAboveOwn):
+			ctx.JSON(http.StatusForbidden, &api.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrUnknownPermission):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrBuiltInRole):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "role does not exist",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.RoleResponse{
+		Code:    http.StatusOK,
+		Message: "role succesfully updated",
+		Body:    role,
+	})
+}
+
+// @Summary      Delete custom role which is not assigned to any user. Requires "roles.manage" permission
+// @Tags         Moderation
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        name   path      string  true  "Name of role"
+//
+// @Success      200 {object} api.DefaultResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      403  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/roles/{name} [delete]
+func (h *Handler) deleteRole(ctx *gin.Context) {
+	name, ok := bindRoleName(ctx)
+	if !ok {
+		return
+	}
+
+	actorID := ctx.MustGet("userID").(int64)
+
+	err := h.Services.DeleteRole(ctx, actorID, name)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRoleAboveOwn):
+			ctx.JSON(http.StatusForbidden, &api.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrBuiltInRole), errors.Is(err, service.ErrRoleInUse):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "role does not exist",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "role succesfully deleted",
+	})
+}
+
+// bindRoleName reads name of the role from path, response is written when
+// the name is invalid
+// @Summary      List role changes of user. Requires "roles.grant" permission
+// @Tags         Moderation
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true  "User ID"
+// @Param        query  query     api.Filter  false  "Pagination, sort by created_at"
+//
+// @Success      200 {object} api.GetRoleHistoryResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/users/{id}/roles/history [get]
+func (h *Handler) getRoleHistory(ctx *gin.Context) {
+	var id api.ID
+	var filter api.Filter
+
+	err := ctx.ShouldBindUri(&id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = ctx.ShouldBindQuery(&filter)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	changes, meta, err := h.Services.GetRoleHistory(ctx, id.Value, util.NewFilter(filter.Page, filter.PageSize, filter.Sort))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSortValue):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "user does not exists",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetRoleHistoryResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    changes,
+		Meta:    *meta,
+	})
+}
+
+func bindRoleName(ctx *gin.Context) (entity.Role, bool) {
+	var req api.RoleName
+
+	err := ctx.ShouldBindUri(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return "", false
+	}
+
+	name, ok := entity.StringToRole(req.Value)
+	if !ok {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "role does not exist",
+		})
+		return "", false
+	}
+
+	return name, true
+}
+
+func toPermissions(permissions []string) []entity.Permission {
+	result := make([]entity.Permission, len(permissions))
+	for i, permission := range permissions {
+		result[i] = entity.Permission(permission)
+	}
+
+	return result
+}
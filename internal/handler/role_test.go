@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/internal/service/mocks"
+	"one-lab-final/pkg/util"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRole(t *testing.T) {
+	var actorID int64 = 1
+	tests := []struct {
+		Name         string
+		RequestJSON  string
+		MockError    error
+		ExpectMock   bool
+		ExpectedRole *entity.RoleDefinition
+		ExpectedCode int
+	}{
+		{
+			Name:        "Role created",
+			RequestJSON: `{"name": "librarian", "permissions": ["books.create", "books.update"]}`,
+			ExpectMock:  true,
+			ExpectedRole: &entity.RoleDefinition{
+				Name:        "LIBRARIAN",
+				Permissions: []entity.Permission{entity.PermissionBooksCreate, entity.PermissionBooksUpdate},
+			},
+			ExpectedCode: http.StatusCreated,
+		},
+		{
+			Name:         "Missing permissions",
+			RequestJSON:  `{"name": "librarian"}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Non-valid name",
+			RequestJSON:  `{"name": "head librarian", "permissions": []}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:        "Unknown permission",
+			RequestJSON: `{"name": "librarian", "permissions": ["books.burn"]}`,
+			MockError:   service.ErrUnknownPermission,
+			ExpectMock:  true,
+			ExpectedRole: &entity.RoleDefinition{
+				Name:        "LIBRARIAN",
+				Permissions: []entity.Permission{"books.burn"},
+			},
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:        "Permission above own",
+			RequestJSON: `{"name": "librarian", "permissions": ["roles.grant"]}`,
+			MockError:   service.ErrRoleAboveOwn,
+			ExpectMock:  true,
+			ExpectedRole: &entity.RoleDefinition{
+				Name:        "LIBRARIAN",
+				Permissions: []entity.Permission{entity.PermissionRolesGrant},
+			},
+			ExpectedCode: http.StatusForbidden,
+		},
+		{
+			Name:        "Role already exists",
+			RequestJSON: `{"name": "moderator", "permissions": []}`,
+			MockError:   service.ErrRoleExists,
+			ExpectMock:  true,
+			ExpectedRole: &entity.RoleDefinition{
+				Name:        entity.MODERATOR,
+				Permissions: []entity.Permission{},
+			},
+			ExpectedCode: http.StatusConflict,
+		},
+		{
+			Name:        "Error while creating",
+			RequestJSON: `{"name": "librarian", "permissions": []}`,
+			MockError:   errors.New("critical error"),
+			ExpectMock:  true,
+			ExpectedRole: &entity.RoleDefinition{
+				Name:        "LIBRARIAN",
+				Permissions: []entity.Permission{},
+			},
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/mod/roles", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+			ctx.Request = req
+			ctx.Set("userID", actorID)
+
+			if test.ExpectMock {
+				services.On("CreateRole", ctx, actorID, test.ExpectedRole).Return(test.MockError)
+			}
+
+			handler.createRole(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteRole(t *testing.T) {
+	var actorID int64 = 1
+	tests := []struct {
+		Name         string
+		RoleName     string
+		MockError    error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Role deleted",
+			RoleName:     "librarian",
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Built-in role",
+			RoleName:     "admin",
+			MockError:    service.ErrBuiltInRole,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusConflict,
+		},
+		{
+			Name:         "Role is assigned to users",
+			RoleName:     "librarian",
+			MockError:    service.ErrRoleInUse,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusConflict,
+		},
+		{
+			Name:         "Role does not exist",
+			RoleName:     "librarian",
+			MockError:    repository.ErrRecordNotFound,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusNotFound,
+		},
+		{
+			Name:         "Role above own",
+			RoleName:     "senior",
+			MockError:    service.ErrRoleAboveOwn,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusForbidden,
+		},
+		{
+			Name:         "Non-valid name",
+			RoleName:     "-",
+			ExpectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("DELETE", "/mod/roles/"+test.RoleName, nil)
+			ctx.Request = req
+			ctx.Params = gin.Params{{Key: "name", Value: test.RoleName}}
+			ctx.Set("userID", actorID)
+
+			if test.ExpectMock {
+				services.On("DeleteRole", ctx, actorID, entity.Role(strings.ToUpper(test.RoleName))).Return(test.MockError)
+			}
+
+			handler.deleteRole(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetRoleHistory(t *testing.T) {
+	var userID int64 = 15
+	tests := []struct {
+		Name         string
+		RequestURI   string
+		Query        string
+		MockResult   []*entity.RoleChange
+		MockError    error
+		ExpectMock   bool
+		ExpectedSort string
+		ExpectedCode int
+	}{
+		{
+			Name:       "History received",
+			RequestURI: "15",
+			MockResult: []*entity.RoleChange{
+				{ID: 1, UserID: userID, OldRole: entity.USER, NewRole: entity.MODERATOR},
+			},
+			ExpectMock:   true,
+			ExpectedSort: "created_at",
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Newest first",
+			RequestURI:   "15",
+			Query:        "?sort=-created_at",
+			MockResult:   []*entity.RoleChange{},
+			ExpectMock:   true,
+			ExpectedSort: "-created_at",
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Malformed id",
+			RequestURI:   "noo",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Invalid sort",
+			RequestURI:   "15",
+			Query:        "?sort=new_role",
+			MockError:    service.ErrInvalidSortValue,
+			ExpectMock:   true,
+			ExpectedSort: "new_role",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "User does not exist",
+			RequestURI:   "15",
+			MockError:    repository.ErrRecordNotFound,
+			ExpectMock:   true,
+			ExpectedSort: "created_at",
+			ExpectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("GET", "/mod/users/"+test.RequestURI+"/roles/history"+test.Query, nil)
+			ctx.Request = req
+			ctx.Params = gin.Params{{Key: "id", Value: test.RequestURI}}
+
+			if test.ExpectMock {
+				var meta *util.Metadata
+				if test.MockError == nil {
+					meta = &util.Metadata{}
+				}
+
+				filter := util.NewFilter(1, 50, test.ExpectedSort)
+				services.On("GetRoleHistory", ctx, userID, filter).Return(test.MockResult, meta, test.MockError)
+			}
+
+			handler.getRoleHistory(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
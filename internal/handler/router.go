@@ -3,6 +3,8 @@ package handler
 import (
 	_ "one-lab-final/docs"
 	"one-lab-final/internal/entity"
+	"path"
+	"path/filepath"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"     // swagger embed files
@@ -10,17 +12,31 @@ import (
 	ginprometheus "github.com/zsais/go-gin-prometheus"
 )
 
-func (h *Handler) InitRouter() *gin.Engine {
+func (h *Handler) InitRouter() (*gin.Engine, error) {
 	router := gin.Default()
 
+	// Client IP limits failed logins, so X-Forwarded-For is not trusted
+	// unless it is set by a known proxy
+	err := router.SetTrustedProxies(h.Config.HTTP.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	p := ginprometheus.NewPrometheus("gin")
 	p.Use(router)
 
+	// Public blobs of "local" driver are served by the API itself, data exports
+	// are downloaded only with signed URL
+	if h.Config.BLOB.Driver == "local" || h.Config.BLOB.Driver == "" {
+		router.Static(path.Join(h.Config.BLOB.BaseURL, "avatars"), filepath.Join(h.Config.BLOB.Directory, "avatars"))
+	}
+
 	v1 := router.Group("/api/v1")
 
 	v1.GET("/healthcheck", h.healthcheck)
+	v1.GET("/feed", h.requireAuthenticatedUser(""), h.getFeed)
 
 	userV1 := v1.Group("/users")
 	bookV1 := v1.Group("/books")
@@ -28,28 +44,85 @@ func (h *Handler) InitRouter() *gin.Engine {
 	modV1 := v1.Group("/mod")
 
 	userV1.GET("/:username", h.getUserByUsername)
+	userV1.GET("/:username/stats", h.getUserStats)
+	userV1.GET("/:username/followers", h.getFollowers)
+	userV1.GET("/:username/following", h.getFollowing)
+	userV1.POST("/:username/follow", h.requireAuthenticatedUser(""), h.followUser)
+	userV1.DELETE("/:username/follow", h.requireAuthenticatedUser(""), h.unfollowUser)
 	userV1.GET("/suspensions/:id", h.checkSuspension)
 	userV1.POST("/register", h.createUser)
+	userV1.PUT("/activate", h.activateUser)
+	userV1.POST("/activate/resend", h.requireAuthenticatedUser(""), h.resendActivation)
 	userV1.POST("/login", h.login)
-	userV1.PATCH("/update", h.requireAuthenticatedUser(), h.updateUser)
-	userV1.DELETE("/delete", h.requireAuthenticatedUser(), h.deleteUser)
+	userV1.POST("/login/2fa", h.loginTwoFactor)
+	userV1.GET("/oidc/:provider/login", h.oidcLogin)
+	userV1.GET("/oidc/:provider/callback", h.oidcCallback)
+	userV1.POST("/token/refresh", h.refreshToken)
+	userV1.POST("/password-reset", h.requestPasswordReset)
+	userV1.PUT("/password", h.resetPassword)
+	userV1.POST("/logout", h.requireAuthenticatedUser(""), h.logout)
+	userV1.GET("/sessions", h.requireAuthenticatedUser(""), h.getSessions)
+	userV1.DELETE("/sessions", h.requireAuthenticatedUser(""), h.deleteAllSessions)
+	userV1.DELETE("/sessions/:id", h.requireAuthenticatedUser(""), h.deleteSession)
+	userV1.POST("/2fa/enroll", h.requireAuthenticatedUser(""), h.enrollTwoFactor)
+	userV1.POST("/2fa/confirm", h.requireAuthenticatedUser(""), h.confirmTwoFactor)
+	userV1.DELETE("/2fa", h.requireAuthenticatedUser(""), h.disableTwoFactor)
+	userV1.POST("/api-keys", h.requireAuthenticatedUser(""), h.createAPIKey)
+	userV1.GET("/api-keys", h.requireAuthenticatedUser(""), h.getAPIKeys)
+	userV1.DELETE("/api-keys/:id", h.requireAuthenticatedUser(""), h.deleteAPIKey)
+	userV1.POST("/email", h.requireAuthenticatedUser(""), h.requestEmailChange)
+	userV1.PUT("/email/confirm", h.confirmEmailChange)
+	userV1.PUT("/email/cancel", h.cancelEmailChange)
+	userV1.PUT("/username", h.requireAuthenticatedUser(""), h.changeUsername)
+	userV1.PUT("/avatar", h.requireAuthenticatedUser(""), h.updateAvatar)
+	userV1.DELETE("/avatar", h.requireAuthenticatedUser(""), h.deleteAvatar)
+	userV1.POST("/export", h.requireAuthenticatedUser(""), h.requestDataExport)
+	userV1.GET("/export/:id", h.requireAuthenticatedUser(""), h.getDataExport)
+	userV1.GET("/export/:id/download", h.downloadDataExport)
+	userV1.PATCH("/update", h.requireAuthenticatedUser(""), h.updateUser)
+	userV1.DELETE("/delete", h.requireAuthenticatedUser(""), h.deleteUser)
 
 	bookV1.GET("", h.getBooks)
 	bookV1.GET("/:id", h.getBookByID)
 	bookV1.GET("/:id/reviews", h.getReviewsByBookID)
 
-	bookV1.POST("/new", h.requireRole(entity.MODERATOR), h.createBook)
-	bookV1.DELETE("/delete/:id", h.requireRole(entity.MODERATOR), h.deleteBook)
-	bookV1.PATCH("/update/:id", h.requireRole(entity.MODERATOR), h.updateBook)
+	bookV1.POST("/new", h.requirePermission(entity.PermissionBooksCreate, entity.APIScopeBooksWrite), h.createBook)
+	bookV1.DELETE("/delete/:id", h.requirePermission(entity.PermissionBooksDelete, entity.APIScopeBooksWrite), h.deleteBook)
+	bookV1.PATCH("/update/:id", h.requirePermission(entity.PermissionBooksUpdate, entity.APIScopeBooksWrite), h.updateBook)
+
+	reviewV1.GET("/mine", h.requireAuthenticatedUser(""), h.getOwnReviews)
+	reviewV1.POST("/new", h.requireActivatedUser(entity.APIScopeReviewsWrite), h.createReview)
+	reviewV1.PATCH("/update/:id", h.requireAuthenticatedUser(entity.APIScopeReviewsWrite), h.updateReview)
+	reviewV1.DELETE("/delete/:id", h.requireAuthenticatedUser(entity.APIScopeReviewsWrite), h.deleteReview)
+	reviewV1.POST("/:id/report", h.requireActivatedUser(entity.APIScopeReviewsWrite), h.reportReview)
+
+	modV1.POST("/suspensions/new", h.requirePermission(entity.PermissionUsersSuspend, entity.APIScopeModSuspend), h.suspendUser)
+	modV1.PATCH("/suspensions/update/:id", h.requirePermission(entity.PermissionUsersSuspend, entity.APIScopeModSuspend), h.updateSuspension)
+	modV1.GET("/suspensions", h.requirePermission(entity.PermissionUsersSuspend, entity.APIScopeModSuspend), h.getSuspensions)
+	modV1.GET("/suspensions/:id", h.requirePermission(entity.PermissionUsersSuspend, entity.APIScopeModSuspend), h.getSuspension)
+	modV1.POST("/suspensions/:id/lift", h.requirePermission(entity.PermissionUsersSuspend, entity.APIScopeModSuspend), h.liftSuspension)
+
+	modV1.GET("/reports", h.requirePermission(entity.PermissionReviewsModerate, entity.APIScopeModReviews), h.getReportedReviews)
+	modV1.GET("/reports/:id", h.requirePermission(entity.PermissionReviewsModerate, entity.APIScopeModReviews), h.getReviewReports)
+	modV1.POST("/reports/:id/resolve", h.requirePermission(entity.PermissionReviewsModerate, entity.APIScopeModReviews), h.resolveReports)
+	modV1.POST("/reviews/:id/hide", h.requirePermission(entity.PermissionReviewsModerate, entity.APIScopeModReviews), h.hideReview)
+	modV1.POST("/reviews/:id/restore", h.requirePermission(entity.PermissionReviewsModerate, entity.APIScopeModReviews), h.restoreReview)
+	modV1.GET("/reviews", h.requirePermission(entity.PermissionReviewsModerate, entity.APIScopeModReviews), h.getModerationReviews)
 
-	reviewV1.POST("/new", h.requireAuthenticatedUser(), h.createReview)
-	reviewV1.PATCH("/update/:id", h.requireAuthenticatedUser(), h.updateReview)
-	reviewV1.DELETE("/delete/:id", h.requireAuthenticatedUser(), h.deleteReview)
+	modV1.GET("/content-rules", h.requirePermission(entity.PermissionReviewsModerate, entity.APIScopeModReviews), h.getContentRules)
+	modV1.POST("/content-rules", h.requirePermission(entity.PermissionReviewsModerate, entity.APIScopeModReviews), h.createContentRule)
+	modV1.PATCH("/content-rules/:id", h.requirePermission(entity.PermissionReviewsModerate, entity.APIScopeModReviews), h.updateContentRule)
+	modV1.DELETE("/content-rules/:id", h.requirePermission(entity.PermissionReviewsModerate, entity.APIScopeModReviews), h.deleteContentRule)
 
-	modV1.POST("/suspensions/new", h.requireRole(entity.MODERATOR), h.suspendUser)
-	modV1.PATCH("/suspensions/update/:id", h.requireRole(entity.MODERATOR), h.updateSuspension)
+	modV1.GET("/users", h.requirePermission(entity.PermissionUsersView, entity.APIScopeModRoles), h.getUsers)
+	modV1.GET("/users/:id/roles/history", h.requirePermission(entity.PermissionRolesGrant, entity.APIScopeModRoles), h.getRoleHistory)
+	modV1.PATCH("/roles/:id", h.requirePermission(entity.PermissionRolesGrant, entity.APIScopeModRoles), h.grantRoleToUser)
 
-	modV1.PATCH("/roles/:id", h.requireRole(entity.ADMIN), h.grantRoleToUser)
+	modV1.GET("/roles", h.requirePermission(entity.PermissionRolesManage, entity.APIScopeModRoles), h.getRoles)
+	modV1.GET("/roles/:name", h.requirePermission(entity.PermissionRolesManage, entity.APIScopeModRoles), h.getRole)
+	modV1.POST("/roles", h.requirePermission(entity.PermissionRolesManage, entity.APIScopeModRoles), h.createRole)
+	modV1.PUT("/roles/:name", h.requirePermission(entity.PermissionRolesManage, entity.APIScopeModRoles), h.updateRole)
+	modV1.DELETE("/roles/:name", h.requirePermission(entity.PermissionRolesManage, entity.APIScopeModRoles), h.deleteRole)
 
-	return router
+	return router, nil
 }
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Exchange refresh token for a new pair of tokens
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param data body api.RefreshTokenRequest true "Request body"
+//
+// @Success      201 {object} api.LoginResponse "Token succesfully refreshed"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      401  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/token/refresh [post]
+func (h *Handler) refreshToken(ctx *gin.Context) {
+	var req api.RefreshTokenRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	token, err := h.Services.RefreshToken(ctx, req.RefreshToken, entity.Client{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusUnauthorized, &api.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: "invalid or expired refresh token",
+			})
+			return
+		case errors.Is(err, service.ErrRefreshTokenReused):
+			ctx.JSON(http.StatusUnauthorized, &api.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, &api.LoginResponse{
+		Code:    http.StatusCreated,
+		Message: "token succesfully refreshed",
+		Body:    token,
+	})
+}
+
+// @Summary      Revoke token used for this request
+// @Tags         Authentication
+// @Produce      json
+// @Security ApiKeyAuth
+//
+// @Success      200 {object} api.DefaultResponse "Logged out successfully"
+// @Failure      401  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/logout [post]
+func (h *Handler) logout(ctx *gin.Context) {
+	err := h.Services.Logout(ctx, ctx.GetString("token"))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusUnauthorized, &api.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: "invalid token",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "logged out successfully",
+	})
+}
+
+// @Summary      Get active sessions of current user
+// @Tags         Authentication
+// @Produce      json
+// @Security ApiKeyAuth
+//
+// @Success      200 {object} api.GetSessionsResponse "ok"
+// @Failure      401  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/sessions [get]
+func (h *Handler) getSessions(ctx *gin.Context) {
+	userID := ctx.MustGet("userID").(int64)
+
+	sessions, err := h.Services.GetSessions(ctx, userID, ctx.GetString("token"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetSessionsResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    sessions,
+	})
+}
+
+// @Summary      Revoke session of current user by ID
+// @Tags         Authentication
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true  "Session ID"
+//
+// @Success      200 {object} api.DefaultResponse "Session was successfully revoked"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/sessions/{id} [delete]
+func (h *Handler) deleteSession(ctx *gin.Context) {
+	var id api.ID
+
+	err := ctx.ShouldBindUri(&id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.MustGet("userID").(int64)
+
+	err = h.Services.DeleteSession(ctx, id.Value, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "session does not exists",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "session was successfully revoked",
+	})
+}
+
+// @Summary      Revoke all sessions of current user, including this one
+// @Tags         Authentication
+// @Produce      json
+// @Security ApiKeyAuth
+//
+// @Success      200 {object} api.DefaultResponse "Logged out everywhere"
+// @Failure      401  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/sessions [delete]
+func (h *Handler) deleteAllSessions(ctx *gin.Context) {
+	userID := ctx.MustGet("userID").(int64)
+
+	err := h.Services.DeleteAllSessions(ctx, userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "logged out everywhere",
+	})
+}
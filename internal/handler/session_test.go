@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/internal/service/mocks"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogout(t *testing.T) {
+	tests := []struct {
+		Name         string
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Logged out successfully",
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Token already revoked",
+			MockError:    repository.ErrRecordNotFound,
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name:         "Error while deleting token",
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			service := &mocks.Service{}
+			handler := New(service, nil)
+
+			req, _ := http.NewRequest("POST", "/users/logout", strings.NewReader(""))
+			ctx.Request = req
+			ctx.Set("token", "token")
+
+			service.On("Logout", ctx, "token").Return(test.MockError)
+			handler.logout(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestGetSessions(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		MockResult   any
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Get sessions successfully",
+			MockResult:   []*entity.Session{{ID: 1, Current: true}},
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Error while retrieving sessions",
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			service := &mocks.Service{}
+			handler := New(service, nil)
+
+			req, _ := http.NewRequest("GET", "/users/sessions", strings.NewReader(""))
+			ctx.Request = req
+			ctx.Set("userID", userID)
+			ctx.Set("token", "token")
+
+			service.On("GetSessions", ctx, userID, "token").Return(test.MockResult, test.MockError)
+			handler.getSessions(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestDeleteSession(t *testing.T) {
+	var userID int64 = 123
+	var sessionID int64 = 5
+	tests := []struct {
+		Name         string
+		RequestURI   string
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Session revoked successfully",
+			RequestURI:   fmt.Sprintf("%d", sessionID),
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Non-valid id",
+			RequestURI:   "abc",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Session of another user",
+			RequestURI:   fmt.Sprintf("%d", sessionID),
+			MockError:    repository.ErrRecordNotFound,
+			ExpectedCode: http.StatusNotFound,
+		},
+		{
+			Name:         "Error while deleting session",
+			RequestURI:   fmt.Sprintf("%d", sessionID),
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			service := &mocks.Service{}
+			handler := New(service, nil)
+
+			req, _ := http.NewRequest("DELETE", "/users/sessions/"+test.RequestURI, strings.NewReader(""))
+			ctx.Request = req
+			ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: test.RequestURI})
+			ctx.Set("userID", userID)
+
+			service.On("DeleteSession", ctx, sessionID, userID).Return(test.MockError)
+			handler.deleteSession(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestDeleteAllSessions(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Logged out everywhere",
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Error while deleting sessions",
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			service := &mocks.Service{}
+			handler := New(service, nil)
+
+			req, _ := http.NewRequest("DELETE", "/users/sessions", strings.NewReader(""))
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			service.On("DeleteAllSessions", ctx, userID).Return(test.MockError)
+			handler.deleteAllSessions(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	tests := []struct {
+		Name         string
+		RequestJSON  string
+		MockResult   any
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Token refreshed successfully",
+			RequestJSON:  `{"refresh_token": "refresh"}`,
+			MockResult:   &entity.Token{},
+			ExpectedCode: http.StatusCreated,
+		},
+		{
+			Name:         "Missing refresh token",
+			RequestJSON:  `{}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Unknown refresh token",
+			RequestJSON:  `{"refresh_token": "refresh"}`,
+			MockError:    repository.ErrRecordNotFound,
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name:         "Reused refresh token",
+			RequestJSON:  `{"refresh_token": "refresh"}`,
+			MockError:    service.ErrRefreshTokenReused,
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name:         "Error while rotating token",
+			RequestJSON:  `{"refresh_token": "refresh"}`,
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/users/token/refresh", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+			ctx.Request = req
+
+			services.On("RefreshToken", ctx, "refresh", entity.Client{}).Return(test.MockResult, test.MockError)
+			handler.refreshToken(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
@@ -1,24 +1,29 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"one-lab-final/internal/entity"
 	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/pkg/util"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// @Summary      Suspend user for some time. Requires MODERATOR role or higher
+// @Summary      Suspend user for some time. Requires "users.suspend" permission
 // @Tags         Moderation
 // @Accept       json
 // @Produce      json
 // @Security ApiKeyAuth
 // @Param data body api.CreateSuspensionRequest true "Request body"
 //
-// @Success      200 {object} api.DefaultResponse "User was successfully suspended"
+// @Success      201 {object} api.SuspensionResponse "User was successfully suspended"
 // @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
 // @Failure      500  {object}  api.ErrorResponse
 // @Router       /mod/suspensions/new [post]
 func (h *Handler) suspendUser(ctx *gin.Context) {
@@ -44,18 +49,28 @@ func (h *Handler) suspendUser(ctx *gin.Context) {
 
 	err = h.Services.NewSuspension(ctx, suspension)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		})
-		return
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "user does not exists",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
 	}
 
-	ctx.Header("Locations", fmt.Sprintf("/mod/suspensions/%d", suspension.ID))
+	ctx.Header("Location", fmt.Sprintf("/mod/suspensions/%d", suspension.ID))
 
-	ctx.JSON(http.StatusCreated, &api.DefaultResponse{
+	ctx.JSON(http.StatusCreated, &api.SuspensionResponse{
 		Code:    http.StatusCreated,
 		Message: "user was successfully suspended",
+		Body:    suspension,
 	})
 }
 
@@ -97,7 +112,116 @@ func (h *Handler) checkSuspension(ctx *gin.Context) {
 	})
 }
 
-// @Summary      Modify suspension of user. Requires MODERATOR role or higher
+// @Summary      List suspensions, expired and lifted included. Requires "users.suspend" permission
+// @Tags         Moderation
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        query  query     api.GetSuspensionsRequest  false  "Filters, pagination, sort by id, created_at or expires_at"
+//
+// @Success      200 {object} api.GetSuspensionsResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/suspensions [get]
+func (h *Handler) getSuspensions(ctx *gin.Context) {
+	var req api.GetSuspensionsRequest
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	search := entity.SuspensionSearch{
+		Active:       req.Active,
+		ModeratorID:  req.ModeratorID,
+		UserID:       req.UserID,
+		CreatedAfter: req.From,
+	}
+
+	// The last day of the range is included
+	if req.To != nil {
+		before := req.To.Add(24 * time.Hour)
+		search.CreatedBefore = &before
+	}
+
+	suspensions, meta, err := h.Services.GetSuspensions(ctx, search, util.NewFilter(req.Page, req.PageSize, req.Sort))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSortValue):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetSuspensionsResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    suspensions,
+		Meta:    *meta,
+	})
+}
+
+// @Summary      Get suspension. Requires "users.suspend" permission
+// @Tags         Moderation
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true  "Suspension ID"
+//
+// @Success      200 {object} api.SuspensionResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/suspensions/{id} [get]
+func (h *Handler) getSuspension(ctx *gin.Context) {
+	var req api.ID
+
+	err := ctx.ShouldBindUri(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	suspension, err := h.Services.GetSuspension(ctx, req.Value)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "suspension does not exists",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.SuspensionResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    suspension,
+	})
+}
+
+// @Summary      Change reason or duration of active suspension. Requires "users.suspend" permission
 // @Tags         Moderation
 // @Accept       json
 // @Produce      json
@@ -105,8 +229,10 @@ func (h *Handler) checkSuspension(ctx *gin.Context) {
 // @Param        id   path      int  true  "Suspension ID"
 // @Param data body api.UpdateSuspensionRequest true "Request body"
 //
-// @Success      200 {object} api.DefaultResponse "suspension was successfully updated"
+// @Success      200 {object} api.SuspensionResponse "suspension was successfully updated"
 // @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
 // @Failure      500  {object}  api.ErrorResponse
 // @Router       /mod/suspensions/update/{id} [patch]
 func (h *Handler) updateSuspension(ctx *gin.Context) {
@@ -130,25 +256,135 @@ func (h *Handler) updateSuspension(ctx *gin.Context) {
 		return
 	}
 
+	if req.Reason == nil && req.ExpiresIn == nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "reason or expires_in must be set",
+		})
+		return
+	}
+
 	userID := ctx.MustGet("userID").(int64)
-	ExpiresIn := time.Minute * time.Duration(req.ExpiresIn)
 
-	err = h.Services.UpdateSuspension(ctx, &entity.Suspension{
-		ID:          req.ID.Value,
-		Reason:      &req.Reason,
-		ModeratorID: userID,
-		ExpiresIn:   &ExpiresIn,
+	suspension := &entity.Suspension{
+		ID:        req.ID.Value,
+		Reason:    req.Reason,
+		UpdatedBy: &userID,
+	}
+
+	if req.ExpiresIn != nil {
+		expiresIn := time.Minute * time.Duration(*req.ExpiresIn)
+		suspension.ExpiresIn = &expiresIn
+	}
+
+	err = h.Services.UpdateSuspension(ctx, suspension)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSuspensionTooShort):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "suspension does not exists",
+			})
+			return
+		case errors.Is(err, service.ErrSuspensionNotActive):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.SuspensionResponse{
+		Code:    http.StatusOK,
+		Message: "suspension was successfully updated",
+		Body:    suspension,
 	})
+}
+
+// @Summary      End active suspension before it expires. Requires "users.suspend" permission
+// @Tags         Moderation
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true  "Suspension ID"
+// @Param data body api.LiftSuspensionRequest true "Request body"
+//
+// @Success      200 {object} api.SuspensionResponse "suspension was successfully lifted"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/suspensions/{id}/lift [post]
+func (h *Handler) liftSuspension(ctx *gin.Context) {
+	var req api.LiftSuspensionRequest
+	var id api.ID
+
+	err := ctx.ShouldBindUri(&id)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
-			Code:    http.StatusInternalServerError,
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
 			Message: err.Error(),
 		})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.MustGet("userID").(int64)
+
+	suspension := &entity.Suspension{
+		ID:         id.Value,
+		LiftedBy:   &userID,
+		LiftReason: &req.Reason,
+	}
+
+	err = h.Services.LiftSuspension(ctx, suspension)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "suspension does not exists",
+			})
+			return
+		case errors.Is(err, service.ErrSuspensionNotActive):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.SuspensionResponse{
 		Code:    http.StatusOK,
-		Message: "suspension was successfully updated",
+		Message: "suspension was successfully lifted",
+		Body:    suspension,
 	})
 }
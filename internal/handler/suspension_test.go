@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
 	"one-lab-final/internal/service/mocks"
 	"one-lab-final/pkg/util"
 	"strings"
@@ -14,6 +16,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestSuspendUser(t *testing.T) {
@@ -50,6 +53,22 @@ func TestSuspendUser(t *testing.T) {
 			}`,
 			ExpectedCode: http.StatusBadRequest,
 		},
+		{
+			Name: "User does not exist",
+			RequestJSON: `{
+				"reason": "Bad behaviour",
+				"expires_in": 100,
+				"user_id": 2
+			}`,
+			MockResult: repository.ErrRecordNotFound,
+			ExpectedSuspension: &entity.Suspension{
+				UserID:      2,
+				Reason:      util.StringToPointer("Bad behaviour"),
+				ModeratorID: userID,
+				ExpiresIn:   &expiresIn,
+			},
+			ExpectedCode: http.StatusNotFound,
+		},
 		{
 			Name: "Error while saving record",
 			RequestJSON: `{
@@ -176,10 +195,10 @@ func TestUpdateSuspension(t *testing.T) {
 			}`,
 			MockResult: nil,
 			ExpectedSuspension: &entity.Suspension{
-				ID:          2,
-				Reason:      util.StringToPointer("Bad behaviour"),
-				ModeratorID: userID,
-				ExpiresIn:   &expiresIn,
+				ID:        2,
+				Reason:    util.StringToPointer("Bad behaviour"),
+				UpdatedBy: &userID,
+				ExpiresIn: &expiresIn,
 			},
 			ExpectedCode: http.StatusOK,
 		},
@@ -196,6 +215,59 @@ func TestUpdateSuspension(t *testing.T) {
 			}`,
 			ExpectedCode: http.StatusBadRequest,
 		},
+		{
+			Name:        "Extend suspension only",
+			RequestURI:  "2",
+			RequestJSON: `{"expires_in": 100}`,
+			ExpectedSuspension: &entity.Suspension{
+				ID:        2,
+				UpdatedBy: &userID,
+				ExpiresIn: &expiresIn,
+			},
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Nothing to update",
+			RequestURI:   "2",
+			RequestJSON:  `{}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:        "Suspension is not active",
+			RequestURI:  "2",
+			RequestJSON: `{"expires_in": 100}`,
+			MockResult:  service.ErrSuspensionNotActive,
+			ExpectedSuspension: &entity.Suspension{
+				ID:        2,
+				UpdatedBy: &userID,
+				ExpiresIn: &expiresIn,
+			},
+			ExpectedCode: http.StatusConflict,
+		},
+		{
+			Name:        "Suspension would end in the past",
+			RequestURI:  "2",
+			RequestJSON: `{"expires_in": 100}`,
+			MockResult:  service.ErrSuspensionTooShort,
+			ExpectedSuspension: &entity.Suspension{
+				ID:        2,
+				UpdatedBy: &userID,
+				ExpiresIn: &expiresIn,
+			},
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:        "Suspension does not exist",
+			RequestURI:  "2",
+			RequestJSON: `{"expires_in": 100}`,
+			MockResult:  repository.ErrRecordNotFound,
+			ExpectedSuspension: &entity.Suspension{
+				ID:        2,
+				UpdatedBy: &userID,
+				ExpiresIn: &expiresIn,
+			},
+			ExpectedCode: http.StatusNotFound,
+		},
 		{
 			Name:       "Error while saving record",
 			RequestURI: "2",
@@ -205,10 +277,10 @@ func TestUpdateSuspension(t *testing.T) {
 			}`,
 			MockResult: errors.New("critical error"),
 			ExpectedSuspension: &entity.Suspension{
-				ID:          2,
-				Reason:      util.StringToPointer("Bad behaviour"),
-				ModeratorID: userID,
-				ExpiresIn:   &expiresIn,
+				ID:        2,
+				Reason:    util.StringToPointer("Bad behaviour"),
+				UpdatedBy: &userID,
+				ExpiresIn: &expiresIn,
 			},
 			ExpectedCode: http.StatusInternalServerError,
 		},
@@ -221,10 +293,10 @@ func TestUpdateSuspension(t *testing.T) {
 			w := httptest.NewRecorder()
 			ctx, _ := gin.CreateTestContext(w)
 
-			service := &mocks.Service{}
-			handler := New(service, nil)
+			services := &mocks.Service{}
+			handler := New(services, nil)
 
-			req, _ := http.NewRequest("POST", "/mod/suspensions/new", strings.NewReader(test.RequestJSON))
+			req, _ := http.NewRequest("PATCH", "/mod/suspensions/update/"+test.RequestURI, strings.NewReader(test.RequestJSON))
 			req.Header.Set("Content-Type", "application/json")
 
 			param := gin.Param{Key: "id", Value: test.RequestURI}
@@ -232,9 +304,177 @@ func TestUpdateSuspension(t *testing.T) {
 			ctx.Request = req
 			ctx.Set("userID", userID)
 
-			service.On("UpdateSuspension", ctx, test.ExpectedSuspension).Return(test.MockResult)
+			services.On("UpdateSuspension", ctx, test.ExpectedSuspension).Return(test.MockResult)
 			handler.updateSuspension(ctx)
 			assert.Equal(t, test.ExpectedCode, w.Code)
 		})
 	}
 }
+
+func TestLiftSuspension(t *testing.T) {
+	var userID int64 = 123
+	reason := "Appeal accepted"
+	tests := []struct {
+		Name         string
+		RequestURI   string
+		RequestJSON  string
+		MockResult   error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Lift suspension successfully",
+			RequestURI:   "2",
+			RequestJSON:  `{"reason": "Appeal accepted"}`,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Missing reason",
+			RequestURI:   "2",
+			RequestJSON:  `{}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Non-valid id",
+			RequestURI:   "bleh",
+			RequestJSON:  `{"reason": "Appeal accepted"}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Suspension is not active",
+			RequestURI:   "2",
+			RequestJSON:  `{"reason": "Appeal accepted"}`,
+			MockResult:   service.ErrSuspensionNotActive,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusConflict,
+		},
+		{
+			Name:         "Suspension does not exist",
+			RequestURI:   "2",
+			RequestJSON:  `{"reason": "Appeal accepted"}`,
+			MockResult:   repository.ErrRecordNotFound,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/mod/suspensions/"+test.RequestURI+"/lift", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+
+			ctx.Params = gin.Params{{Key: "id", Value: test.RequestURI}}
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			if test.ExpectMock {
+				expected := &entity.Suspension{ID: 2, LiftedBy: &userID, LiftReason: &reason}
+				services.On("LiftSuspension", ctx, expected).Return(test.MockResult)
+			}
+
+			handler.liftSuspension(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetSuspensions(t *testing.T) {
+	var moderatorID int64 = 1
+	active := true
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name           string
+		Query          string
+		MockError      error
+		ExpectMock     bool
+		ExpectedSearch entity.SuspensionSearch
+		ExpectedSort   string
+		ExpectedCode   int
+	}{
+		{
+			Name:         "Without filters",
+			ExpectMock:   true,
+			ExpectedSort: "created_at",
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:       "With filters",
+			Query:      "?active=true&moderator_id=1&from=2023-01-01&to=2023-12-31&sort=-expires_at",
+			ExpectMock: true,
+			ExpectedSearch: entity.SuspensionSearch{
+				Active:        &active,
+				ModeratorID:   &moderatorID,
+				CreatedAfter:  &from,
+				CreatedBefore: &before,
+			},
+			ExpectedSort: "-expires_at",
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Non-valid date",
+			Query:        "?from=01.01.2023",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Non-valid user",
+			Query:        "?user_id=0",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Invalid sort",
+			Query:        "?sort=reason",
+			MockError:    service.ErrInvalidSortValue,
+			ExpectMock:   true,
+			ExpectedSort: "reason",
+			ExpectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("GET", "/mod/suspensions"+test.Query, nil)
+			ctx.Request = req
+
+			if test.ExpectMock {
+				var meta *util.Metadata
+				var suspensions []*entity.Suspension
+				if test.MockError == nil {
+					meta = &util.Metadata{}
+					suspensions = []*entity.Suspension{}
+				}
+
+				services.On("GetSuspensions", ctx, mock.MatchedBy(func(search entity.SuspensionSearch) bool {
+					return assert.ObjectsAreEqual(test.ExpectedSearch.Active, search.Active) &&
+						assert.ObjectsAreEqual(test.ExpectedSearch.ModeratorID, search.ModeratorID) &&
+						assert.ObjectsAreEqual(test.ExpectedSearch.UserID, search.UserID) &&
+						equalTime(test.ExpectedSearch.CreatedAfter, search.CreatedAfter) &&
+						equalTime(test.ExpectedSearch.CreatedBefore, search.CreatedBefore)
+				}), util.NewFilter(1, 50, test.ExpectedSort)).Return(suspensions, meta, test.MockError)
+			}
+
+			handler.getSuspensions(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Exchange partial token returned by login for access token
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param data body api.LoginTwoFactorRequest true "Request body"
+//
+// @Success      201 {object} api.LoginResponse "Token succesfully created"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      401  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/login/2fa [post]
+func (h *Handler) loginTwoFactor(ctx *gin.Context) {
+	var req api.LoginTwoFactorRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	token, err := h.Services.LoginTwoFactor(ctx, req.Token, req.Code, entity.Client{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusUnauthorized, &api.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: "invalid or expired token",
+			})
+			return
+		case errors.Is(err, service.ErrInvalidTwoFactorCode):
+			ctx.JSON(http.StatusUnauthorized, &api.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: "two-factor code is invalid, log in again",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, &api.LoginResponse{
+		Code:    http.StatusCreated,
+		Message: "token succesfully created",
+		Body:    token,
+	})
+}
+
+// @Summary      Generate TOTP secret. 2FA is enabled after confirmation with the first code
+// @Tags         Authentication
+// @Produce      json
+// @Security ApiKeyAuth
+//
+// @Success      201 {object} api.EnrollTwoFactorResponse "Secret succesfully generated"
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/2fa/enroll [post]
+func (h *Handler) enrollTwoFactor(ctx *gin.Context) {
+	userID := ctx.MustGet("userID").(int64)
+
+	twoFactor, err := h.Services.EnrollTwoFactor(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTwoFactorAlreadyEnabled):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, &api.EnrollTwoFactorResponse{
+		Code:    http.StatusCreated,
+		Message: "secret succesfully generated, confirm it with code from authenticator",
+		Body:    twoFactor,
+	})
+}
+
+// @Summary      Enable 2FA with the first code. Returns recovery codes, they are shown only once
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param data body api.TwoFactorCodeRequest true "Request body"
+//
+// @Success      200 {object} api.ConfirmTwoFactorResponse "2FA succesfully enabled"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      401  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/2fa/confirm [post]
+func (h *Handler) confirmTwoFactor(ctx *gin.Context) {
+	var req api.TwoFactorCodeRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.MustGet("userID").(int64)
+
+	codes, err := h.Services.ConfirmTwoFactor(ctx, userID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTwoFactorCode):
+			ctx.JSON(http.StatusUnauthorized, &api.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrTwoFactorAlreadyEnabled) || errors.Is(err, service.ErrTwoFactorNotEnrolled):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.ConfirmTwoFactorResponse{
+		Code:    http.StatusOK,
+		Message: "two-factor authentication succesfully enabled, store recovery codes in a safe place",
+		Body:    codes,
+	})
+}
+
+// @Summary      Disable 2FA. Not allowed if 2FA is required for role of the user
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param data body api.TwoFactorCodeRequest true "Request body"
+//
+// @Success      200 {object} api.DefaultResponse "2FA succesfully disabled"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      401  {object}  api.ErrorResponse
+// @Failure      403  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/2fa [delete]
+func (h *Handler) disableTwoFactor(ctx *gin.Context) {
+	var req api.TwoFactorCodeRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	userID := ctx.MustGet("userID").(int64)
+
+	err = h.Services.DisableTwoFactor(ctx, userID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTwoFactorCode):
+			ctx.JSON(http.StatusUnauthorized, &api.ErrorResponse{
+				Code:    http.StatusUnauthorized,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrTwoFactorRequired):
+			ctx.JSON(http.StatusForbidden, &api.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrTwoFactorNotEnabled):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "two-factor authentication succesfully disabled",
+	})
+}
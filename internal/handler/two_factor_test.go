@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+	"one-lab-final/internal/service/mocks"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoginTwoFactor(t *testing.T) {
+	tests := []struct {
+		Name         string
+		RequestJSON  string
+		MockResult   *entity.Token
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Token created successfully",
+			RequestJSON:  `{"token": "token", "code": "123456"}`,
+			MockResult:   &entity.Token{},
+			ExpectedCode: http.StatusCreated,
+		},
+		{
+			Name:         "Missing code",
+			RequestJSON:  `{"token": "token"}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Invalid code",
+			RequestJSON:  `{"token": "token", "code": "123456"}`,
+			MockError:    service.ErrInvalidTwoFactorCode,
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name:         "Invalid token",
+			RequestJSON:  `{"token": "token", "code": "123456"}`,
+			MockError:    repository.ErrRecordNotFound,
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name:         "Error while creating token",
+			RequestJSON:  `{"token": "token", "code": "123456"}`,
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/users/login/2fa", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+			ctx.Request = req
+
+			services.On("LoginTwoFactor", ctx, "token", "123456", mock.AnythingOfType("entity.Client")).Return(test.MockResult, test.MockError)
+			handler.loginTwoFactor(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestEnrollTwoFactor(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		MockResult   *entity.TwoFactor
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Secret generated successfully",
+			MockResult:   &entity.TwoFactor{Secret: "secret"},
+			ExpectedCode: http.StatusCreated,
+		},
+		{
+			Name:         "Already enabled",
+			MockError:    service.ErrTwoFactorAlreadyEnabled,
+			ExpectedCode: http.StatusConflict,
+		},
+		{
+			Name:         "Error while generating secret",
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/users/2fa/enroll", strings.NewReader(""))
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			services.On("EnrollTwoFactor", ctx, userID).Return(test.MockResult, test.MockError)
+			handler.enrollTwoFactor(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestConfirmTwoFactor(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		RequestJSON  string
+		MockResult   []string
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Enabled successfully",
+			RequestJSON:  `{"code": "123456"}`,
+			MockResult:   []string{"abcde-fghij"},
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Missing code",
+			RequestJSON:  `{}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Invalid code",
+			RequestJSON:  `{"code": "123456"}`,
+			MockError:    service.ErrInvalidTwoFactorCode,
+			ExpectedCode: http.StatusUnauthorized,
+		},
+		{
+			Name:         "Not enrolled",
+			RequestJSON:  `{"code": "123456"}`,
+			MockError:    service.ErrTwoFactorNotEnrolled,
+			ExpectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/users/2fa/confirm", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			services.On("ConfirmTwoFactor", ctx, userID, "123456").Return(test.MockResult, test.MockError)
+			handler.confirmTwoFactor(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		RequestJSON  string
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Disabled successfully",
+			RequestJSON:  `{"code": "123456"}`,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Required for role",
+			RequestJSON:  `{"code": "123456"}`,
+			MockError:    service.ErrTwoFactorRequired,
+			ExpectedCode: http.StatusForbidden,
+		},
+		{
+			Name:         "Not enabled",
+			RequestJSON:  `{"code": "123456"}`,
+			MockError:    service.ErrTwoFactorNotEnabled,
+			ExpectedCode: http.StatusConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("DELETE", "/users/2fa", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			services.On("DisableTwoFactor", ctx, userID, "123456").Return(test.MockError)
+			handler.disableTwoFactor(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
@@ -2,11 +2,16 @@ package handler
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"net/url"
 	"one-lab-final/internal/entity"
 	"one-lab-final/internal/handler/api"
 	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
 	"one-lab-final/pkg/util"
+	"path"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,7 +23,7 @@ import (
 // @Param data body api.CreateUserRequest true "Request body"
 //
 // @Success      201 {object} api.DefaultResponse "User succesfully created"
-// @Failure      400  {object}  api.ErrorResponse
+// @Failure      400  {object}  api.ValidationErrorResponse
 // @Failure      500  {object}  api.ErrorResponse
 // @Router       /users/register [post]
 func (h *Handler) createUser(ctx *gin.Context) {
@@ -43,6 +48,138 @@ func (h *Handler) createUser(ctx *gin.Context) {
 		},
 		Role: entity.USER,
 	})
+	if err != nil {
+		var validationErr *service.ValidationError
+
+		switch {
+		case errors.As(err, &validationErr):
+			ctx.JSON(http.StatusBadRequest, &api.ValidationErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "validation failed",
+				Fields:  validationErr.Fields,
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusCreated, &api.DefaultResponse{
+		Code:    http.StatusCreated,
+		Message: "user succesfully created, activation token was sent to email",
+	})
+}
+
+// @Summary      Activate account with token sent to email
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param data body api.ActivateUserRequest true "Request body"
+//
+// @Success      200 {object} api.DefaultResponse "User succesfully activated"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/activate [put]
+func (h *Handler) activateUser(ctx *gin.Context) {
+	var req api.ActivateUserRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.Services.ActivateUser(ctx, req.Token)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "activation token is invalid or expired",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "user succesfully activated",
+	})
+}
+
+// @Summary      Send new activation token to email of current user
+// @Tags         Users
+// @Produce      json
+// @Security ApiKeyAuth
+//
+// @Success      200 {object} api.DefaultResponse "Activation token was sent"
+// @Failure      409  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/activate/resend [post]
+func (h *Handler) resendActivation(ctx *gin.Context) {
+	userID := ctx.MustGet("userID").(int64)
+
+	err := h.Services.ResendActivation(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserAlreadyActivated):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "activation token was sent to email",
+	})
+}
+
+// @Summary      Send password reset token to email of the account
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param data body api.PasswordResetRequest true "Request body"
+//
+// @Success      202 {object} api.DefaultResponse "Token was sent if account exists"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/password-reset [post]
+func (h *Handler) requestPasswordReset(ctx *gin.Context) {
+	var req api.PasswordResetRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.Services.RequestPasswordReset(ctx, req.Credentials)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
 			Code:    http.StatusInternalServerError,
@@ -51,9 +188,65 @@ func (h *Handler) createUser(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, &api.DefaultResponse{
-		Code:    http.StatusCreated,
-		Message: "user succesfully created",
+	ctx.JSON(http.StatusAccepted, &api.DefaultResponse{
+		Code:    http.StatusAccepted,
+		Message: "if account exists, password reset token was sent to its email",
+	})
+}
+
+// @Summary      Set new password with token sent to email. Logs out all sessions
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param data body api.ResetPasswordRequest true "Request body"
+//
+// @Success      200 {object} api.DefaultResponse "Password succesfully updated"
+// @Failure      400  {object}  api.ValidationErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/password [put]
+func (h *Handler) resetPassword(ctx *gin.Context) {
+	var req api.ResetPasswordRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	err = h.Services.ResetPassword(ctx, req.Token, req.Password)
+	if err != nil {
+		var validationErr *service.ValidationError
+
+		switch {
+		case errors.As(err, &validationErr):
+			ctx.JSON(http.StatusBadRequest, &api.ValidationErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "validation failed",
+				Fields:  validationErr.Fields,
+			})
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "password reset token is invalid or expired",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.DefaultResponse{
+		Code:    http.StatusOK,
+		Message: "password succesfully updated",
 	})
 }
 
@@ -64,6 +257,7 @@ func (h *Handler) createUser(ctx *gin.Context) {
 // @Param        username   path      string  true  "Username of user"
 //
 // @Success      200 {object} api.GetUserByUsernameResponse "Ok"
+// @Success      301 "User was renamed, redirect to the current username"
 // @Failure      400  {object}  api.ErrorResponse
 // @Failure      404  {object}  api.ErrorResponse
 // @Failure      500  {object}  api.ErrorResponse
@@ -82,7 +276,12 @@ func (h *Handler) getUserByUsername(ctx *gin.Context) {
 
 	user, err := h.Services.GetUserByUsername(ctx, req.Value)
 	if err != nil {
+		var movedErr *service.UsernameMovedError
+
 		switch {
+		case errors.As(err, &movedErr):
+			ctx.Redirect(http.StatusMovedPermanently, path.Join(path.Dir(ctx.Request.URL.Path), url.PathEscape(movedErr.Username)))
+			return
 		case errors.Is(err, repository.ErrRecordNotFound):
 			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
 				Code:    http.StatusNotFound,
@@ -112,7 +311,11 @@ func (h *Handler) getUserByUsername(ctx *gin.Context) {
 // @Param data body api.LoginRequest true "Request body"
 //
 // @Success      201 {object} api.LoginResponse "Token succesfully created"
+// @Success      202 {object} api.LoginResponse "Password accepted, partial token must be exchanged with two-factor code"
 // @Failure      400  {object}  api.ErrorResponse
+// @Failure      401  {object}  api.ErrorResponse
+// @Failure      423  {object}  api.ErrorResponse "Too many failed attempts, see Retry-After header"
+// @Failure      429  {object}  api.ErrorResponse "Attempted too early after failed one, see Retry-After header"
 // @Failure      500  {object}  api.ErrorResponse
 // @Router       /users/login [post]
 func (h *Handler) login(ctx *gin.Context) {
@@ -127,9 +330,26 @@ func (h *Handler) login(ctx *gin.Context) {
 		return
 	}
 
-	token, err := h.Services.Login(ctx, req.Credentials, req.Password)
+	token, err := h.Services.Login(ctx, req.Credentials, req.Password, entity.Client{
+		IP:        ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+	})
 	if err != nil {
+		var limitErr *service.LoginLimitError
+
 		switch {
+		case errors.As(err, &limitErr):
+			code := http.StatusTooManyRequests
+			if limitErr.Locked {
+				code = http.StatusLocked
+			}
+
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(limitErr.RetryAfter.Seconds()))))
+			ctx.JSON(code, &api.ErrorResponse{
+				Code:    code,
+				Message: limitErr.Error(),
+			})
+			return
 		case errors.Is(err, util.ErrMismatchedPassword) || errors.Is(err, repository.ErrRecordNotFound):
 			ctx.JSON(http.StatusUnauthorized, &api.ErrorResponse{
 				Code:    http.StatusUnauthorized,
@@ -145,6 +365,15 @@ func (h *Handler) login(ctx *gin.Context) {
 		}
 	}
 
+	if token.Partial {
+		ctx.JSON(http.StatusAccepted, &api.LoginResponse{
+			Code:    http.StatusAccepted,
+			Message: "two-factor code is required, exchange token at /users/login/2fa",
+			Body:    token,
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusCreated, &api.LoginResponse{
 		Code:    http.StatusCreated,
 		Message: "token succesfully created",
@@ -152,14 +381,14 @@ func (h *Handler) login(ctx *gin.Context) {
 	})
 }
 
-// @Summary      Update user info
+// @Summary      Update user info. Changing password logs out all other sessions
 // @Tags         Users
 // @Produce      json
 // @Security ApiKeyAuth
 // @Param data body api.UpdateUserRequest true "Request body"
 //
 // @Success      200 {object} api.DefaultResponse "User succesfully updated"
-// @Failure      400  {object}  api.ErrorResponse
+// @Failure      400  {object}  api.ValidationErrorResponse
 // @Failure      500  {object}  api.ErrorResponse
 // @Router       /users/update [patch]
 func (h *Handler) updateUser(ctx *gin.Context) {
@@ -180,16 +409,31 @@ func (h *Handler) updateUser(ctx *gin.Context) {
 		ID:        userID,
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
+		Bio:       req.Bio,
+		Location:  req.Location,
+		Website:   req.Website,
 		Password: entity.Password{
 			Plaintext: req.Password,
 		},
-	})
+	}, ctx.GetString("token"))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		})
-		return
+		var validationErr *service.ValidationError
+
+		switch {
+		case errors.As(err, &validationErr):
+			ctx.JSON(http.StatusBadRequest, &api.ValidationErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "validation failed",
+				Fields:  validationErr.Fields,
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
 	}
 
 	ctx.JSON(http.StatusOK, &api.DefaultResponse{
@@ -198,13 +442,14 @@ func (h *Handler) updateUser(ctx *gin.Context) {
 	})
 }
 
-// @Summary      Delete user
+// @Summary      Delete user. Logging in during grace period restores the account, after it the profile is anonymized
 // @Tags         Users
 // @Produce      json
 // @Security ApiKeyAuth
 //
 // @Success      200 {object} api.DefaultResponse "User succesfully deleted"
 // @Failure      400  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
 // @Failure      500  {object}  api.ErrorResponse
 // @Router       /users/delete [delete]
 func (h *Handler) deleteUser(ctx *gin.Context) {
@@ -212,20 +457,29 @@ func (h *Handler) deleteUser(ctx *gin.Context) {
 
 	err := h.Services.DeleteUser(ctx, userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		})
-		return
+		switch {
+		case errors.Is(err, service.ErrLastAdmin):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
 	}
 
 	ctx.JSON(http.StatusOK, &api.DefaultResponse{
 		Code:    http.StatusOK,
-		Message: "user succesfully deleted",
+		Message: "user succesfully deleted, log in during grace period to restore the account",
 	})
 }
 
-// @Summary      Update role of user by his ID. Requires ADMIN role
+// @Summary      Update role of user by his ID and record it in role history. Requires "roles.grant" permission and every permission of current and new role of the user. The last admin can't be demoted
 // @Tags         Moderation
 // @Accept       json
 // @Produce      json
@@ -235,6 +489,9 @@ func (h *Handler) deleteUser(ctx *gin.Context) {
 //
 // @Success      200 {object} api.DefaultResponse "role of user was successfully updated"
 // @Failure      400  {object}  api.ErrorResponse
+// @Failure      403  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      409  {object}  api.ErrorResponse
 // @Failure      500  {object}  api.ErrorResponse
 // @Router       /mod/roles/{id} [patch]
 func (h *Handler) grantRoleToUser(ctx *gin.Context) {
@@ -268,13 +525,53 @@ func (h *Handler) grantRoleToUser(ctx *gin.Context) {
 		return
 	}
 
-	err = h.Services.GrantRoleToUser(ctx, id.Value, role)
+	actorID := ctx.MustGet("userID").(int64)
+
+	err = h.Services.GrantRoleToUser(ctx, &entity.RoleChange{
+		UserID:  id.Value,
+		ActorID: &actorID,
+		NewRole: role,
+		Reason:  req.Reason,
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-		})
-		return
+		switch {
+		case errors.Is(err, service.ErrUnknownRole):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "role does not exists",
+			})
+			return
+		case errors.Is(err, service.ErrRoleAboveOwn):
+			ctx.JSON(http.StatusForbidden, &api.ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrLastAdmin):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+			return
+		case errors.Is(err, repository.ErrEditConflict):
+			ctx.JSON(http.StatusConflict, &api.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: "role of user was changed by another request, try again",
+			})
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "user does not exists",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
 	}
 
 	ctx.JSON(http.StatusOK, &api.DefaultResponse{
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/service"
+	"one-lab-final/pkg/util"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      List users with filters and prefix search. Requires "users.view" permission
+// @Tags         Moderation
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        query  query     api.GetUsersRequest  false  "Filters"
+//
+// @Success      200 {object} api.GetUsersResponse
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /mod/users [get]
+func (h *Handler) getUsers(ctx *gin.Context) {
+	var req api.GetUsersRequest
+
+	err := ctx.ShouldBindQuery(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	search := entity.UserSearch{
+		Search:       req.Search,
+		Suspended:    req.Suspended,
+		Activated:    req.Activated,
+		CreatedAfter: req.RegisteredFrom,
+	}
+
+	if req.Role != nil {
+		role, ok := entity.StringToRole(*req.Role)
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "role does not exists",
+			})
+			return
+		}
+
+		search.Role = &role
+	}
+
+	// The last day of the range is included
+	if req.RegisteredTo != nil {
+		before := req.RegisteredTo.Add(24 * time.Hour)
+		search.CreatedBefore = &before
+	}
+
+	users, meta, err := h.Services.GetUsers(ctx, search, util.NewFilter(req.Page, req.PageSize, req.Sort))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidSortValue):
+			ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetUsersResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    users,
+		Meta:    *meta,
+	})
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/service"
+	"one-lab-final/internal/service/mocks"
+	"one-lab-final/pkg/util"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetUsers(t *testing.T) {
+	moderator := entity.MODERATOR
+	suspended := true
+	from := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		Name           string
+		RequestQuery   string
+		MockResultErr  error
+		ExpectMock     bool
+		ExpectedSearch entity.UserSearch
+		ExpectedCode   int
+	}{
+		{
+			Name:           "Get users without filters",
+			RequestQuery:   `page=1&page_size=10`,
+			ExpectMock:     true,
+			ExpectedSearch: entity.UserSearch{},
+			ExpectedCode:   http.StatusOK,
+		},
+		{
+			Name:         "Get users with filters",
+			RequestQuery: `search=joh&role=moderator&suspended=true&registered_from=2023-01-01&registered_to=2023-12-31`,
+			ExpectMock:   true,
+			ExpectedSearch: entity.UserSearch{
+				Search:        util.StringToPointer("joh"),
+				Role:          &moderator,
+				Suspended:     &suspended,
+				CreatedAfter:  &from,
+				CreatedBefore: &to,
+			},
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Non-valid role",
+			RequestQuery: `role=owner%21`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Non-valid date",
+			RequestQuery: `registered_from=yesterday`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:           "Non-valid sort",
+			RequestQuery:   `sort=password_hash`,
+			MockResultErr:  service.ErrInvalidSortValue,
+			ExpectMock:     true,
+			ExpectedSearch: entity.UserSearch{},
+			ExpectedCode:   http.StatusBadRequest,
+		},
+		{
+			Name:           "Error while retriving",
+			RequestQuery:   `page=1&page_size=10`,
+			MockResultErr:  errors.New("critical error"),
+			ExpectMock:     true,
+			ExpectedSearch: entity.UserSearch{},
+			ExpectedCode:   http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("GET", "/mod/users?"+test.RequestQuery, nil)
+			ctx.Request = req
+
+			if test.ExpectMock {
+				var users []*entity.UserOverview
+				var meta *util.Metadata
+				if test.MockResultErr == nil {
+					users = []*entity.UserOverview{{ID: 1, Role: "MODERATOR", ReviewCount: 3}}
+					meta = &util.Metadata{}
+				}
+				services.On("GetUsers", ctx, mock.MatchedBy(func(search entity.UserSearch) bool {
+					return assert.ObjectsAreEqual(test.ExpectedSearch.Search, search.Search) &&
+						assert.ObjectsAreEqual(test.ExpectedSearch.Role, search.Role) &&
+						assert.ObjectsAreEqual(test.ExpectedSearch.Suspended, search.Suspended) &&
+						assert.ObjectsAreEqual(test.ExpectedSearch.Activated, search.Activated) &&
+						equalTime(test.ExpectedSearch.CreatedAfter, search.CreatedAfter) &&
+						equalTime(test.ExpectedSearch.CreatedBefore, search.CreatedBefore)
+				}), mock.AnythingOfType("util.Filter")).Return(users, meta, test.MockResultErr)
+			}
+
+			handler.getUsers(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
+
+func equalTime(expected *time.Time, actual *time.Time) bool {
+	if expected == nil || actual == nil {
+		return expected == actual
+	}
+
+	return expected.Equal(*actual)
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"one-lab-final/internal/handler/api"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary      Get reviewer statistics of the user. Statistics are refreshed every hour
+// @Tags         Users
+// @Produce      json
+// @Param        username   path      string  true  "Username"
+//
+// @Success      200 {object} api.GetUserStatsResponse
+// @Success      301 "User was renamed, redirect to the current username"
+// @Failure      400  {object}  api.ErrorResponse
+// @Failure      404  {object}  api.ErrorResponse
+// @Failure      500  {object}  api.ErrorResponse
+// @Router       /users/{username}/stats [get]
+func (h *Handler) getUserStats(ctx *gin.Context) {
+	var req api.Username
+
+	err := ctx.ShouldBindUri(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &api.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	stats, err := h.Services.GetUserStats(ctx, req.Value)
+	if err != nil {
+		var movedErr *service.UsernameMovedError
+
+		switch {
+		case errors.As(err, &movedErr):
+			ctx.Redirect(http.StatusMovedPermanently, movedUsernamePath(ctx, req.Value, movedErr.Username))
+			return
+		case errors.Is(err, repository.ErrRecordNotFound):
+			ctx.JSON(http.StatusNotFound, &api.ErrorResponse{
+				Code:    http.StatusNotFound,
+				Message: "user does not exists",
+			})
+			return
+		default:
+			ctx.JSON(http.StatusInternalServerError, &api.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &api.GetUserStatsResponse{
+		Code:    http.StatusOK,
+		Message: "ok",
+		Body:    stats,
+	})
+}
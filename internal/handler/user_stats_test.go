@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserStats(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Username     string
+		MockError    error
+		ExpectMock   bool
+		ExpectedCode int
+	}{
+		{
+			Name:         "Stats retrieved",
+			Username:     "john_doe",
+			ExpectMock:   true,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Non-valid username",
+			Username:     "jd",
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "User does not exist",
+			Username:     "john_doe",
+			MockError:    repository.ErrRecordNotFound,
+			ExpectMock:   true,
+			ExpectedCode: http.StatusNotFound,
+		},
+		{
+			Name:         "Error while retrieving",
+			Username:     "john_doe",
+			MockError:    errors.New("critical error"),
+			ExpectMock:   true,
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("GET", "/users/"+test.Username+"/stats", nil)
+			ctx.Request = req
+			ctx.Params = gin.Params{{Key: "username", Value: test.Username}}
+
+			if test.ExpectMock {
+				var stats *entity.UserStats
+				if test.MockError == nil {
+					stats = &entity.UserStats{ReviewCount: 3}
+				}
+				services.On("GetUserStats", ctx, test.Username).Return(stats, test.MockError)
+			}
+
+			handler.getUserStats(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+			services.AssertExpectations(t)
+		})
+	}
+}
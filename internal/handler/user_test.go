@@ -5,15 +5,19 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"one-lab-final/internal/config"
 	"one-lab-final/internal/entity"
 	"one-lab-final/internal/repository"
+	"one-lab-final/internal/service"
 	"one-lab-final/internal/service/mocks"
 	"one-lab-final/pkg/util"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestCreateUser(t *testing.T) {
@@ -43,6 +47,7 @@ func TestCreateUser(t *testing.T) {
 				Password: entity.Password{
 					Plaintext: util.StringToPointer("password"),
 				},
+				Role: entity.USER,
 			},
 			ExpectedCode: http.StatusCreated,
 		},
@@ -82,10 +87,34 @@ func TestCreateUser(t *testing.T) {
 				Password: entity.Password{
 					Plaintext: util.StringToPointer("password"),
 				},
+				Role: entity.USER,
 			},
 			MockResult:   errors.New("critical error"),
 			ExpectedCode: http.StatusInternalServerError,
 		},
+		{
+			Name: "Password is rejected by policy",
+			RequestJSON: `
+			{
+				"username": "Flove",
+				"email": "example@gmail.com",
+				"password": "password",
+				"first_name": "Firstname",
+				"last_name": "Surname"
+			}`,
+			ExpectedUser: entity.User{
+				Username:  util.StringToPointer("Flove"),
+				Email:     util.StringToPointer("example@gmail.com"),
+				FirstName: util.StringToPointer("Firstname"),
+				LastName:  util.StringToPointer("Surname"),
+				Password: entity.Password{
+					Plaintext: util.StringToPointer("password"),
+				},
+				Role: entity.USER,
+			},
+			MockResult:   &service.ValidationError{Fields: map[string]string{"password": "was found in a data breach, choose another one"}},
+			ExpectedCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, test := range tests {
@@ -118,6 +147,7 @@ func TestGetUserByUsername(t *testing.T) {
 		MockError        error
 		ExpectedUsername string
 		ExpectedCode     int
+		ExpectedLocation string
 	}{
 		{
 			Name:             "Get user successfully",
@@ -145,6 +175,14 @@ func TestGetUserByUsername(t *testing.T) {
 			ExpectedUsername: "flove",
 			ExpectedCode:     http.StatusInternalServerError,
 		},
+		{
+			Name:             "User was renamed",
+			RequestURI:       util.StringToPointer("flove"),
+			MockError:        &service.UsernameMovedError{Username: "new flove"},
+			ExpectedUsername: "flove",
+			ExpectedCode:     http.StatusMovedPermanently,
+			ExpectedLocation: "/api/v1/users/new%20flove",
+		},
 	}
 
 	for _, test := range tests {
@@ -157,7 +195,7 @@ func TestGetUserByUsername(t *testing.T) {
 			service := &mocks.Service{}
 			handler := New(service, nil)
 
-			req, _ := http.NewRequest("GET", "/users/"+test.ExpectedUsername, strings.NewReader(""))
+			req, _ := http.NewRequest("GET", "/api/v1/users/"+test.ExpectedUsername, strings.NewReader(""))
 			req.Header.Set("Content-Type", "application/json")
 
 			if test.RequestURI != nil {
@@ -170,6 +208,7 @@ func TestGetUserByUsername(t *testing.T) {
 			service.On("GetUserByUsername", ctx, test.ExpectedUsername).Return(test.MockResult, test.MockError)
 			handler.getUserByUsername(ctx)
 			assert.Equal(t, test.ExpectedCode, w.Code)
+			assert.Equal(t, test.ExpectedLocation, w.Header().Get("Location"))
 		})
 	}
 }
@@ -183,6 +222,7 @@ func TestLogin(t *testing.T) {
 		ExpectedCredentials string
 		ExpectedPassword    string
 		ExpectedCode        int
+		ExpectedRetryAfter  string
 	}{
 		{
 			Name: "Logged in successfully",
@@ -196,6 +236,18 @@ func TestLogin(t *testing.T) {
 			ExpectedPassword:    "password",
 			ExpectedCode:        http.StatusCreated,
 		},
+		{
+			Name: "Two-factor code is required",
+			RequestJSON: `
+			{
+				"credentials": "flove",
+				"password": "password"
+			}`,
+			MockResult:          &entity.Token{Partial: true},
+			ExpectedCredentials: "flove",
+			ExpectedPassword:    "password",
+			ExpectedCode:        http.StatusAccepted,
+		},
 		{
 			Name: "Non-valid JSON",
 			RequestJSON: `
@@ -216,6 +268,32 @@ func TestLogin(t *testing.T) {
 			ExpectedPassword:    "password",
 			ExpectedCode:        http.StatusUnauthorized,
 		},
+		{
+			Name: "Attempted too early",
+			RequestJSON: `
+			{
+				"credentials": "flove",
+				"password": "password"
+			}`,
+			MockError:           &service.LoginLimitError{RetryAfter: 1500 * time.Millisecond},
+			ExpectedCredentials: "flove",
+			ExpectedPassword:    "password",
+			ExpectedCode:        http.StatusTooManyRequests,
+			ExpectedRetryAfter:  "2",
+		},
+		{
+			Name: "Account is locked",
+			RequestJSON: `
+			{
+				"credentials": "flove",
+				"password": "password"
+			}`,
+			MockError:           &service.LoginLimitError{RetryAfter: 15 * time.Minute, Locked: true},
+			ExpectedCredentials: "flove",
+			ExpectedPassword:    "password",
+			ExpectedCode:        http.StatusLocked,
+			ExpectedRetryAfter:  "900",
+		},
 		{
 			Name: "Error while saving entity",
 			RequestJSON: `
@@ -245,9 +323,56 @@ func TestLogin(t *testing.T) {
 
 			ctx.Request = req
 
-			service.On("Login", ctx, test.ExpectedCredentials, test.ExpectedPassword).Return(test.MockResult, test.MockError)
+			service.On("Login", ctx, test.ExpectedCredentials, test.ExpectedPassword, entity.Client{}).Return(test.MockResult, test.MockError)
 			handler.login(ctx)
 			assert.Equal(t, test.ExpectedCode, w.Code)
+			assert.Equal(t, test.ExpectedRetryAfter, w.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func TestLoginClientIP(t *testing.T) {
+	tests := []struct {
+		Name           string
+		TrustedProxies []string
+		ExpectedIP     string
+	}{
+		{
+			Name:       "Spoofed X-Forwarded-For is ignored",
+			ExpectedIP: "10.0.0.1",
+		},
+		{
+			Name:           "X-Forwarded-For of trusted proxy is used",
+			TrustedProxies: []string{"10.0.0.0/8"},
+			ExpectedIP:     "203.0.113.7",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			services := &mocks.Service{}
+			handler := New(services, &config.Config{
+				HTTP: config.ServerConfig{
+					TrustedProxies: test.TrustedProxies,
+				},
+			})
+
+			router, err := handler.InitRouter()
+			assert.Nil(t, err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/api/v1/users/login", strings.NewReader(`{"credentials": "flove", "password": "password"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			req.RemoteAddr = "10.0.0.1:4242"
+
+			services.On("Login", mock.Anything, "flove", "password", entity.Client{IP: test.ExpectedIP}).Return(nil, util.ErrMismatchedPassword)
+
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			services.AssertExpectations(t)
 		})
 	}
 }
@@ -293,6 +418,30 @@ func TestUpdateUser(t *testing.T) {
 			},
 			ExpectedCode: http.StatusOK,
 		},
+		{
+			Name: "Update profile",
+			RequestJSON: `
+			{
+				"bio": "Reads mostly science fiction",
+				"location": "Winterfell",
+				"website": "https://example.com"
+			}`,
+			ExpectedUser: entity.User{
+				ID:       userID,
+				Bio:      util.StringToPointer("Reads mostly science fiction"),
+				Location: util.StringToPointer("Winterfell"),
+				Website:  util.StringToPointer("https://example.com"),
+			},
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name: "Non-valid website",
+			RequestJSON: `
+			{
+				"website": "example"
+			}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
 		{
 			Name:         "Non-valid URI path",
 			ExpectedCode: http.StatusBadRequest,
@@ -341,7 +490,7 @@ func TestUpdateUser(t *testing.T) {
 			ctx.Set("userID", userID)
 			ctx.Request = req
 
-			service.On("UpdateUser", ctx, &test.ExpectedUser).Return(test.MockResult)
+			service.On("UpdateUser", ctx, &test.ExpectedUser, "").Return(test.MockResult)
 			handler.updateUser(ctx)
 			assert.Equal(t, test.ExpectedCode, w.Code)
 		})
@@ -368,6 +517,12 @@ func TestDeleteUser(t *testing.T) {
 			ExpectedID:   userID,
 			ExpectedCode: http.StatusInternalServerError,
 		},
+		{
+			Name:         "Last admin",
+			MockResult:   service.ErrLastAdmin,
+			ExpectedID:   userID,
+			ExpectedCode: http.StatusConflict,
+		},
 	}
 
 	for _, test := range tests {
@@ -395,15 +550,18 @@ func TestDeleteUser(t *testing.T) {
 
 func TestGrantRoleToUser(t *testing.T) {
 	var userID int64 = 123
+	var actorID int64 = 1
 	userRole := entity.ADMIN
+	reason := "Joined moderation team"
 	tests := []struct {
-		Name         string
-		RequestURI   string
-		RequestJSON  string
-		MockResult   any
-		ExpectedID   int64
-		ExpectedRole entity.Role
-		ExpectedCode int
+		Name           string
+		RequestURI     string
+		RequestJSON    string
+		MockResult     any
+		ExpectedID     int64
+		ExpectedRole   entity.Role
+		ExpectedReason *string
+		ExpectedCode   int
 	}{
 		{
 			Name:       "Update role successfully",
@@ -417,6 +575,42 @@ func TestGrantRoleToUser(t *testing.T) {
 			MockResult:   nil,
 			ExpectedCode: http.StatusOK,
 		},
+		{
+			Name:           "Update role with reason",
+			RequestURI:     fmt.Sprintf("%d", userID),
+			RequestJSON:    `{"role": "moderator", "reason": "Joined moderation team"}`,
+			ExpectedID:     userID,
+			ExpectedRole:   entity.MODERATOR,
+			ExpectedReason: &reason,
+			ExpectedCode:   http.StatusOK,
+		},
+		{
+			Name:         "Role above own",
+			RequestURI:   fmt.Sprintf("%d", userID),
+			RequestJSON:  `{"role": "admin"}`,
+			ExpectedID:   userID,
+			ExpectedRole: userRole,
+			MockResult:   service.ErrRoleAboveOwn,
+			ExpectedCode: http.StatusForbidden,
+		},
+		{
+			Name:         "Last admin",
+			RequestURI:   fmt.Sprintf("%d", userID),
+			RequestJSON:  `{"role": "user"}`,
+			ExpectedID:   userID,
+			ExpectedRole: entity.USER,
+			MockResult:   service.ErrLastAdmin,
+			ExpectedCode: http.StatusConflict,
+		},
+		{
+			Name:         "User does not exist",
+			RequestURI:   fmt.Sprintf("%d", userID),
+			RequestJSON:  `{"role": "user"}`,
+			ExpectedID:   userID,
+			ExpectedRole: entity.USER,
+			MockResult:   repository.ErrRecordNotFound,
+			ExpectedCode: http.StatusNotFound,
+		},
 		{
 			Name:         "Malformed id",
 			RequestURI:   "noo",
@@ -439,6 +633,15 @@ func TestGrantRoleToUser(t *testing.T) {
 			{
 				"role": "fdsf"
 			}`,
+			ExpectedID:   userID,
+			ExpectedRole: entity.Role("FDSF"),
+			MockResult:   service.ErrUnknownRole,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Non-valid role name",
+			RequestURI:   fmt.Sprintf("%d", userID),
+			RequestJSON:  `{"role": "fd sf"}`,
 			ExpectedCode: http.StatusBadRequest,
 		},
 		{
@@ -468,10 +671,217 @@ func TestGrantRoleToUser(t *testing.T) {
 			param := gin.Param{Key: "id", Value: test.RequestURI}
 			ctx.Params = append(ctx.Params, param)
 			ctx.Request = req
+			ctx.Set("userID", actorID)
 
-			service.On("GrantRoleToUser", ctx, test.ExpectedID, test.ExpectedRole).Return(test.MockResult)
+			change := &entity.RoleChange{
+				UserID:  test.ExpectedID,
+				ActorID: &actorID,
+				NewRole: test.ExpectedRole,
+				Reason:  test.ExpectedReason,
+			}
+			service.On("GrantRoleToUser", ctx, change).Return(test.MockResult)
 			handler.grantRoleToUser(ctx)
 			assert.Equal(t, test.ExpectedCode, w.Code)
 		})
 	}
 }
+
+func TestActivateUser(t *testing.T) {
+	tests := []struct {
+		Name         string
+		RequestJSON  string
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Activated successfully",
+			RequestJSON:  `{"token": "token"}`,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Missing token",
+			RequestJSON:  `{}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Invalid token",
+			RequestJSON:  `{"token": "token"}`,
+			MockError:    repository.ErrRecordNotFound,
+			ExpectedCode: http.StatusNotFound,
+		},
+		{
+			Name:         "Error while activating",
+			RequestJSON:  `{"token": "token"}`,
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("PUT", "/users/activate", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+			ctx.Request = req
+
+			services.On("ActivateUser", ctx, "token").Return(test.MockError)
+			handler.activateUser(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestResendActivation(t *testing.T) {
+	var userID int64 = 123
+	tests := []struct {
+		Name         string
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Token sent successfully",
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Already activated",
+			MockError:    service.ErrUserAlreadyActivated,
+			ExpectedCode: http.StatusConflict,
+		},
+		{
+			Name:         "Error while sending token",
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/users/activate/resend", strings.NewReader(""))
+			ctx.Request = req
+			ctx.Set("userID", userID)
+
+			services.On("ResendActivation", ctx, userID).Return(test.MockError)
+			handler.resendActivation(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	tests := []struct {
+		Name         string
+		RequestJSON  string
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Request accepted",
+			RequestJSON:  `{"credentials": "username"}`,
+			ExpectedCode: http.StatusAccepted,
+		},
+		{
+			Name:         "Missing credentials",
+			RequestJSON:  `{}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Error while sending token",
+			RequestJSON:  `{"credentials": "username"}`,
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("POST", "/users/password-reset", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+			ctx.Request = req
+
+			services.On("RequestPasswordReset", ctx, "username").Return(test.MockError)
+			handler.requestPasswordReset(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	tests := []struct {
+		Name         string
+		RequestJSON  string
+		MockError    error
+		ExpectedCode int
+	}{
+		{
+			Name:         "Password updated successfully",
+			RequestJSON:  `{"token": "token", "password": "password"}`,
+			ExpectedCode: http.StatusOK,
+		},
+		{
+			Name:         "Password is rejected by policy",
+			RequestJSON:  `{"token": "token", "password": "password"}`,
+			MockError:    &service.ValidationError{Fields: map[string]string{"password": "must be at least 12 characters long"}},
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Missing password",
+			RequestJSON:  `{"token": "token"}`,
+			ExpectedCode: http.StatusBadRequest,
+		},
+		{
+			Name:         "Invalid token",
+			RequestJSON:  `{"token": "token", "password": "password"}`,
+			MockError:    repository.ErrRecordNotFound,
+			ExpectedCode: http.StatusNotFound,
+		},
+		{
+			Name:         "Error while updating password",
+			RequestJSON:  `{"token": "token", "password": "password"}`,
+			MockError:    errors.New("critical error"),
+			ExpectedCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+
+			services := &mocks.Service{}
+			handler := New(services, nil)
+
+			req, _ := http.NewRequest("PUT", "/users/password", strings.NewReader(test.RequestJSON))
+			req.Header.Set("Content-Type", "application/json")
+			ctx.Request = req
+
+			services.On("ResetPassword", ctx, "token", "password").Return(test.MockError)
+			handler.resetPassword(ctx)
+			assert.Equal(t, test.ExpectedCode, w.Code)
+		})
+	}
+}
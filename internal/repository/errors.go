@@ -3,5 +3,8 @@ package repository
 import "errors"
 
 var (
-	ErrRecordNotFound = errors.New("no records were found")
+	ErrRecordNotFound  = errors.New("no records were found")
+	ErrDuplicateRecord = errors.New("record violates unique constraint")
+	ErrForeignKey      = errors.New("record violates foreign key constraint")
+	ErrEditConflict    = errors.New("record was changed by another request")
 )
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"one-lab-final/internal/entity"
 	"one-lab-final/pkg/util"
@@ -12,12 +13,60 @@ type Repository interface {
 	CreateUser(ctx context.Context, user *entity.User) error
 	GetUserByUsername(ctx context.Context, username string) (*entity.User, error)
 	GetUserByCredentials(ctx context.Context, credentials string) (*entity.User, error)
+	GetUserByID(ctx context.Context, userID int64) (*entity.User, error)
 	GetUserByToken(ctx context.Context, token string) (*entity.User, error)
+	GetUserByScopedToken(ctx context.Context, token string, scope string) (*entity.User, error)
+	ConsumeScopedToken(ctx context.Context, token string, scope string) (*entity.User, error)
+	ActivateUser(ctx context.Context, userID int64) error
 	UpdateUser(ctx context.Context, user *entity.User) error
 	DeleteUser(ctx context.Context, userID int64) error
+	RestoreUser(ctx context.Context, userID int64) error
+	AnonymizeDeletedUsers(ctx context.Context, deletedBefore time.Time, deleteReviews bool) ([]string, error)
 
 	CreateToken(ctx context.Context, token *entity.Token) error
+	GetSessionsByUserID(ctx context.Context, userID int64) ([]*entity.Session, error)
+	DeleteToken(ctx context.Context, token string) error
+	DeleteSession(ctx context.Context, sessionID int64, userID int64) error
+	DeleteUserTokens(ctx context.Context, userID int64, exceptToken string) error
 	DeleteExpiredTokens(ctx context.Context) error
+	DeleteScopedTokens(ctx context.Context, userID int64, scope string) error
+	CountScopedTokens(ctx context.Context, userID int64, scope string, since time.Time) (int, error)
+	GetTokenStatus(ctx context.Context, jti string, userID int64) (*entity.TokenStatus, error)
+
+	GetLoginAttempts(ctx context.Context, keys []string) ([]*entity.LoginAttempt, error)
+	RecordLoginFailure(ctx context.Context, key string, threshold int, window time.Duration) (*entity.LoginAttempt, error)
+	ResetLoginAttempts(ctx context.Context, key string) error
+	DeleteStaleLoginAttempts(ctx context.Context, before time.Time) error
+
+	CreateIdentity(ctx context.Context, identity *entity.Identity) error
+	GetUserByIdentity(ctx context.Context, provider string, subject string) (*entity.User, error)
+	GetIdentitiesByUserID(ctx context.Context, userID int64) ([]*entity.Identity, error)
+	CreateOIDCState(ctx context.Context, state *entity.OIDCState) error
+	ConsumeOIDCState(ctx context.Context, state string, provider string) (*entity.OIDCState, error)
+
+	CreateEmailChange(ctx context.Context, change *entity.EmailChange) error
+	ConfirmEmailChange(ctx context.Context, token string) (*entity.EmailChange, error)
+	CancelEmailChange(ctx context.Context, token string) (*entity.EmailChange, error)
+	GetEmailChangesByUserID(ctx context.Context, userID int64) ([]*entity.EmailChange, error)
+	ChangeUsername(ctx context.Context, userID int64, username string) error
+	GetUsernameRedirect(ctx context.Context, username string) (string, error)
+	SetUserAvatar(ctx context.Context, userID int64, avatarKey *string) (*string, error)
+
+	CreateAPIKey(ctx context.Context, key *entity.APIKey) error
+	GetAPIKeysByUserID(ctx context.Context, userID int64) ([]*entity.APIKey, error)
+	GetUserByAPIKey(ctx context.Context, key string) (*entity.User, error)
+	DeleteAPIKey(ctx context.Context, keyID int64, userID int64) error
+
+	GetTwoFactor(ctx context.Context, userID int64) (*entity.TwoFactor, error)
+	SetTwoFactorSecret(ctx context.Context, userID int64, secret string) error
+	EnableTwoFactor(ctx context.Context, userID int64, recoveryCodes [][]byte) error
+	DisableTwoFactor(ctx context.Context, userID int64) error
+	UseRecoveryCode(ctx context.Context, userID int64, code string) error
+	UseTOTPStep(ctx context.Context, userID int64, step int64) error
+
+	CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error
+	GetRefreshToken(ctx context.Context, token string) (*entity.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, used *entity.RefreshToken, access *entity.Token, refresh *entity.RefreshToken) error
 
 	CreateBook(ctx context.Context, book *entity.Book) error
 	GetBookByID(ctx context.Context, bookID int64) (*entity.Book, error)
@@ -26,15 +75,59 @@ type Repository interface {
 	DeleteBook(ctx context.Context, articleID int64) error
 
 	RefreshBooksRating(ctx context.Context) error
+	RefreshUserStats(ctx context.Context) error
 
 	CreateReview(ctx context.Context, review *entity.Review) error
+	GetReviewByID(ctx context.Context, reviewID int64) (*entity.Review, error)
 	GetReviewsByBookID(ctx context.Context, bookID int64, filter util.Filter) ([]*entity.Review, *util.Metadata, error)
+	GetReviewsByUserID(ctx context.Context, userID int64) ([]*entity.Review, error)
+	GetReviews(ctx context.Context, search entity.ReviewSearch, filter util.Filter) ([]*entity.Review, *util.Metadata, error)
+	GetUserStats(ctx context.Context, userID int64) (*entity.UserStats, error)
 	UpdateReview(ctx context.Context, review *entity.Review) error
 	DeleteReview(ctx context.Context, reviewID int64, userID int64) error
+	RemoveReview(ctx context.Context, review *entity.Review) error
+	HideReview(ctx context.Context, review *entity.Review) error
+	RestoreReview(ctx context.Context, review *entity.Review) error
+
+	CreateContentRule(ctx context.Context, rule *entity.ContentRule) error
+	GetContentRule(ctx context.Context, ruleID int64) (*entity.ContentRule, error)
+	GetContentRules(ctx context.Context) ([]*entity.ContentRule, error)
+	UpdateContentRule(ctx context.Context, rule *entity.ContentRule) error
+	DeleteContentRule(ctx context.Context, ruleID int64) error
+
+	CreateReviewReport(ctx context.Context, report *entity.ReviewReport) error
+	GetReviewReports(ctx context.Context, reviewID int64) ([]*entity.ReviewReport, error)
+	GetReviewReportsByReporterID(ctx context.Context, reporterID int64) ([]*entity.ReviewReport, error)
+	GetReportedReviews(ctx context.Context, search entity.ReportSearch, filter util.Filter) ([]*entity.ReportedReview, *util.Metadata, error)
+	ResolveReviewReports(ctx context.Context, resolution *entity.ReportResolution) error
+
+	FollowUser(ctx context.Context, followerID int64, followeeID int64) error
+	UnfollowUser(ctx context.Context, followerID int64, followeeID int64) error
+	GetFollowers(ctx context.Context, userID int64, filter util.Filter) ([]*entity.Follow, *util.Metadata, error)
+	GetFollowing(ctx context.Context, userID int64, filter util.Filter) ([]*entity.Follow, *util.Metadata, error)
+	GetFeed(ctx context.Context, userID int64, after *entity.FeedCursor, limit int) ([]*entity.Activity, error)
 
 	NewSuspension(ctx context.Context, suspension *entity.Suspension) error
+	GetSuspension(ctx context.Context, suspensionID int64) (*entity.Suspension, error)
 	CheckSuspension(ctx context.Context, userID int64) ([]*entity.Suspension, error)
+	GetSuspensionsByUserID(ctx context.Context, userID int64) ([]*entity.Suspension, error)
+	GetSuspensions(ctx context.Context, search entity.SuspensionSearch, filter util.Filter) ([]*entity.Suspension, *util.Metadata, error)
 	UpdateSuspension(ctx context.Context, suspension *entity.Suspension) error
+	LiftSuspension(ctx context.Context, suspension *entity.Suspension) error
+
+	GrantRoleToUser(ctx context.Context, change *entity.RoleChange) error
+	CountUsersWithRole(ctx context.Context, role entity.Role) (int, error)
+	GetRoleHistory(ctx context.Context, userID int64, filter util.Filter) ([]*entity.RoleChange, *util.Metadata, error)
+	GetRoles(ctx context.Context) ([]*entity.RoleDefinition, error)
+	GetRole(ctx context.Context, name entity.Role) (*entity.RoleDefinition, error)
+	CreateRole(ctx context.Context, role *entity.RoleDefinition) error
+	UpdateRole(ctx context.Context, role *entity.RoleDefinition) error
+	DeleteRole(ctx context.Context, name entity.Role) error
+	GetUsers(ctx context.Context, search entity.UserSearch, filter util.Filter) ([]*entity.UserOverview, *util.Metadata, error)
 
-	GrantRoleToUser(ctx context.Context, userID int64, role entity.Role) error
+	CreateDataExport(ctx context.Context, export *entity.DataExport) error
+	GetDataExport(ctx context.Context, exportID int64) (*entity.DataExport, error)
+	ClaimDataExport(ctx context.Context, staleAfter time.Duration) (*entity.DataExport, error)
+	FinishDataExport(ctx context.Context, export *entity.DataExport) error
+	DeleteExpiredDataExports(ctx context.Context) ([]string, error)
 }
@@ -8,6 +8,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	util "one-lab-final/pkg/util"
 )
 
@@ -16,95 +18,79 @@ type Repository struct {
 	mock.Mock
 }
 
-// CheckSuspension provides a mock function with given fields: ctx, userID
-func (_m *Repository) CheckSuspension(ctx context.Context, userID int64) ([]*entity.Suspension, error) {
+// ActivateUser provides a mock function with given fields: ctx, userID
+func (_m *Repository) ActivateUser(ctx context.Context, userID int64) error {
 	ret := _m.Called(ctx, userID)
 
-	var r0 []*entity.Suspension
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*entity.Suspension, error)); ok {
-		return rf(ctx, userID)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64) []*entity.Suspension); ok {
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
 		r0 = rf(ctx, userID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*entity.Suspension)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, userID)
-	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// CreateBook provides a mock function with given fields: ctx, book
-func (_m *Repository) CreateBook(ctx context.Context, book *entity.Book) error {
-	ret := _m.Called(ctx, book)
+// AnonymizeDeletedUsers provides a mock function with given fields: ctx, deletedBefore, deleteReviews
+func (_m *Repository) AnonymizeDeletedUsers(ctx context.Context, deletedBefore time.Time, deleteReviews bool) ([]string, error) {
+	ret := _m.Called(ctx, deletedBefore, deleteReviews)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.Book) error); ok {
-		r0 = rf(ctx, book)
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, bool) ([]string, error)); ok {
+		return rf(ctx, deletedBefore, deleteReviews)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, bool) []string); ok {
+		r0 = rf(ctx, deletedBefore, deleteReviews)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
 	}
 
-	return r0
-}
-
-// CreateReview provides a mock function with given fields: ctx, review
-func (_m *Repository) CreateReview(ctx context.Context, review *entity.Review) error {
-	ret := _m.Called(ctx, review)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.Review) error); ok {
-		r0 = rf(ctx, review)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, bool) error); ok {
+		r1 = rf(ctx, deletedBefore, deleteReviews)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
-// CreateToken provides a mock function with given fields: ctx, token
-func (_m *Repository) CreateToken(ctx context.Context, token *entity.Token) error {
+// CancelEmailChange provides a mock function with given fields: ctx, token
+func (_m *Repository) CancelEmailChange(ctx context.Context, token string) (*entity.EmailChange, error) {
 	ret := _m.Called(ctx, token)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.Token) error); ok {
+	var r0 *entity.EmailChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.EmailChange, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.EmailChange); ok {
 		r0 = rf(ctx, token)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.EmailChange)
+		}
 	}
 
-	return r0
-}
-
-// CreateUser provides a mock function with given fields: ctx, user
-func (_m *Repository) CreateUser(ctx context.Context, user *entity.User) error {
-	ret := _m.Called(ctx, user)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.User) error); ok {
-		r0 = rf(ctx, user)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
-// DeleteBook provides a mock function with given fields: ctx, articleID
-func (_m *Repository) DeleteBook(ctx context.Context, articleID int64) error {
-	ret := _m.Called(ctx, articleID)
+// ChangeUsername provides a mock function with given fields: ctx, userID, username
+func (_m *Repository) ChangeUsername(ctx context.Context, userID int64, username string) error {
+	ret := _m.Called(ctx, userID, username)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, articleID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, username)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -112,67 +98,51 @@ func (_m *Repository) DeleteBook(ctx context.Context, articleID int64) error {
 	return r0
 }
 
-// DeleteExpiredTokens provides a mock function with given fields: ctx
-func (_m *Repository) DeleteExpiredTokens(ctx context.Context) error {
-	ret := _m.Called(ctx)
+// CheckSuspension provides a mock function with given fields: ctx, userID
+func (_m *Repository) CheckSuspension(ctx context.Context, userID int64) ([]*entity.Suspension, error) {
+	ret := _m.Called(ctx, userID)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
-		r0 = rf(ctx)
-	} else {
-		r0 = ret.Error(0)
+	var r0 []*entity.Suspension
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*entity.Suspension, error)); ok {
+		return rf(ctx, userID)
 	}
-
-	return r0
-}
-
-// DeleteReview provides a mock function with given fields: ctx, reviewID, userID
-func (_m *Repository) DeleteReview(ctx context.Context, reviewID int64, userID int64) error {
-	ret := _m.Called(ctx, reviewID, userID)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
-		r0 = rf(ctx, reviewID, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*entity.Suspension); ok {
+		r0 = rf(ctx, userID)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Suspension)
+		}
 	}
 
-	return r0
-}
-
-// DeleteUser provides a mock function with given fields: ctx, userID
-func (_m *Repository) DeleteUser(ctx context.Context, userID int64) error {
-	ret := _m.Called(ctx, userID)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
-// GetBookByID provides a mock function with given fields: ctx, bookID
-func (_m *Repository) GetBookByID(ctx context.Context, bookID int64) (*entity.Book, error) {
-	ret := _m.Called(ctx, bookID)
+// ClaimDataExport provides a mock function with given fields: ctx, staleAfter
+func (_m *Repository) ClaimDataExport(ctx context.Context, staleAfter time.Duration) (*entity.DataExport, error) {
+	ret := _m.Called(ctx, staleAfter)
 
-	var r0 *entity.Book
+	var r0 *entity.DataExport
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64) (*entity.Book, error)); ok {
-		return rf(ctx, bookID)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) (*entity.DataExport, error)); ok {
+		return rf(ctx, staleAfter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64) *entity.Book); ok {
-		r0 = rf(ctx, bookID)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Duration) *entity.DataExport); ok {
+		r0 = rf(ctx, staleAfter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.Book)
+			r0 = ret.Get(0).(*entity.DataExport)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
-		r1 = rf(ctx, bookID)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Duration) error); ok {
+		r1 = rf(ctx, staleAfter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -180,95 +150,77 @@ func (_m *Repository) GetBookByID(ctx context.Context, bookID int64) (*entity.Bo
 	return r0, r1
 }
 
-// GetBooks provides a mock function with given fields: ctx, title, author, tags, filter
-func (_m *Repository) GetBooks(ctx context.Context, title *string, author *string, tags *[]string, filter util.Filter) ([]*entity.Book, *util.Metadata, error) {
-	ret := _m.Called(ctx, title, author, tags, filter)
+// ConfirmEmailChange provides a mock function with given fields: ctx, token
+func (_m *Repository) ConfirmEmailChange(ctx context.Context, token string) (*entity.EmailChange, error) {
+	ret := _m.Called(ctx, token)
 
-	var r0 []*entity.Book
-	var r1 *util.Metadata
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, *string, *string, *[]string, util.Filter) ([]*entity.Book, *util.Metadata, error)); ok {
-		return rf(ctx, title, author, tags, filter)
+	var r0 *entity.EmailChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.EmailChange, error)); ok {
+		return rf(ctx, token)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *string, *string, *[]string, util.Filter) []*entity.Book); ok {
-		r0 = rf(ctx, title, author, tags, filter)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.EmailChange); ok {
+		r0 = rf(ctx, token)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*entity.Book)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context, *string, *string, *[]string, util.Filter) *util.Metadata); ok {
-		r1 = rf(ctx, title, author, tags, filter)
-	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*util.Metadata)
+			r0 = ret.Get(0).(*entity.EmailChange)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, *string, *string, *[]string, util.Filter) error); ok {
-		r2 = rf(ctx, title, author, tags, filter)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// GetReviewsByBookID provides a mock function with given fields: ctx, bookID, filter
-func (_m *Repository) GetReviewsByBookID(ctx context.Context, bookID int64, filter util.Filter) ([]*entity.Review, *util.Metadata, error) {
-	ret := _m.Called(ctx, bookID, filter)
+// ConsumeOIDCState provides a mock function with given fields: ctx, state, provider
+func (_m *Repository) ConsumeOIDCState(ctx context.Context, state string, provider string) (*entity.OIDCState, error) {
+	ret := _m.Called(ctx, state, provider)
 
-	var r0 []*entity.Review
-	var r1 *util.Metadata
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) ([]*entity.Review, *util.Metadata, error)); ok {
-		return rf(ctx, bookID, filter)
+	var r0 *entity.OIDCState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entity.OIDCState, error)); ok {
+		return rf(ctx, state, provider)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) []*entity.Review); ok {
-		r0 = rf(ctx, bookID, filter)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entity.OIDCState); ok {
+		r0 = rf(ctx, state, provider)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*entity.Review)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context, int64, util.Filter) *util.Metadata); ok {
-		r1 = rf(ctx, bookID, filter)
-	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*util.Metadata)
+			r0 = ret.Get(0).(*entity.OIDCState)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, int64, util.Filter) error); ok {
-		r2 = rf(ctx, bookID, filter)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, state, provider)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// GetUserByCredentials provides a mock function with given fields: ctx, credentials
-func (_m *Repository) GetUserByCredentials(ctx context.Context, credentials string) (*entity.User, error) {
-	ret := _m.Called(ctx, credentials)
+// ConsumeScopedToken provides a mock function with given fields: ctx, token, scope
+func (_m *Repository) ConsumeScopedToken(ctx context.Context, token string, scope string) (*entity.User, error) {
+	ret := _m.Called(ctx, token, scope)
 
 	var r0 *entity.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
-		return rf(ctx, credentials)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entity.User, error)); ok {
+		return rf(ctx, token, scope)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
-		r0 = rf(ctx, credentials)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entity.User); ok {
+		r0 = rf(ctx, token, scope)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, credentials)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, token, scope)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -276,25 +228,23 @@ func (_m *Repository) GetUserByCredentials(ctx context.Context, credentials stri
 	return r0, r1
 }
 
-// GetUserByToken provides a mock function with given fields: ctx, token
-func (_m *Repository) GetUserByToken(ctx context.Context, token string) (*entity.User, error) {
-	ret := _m.Called(ctx, token)
+// CountScopedTokens provides a mock function with given fields: ctx, userID, scope, since
+func (_m *Repository) CountScopedTokens(ctx context.Context, userID int64, scope string, since time.Time) (int, error) {
+	ret := _m.Called(ctx, userID, scope, since)
 
-	var r0 *entity.User
+	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
-		return rf(ctx, token)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, time.Time) (int, error)); ok {
+		return rf(ctx, userID, scope, since)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
-		r0 = rf(ctx, token)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, time.Time) int); ok {
+		r0 = rf(ctx, userID, scope, since)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.User)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, token)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, scope, since)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -302,25 +252,23 @@ func (_m *Repository) GetUserByToken(ctx context.Context, token string) (*entity
 	return r0, r1
 }
 
-// GetUserByUsername provides a mock function with given fields: ctx, username
-func (_m *Repository) GetUserByUsername(ctx context.Context, username string) (*entity.User, error) {
-	ret := _m.Called(ctx, username)
+// CountUsersWithRole provides a mock function with given fields: ctx, role
+func (_m *Repository) CountUsersWithRole(ctx context.Context, role entity.Role) (int, error) {
+	ret := _m.Called(ctx, role)
 
-	var r0 *entity.User
+	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
-		return rf(ctx, username)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Role) (int, error)); ok {
+		return rf(ctx, role)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
-		r0 = rf(ctx, username)
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Role) int); ok {
+		r0 = rf(ctx, role)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.User)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, username)
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Role) error); ok {
+		r1 = rf(ctx, role)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -328,13 +276,13 @@ func (_m *Repository) GetUserByUsername(ctx context.Context, username string) (*
 	return r0, r1
 }
 
-// GrantRoleToUser provides a mock function with given fields: ctx, userID, role
-func (_m *Repository) GrantRoleToUser(ctx context.Context, userID int64, role entity.Role) error {
-	ret := _m.Called(ctx, userID, role)
+// CreateAPIKey provides a mock function with given fields: ctx, key
+func (_m *Repository) CreateAPIKey(ctx context.Context, key *entity.APIKey) error {
+	ret := _m.Called(ctx, key)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, entity.Role) error); ok {
-		r0 = rf(ctx, userID, role)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.APIKey) error); ok {
+		r0 = rf(ctx, key)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -342,13 +290,13 @@ func (_m *Repository) GrantRoleToUser(ctx context.Context, userID int64, role en
 	return r0
 }
 
-// NewSuspension provides a mock function with given fields: ctx, suspension
-func (_m *Repository) NewSuspension(ctx context.Context, suspension *entity.Suspension) error {
-	ret := _m.Called(ctx, suspension)
+// CreateBook provides a mock function with given fields: ctx, book
+func (_m *Repository) CreateBook(ctx context.Context, book *entity.Book) error {
+	ret := _m.Called(ctx, book)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.Suspension) error); ok {
-		r0 = rf(ctx, suspension)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Book) error); ok {
+		r0 = rf(ctx, book)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -356,13 +304,13 @@ func (_m *Repository) NewSuspension(ctx context.Context, suspension *entity.Susp
 	return r0
 }
 
-// RefreshBooksRating provides a mock function with given fields: ctx
-func (_m *Repository) RefreshBooksRating(ctx context.Context) error {
-	ret := _m.Called(ctx)
+// CreateContentRule provides a mock function with given fields: ctx, rule
+func (_m *Repository) CreateContentRule(ctx context.Context, rule *entity.ContentRule) error {
+	ret := _m.Called(ctx, rule)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ContentRule) error); ok {
+		r0 = rf(ctx, rule)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -370,13 +318,13 @@ func (_m *Repository) RefreshBooksRating(ctx context.Context) error {
 	return r0
 }
 
-// UpdateBook provides a mock function with given fields: ctx, book
-func (_m *Repository) UpdateBook(ctx context.Context, book *entity.Book) error {
-	ret := _m.Called(ctx, book)
+// CreateDataExport provides a mock function with given fields: ctx, export
+func (_m *Repository) CreateDataExport(ctx context.Context, export *entity.DataExport) error {
+	ret := _m.Called(ctx, export)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.Book) error); ok {
-		r0 = rf(ctx, book)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.DataExport) error); ok {
+		r0 = rf(ctx, export)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -384,13 +332,13 @@ func (_m *Repository) UpdateBook(ctx context.Context, book *entity.Book) error {
 	return r0
 }
 
-// UpdateReview provides a mock function with given fields: ctx, review
-func (_m *Repository) UpdateReview(ctx context.Context, review *entity.Review) error {
-	ret := _m.Called(ctx, review)
+// CreateEmailChange provides a mock function with given fields: ctx, change
+func (_m *Repository) CreateEmailChange(ctx context.Context, change *entity.EmailChange) error {
+	ret := _m.Called(ctx, change)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.Review) error); ok {
-		r0 = rf(ctx, review)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.EmailChange) error); ok {
+		r0 = rf(ctx, change)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -398,7 +346,1766 @@ func (_m *Repository) UpdateReview(ctx context.Context, review *entity.Review) e
 	return r0
 }
 
-// UpdateSuspension provides a mock function with given fields: ctx, suspension
+// CreateIdentity provides a mock function with given fields: ctx, identity
+func (_m *Repository) CreateIdentity(ctx context.Context, identity *entity.Identity) error {
+	ret := _m.Called(ctx, identity)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Identity) error); ok {
+		r0 = rf(ctx, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOIDCState provides a mock function with given fields: ctx, state
+func (_m *Repository) CreateOIDCState(ctx context.Context, state *entity.OIDCState) error {
+	ret := _m.Called(ctx, state)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.OIDCState) error); ok {
+		r0 = rf(ctx, state)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateRefreshToken provides a mock function with given fields: ctx, token
+func (_m *Repository) CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	ret := _m.Called(ctx, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RefreshToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateReview provides a mock function with given fields: ctx, review
+func (_m *Repository) CreateReview(ctx context.Context, review *entity.Review) error {
+	ret := _m.Called(ctx, review)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Review) error); ok {
+		r0 = rf(ctx, review)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateReviewReport provides a mock function with given fields: ctx, report
+func (_m *Repository) CreateReviewReport(ctx context.Context, report *entity.ReviewReport) error {
+	ret := _m.Called(ctx, report)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ReviewReport) error); ok {
+		r0 = rf(ctx, report)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateRole provides a mock function with given fields: ctx, role
+func (_m *Repository) CreateRole(ctx context.Context, role *entity.RoleDefinition) error {
+	ret := _m.Called(ctx, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RoleDefinition) error); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateToken provides a mock function with given fields: ctx, token
+func (_m *Repository) CreateToken(ctx context.Context, token *entity.Token) error {
+	ret := _m.Called(ctx, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Token) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUser provides a mock function with given fields: ctx, user
+func (_m *Repository) CreateUser(ctx context.Context, user *entity.User) error {
+	ret := _m.Called(ctx, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAPIKey provides a mock function with given fields: ctx, keyID, userID
+func (_m *Repository) DeleteAPIKey(ctx context.Context, keyID int64, userID int64) error {
+	ret := _m.Called(ctx, keyID, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, keyID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteBook provides a mock function with given fields: ctx, articleID
+func (_m *Repository) DeleteBook(ctx context.Context, articleID int64) error {
+	ret := _m.Called(ctx, articleID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, articleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteContentRule provides a mock function with given fields: ctx, ruleID
+func (_m *Repository) DeleteContentRule(ctx context.Context, ruleID int64) error {
+	ret := _m.Called(ctx, ruleID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, ruleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpiredDataExports provides a mock function with given fields: ctx
+func (_m *Repository) DeleteExpiredDataExports(ctx context.Context) ([]string, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteExpiredTokens provides a mock function with given fields: ctx
+func (_m *Repository) DeleteExpiredTokens(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteReview provides a mock function with given fields: ctx, reviewID, userID
+func (_m *Repository) DeleteReview(ctx context.Context, reviewID int64, userID int64) error {
+	ret := _m.Called(ctx, reviewID, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, reviewID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRole provides a mock function with given fields: ctx, name
+func (_m *Repository) DeleteRole(ctx context.Context, name entity.Role) error {
+	ret := _m.Called(ctx, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Role) error); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteScopedTokens provides a mock function with given fields: ctx, userID, scope
+func (_m *Repository) DeleteScopedTokens(ctx context.Context, userID int64, scope string) error {
+	ret := _m.Called(ctx, userID, scope)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, scope)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSession provides a mock function with given fields: ctx, sessionID, userID
+func (_m *Repository) DeleteSession(ctx context.Context, sessionID int64, userID int64) error {
+	ret := _m.Called(ctx, sessionID, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, sessionID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteStaleLoginAttempts provides a mock function with given fields: ctx, before
+func (_m *Repository) DeleteStaleLoginAttempts(ctx context.Context, before time.Time) error {
+	ret := _m.Called(ctx, before)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteToken provides a mock function with given fields: ctx, token
+func (_m *Repository) DeleteToken(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUser provides a mock function with given fields: ctx, userID
+func (_m *Repository) DeleteUser(ctx context.Context, userID int64) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUserTokens provides a mock function with given fields: ctx, userID, exceptToken
+func (_m *Repository) DeleteUserTokens(ctx context.Context, userID int64, exceptToken string) error {
+	ret := _m.Called(ctx, userID, exceptToken)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, exceptToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableTwoFactor provides a mock function with given fields: ctx, userID
+func (_m *Repository) DisableTwoFactor(ctx context.Context, userID int64) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableTwoFactor provides a mock function with given fields: ctx, userID, recoveryCodes
+func (_m *Repository) EnableTwoFactor(ctx context.Context, userID int64, recoveryCodes [][]byte) error {
+	ret := _m.Called(ctx, userID, recoveryCodes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, [][]byte) error); ok {
+		r0 = rf(ctx, userID, recoveryCodes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FinishDataExport provides a mock function with given fields: ctx, export
+func (_m *Repository) FinishDataExport(ctx context.Context, export *entity.DataExport) error {
+	ret := _m.Called(ctx, export)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.DataExport) error); ok {
+		r0 = rf(ctx, export)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FollowUser provides a mock function with given fields: ctx, followerID, followeeID
+func (_m *Repository) FollowUser(ctx context.Context, followerID int64, followeeID int64) error {
+	ret := _m.Called(ctx, followerID, followeeID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, followerID, followeeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAPIKeysByUserID provides a mock function with given fields: ctx, userID
+func (_m *Repository) GetAPIKeysByUserID(ctx context.Context, userID int64) ([]*entity.APIKey, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []*entity.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*entity.APIKey, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*entity.APIKey); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBookByID provides a mock function with given fields: ctx, bookID
+func (_m *Repository) GetBookByID(ctx context.Context, bookID int64) (*entity.Book, error) {
+	ret := _m.Called(ctx, bookID)
+
+	var r0 *entity.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*entity.Book, error)); ok {
+		return rf(ctx, bookID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *entity.Book); ok {
+		r0 = rf(ctx, bookID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, bookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBooks provides a mock function with given fields: ctx, title, author, tags, filter
+func (_m *Repository) GetBooks(ctx context.Context, title *string, author *string, tags *[]string, filter util.Filter) ([]*entity.Book, *util.Metadata, error) {
+	ret := _m.Called(ctx, title, author, tags, filter)
+
+	var r0 []*entity.Book
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *string, *string, *[]string, util.Filter) ([]*entity.Book, *util.Metadata, error)); ok {
+		return rf(ctx, title, author, tags, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *string, *string, *[]string, util.Filter) []*entity.Book); ok {
+		r0 = rf(ctx, title, author, tags, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *string, *string, *[]string, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, title, author, tags, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *string, *string, *[]string, util.Filter) error); ok {
+		r2 = rf(ctx, title, author, tags, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetContentRule provides a mock function with given fields: ctx, ruleID
+func (_m *Repository) GetContentRule(ctx context.Context, ruleID int64) (*entity.ContentRule, error) {
+	ret := _m.Called(ctx, ruleID)
+
+	var r0 *entity.ContentRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*entity.ContentRule, error)); ok {
+		return rf(ctx, ruleID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *entity.ContentRule); ok {
+		r0 = rf(ctx, ruleID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ContentRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, ruleID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetContentRules provides a mock function with given fields: ctx
+func (_m *Repository) GetContentRules(ctx context.Context) ([]*entity.ContentRule, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*entity.ContentRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*entity.ContentRule, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*entity.ContentRule); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ContentRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDataExport provides a mock function with given fields: ctx, exportID
+func (_m *Repository) GetDataExport(ctx context.Context, exportID int64) (*entity.DataExport, error) {
+	ret := _m.Called(ctx, exportID)
+
+	var r0 *entity.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*entity.DataExport, error)); ok {
+		return rf(ctx, exportID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *entity.DataExport); ok {
+		r0 = rf(ctx, exportID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, exportID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEmailChangesByUserID provides a mock function with given fields: ctx, userID
+func (_m *Repository) GetEmailChangesByUserID(ctx context.Context, userID int64) ([]*entity.EmailChange, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []*entity.EmailChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*entity.EmailChange, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*entity.EmailChange); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.EmailChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFeed provides a mock function with given fields: ctx, userID, after, limit
+func (_m *Repository) GetFeed(ctx context.Context, userID int64, after *entity.FeedCursor, limit int) ([]*entity.Activity, error) {
+	ret := _m.Called(ctx, userID, after, limit)
+
+	var r0 []*entity.Activity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *entity.FeedCursor, int) ([]*entity.Activity, error)); ok {
+		return rf(ctx, userID, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *entity.FeedCursor, int) []*entity.Activity); ok {
+		r0 = rf(ctx, userID, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Activity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *entity.FeedCursor, int) error); ok {
+		r1 = rf(ctx, userID, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFollowers provides a mock function with given fields: ctx, userID, filter
+func (_m *Repository) GetFollowers(ctx context.Context, userID int64, filter util.Filter) ([]*entity.Follow, *util.Metadata, error) {
+	ret := _m.Called(ctx, userID, filter)
+
+	var r0 []*entity.Follow
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) ([]*entity.Follow, *util.Metadata, error)); ok {
+		return rf(ctx, userID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) []*entity.Follow); ok {
+		r0 = rf(ctx, userID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Follow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, userID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, util.Filter) error); ok {
+		r2 = rf(ctx, userID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetFollowing provides a mock function with given fields: ctx, userID, filter
+func (_m *Repository) GetFollowing(ctx context.Context, userID int64, filter util.Filter) ([]*entity.Follow, *util.Metadata, error) {
+	ret := _m.Called(ctx, userID, filter)
+
+	var r0 []*entity.Follow
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) ([]*entity.Follow, *util.Metadata, error)); ok {
+		return rf(ctx, userID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) []*entity.Follow); ok {
+		r0 = rf(ctx, userID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Follow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, userID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, util.Filter) error); ok {
+		r2 = rf(ctx, userID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetIdentitiesByUserID provides a mock function with given fields: ctx, userID
+func (_m *Repository) GetIdentitiesByUserID(ctx context.Context, userID int64) ([]*entity.Identity, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []*entity.Identity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*entity.Identity, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*entity.Identity); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Identity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLoginAttempts provides a mock function with given fields: ctx, keys
+func (_m *Repository) GetLoginAttempts(ctx context.Context, keys []string) ([]*entity.LoginAttempt, error) {
+	ret := _m.Called(ctx, keys)
+
+	var r0 []*entity.LoginAttempt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]*entity.LoginAttempt, error)); ok {
+		return rf(ctx, keys)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*entity.LoginAttempt); ok {
+		r0 = rf(ctx, keys)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.LoginAttempt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, keys)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRefreshToken provides a mock function with given fields: ctx, token
+func (_m *Repository) GetRefreshToken(ctx context.Context, token string) (*entity.RefreshToken, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *entity.RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.RefreshToken, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.RefreshToken); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RefreshToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReportedReviews provides a mock function with given fields: ctx, search, filter
+func (_m *Repository) GetReportedReviews(ctx context.Context, search entity.ReportSearch, filter util.Filter) ([]*entity.ReportedReview, *util.Metadata, error) {
+	ret := _m.Called(ctx, search, filter)
+
+	var r0 []*entity.ReportedReview
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReportSearch, util.Filter) ([]*entity.ReportedReview, *util.Metadata, error)); ok {
+		return rf(ctx, search, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReportSearch, util.Filter) []*entity.ReportedReview); ok {
+		r0 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ReportedReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ReportSearch, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.ReportSearch, util.Filter) error); ok {
+		r2 = rf(ctx, search, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetReviewByID provides a mock function with given fields: ctx, reviewID
+func (_m *Repository) GetReviewByID(ctx context.Context, reviewID int64) (*entity.Review, error) {
+	ret := _m.Called(ctx, reviewID)
+
+	var r0 *entity.Review
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*entity.Review, error)); ok {
+		return rf(ctx, reviewID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *entity.Review); ok {
+		r0 = rf(ctx, reviewID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, reviewID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReviewReports provides a mock function with given fields: ctx, reviewID
+func (_m *Repository) GetReviewReports(ctx context.Context, reviewID int64) ([]*entity.ReviewReport, error) {
+	ret := _m.Called(ctx, reviewID)
+
+	var r0 []*entity.ReviewReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*entity.ReviewReport, error)); ok {
+		return rf(ctx, reviewID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*entity.ReviewReport); ok {
+		r0 = rf(ctx, reviewID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ReviewReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, reviewID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReviewReportsByReporterID provides a mock function with given fields: ctx, reporterID
+func (_m *Repository) GetReviewReportsByReporterID(ctx context.Context, reporterID int64) ([]*entity.ReviewReport, error) {
+	ret := _m.Called(ctx, reporterID)
+
+	var r0 []*entity.ReviewReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*entity.ReviewReport, error)); ok {
+		return rf(ctx, reporterID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*entity.ReviewReport); ok {
+		r0 = rf(ctx, reporterID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ReviewReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, reporterID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReviews provides a mock function with given fields: ctx, search, filter
+func (_m *Repository) GetReviews(ctx context.Context, search entity.ReviewSearch, filter util.Filter) ([]*entity.Review, *util.Metadata, error) {
+	ret := _m.Called(ctx, search, filter)
+
+	var r0 []*entity.Review
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReviewSearch, util.Filter) ([]*entity.Review, *util.Metadata, error)); ok {
+		return rf(ctx, search, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReviewSearch, util.Filter) []*entity.Review); ok {
+		r0 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ReviewSearch, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.ReviewSearch, util.Filter) error); ok {
+		r2 = rf(ctx, search, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetReviewsByBookID provides a mock function with given fields: ctx, bookID, filter
+func (_m *Repository) GetReviewsByBookID(ctx context.Context, bookID int64, filter util.Filter) ([]*entity.Review, *util.Metadata, error) {
+	ret := _m.Called(ctx, bookID, filter)
+
+	var r0 []*entity.Review
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) ([]*entity.Review, *util.Metadata, error)); ok {
+		return rf(ctx, bookID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) []*entity.Review); ok {
+		r0 = rf(ctx, bookID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, bookID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, util.Filter) error); ok {
+		r2 = rf(ctx, bookID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetReviewsByUserID provides a mock function with given fields: ctx, userID
+func (_m *Repository) GetReviewsByUserID(ctx context.Context, userID int64) ([]*entity.Review, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []*entity.Review
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*entity.Review, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*entity.Review); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRole provides a mock function with given fields: ctx, name
+func (_m *Repository) GetRole(ctx context.Context, name entity.Role) (*entity.RoleDefinition, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *entity.RoleDefinition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Role) (*entity.RoleDefinition, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Role) *entity.RoleDefinition); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RoleDefinition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Role) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRoleHistory provides a mock function with given fields: ctx, userID, filter
+func (_m *Repository) GetRoleHistory(ctx context.Context, userID int64, filter util.Filter) ([]*entity.RoleChange, *util.Metadata, error) {
+	ret := _m.Called(ctx, userID, filter)
+
+	var r0 []*entity.RoleChange
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) ([]*entity.RoleChange, *util.Metadata, error)); ok {
+		return rf(ctx, userID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) []*entity.RoleChange); ok {
+		r0 = rf(ctx, userID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.RoleChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, userID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, util.Filter) error); ok {
+		r2 = rf(ctx, userID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRoles provides a mock function with given fields: ctx
+func (_m *Repository) GetRoles(ctx context.Context) ([]*entity.RoleDefinition, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*entity.RoleDefinition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*entity.RoleDefinition, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*entity.RoleDefinition); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.RoleDefinition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessionsByUserID provides a mock function with given fields: ctx, userID
+func (_m *Repository) GetSessionsByUserID(ctx context.Context, userID int64) ([]*entity.Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []*entity.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*entity.Session, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*entity.Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSuspension provides a mock function with given fields: ctx, suspensionID
+func (_m *Repository) GetSuspension(ctx context.Context, suspensionID int64) (*entity.Suspension, error) {
+	ret := _m.Called(ctx, suspensionID)
+
+	var r0 *entity.Suspension
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*entity.Suspension, error)); ok {
+		return rf(ctx, suspensionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *entity.Suspension); ok {
+		r0 = rf(ctx, suspensionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Suspension)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, suspensionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSuspensions provides a mock function with given fields: ctx, search, filter
+func (_m *Repository) GetSuspensions(ctx context.Context, search entity.SuspensionSearch, filter util.Filter) ([]*entity.Suspension, *util.Metadata, error) {
+	ret := _m.Called(ctx, search, filter)
+
+	var r0 []*entity.Suspension
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.SuspensionSearch, util.Filter) ([]*entity.Suspension, *util.Metadata, error)); ok {
+		return rf(ctx, search, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.SuspensionSearch, util.Filter) []*entity.Suspension); ok {
+		r0 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Suspension)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.SuspensionSearch, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.SuspensionSearch, util.Filter) error); ok {
+		r2 = rf(ctx, search, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetSuspensionsByUserID provides a mock function with given fields: ctx, userID
+func (_m *Repository) GetSuspensionsByUserID(ctx context.Context, userID int64) ([]*entity.Suspension, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []*entity.Suspension
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*entity.Suspension, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*entity.Suspension); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Suspension)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenStatus provides a mock function with given fields: ctx, jti, userID
+func (_m *Repository) GetTokenStatus(ctx context.Context, jti string, userID int64) (*entity.TokenStatus, error) {
+	ret := _m.Called(ctx, jti, userID)
+
+	var r0 *entity.TokenStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (*entity.TokenStatus, error)); ok {
+		return rf(ctx, jti, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) *entity.TokenStatus); ok {
+		r0 = rf(ctx, jti, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.TokenStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, jti, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTwoFactor provides a mock function with given fields: ctx, userID
+func (_m *Repository) GetTwoFactor(ctx context.Context, userID int64) (*entity.TwoFactor, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 *entity.TwoFactor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*entity.TwoFactor, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *entity.TwoFactor); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.TwoFactor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByAPIKey provides a mock function with given fields: ctx, key
+func (_m *Repository) GetUserByAPIKey(ctx context.Context, key string) (*entity.User, error) {
+	ret := _m.Called(ctx, key)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByCredentials provides a mock function with given fields: ctx, credentials
+func (_m *Repository) GetUserByCredentials(ctx context.Context, credentials string) (*entity.User, error) {
+	ret := _m.Called(ctx, credentials)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
+		return rf(ctx, credentials)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
+		r0 = rf(ctx, credentials)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, credentials)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByID provides a mock function with given fields: ctx, userID
+func (_m *Repository) GetUserByID(ctx context.Context, userID int64) (*entity.User, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*entity.User, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *entity.User); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByIdentity provides a mock function with given fields: ctx, provider, subject
+func (_m *Repository) GetUserByIdentity(ctx context.Context, provider string, subject string) (*entity.User, error) {
+	ret := _m.Called(ctx, provider, subject)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entity.User, error)); ok {
+		return rf(ctx, provider, subject)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entity.User); ok {
+		r0 = rf(ctx, provider, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provider, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByScopedToken provides a mock function with given fields: ctx, token, scope
+func (_m *Repository) GetUserByScopedToken(ctx context.Context, token string, scope string) (*entity.User, error) {
+	ret := _m.Called(ctx, token, scope)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entity.User, error)); ok {
+		return rf(ctx, token, scope)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entity.User); ok {
+		r0 = rf(ctx, token, scope)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, token, scope)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByToken provides a mock function with given fields: ctx, token
+func (_m *Repository) GetUserByToken(ctx context.Context, token string) (*entity.User, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByUsername provides a mock function with given fields: ctx, username
+func (_m *Repository) GetUserByUsername(ctx context.Context, username string) (*entity.User, error) {
+	ret := _m.Called(ctx, username)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserStats provides a mock function with given fields: ctx, userID
+func (_m *Repository) GetUserStats(ctx context.Context, userID int64) (*entity.UserStats, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 *entity.UserStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*entity.UserStats, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *entity.UserStats); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.UserStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsernameRedirect provides a mock function with given fields: ctx, username
+func (_m *Repository) GetUsernameRedirect(ctx context.Context, username string) (string, error) {
+	ret := _m.Called(ctx, username)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, username)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsers provides a mock function with given fields: ctx, search, filter
+func (_m *Repository) GetUsers(ctx context.Context, search entity.UserSearch, filter util.Filter) ([]*entity.UserOverview, *util.Metadata, error) {
+	ret := _m.Called(ctx, search, filter)
+
+	var r0 []*entity.UserOverview
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserSearch, util.Filter) ([]*entity.UserOverview, *util.Metadata, error)); ok {
+		return rf(ctx, search, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserSearch, util.Filter) []*entity.UserOverview); ok {
+		r0 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.UserOverview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.UserSearch, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.UserSearch, util.Filter) error); ok {
+		r2 = rf(ctx, search, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GrantRoleToUser provides a mock function with given fields: ctx, change
+func (_m *Repository) GrantRoleToUser(ctx context.Context, change *entity.RoleChange) error {
+	ret := _m.Called(ctx, change)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RoleChange) error); ok {
+		r0 = rf(ctx, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HideReview provides a mock function with given fields: ctx, review
+func (_m *Repository) HideReview(ctx context.Context, review *entity.Review) error {
+	ret := _m.Called(ctx, review)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Review) error); ok {
+		r0 = rf(ctx, review)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LiftSuspension provides a mock function with given fields: ctx, suspension
+func (_m *Repository) LiftSuspension(ctx context.Context, suspension *entity.Suspension) error {
+	ret := _m.Called(ctx, suspension)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Suspension) error); ok {
+		r0 = rf(ctx, suspension)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSuspension provides a mock function with given fields: ctx, suspension
+func (_m *Repository) NewSuspension(ctx context.Context, suspension *entity.Suspension) error {
+	ret := _m.Called(ctx, suspension)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Suspension) error); ok {
+		r0 = rf(ctx, suspension)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordLoginFailure provides a mock function with given fields: ctx, key, threshold, window
+func (_m *Repository) RecordLoginFailure(ctx context.Context, key string, threshold int, window time.Duration) (*entity.LoginAttempt, error) {
+	ret := _m.Called(ctx, key, threshold, window)
+
+	var r0 *entity.LoginAttempt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, time.Duration) (*entity.LoginAttempt, error)); ok {
+		return rf(ctx, key, threshold, window)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, time.Duration) *entity.LoginAttempt); ok {
+		r0 = rf(ctx, key, threshold, window)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.LoginAttempt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, time.Duration) error); ok {
+		r1 = rf(ctx, key, threshold, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshBooksRating provides a mock function with given fields: ctx
+func (_m *Repository) RefreshBooksRating(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshUserStats provides a mock function with given fields: ctx
+func (_m *Repository) RefreshUserStats(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveReview provides a mock function with given fields: ctx, review
+func (_m *Repository) RemoveReview(ctx context.Context, review *entity.Review) error {
+	ret := _m.Called(ctx, review)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Review) error); ok {
+		r0 = rf(ctx, review)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetLoginAttempts provides a mock function with given fields: ctx, key
+func (_m *Repository) ResetLoginAttempts(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveReviewReports provides a mock function with given fields: ctx, resolution
+func (_m *Repository) ResolveReviewReports(ctx context.Context, resolution *entity.ReportResolution) error {
+	ret := _m.Called(ctx, resolution)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ReportResolution) error); ok {
+		r0 = rf(ctx, resolution)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreReview provides a mock function with given fields: ctx, review
+func (_m *Repository) RestoreReview(ctx context.Context, review *entity.Review) error {
+	ret := _m.Called(ctx, review)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Review) error); ok {
+		r0 = rf(ctx, review)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreUser provides a mock function with given fields: ctx, userID
+func (_m *Repository) RestoreUser(ctx context.Context, userID int64) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RotateRefreshToken provides a mock function with given fields: ctx, used, access, refresh
+func (_m *Repository) RotateRefreshToken(ctx context.Context, used *entity.RefreshToken, access *entity.Token, refresh *entity.RefreshToken) error {
+	ret := _m.Called(ctx, used, access, refresh)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RefreshToken, *entity.Token, *entity.RefreshToken) error); ok {
+		r0 = rf(ctx, used, access, refresh)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTwoFactorSecret provides a mock function with given fields: ctx, userID, secret
+func (_m *Repository) SetTwoFactorSecret(ctx context.Context, userID int64, secret string) error {
+	ret := _m.Called(ctx, userID, secret)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserAvatar provides a mock function with given fields: ctx, userID, avatarKey
+func (_m *Repository) SetUserAvatar(ctx context.Context, userID int64, avatarKey *string) (*string, error) {
+	ret := _m.Called(ctx, userID, avatarKey)
+
+	var r0 *string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *string) (*string, error)); ok {
+		return rf(ctx, userID, avatarKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *string) *string); ok {
+		r0 = rf(ctx, userID, avatarKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *string) error); ok {
+		r1 = rf(ctx, userID, avatarKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnfollowUser provides a mock function with given fields: ctx, followerID, followeeID
+func (_m *Repository) UnfollowUser(ctx context.Context, followerID int64, followeeID int64) error {
+	ret := _m.Called(ctx, followerID, followeeID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, followerID, followeeID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateBook provides a mock function with given fields: ctx, book
+func (_m *Repository) UpdateBook(ctx context.Context, book *entity.Book) error {
+	ret := _m.Called(ctx, book)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Book) error); ok {
+		r0 = rf(ctx, book)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateContentRule provides a mock function with given fields: ctx, rule
+func (_m *Repository) UpdateContentRule(ctx context.Context, rule *entity.ContentRule) error {
+	ret := _m.Called(ctx, rule)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ContentRule) error); ok {
+		r0 = rf(ctx, rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateReview provides a mock function with given fields: ctx, review
+func (_m *Repository) UpdateReview(ctx context.Context, review *entity.Review) error {
+	ret := _m.Called(ctx, review)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Review) error); ok {
+		r0 = rf(ctx, review)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRole provides a mock function with given fields: ctx, role
+func (_m *Repository) UpdateRole(ctx context.Context, role *entity.RoleDefinition) error {
+	ret := _m.Called(ctx, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RoleDefinition) error); ok {
+		r0 = rf(ctx, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSuspension provides a mock function with given fields: ctx, suspension
 func (_m *Repository) UpdateSuspension(ctx context.Context, suspension *entity.Suspension) error {
 	ret := _m.Called(ctx, suspension)
 
@@ -426,6 +2133,34 @@ func (_m *Repository) UpdateUser(ctx context.Context, user *entity.User) error {
 	return r0
 }
 
+// UseRecoveryCode provides a mock function with given fields: ctx, userID, code
+func (_m *Repository) UseRecoveryCode(ctx context.Context, userID int64, code string) error {
+	ret := _m.Called(ctx, userID, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseTOTPStep provides a mock function with given fields: ctx, userID, step
+func (_m *Repository) UseTOTPStep(ctx context.Context, userID int64, step int64) error {
+	ret := _m.Called(ctx, userID, step)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRepository(t interface {
@@ -0,0 +1,207 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+func (p *Postgres) CreateAPIKey(ctx context.Context, key *entity.APIKey) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (
+			user_id,
+			name,
+			prefix,
+			hash,
+			scopes,
+			expiry
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`, apiKeysTable)
+
+	err := p.Pool.QueryRow(ctx, query, key.UserID, key.Name, key.Prefix, key.Hash, scopesToStrings(key.Scopes), key.Expiry).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *Postgres) GetAPIKeysByUserID(ctx context.Context, userID int64) ([]*entity.APIKey, error) {
+	query := fmt.Sprintf(`
+		SELECT 
+			id,
+			user_id,
+			name,
+			prefix,
+			scopes,
+			expiry,
+			last_used_at,
+			created_at
+		FROM %s
+		WHERE 
+			user_id = $1
+		ORDER BY created_at DESC, id DESC
+	`, apiKeysTable)
+
+	rows, err := p.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	keys := make([]*entity.APIKey, 0)
+
+	for rows.Next() {
+		var key entity.APIKey
+		var scopes []string
+
+		err = rows.Scan(
+			&key.ID,
+			&key.UserID,
+			&key.Name,
+			&key.Prefix,
+			&scopes,
+			&key.Expiry,
+			&key.LastUsedAt,
+			&key.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		key.Scopes = stringsToScopes(scopes)
+		keys = append(keys, &key)
+	}
+
+	return keys, rows.Err()
+}
+
+// GetUserByAPIKey returns owner of the key together with the key itself
+// and tracks last usage of the key
+func (p *Postgres) GetUserByAPIKey(ctx context.Context, key string) (*entity.User, error) {
+	query := fmt.Sprintf(`
+		WITH k AS (
+			UPDATE %[2]s SET
+				last_used_at = $2
+			WHERE hash = $1
+			AND (expiry IS NULL OR expiry > $2)
+			RETURNING id, user_id, name, prefix, scopes, expiry, created_at
+		)
+		SELECT 
+			u.id,
+			u.username,
+			u.email,
+			u.first_name,
+			u.last_name,
+			u.role,
+			u.activated,
+			u.totp_enabled,
+			(SELECT EXISTS(SELECT * FROM %[3]s s WHERE s.user_id=u.id AND s.lifted_at IS NULL AND (s.created_at + s.expires_in) > $2)) AS suspended,
+			k.id,
+			k.name,
+			k.prefix,
+			k.scopes,
+			k.expiry,
+			k.created_at,
+			COALESCE((SELECT r.permissions FROM %[4]s r WHERE r.name = u.role), '{}')
+		FROM %[1]s u
+		INNER JOIN k
+		ON u.id = k.user_id
+		WHERE u.deleted_at IS NULL
+	`, usersTable, apiKeysTable, suspensionsTable, rolesTable)
+
+	now := time.Now()
+	user := new(entity.User)
+	apiKey := &entity.APIKey{LastUsedAt: &now}
+	var roleString string
+	var scopes []string
+	var permissions []string
+
+	err := p.Pool.QueryRow(ctx, query, util.HashToken(key), now).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.FirstName,
+		&user.LastName,
+		&roleString,
+		&user.Activated,
+		&user.TwoFactor,
+		&user.Suspended,
+		&apiKey.ID,
+		&apiKey.Name,
+		&apiKey.Prefix,
+		&scopes,
+		&apiKey.Expiry,
+		&apiKey.CreatedAt,
+		&permissions,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	role, ok := entity.StringToRole(roleString)
+	if !ok {
+		return nil, errors.New("error while parsing role")
+	}
+
+	user.Role = role
+	user.Permissions = stringsToPermissions(permissions)
+	apiKey.UserID = user.ID
+	apiKey.Scopes = stringsToScopes(scopes)
+	user.APIKey = apiKey
+
+	return user, nil
+}
+
+func (p *Postgres) DeleteAPIKey(ctx context.Context, keyID int64, userID int64) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE 
+			id = $1
+		AND
+			user_id = $2
+	`, apiKeysTable)
+
+	tag, err := p.Pool.Exec(ctx, query, keyID, userID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func scopesToStrings(scopes []entity.APIScope) []string {
+	result := make([]string, len(scopes))
+	for i, scope := range scopes {
+		result[i] = string(scope)
+	}
+
+	return result
+}
+
+func stringsToScopes(scopes []string) []entity.APIScope {
+	result := make([]entity.APIScope, len(scopes))
+	for i, scope := range scopes {
+		result[i] = entity.APIScope(scope)
+	}
+
+	return result
+}
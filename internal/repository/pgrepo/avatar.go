@@ -0,0 +1,44 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/repository"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// SetUserAvatar replaces avatar key of the user and returns the previous one,
+// so blobs of the old avatar can be removed. Nil key removes the avatar
+func (p *Postgres) SetUserAvatar(ctx context.Context, userID int64, avatarKey *string) (*string, error) {
+	query := fmt.Sprintf(`
+		UPDATE %[1]s u SET
+			avatar_key = $2,
+			updated_at = NOW()
+		FROM (
+			SELECT id, avatar_key
+			FROM %[1]s
+			WHERE 
+				id = $1
+			FOR UPDATE
+		) old
+		WHERE 
+			u.id = old.id
+		RETURNING old.avatar_key
+	`, usersTable)
+
+	var previous *string
+
+	err := p.Pool.QueryRow(ctx, query, userID, avatarKey).Scan(&previous)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return previous, nil
+}
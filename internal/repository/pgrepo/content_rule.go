@@ -0,0 +1,165 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const contentRuleColumns = `
+	id,
+	kind,
+	pattern,
+	threshold,
+	severity,
+	enabled,
+	created_by,
+	created_at,
+	updated_at
+`
+
+func (p *Postgres) CreateContentRule(ctx context.Context, rule *entity.ContentRule) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (
+			kind,
+			pattern,
+			threshold,
+			severity,
+			enabled,
+			created_by
+		)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING %s
+	`, contentRulesTable, contentRuleColumns)
+
+	return scanContentRule(p.Pool.QueryRow(ctx, query,
+		rule.Kind,
+		rule.Pattern,
+		rule.Threshold,
+		rule.Severity,
+		rule.Enabled,
+		rule.CreatedBy,
+	), rule)
+}
+
+func (p *Postgres) GetContentRule(ctx context.Context, ruleID int64) (*entity.ContentRule, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE
+			id = $1
+	`, contentRuleColumns, contentRulesTable)
+
+	var rule entity.ContentRule
+
+	err := scanContentRule(p.Pool.QueryRow(ctx, query, ruleID), &rule)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &rule, nil
+}
+
+func (p *Postgres) GetContentRules(ctx context.Context) ([]*entity.ContentRule, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		ORDER BY id
+	`, contentRuleColumns, contentRulesTable)
+
+	rows, err := p.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	rules := make([]*entity.ContentRule, 0)
+
+	for rows.Next() {
+		var rule entity.ContentRule
+		err = scanContentRule(rows, &rule)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, &rule)
+	}
+
+	return rules, rows.Err()
+}
+
+// UpdateContentRule saves every field of the rule except its kind
+func (p *Postgres) UpdateContentRule(ctx context.Context, rule *entity.ContentRule) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			pattern = $1,
+			threshold = $2,
+			severity = $3,
+			enabled = $4,
+			updated_at = NOW()
+		WHERE
+			id = $5
+		RETURNING %s
+	`, contentRulesTable, contentRuleColumns)
+
+	err := scanContentRule(p.Pool.QueryRow(ctx, query,
+		rule.Pattern,
+		rule.Threshold,
+		rule.Severity,
+		rule.Enabled,
+		rule.ID,
+	), rule)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return repository.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Postgres) DeleteContentRule(ctx context.Context, ruleID int64) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE
+			id = $1
+	`, contentRulesTable)
+
+	tag, err := p.Pool.Exec(ctx, query, ruleID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func scanContentRule(row pgx.Row, rule *entity.ContentRule) error {
+	return row.Scan(
+		&rule.ID,
+		&rule.Kind,
+		&rule.Pattern,
+		&rule.Threshold,
+		&rule.Severity,
+		&rule.Enabled,
+		&rule.CreatedBy,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+}
@@ -0,0 +1,165 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const dataExportColumns = `
+	id,
+	user_id,
+	status,
+	blob_key,
+	error,
+	expiry,
+	created_at,
+	updated_at
+`
+
+func scanDataExport(row pgx.Row) (*entity.DataExport, error) {
+	export := new(entity.DataExport)
+
+	err := row.Scan(
+		&export.ID,
+		&export.UserID,
+		&export.Status,
+		&export.BlobKey,
+		&export.Error,
+		&export.Expiry,
+		&export.CreatedAt,
+		&export.UpdatedAt,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return export, nil
+}
+
+// CreateDataExport returns repository.ErrDuplicateRecord if another export
+// of the user is still in progress
+func (p *Postgres) CreateDataExport(ctx context.Context, export *entity.DataExport) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (
+			user_id,
+			status
+		)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`, dataExportsTable)
+
+	err := p.Pool.QueryRow(ctx, query, export.UserID, export.Status).Scan(&export.ID, &export.CreatedAt, &export.UpdatedAt)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+
+	return nil
+}
+
+func (p *Postgres) GetDataExport(ctx context.Context, exportID int64) (*entity.DataExport, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE 
+			id = $1
+	`, dataExportColumns, dataExportsTable)
+
+	return scanDataExport(p.Pool.QueryRow(ctx, query, exportID))
+}
+
+// ClaimDataExport marks the oldest pending export as running and returns it.
+// Exports left running longer than staleAfter (e.g. by crashed instance) are
+// claimed again. Concurrent workers never claim the same export
+func (p *Postgres) ClaimDataExport(ctx context.Context, staleAfter time.Duration) (*entity.DataExport, error) {
+	query := fmt.Sprintf(`
+		UPDATE %[2]s SET
+			status = '%[3]s',
+			updated_at = NOW()
+		WHERE id = (
+			SELECT id
+			FROM %[2]s
+			WHERE 
+				status = '%[4]s'
+			OR
+				(status = '%[3]s' AND updated_at < $1)
+			ORDER BY created_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING %[1]s
+	`, dataExportColumns, dataExportsTable, entity.ExportRunning, entity.ExportPending)
+
+	return scanDataExport(p.Pool.QueryRow(ctx, query, time.Now().Add(-staleAfter)))
+}
+
+// FinishDataExport stores result of the export: status, archive key, error
+// and expiry
+func (p *Postgres) FinishDataExport(ctx context.Context, export *entity.DataExport) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			status = $2,
+			blob_key = $3,
+			error = $4,
+			expiry = $5,
+			updated_at = NOW()
+		WHERE 
+			id = $1
+	`, dataExportsTable)
+
+	tag, err := p.Pool.Exec(ctx, query, export.ID, export.Status, export.BlobKey, export.Error, export.Expiry)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// DeleteExpiredDataExports removes expired exports and returns keys of their
+// archives, so they can be removed from storage
+func (p *Postgres) DeleteExpiredDataExports(ctx context.Context) ([]string, error) {
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE 
+			expiry < $1
+		RETURNING blob_key
+	`, dataExportsTable)
+
+	rows, err := p.Pool.Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	keys := make([]string, 0)
+
+	for rows.Next() {
+		var key *string
+
+		err = rows.Scan(&key)
+		if err != nil {
+			return nil, err
+		}
+
+		if key != nil {
+			keys = append(keys, *key)
+		}
+	}
+
+	return keys, rows.Err()
+}
@@ -10,9 +10,27 @@ const (
 	usersTable         = "users"
 	booksTable         = "books"
 	tokensTable        = "tokens"
+	refreshTokensTable = "refresh_tokens"
+	recoveryCodesTable = "recovery_codes"
+	loginAttemptsTable = "login_attempts"
+	identitiesTable    = "identities"
+	oidcStatesTable    = "oidc_states"
+	apiKeysTable       = "api_keys"
+	emailChangesTable  = "email_changes"
+	redirectsTable     = "username_redirects"
+	tokenDenylistTable = "token_denylist"
 	reviewsTable       = "reviews"
 	suspensionsTable   = "suspensions"
+	dataExportsTable   = "data_exports"
+	followsTable       = "follows"
+	activitiesTable    = "activities"
+	rolesTable         = "roles"
+	roleChangesTable   = "role_changes"
+	reportsTable       = "review_reports"
+	contentRulesTable  = "content_rules"
 	booksAvgRatingView = "books_avg_rating_view"
+
+	userReviewStatsView = "user_review_stats_view"
 )
 
 type Postgres struct {
@@ -0,0 +1,163 @@
+package pgrepo
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AnonymizeDeletedUsers erases personal data of users deleted before the given
+// time and everything that allows to log in as them. The row itself is kept
+// as "Deleted user", so reviews and moderation history stay consistent.
+// Reviews are removed as well if deleteReviews is set. Returned avatar keys
+// of anonymized users are no longer referenced and can be removed from storage
+func (p *Postgres) AnonymizeDeletedUsers(ctx context.Context, deletedBefore time.Time, deleteReviews bool) ([]string, error) {
+	anonymizeQuery := fmt.Sprintf(`
+		UPDATE %[1]s u SET
+			username = NULL,
+			email = NULL,
+			first_name = 'Deleted',
+			last_name = 'user',
+			password_hash = '',
+			activated = false,
+			totp_enabled = false,
+			totp_secret = NULL,
+			totp_last_step = NULL,
+			bio = NULL,
+			location = NULL,
+			website = NULL,
+			avatar_key = NULL,
+			anonymized_at = NOW(),
+			updated_at = NOW()
+		FROM (
+			SELECT id, avatar_key
+			FROM %[1]s
+			WHERE 
+				deleted_at < $1
+			AND anonymized_at IS NULL
+			FOR UPDATE
+		) old
+		WHERE 
+			u.id = old.id
+		RETURNING u.id, old.avatar_key
+	`, usersTable)
+
+	related := []string{
+		tokensTable,
+		recoveryCodesTable,
+		identitiesTable,
+		apiKeysTable,
+		emailChangesTable,
+		redirectsTable,
+		activitiesTable,
+	}
+
+	deleteFollowsQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE follower_id = ANY($1)
+		OR followee_id = ANY($1)
+	`, followsTable)
+
+	deleteFollowedActivitiesQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE target_user_id = ANY($1)
+	`, activitiesTable)
+
+	// Archives of built exports are removed from storage by the next cleanup
+	// of expired exports
+	expireExportsQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			expiry = NOW()
+		WHERE user_id = ANY($1)
+	`, dataExportsTable)
+
+	deleteExportsQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE user_id = ANY($1)
+		AND blob_key IS NULL
+	`, dataExportsTable)
+
+	if deleteReviews {
+		related = append(related, reviewsTable)
+	}
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, anonymizeQuery, deletedBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, 0)
+	avatarKeys := make([]string, 0)
+
+	for rows.Next() {
+		var userID int64
+		var avatarKey *string
+
+		err = rows.Scan(&userID, &avatarKey)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		userIDs = append(userIDs, userID)
+		if avatarKey != nil {
+			avatarKeys = append(avatarKeys, *avatarKey)
+		}
+	}
+
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(userIDs) == 0 {
+		return avatarKeys, nil
+	}
+
+	for _, table := range related {
+		query := fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE user_id = ANY($1)
+		`, table)
+
+		_, err = tx.Exec(ctx, query, userIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec(ctx, deleteFollowsQuery, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, deleteFollowedActivitiesQuery, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, deleteExportsQuery, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, expireExportsQuery, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return avatarKeys, nil
+}
@@ -0,0 +1,239 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+)
+
+// CreateEmailChange replaces pending change of the user, if any. Confirmed
+// change which can still be reverted is not replaced, ErrEditConflict is
+// returned instead
+func (p *Postgres) CreateEmailChange(ctx context.Context, change *entity.EmailChange) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s (
+			user_id,
+			email,
+			confirm_hash,
+			cancel_hash,
+			expiry
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET
+			email = EXCLUDED.email,
+			old_email = NULL,
+			confirm_hash = EXCLUDED.confirm_hash,
+			cancel_hash = EXCLUDED.cancel_hash,
+			expiry = EXCLUDED.expiry,
+			confirmed_at = NULL,
+			created_at = NOW()
+		WHERE
+			%[1]s.confirmed_at IS NULL
+		OR
+			%[1]s.expiry <= NOW()
+		RETURNING created_at
+	`, emailChangesTable)
+
+	err := p.Pool.QueryRow(ctx, query, change.UserID, change.Email, change.ConfirmHash, change.CancelHash, change.Expiry).Scan(&change.CreatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return repository.ErrEditConflict
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange applies pending change with the token sent to the new
+// address. The address is considered verified, so the user becomes activated.
+// Previous address is kept with the change, so it can be reverted until expiry
+func (p *Postgres) ConfirmEmailChange(ctx context.Context, token string) (*entity.EmailChange, error) {
+	confirmQuery := fmt.Sprintf(`
+		UPDATE %s AS ec SET
+			confirm_hash = NULL,
+			old_email = u.email,
+			confirmed_at = NOW()
+		FROM %s AS u
+		WHERE
+			ec.confirm_hash = $1
+		AND
+			ec.expiry > $2
+		AND
+			u.id = ec.user_id
+		RETURNING ec.user_id, ec.email, ec.old_email, ec.expiry, ec.confirmed_at, ec.created_at
+	`, emailChangesTable, usersTable)
+
+	updateQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			email = $2,
+			activated = true,
+			updated_at = NOW()
+		WHERE 
+			id = $1
+	`, usersTable)
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	change := new(entity.EmailChange)
+
+	err = tx.QueryRow(ctx, confirmQuery, util.HashToken(token), time.Now()).Scan(
+		&change.UserID,
+		&change.Email,
+		&change.OldEmail,
+		&change.Expiry,
+		&change.ConfirmedAt,
+		&change.CreatedAt,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec(ctx, updateQuery, change.UserID, change.Email)
+	if err != nil {
+		return nil, uniqueViolation(err)
+	}
+
+	return change, tx.Commit(ctx)
+}
+
+// GetEmailChangesByUserID returns pending or revertible change of the user,
+// the list is empty when there is none
+func (p *Postgres) GetEmailChangesByUserID(ctx context.Context, userID int64) ([]*entity.EmailChange, error) {
+	query := fmt.Sprintf(`
+		SELECT 
+			user_id,
+			email,
+			old_email,
+			expiry,
+			confirmed_at,
+			created_at
+		FROM %s
+		WHERE user_id = $1
+	`, emailChangesTable)
+
+	rows, err := p.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	changes := make([]*entity.EmailChange, 0)
+
+	for rows.Next() {
+		var change entity.EmailChange
+		err = rows.Scan(
+			&change.UserID,
+			&change.Email,
+			&change.OldEmail,
+			&change.Expiry,
+			&change.ConfirmedAt,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, &change)
+	}
+
+	return changes, rows.Err()
+}
+
+// CancelEmailChange drops pending change with the token sent to the old
+// address. Confirmed change is reverted, the old address is restored unless
+// the email was changed meanwhile
+func (p *Postgres) CancelEmailChange(ctx context.Context, token string) (*entity.EmailChange, error) {
+	deleteQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE
+			cancel_hash = $1
+		AND
+			(confirmed_at IS NULL OR expiry > $2)
+		RETURNING user_id, email, old_email, expiry, confirmed_at, created_at
+	`, emailChangesTable)
+
+	revertQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			email = $3,
+			updated_at = NOW()
+		WHERE 
+			id = $1
+		AND
+			email = $2
+	`, usersTable)
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	change := new(entity.EmailChange)
+
+	err = tx.QueryRow(ctx, deleteQuery, util.HashToken(token), time.Now()).Scan(
+		&change.UserID,
+		&change.Email,
+		&change.OldEmail,
+		&change.Expiry,
+		&change.ConfirmedAt,
+		&change.CreatedAt,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	if change.ConfirmedAt != nil && change.OldEmail != nil {
+		_, err = tx.Exec(ctx, revertQuery, change.UserID, change.Email, *change.OldEmail)
+		if err != nil {
+			return nil, uniqueViolation(err)
+		}
+	}
+
+	return change, tx.Commit(ctx)
+}
+
+func uniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+		return repository.ErrDuplicateRecord
+	}
+
+	return err
+}
+
+func foreignKeyViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+		return repository.ErrForeignKey
+	}
+
+	return err
+}
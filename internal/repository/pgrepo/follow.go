@@ -0,0 +1,240 @@
+package pgrepo
+
+import (
+	"context"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/pkg/util"
+	"time"
+)
+
+// FollowUser is idempotent, activity is recorded only for a new follow
+func (p *Postgres) FollowUser(ctx context.Context, followerID int64, followeeID int64) error {
+	query := fmt.Sprintf(`
+		WITH follow AS (
+			INSERT INTO %[1]s (follower_id, followee_id)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+			RETURNING follower_id, followee_id
+		)
+		INSERT INTO %[2]s (user_id, kind, target_user_id)
+		SELECT follower_id, $3, followee_id FROM follow
+	`, followsTable, activitiesTable)
+
+	_, err := p.Pool.Exec(ctx, query, followerID, followeeID, entity.ActivityUserFollowed)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *Postgres) UnfollowUser(ctx context.Context, followerID int64, followeeID int64) error {
+	query := fmt.Sprintf(`
+		WITH follow AS (
+			DELETE FROM %[1]s
+			WHERE 
+				follower_id = $1
+			AND 
+				followee_id = $2
+			RETURNING follower_id, followee_id
+		)
+		DELETE FROM %[2]s a
+		USING follow f
+		WHERE 
+			a.user_id = f.follower_id
+		AND 
+			a.target_user_id = f.followee_id
+		AND 
+			a.kind = $3
+	`, followsTable, activitiesTable)
+
+	_, err := p.Pool.Exec(ctx, query, followerID, followeeID, entity.ActivityUserFollowed)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetFollowers returns users following the given one
+func (p *Postgres) GetFollowers(ctx context.Context, userID int64, filter util.Filter) ([]*entity.Follow, *util.Metadata, error) {
+	return p.getFollows(ctx, "followee_id", "follower_id", userID, filter)
+}
+
+// GetFollowing returns users followed by the given one
+func (p *Postgres) GetFollowing(ctx context.Context, userID int64, filter util.Filter) ([]*entity.Follow, *util.Metadata, error) {
+	return p.getFollows(ctx, "follower_id", "followee_id", userID, filter)
+}
+
+// getFollows lists the other side of follows where column matches the user,
+// deleted accounts are skipped
+func (p *Postgres) getFollows(ctx context.Context, column string, other string, userID int64, filter util.Filter) ([]*entity.Follow, *util.Metadata, error) {
+	totalQuery := fmt.Sprintf(`
+	SELECT 
+		count(*) AS total_count
+	FROM %[1]s f
+	JOIN %[2]s u ON u.id = f.%[4]s
+	WHERE 
+		f.%[3]s = $1
+	AND 
+		u.deleted_at IS NULL
+	`, followsTable, usersTable, column, other)
+
+	dataQuery := fmt.Sprintf(`
+	SELECT 
+		u.id,
+		u.username,
+		u.first_name,
+		u.last_name,
+		f.created_at
+	FROM %[1]s f
+	JOIN %[2]s u ON u.id = f.%[4]s
+	WHERE 
+		f.%[3]s = $1
+	AND 
+		u.deleted_at IS NULL
+	ORDER BY %[5]s %[6]s, u.id ASC
+	LIMIT $2 OFFSET $3
+	`, followsTable, usersTable, column, other, filter.FormatSort(), filter.SortDirection())
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var totalCount int
+	follows := make([]*entity.Follow, 0)
+
+	err = tx.QueryRow(ctx, totalQuery, userID).Scan(&totalCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := tx.Query(ctx, dataQuery, userID, filter.Limit(), filter.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var follow entity.Follow
+		err = rows.Scan(
+			&follow.UserID,
+			&follow.Username,
+			&follow.FirstName,
+			&follow.LastName,
+			&follow.FollowedAt,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		follows = append(follows, &follow)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	metadata := filter.CalculateMetadata(totalCount)
+
+	return follows, &metadata, nil
+}
+
+// GetFeed returns activities of users followed by the given one, newest
+// first, starting after the cursor. Suspended and deleted users and hidden
+// reviews are skipped
+func (p *Postgres) GetFeed(ctx context.Context, userID int64, after *entity.FeedCursor, limit int) ([]*entity.Activity, error) {
+	query := fmt.Sprintf(`
+		SELECT 
+			a.id,
+			a.kind,
+			a.user_id,
+			u.username,
+			a.created_at,
+			r.id,
+			r.content,
+			r.rating,
+			r.user_id,
+			r.book_id,
+			r.created_at,
+			r.updated_at,
+			t.username
+		FROM %[1]s a
+		JOIN %[2]s f ON f.followee_id = a.user_id AND f.follower_id = $1
+		JOIN %[3]s u ON u.id = a.user_id
+		LEFT JOIN %[4]s r ON r.id = a.review_id
+		LEFT JOIN %[3]s t ON t.id = a.target_user_id AND t.deleted_at IS NULL
+		WHERE 
+			u.deleted_at IS NULL
+		AND 
+			NOT EXISTS(SELECT * FROM %[5]s s WHERE s.user_id = a.user_id AND s.lifted_at IS NULL AND (s.created_at + s.expires_in) > $2)
+		AND 
+			(a.target_user_id IS NULL OR t.id IS NOT NULL)
+		AND 
+			(a.review_id IS NULL OR r.visibility = '%[6]s')
+		AND 
+			($3::timestamptz IS NULL OR (a.created_at, a.id) < ($3, $4))
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT $5
+	`, activitiesTable, followsTable, usersTable, reviewsTable, suspensionsTable, entity.ReviewVisible)
+
+	var afterCreatedAt *time.Time
+	var afterID int64
+	if after != nil {
+		afterCreatedAt = &after.CreatedAt
+		afterID = after.ID
+	}
+
+	rows, err := p.Pool.Query(ctx, query, userID, time.Now(), afterCreatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	activities := make([]*entity.Activity, 0)
+
+	for rows.Next() {
+		var activity entity.Activity
+		var reviewID, reviewUserID, reviewBookID *int64
+		var reviewCreatedAt, reviewUpdatedAt *time.Time
+		review := entity.Review{}
+
+		err = rows.Scan(
+			&activity.ID,
+			&activity.Kind,
+			&activity.UserID,
+			&activity.Username,
+			&activity.CreatedAt,
+			&reviewID,
+			&review.Content,
+			&review.Rating,
+			&reviewUserID,
+			&reviewBookID,
+			&reviewCreatedAt,
+			&reviewUpdatedAt,
+			&activity.FollowedUsername,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if reviewID != nil {
+			review.ID = *reviewID
+			review.UserID = *reviewUserID
+			review.BookID = *reviewBookID
+			review.CreatedAt = *reviewCreatedAt
+			review.UpdatedAt = *reviewUpdatedAt
+			activity.Review = &review
+		}
+
+		activities = append(activities, &activity)
+	}
+
+	return activities, rows.Err()
+}
@@ -0,0 +1,183 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+func (p *Postgres) CreateIdentity(ctx context.Context, identity *entity.Identity) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (
+			user_id,
+			provider,
+			subject,
+			email
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, identitiesTable)
+
+	err := p.Pool.QueryRow(ctx, query, identity.UserID, identity.Provider, identity.Subject, identity.Email).Scan(&identity.ID, &identity.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *Postgres) GetIdentitiesByUserID(ctx context.Context, userID int64) ([]*entity.Identity, error) {
+	query := fmt.Sprintf(`
+		SELECT 
+			id,
+			user_id,
+			provider,
+			subject,
+			email,
+			created_at
+		FROM %s
+		WHERE user_id = $1
+		ORDER BY created_at, id
+	`, identitiesTable)
+
+	rows, err := p.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	identities := make([]*entity.Identity, 0)
+
+	for rows.Next() {
+		var identity entity.Identity
+		err = rows.Scan(
+			&identity.ID,
+			&identity.UserID,
+			&identity.Provider,
+			&identity.Subject,
+			&identity.Email,
+			&identity.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		identities = append(identities, &identity)
+	}
+
+	return identities, rows.Err()
+}
+
+func (p *Postgres) GetUserByIdentity(ctx context.Context, provider string, subject string) (*entity.User, error) {
+	query := fmt.Sprintf(`
+		SELECT 
+			u.id,
+			u.username,
+			u.email,
+			u.first_name,
+			u.last_name,
+			u.role,
+			u.activated,
+			u.totp_enabled,
+			u.deleted_at,
+			u.created_at,
+			u.updated_at
+		FROM %[1]s u
+		INNER JOIN %[2]s i
+		ON u.id = i.user_id
+		WHERE i.provider = $1
+		AND i.subject = $2
+	`, usersTable, identitiesTable)
+
+	user := new(entity.User)
+	var roleString string
+
+	err := p.Pool.QueryRow(ctx, query, provider, subject).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.FirstName,
+		&user.LastName,
+		&roleString,
+		&user.Activated,
+		&user.TwoFactor,
+		&user.DeletedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	role, ok := entity.StringToRole(roleString)
+	if !ok {
+		return nil, errors.New("error while parsing role")
+	}
+
+	user.Role = role
+
+	return user, nil
+}
+
+func (p *Postgres) CreateOIDCState(ctx context.Context, state *entity.OIDCState) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (
+			hash,
+			provider,
+			nonce,
+			code_verifier,
+			expiry
+		)
+		VALUES ($1, $2, $3, $4, $5)
+	`, oidcStatesTable)
+
+	_, err := p.Pool.Exec(ctx, query, state.Hash, state.Provider, state.Nonce, state.CodeVerifier, state.Expiry)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ConsumeOIDCState deletes the state, so the callback can not be replayed
+func (p *Postgres) ConsumeOIDCState(ctx context.Context, state string, provider string) (*entity.OIDCState, error) {
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE hash = $1
+		AND provider = $2
+		AND expiry > $3
+		RETURNING hash, provider, nonce, code_verifier, expiry
+	`, oidcStatesTable)
+
+	s := new(entity.OIDCState)
+
+	err := p.Pool.QueryRow(ctx, query, util.HashToken(state), provider, time.Now()).Scan(
+		&s.Hash,
+		&s.Provider,
+		&s.Nonce,
+		&s.CodeVerifier,
+		&s.Expiry,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
@@ -0,0 +1,111 @@
+package pgrepo
+
+import (
+	"context"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"time"
+)
+
+func (p *Postgres) GetLoginAttempts(ctx context.Context, keys []string) ([]*entity.LoginAttempt, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			key,
+			failures,
+			last_failure_at,
+			locked_until
+		FROM %s
+		WHERE 
+			key = ANY($1)
+	`, loginAttemptsTable)
+
+	rows, err := p.Pool.Query(ctx, query, keys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := make([]*entity.LoginAttempt, 0, len(keys))
+	for rows.Next() {
+		attempt := new(entity.LoginAttempt)
+
+		err = rows.Scan(&attempt.Key, &attempt.Failures, &attempt.LastFailureAt, &attempt.LockedUntil)
+		if err != nil {
+			return nil, err
+		}
+
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, rows.Err()
+}
+
+// RecordLoginFailure increments the counter in a single statement, so instances
+// of the app share it. Counter starts over when the last failure is older than
+// window, and the key is locked for window once threshold is reached
+func (p *Postgres) RecordLoginFailure(ctx context.Context, key string, threshold int, window time.Duration) (*entity.LoginAttempt, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO %[1]s AS a (
+			key,
+			failures,
+			last_failure_at,
+			locked_until
+		)
+		VALUES ($1, 1, $2, CASE WHEN 1 >= $3 THEN $2::timestamptz + $4::interval END)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN a.last_failure_at < $2::timestamptz - $4::interval THEN 1 ELSE a.failures + 1 END,
+			last_failure_at = $2,
+			locked_until = CASE 
+				WHEN (CASE WHEN a.last_failure_at < $2::timestamptz - $4::interval THEN 1 ELSE a.failures + 1 END) >= $3
+				THEN $2::timestamptz + $4::interval 
+				ELSE a.locked_until 
+			END
+		RETURNING key, failures, last_failure_at, locked_until
+	`, loginAttemptsTable)
+
+	attempt := new(entity.LoginAttempt)
+
+	err := p.Pool.QueryRow(ctx, query, key, time.Now(), threshold, window).Scan(
+		&attempt.Key,
+		&attempt.Failures,
+		&attempt.LastFailureAt,
+		&attempt.LockedUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return attempt, nil
+}
+
+func (p *Postgres) ResetLoginAttempts(ctx context.Context, key string) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE 
+			key = $1
+	`, loginAttemptsTable)
+
+	_, err := p.Pool.Exec(ctx, query, key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *Postgres) DeleteStaleLoginAttempts(ctx context.Context, before time.Time) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE 
+			last_failure_at < $1
+		AND
+			(locked_until IS NULL OR locked_until < $1)
+	`, loginAttemptsTable)
+
+	_, err := p.Pool.Exec(ctx, query, before)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
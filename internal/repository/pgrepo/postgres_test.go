@@ -0,0 +1,96 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/config"
+	"one-lab-final/internal/entity"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// newTestPostgres connects to the database from TEST_DATABASE_URL and applies
+// migrations. Tests are skipped when the variable is not set
+func newTestPostgres(t *testing.T) *Postgres {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	m, err := migrate.New("file://../../../migrations", "pgx"+dsn[strings.Index(dsn, "://"):])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatal(err)
+	}
+
+	pool, err := pgxpool.Connect(context.Background(), dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(pool.Close)
+
+	return New(pool, &config.Config{})
+}
+
+// createTestUser creates activated user with unique username and email
+func createTestUser(t *testing.T, p *Postgres, role entity.Role) *entity.User {
+	t.Helper()
+
+	name := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	email := name + "@example.com"
+
+	user := &entity.User{
+		Username:  &name,
+		Email:     &email,
+		FirstName: &name,
+		LastName:  &name,
+		Role:      role,
+		Activated: true,
+	}
+
+	err := p.CreateUser(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return user
+}
+
+// createTestReview creates book with review of the author in the given state
+func createTestReview(t *testing.T, p *Postgres, author *entity.User, visibility entity.ReviewVisibility) *entity.Review {
+	t.Helper()
+
+	title := "Book"
+	book := &entity.Book{Title: &title, Author: &title, Description: &title, Tags: &[]string{}, Year: 2000}
+
+	err := p.CreateBook(context.Background(), book)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := "Buy cheap copies"
+	var rating int64 = 10
+	review := &entity.Review{Content: &content, Rating: &rating, UserID: author.ID, BookID: book.ID, Visibility: visibility}
+
+	err = p.CreateReview(context.Background(), review)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return review
+}
@@ -0,0 +1,339 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const reportColumns = `
+	id,
+	review_id,
+	reporter_id,
+	category,
+	comment,
+	state,
+	resolution,
+	resolved_by,
+	resolved_at,
+	created_at
+`
+
+// Conditions of moderation queue shared by count and data queries
+const reportListConditions = `
+	state = $1
+	AND
+		($2::text IS NULL OR category = $2)
+`
+
+func (p *Postgres) CreateReviewReport(ctx context.Context, report *entity.ReviewReport) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (
+			review_id,
+			reporter_id,
+			category,
+			comment
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING %s
+	`, reportsTable, reportColumns)
+
+	err := scanReport(p.Pool.QueryRow(ctx, query, report.ReviewID, report.ReporterID, report.Category, report.Comment), report)
+	if err != nil {
+		return foreignKeyViolation(uniqueViolation(err))
+	}
+
+	return nil
+}
+
+// GetReviewReports returns every report of the review, resolved included
+func (p *Postgres) GetReviewReports(ctx context.Context, reviewID int64) ([]*entity.ReviewReport, error) {
+	return p.getReports(ctx, "review_id", reviewID)
+}
+
+// GetReviewReportsByReporterID returns reports filed by the user
+func (p *Postgres) GetReviewReportsByReporterID(ctx context.Context, reporterID int64) ([]*entity.ReviewReport, error) {
+	return p.getReports(ctx, "reporter_id", reporterID)
+}
+
+func (p *Postgres) getReports(ctx context.Context, column string, id int64) ([]*entity.ReviewReport, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE 
+			%s = $1
+		ORDER BY created_at, id
+	`, reportColumns, reportsTable, column)
+
+	rows, err := p.Pool.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	reports := make([]*entity.ReviewReport, 0)
+
+	for rows.Next() {
+		var report entity.ReviewReport
+		err = scanReport(rows, &report)
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, &report)
+	}
+
+	return reports, rows.Err()
+}
+
+// GetReportedReviews returns moderation queue, reports in the searched state
+// are grouped by review
+func (p *Postgres) GetReportedReviews(ctx context.Context, search entity.ReportSearch, filter util.Filter) ([]*entity.ReportedReview, *util.Metadata, error) {
+	totalQuery := fmt.Sprintf(`
+	SELECT 
+		count(DISTINCT review_id) AS total_count
+	FROM %s
+	WHERE %s
+	`, reportsTable, reportListConditions)
+
+	dataQuery := fmt.Sprintf(`
+		SELECT 
+			r.id,
+			r.content,
+			r.rating,
+			r.user_id,
+			r.book_id,
+			r.visibility,
+			r.hidden_by,
+			r.hidden_reason,
+			r.hidden_at,
+			r.deleted_at,
+			r.created_at,
+			r.updated_at,
+			g.reports,
+			g.categories,
+			g.created_at,
+			g.last_reported_at
+		FROM (
+			SELECT 
+				review_id,
+				count(*) AS reports,
+				array_agg(DISTINCT category) AS categories,
+				min(created_at) AS created_at,
+				max(created_at) AS last_reported_at
+			FROM %s
+			WHERE %s
+			GROUP BY review_id
+		) g
+		JOIN %s r ON r.id = g.review_id
+		ORDER BY g.%s %s, r.id ASC
+		LIMIT $3 OFFSET $4
+	`, reportsTable, reportListConditions, reviewsTable, filter.FormatSort(), filter.SortDirection())
+
+	args := []any{search.State, search.Category}
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var totalCount int
+	items := make([]*entity.ReportedReview, 0)
+
+	err = tx.QueryRow(ctx, totalQuery, args...).Scan(&totalCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := tx.Query(ctx, dataQuery, append(args, filter.Limit(), filter.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		item := entity.ReportedReview{State: search.State}
+		var categories []string
+
+		err = rows.Scan(
+			&item.Review.ID,
+			&item.Review.Content,
+			&item.Review.Rating,
+			&item.Review.UserID,
+			&item.Review.BookID,
+			&item.Review.Visibility,
+			&item.Review.HiddenBy,
+			&item.Review.HiddenReason,
+			&item.Review.HiddenAt,
+			&item.Review.DeletedAt,
+			&item.Review.CreatedAt,
+			&item.Review.UpdatedAt,
+			&item.Reports,
+			&categories,
+			&item.FirstReportedAt,
+			&item.LastReportedAt,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, category := range categories {
+			item.Categories = append(item.Categories, entity.ReportCategory(category))
+		}
+
+		items = append(items, &item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	metadata := filter.CalculateMetadata(totalCount)
+
+	return items, &metadata, nil
+}
+
+// ResolveReviewReports closes open reports of the review and applies the
+// action in one transaction. Reports are closed first, so concurrent
+// resolutions of the same review can't both succeed. ErrEditConflict is
+// returned when the review can't be hidden anymore
+func (p *Postgres) ResolveReviewReports(ctx context.Context, resolution *entity.ReportResolution) error {
+	authorQuery := fmt.Sprintf(`
+		SELECT user_id
+		FROM %s
+		WHERE 
+			id = $1
+		FOR UPDATE
+	`, reviewsTable)
+
+	resolveQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			state = $1,
+			resolution = $2,
+			resolved_by = $3,
+			resolved_at = NOW()
+		WHERE 
+			review_id = $4
+		AND
+			state = $5
+	`, reportsTable)
+
+	hideQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			visibility = $1,
+			hidden_by = $2,
+			hidden_reason = $3,
+			hidden_at = NOW()
+		WHERE 
+			id = $4
+		AND
+			visibility IN ($5, $6)
+	`, reviewsTable)
+
+	// Removed review is kept, so resolved reports are not lost with it
+	removeQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			visibility = $1,
+			hidden_by = $2,
+			hidden_reason = $3,
+			deleted_at = NOW()
+		WHERE 
+			id = $4
+		AND
+			visibility NOT IN ($1, $5)
+	`, reviewsTable)
+
+	suspendQuery := fmt.Sprintf(`
+		INSERT INTO %s (
+			reason,
+			user_id,
+			moderator_id,
+			expires_in
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING %s
+	`, suspensionsTable, suspensionColumns)
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var authorID int64
+
+	err = tx.QueryRow(ctx, authorQuery, resolution.ReviewID).Scan(&authorID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return repository.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	tag, err := tx.Exec(ctx, resolveQuery,
+		resolution.Action.State(),
+		resolution.Action,
+		resolution.ModeratorID,
+		resolution.ReviewID,
+		entity.ReportOpen,
+	)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	switch resolution.Action {
+	case entity.ReportActionHide:
+		tag, err = tx.Exec(ctx, hideQuery, entity.ReviewHidden, resolution.ModeratorID, resolution.Reason, resolution.ReviewID, entity.ReviewVisible, entity.ReviewPending)
+		if err == nil && tag.RowsAffected() == 0 {
+			return repository.ErrEditConflict
+		}
+	case entity.ReportActionDelete:
+		_, err = tx.Exec(ctx, removeQuery, entity.ReviewRemoved, resolution.ModeratorID, resolution.Reason, resolution.ReviewID, entity.ReviewDeleted)
+	}
+	if err != nil {
+		return err
+	}
+
+	if suspension := resolution.Suspension; suspension != nil {
+		suspension.UserID = authorID
+		suspension.ModeratorID = resolution.ModeratorID
+
+		err = scanSuspension(tx.QueryRow(ctx, suspendQuery, suspension.Reason, suspension.UserID, suspension.ModeratorID, suspension.ExpiresIn), suspension)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func scanReport(row pgx.Row, report *entity.ReviewReport) error {
+	return row.Scan(
+		&report.ID,
+		&report.ReviewID,
+		&report.ReporterID,
+		&report.Category,
+		&report.Comment,
+		&report.State,
+		&report.Resolution,
+		&report.ResolvedBy,
+		&report.ResolvedAt,
+		&report.CreatedAt,
+	)
+}
@@ -0,0 +1,109 @@
+package pgrepo
+
+import (
+	"context"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveReviewReportsDelete(t *testing.T) {
+	p := newTestPostgres(t)
+	ctx := context.Background()
+
+	author := createTestUser(t, p, entity.USER)
+	reporter := createTestUser(t, p, entity.USER)
+	moderator := createTestUser(t, p, entity.MODERATOR)
+
+	review := createTestReview(t, p, author, entity.ReviewVisible)
+
+	report := &entity.ReviewReport{ReviewID: review.ID, ReporterID: reporter.ID, Category: entity.ReportSpam}
+	assert.Nil(t, p.CreateReviewReport(ctx, report))
+
+	err := p.ResolveReviewReports(ctx, &entity.ReportResolution{
+		ReviewID:    review.ID,
+		ModeratorID: moderator.ID,
+		Action:      entity.ReportActionDelete,
+		Reason:      "Advertisement",
+	})
+	assert.Nil(t, err)
+
+	removed, err := p.GetReviewByID(ctx, review.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, entity.ReviewRemoved, removed.Visibility)
+	assert.Equal(t, &moderator.ID, removed.HiddenBy)
+	assert.NotNil(t, removed.DeletedAt)
+
+	reports, err := p.GetReviewReports(ctx, review.ID)
+	assert.Nil(t, err)
+	if assert.Len(t, reports, 1, "reports must survive removal of the review") {
+		assert.Equal(t, entity.ReportActioned, reports[0].State)
+		assert.Equal(t, &moderator.ID, reports[0].ResolvedBy)
+	}
+}
+
+func TestResolveReviewReportsHide(t *testing.T) {
+	p := newTestPostgres(t)
+	ctx := context.Background()
+
+	author := createTestUser(t, p, entity.USER)
+	reporter := createTestUser(t, p, entity.USER)
+	moderator := createTestUser(t, p, entity.MODERATOR)
+
+	tests := []struct {
+		Name          string
+		Visibility    entity.ReviewVisibility
+		Expected      error
+		ExpectedState entity.ReportState
+		ExpectedVis   entity.ReviewVisibility
+	}{
+		{
+			Name:          "Visible review hidden",
+			Visibility:    entity.ReviewVisible,
+			ExpectedState: entity.ReportActioned,
+			ExpectedVis:   entity.ReviewHidden,
+		},
+		{
+			Name:          "Pending review hidden",
+			Visibility:    entity.ReviewPending,
+			ExpectedState: entity.ReportActioned,
+			ExpectedVis:   entity.ReviewHidden,
+		},
+		{
+			Name:          "Hidden review is left as is",
+			Visibility:    entity.ReviewHidden,
+			Expected:      repository.ErrEditConflict,
+			ExpectedState: entity.ReportOpen,
+			ExpectedVis:   entity.ReviewHidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			review := createTestReview(t, p, author, test.Visibility)
+
+			report := &entity.ReviewReport{ReviewID: review.ID, ReporterID: reporter.ID, Category: entity.ReportSpam}
+			assert.Nil(t, p.CreateReviewReport(ctx, report))
+
+			err := p.ResolveReviewReports(ctx, &entity.ReportResolution{
+				ReviewID:    review.ID,
+				ModeratorID: moderator.ID,
+				Action:      entity.ReportActionHide,
+				Reason:      "Advertisement",
+			})
+			assert.Equal(t, test.Expected, err)
+
+			current, err := p.GetReviewByID(ctx, review.ID)
+			assert.Nil(t, err)
+			assert.Equal(t, test.ExpectedVis, current.Visibility)
+
+			reports, err := p.GetReviewReports(ctx, review.ID)
+			assert.Nil(t, err)
+			if assert.Len(t, reports, 1) {
+				assert.Equal(t, test.ExpectedState, reports[0].State)
+			}
+		})
+	}
+}
@@ -5,23 +5,61 @@ import (
 	"errors"
 	"fmt"
 	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
 	"one-lab-final/pkg/util"
 	"time"
+
+	"github.com/jackc/pgx/v4"
 )
 
+const reviewColumns = `
+	id,
+	content,
+	rating,
+	user_id,
+	book_id,
+	visibility,
+	hidden_by,
+	hidden_reason,
+	hidden_at,
+	deleted_at,
+	created_at,
+	updated_at
+`
+
+// CreateReview saves the review as visible unless its visibility is set,
+// hidden reason explains why the review is not public
 func (p *Postgres) CreateReview(ctx context.Context, review *entity.Review) error {
 	query := fmt.Sprintf(`
-		INSERT INTO %s (
-			content,
-			rating,
-			user_id,
-			book_id
+		WITH review AS (
+			INSERT INTO %[1]s (
+				content,
+				rating,
+				user_id,
+				book_id,
+				visibility,
+				hidden_reason,
+				hidden_at
+			)
+			VALUES ($1, $2, $3, $4, COALESCE(NULLIF($6::text, ''), $8), $7, CASE WHEN $7::text IS NULL THEN NULL ELSE NOW() END)
+			RETURNING id, user_id
+		), activity AS (
+			INSERT INTO %[2]s (user_id, kind, review_id)
+			SELECT user_id, $5, id FROM review
 		)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id
-		`, reviewsTable)
+		SELECT id FROM review
+		`, reviewsTable, activitiesTable)
 
-	err := p.Pool.QueryRow(ctx, query, review.Content, review.Rating, review.UserID, review.BookID).Scan(&review.ID)
+	err := p.Pool.QueryRow(ctx, query,
+		review.Content,
+		review.Rating,
+		review.UserID,
+		review.BookID,
+		entity.ActivityReviewCreated,
+		review.Visibility,
+		review.HiddenReason,
+		entity.ReviewVisible,
+	).Scan(&review.ID)
 	if err != nil {
 		return err
 	}
@@ -29,28 +67,114 @@ func (p *Postgres) CreateReview(ctx context.Context, review *entity.Review) erro
 	return nil
 }
 
+func (p *Postgres) GetReviewByID(ctx context.Context, reviewID int64) (*entity.Review, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE 
+			id = $1
+	`, reviewColumns, reviewsTable)
+
+	var review entity.Review
+
+	err := scanReview(p.Pool.QueryRow(ctx, query, reviewID), &review)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &review, nil
+}
+
+// GetReviewsByBookID returns public reviews of the book, hidden ones are skipped
 func (p *Postgres) GetReviewsByBookID(ctx context.Context, bookID int64, filter util.Filter) ([]*entity.Review, *util.Metadata, error) {
 	totalQuery := fmt.Sprintf(`
 	SELECT 
 		count(*) AS total_count
 	FROM %s
-	WHERE book_id = $1	
+	WHERE book_id = $1 AND visibility = $2
 	`, reviewsTable)
 
 	dataQuery := fmt.Sprintf(`
+	SELECT %[1]s
+	FROM %[2]s
+	WHERE book_id = $1 AND visibility = $2
+	ORDER BY %[3]s %[4]s
+	LIMIT $3 OFFSET $4
+`, reviewColumns, reviewsTable, filter.FormatSort(), filter.SortDirection())
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var totalCount int
+	reviews := make([]*entity.Review, 0)
+
+	err = tx.QueryRow(ctx, totalQuery, bookID, entity.ReviewVisible).Scan(&totalCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := tx.Query(ctx, dataQuery, bookID, entity.ReviewVisible, filter.Limit(), filter.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var review entity.Review
+		err = scanReview(rows, &review)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		reviews = append(reviews, &review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	metadata := filter.CalculateMetadata(totalCount)
+
+	return reviews, &metadata, nil
+}
+
+// Conditions of review list shared by count and data queries
+const reviewListConditions = `
+	($1::text IS NULL OR visibility = $1)
+	AND
+		($2::bigint IS NULL OR book_id = $2)
+	AND
+		($3::bigint IS NULL OR user_id = $3)
+`
+
+// GetReviews returns reviews in any visibility, it is used by moderators
+func (p *Postgres) GetReviews(ctx context.Context, search entity.ReviewSearch, filter util.Filter) ([]*entity.Review, *util.Metadata, error) {
+	totalQuery := fmt.Sprintf(`
 	SELECT 
-		id,
-		content,
-		rating,
-		user_id,
-		book_id,
-		created_at,
-		updated_at
-	FROM %[1]s
-	WHERE book_id = $1
-	ORDER BY %[2]s %[3]s
-	LIMIT $2 OFFSET $3
-`, reviewsTable, filter.FormatSort(), filter.SortDirection())
+		count(*) AS total_count
+	FROM %s
+	WHERE %s
+	`, reviewsTable, reviewListConditions)
+
+	dataQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE %s
+		ORDER BY %s %s, id ASC
+		LIMIT $4 OFFSET $5
+	`, reviewColumns, reviewsTable, reviewListConditions, filter.FormatSort(), filter.SortDirection())
+
+	args := []any{search.Visibility, search.BookID, search.UserID}
 
 	tx, err := p.Pool.Begin(ctx)
 	if err != nil {
@@ -62,12 +186,12 @@ func (p *Postgres) GetReviewsByBookID(ctx context.Context, bookID int64, filter
 	var totalCount int
 	reviews := make([]*entity.Review, 0)
 
-	err = tx.QueryRow(ctx, totalQuery, bookID).Scan(&totalCount)
+	err = tx.QueryRow(ctx, totalQuery, args...).Scan(&totalCount)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	rows, err := tx.Query(ctx, dataQuery, bookID, filter.Limit(), filter.Offset())
+	rows, err := tx.Query(ctx, dataQuery, append(args, filter.Limit(), filter.Offset())...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -76,37 +200,92 @@ func (p *Postgres) GetReviewsByBookID(ctx context.Context, bookID int64, filter
 
 	for rows.Next() {
 		var review entity.Review
-		rows.Scan(
-			&review.ID,
-			&review.Content,
-			&review.Rating,
-			&review.UserID,
-			&review.BookID,
-			&review.CreatedAt,
-			&review.UpdatedAt,
-		)
+		err = scanReview(rows, &review)
+		if err != nil {
+			return nil, nil, err
+		}
 
 		reviews = append(reviews, &review)
 	}
 
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
 	metadata := filter.CalculateMetadata(totalCount)
 
 	return reviews, &metadata, nil
 }
 
+func (p *Postgres) GetReviewsByUserID(ctx context.Context, userID int64) ([]*entity.Review, error) {
+	query := fmt.Sprintf(`
+	SELECT %s
+	FROM %s
+	WHERE user_id = $1
+	ORDER BY created_at, id
+`, reviewColumns, reviewsTable)
+
+	rows, err := p.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	reviews := make([]*entity.Review, 0)
+
+	for rows.Next() {
+		var review entity.Review
+		err = scanReview(rows, &review)
+		if err != nil {
+			return nil, err
+		}
+
+		reviews = append(reviews, &review)
+	}
+
+	return reviews, rows.Err()
+}
+
+// UpdateReview changes review of the user, nil fields are kept. Visible
+// review becomes pending when review.Visibility is set to pending, hidden
+// reviews stay hidden
 func (p *Postgres) UpdateReview(ctx context.Context, review *entity.Review) error {
 	query := fmt.Sprintf(`
-		UPDATE %s SET
-			content = COALESCE($1, content),
-			rating = COALESCE($2, rating),
-			updated_at = $3
-		WHERE 
-			id = $4
-		AND
-			user_id = $5
-	`, reviewsTable)
+		WITH review AS (
+			UPDATE %[1]s SET
+				content = COALESCE($1, content),
+				rating = COALESCE($2, rating),
+				visibility = CASE WHEN $8::text = $10 AND visibility = $11 THEN $10 ELSE visibility END,
+				hidden_reason = CASE WHEN $8::text = $10 AND visibility = $11 THEN $9 ELSE hidden_reason END,
+				hidden_at = CASE WHEN $8::text = $10 AND visibility = $11 THEN NOW() ELSE hidden_at END,
+				updated_at = $3
+			WHERE 
+				id = $4
+			AND
+				user_id = $5
+			AND
+				visibility NOT IN ($7, $12)
+			RETURNING id, user_id
+		)
+		INSERT INTO %[2]s (user_id, kind, review_id)
+		SELECT user_id, $6, id FROM review
+	`, reviewsTable, activitiesTable)
 
-	tag, err := p.Pool.Exec(ctx, query, review.Content, review.Rating, time.Now(), review.ID, review.UserID)
+	tag, err := p.Pool.Exec(ctx, query,
+		review.Content,
+		review.Rating,
+		time.Now(),
+		review.ID,
+		review.UserID,
+		entity.ActivityReviewUpdated,
+		entity.ReviewDeleted,
+		review.Visibility,
+		review.HiddenReason,
+		entity.ReviewPending,
+		entity.ReviewVisible,
+		entity.ReviewRemoved,
+	)
 	if err != nil {
 		return err
 	}
@@ -118,23 +297,194 @@ func (p *Postgres) UpdateReview(ctx context.Context, review *entity.Review) erro
 	return nil
 }
 
+// DeleteReview marks review of the user as deleted by author, the row is
+// kept for moderation history
 func (p *Postgres) DeleteReview(ctx context.Context, reviewID int64, userID int64) error {
 	query := fmt.Sprintf(`
-		DELETE FROM %s
+		UPDATE %s SET
+			visibility = $1,
+			deleted_at = NOW()
 		WHERE 
-			id = $1
+			id = $2
+		AND
+			user_id = $3
 		AND
-			user_id = $2
+			visibility NOT IN ($1, $4)
 	`, reviewsTable)
 
-	tag, err := p.Pool.Exec(ctx, query, reviewID, userID)
+	tag, err := p.Pool.Exec(ctx, query, entity.ReviewDeleted, reviewID, userID, entity.ReviewRemoved)
 	if err != nil {
 		return err
 	}
 
 	if tag.RowsAffected() == 0 {
-		return errors.New("review doesn't exists or does not belong to user")
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// HideReview hides visible or pending review and closes its open reports as
+// actioned
+func (p *Postgres) HideReview(ctx context.Context, review *entity.Review) error {
+	hideQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			visibility = $1,
+			hidden_by = $2,
+			hidden_reason = $3,
+			hidden_at = NOW()
+		WHERE 
+			id = $4
+		AND
+			visibility IN ($5, $6)
+		RETURNING %s
+	`, reviewsTable, reviewColumns)
+
+	resolveQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			state = $1,
+			resolution = $2,
+			resolved_by = $3,
+			resolved_at = NOW()
+		WHERE 
+			review_id = $4
+		AND
+			state = $5
+	`, reportsTable)
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = scanReview(tx.QueryRow(ctx, hideQuery,
+		entity.ReviewHidden,
+		review.HiddenBy,
+		review.HiddenReason,
+		review.ID,
+		entity.ReviewVisible,
+		entity.ReviewPending,
+	), review)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return repository.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, resolveQuery, entity.ReportActioned, entity.ReportActionHide, review.HiddenBy, review.ID, entity.ReportOpen)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RestoreReview makes hidden or pending review visible
+func (p *Postgres) RestoreReview(ctx context.Context, review *entity.Review) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			visibility = $1,
+			hidden_by = NULL,
+			hidden_reason = NULL,
+			hidden_at = NULL
+		WHERE 
+			id = $2
+		AND
+			visibility IN ($3, $4)
+		RETURNING %s
+	`, reviewsTable, reviewColumns)
+
+	err := scanReview(p.Pool.QueryRow(ctx, query, entity.ReviewVisible, review.ID, entity.ReviewHidden, entity.ReviewPending), review)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return repository.ErrRecordNotFound
+		default:
+			return err
+		}
 	}
 
 	return nil
 }
+
+// RemoveReview marks review of any user as removed by moderator, open reports
+// of the review are closed as actioned. The review is kept, so its reports
+// and activities stay in moderation history
+func (p *Postgres) RemoveReview(ctx context.Context, review *entity.Review) error {
+	removeQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			visibility = $1,
+			hidden_by = $2,
+			hidden_reason = $3,
+			deleted_at = NOW()
+		WHERE 
+			id = $4
+		AND
+			visibility NOT IN ($1, $5)
+		RETURNING %s
+	`, reviewsTable, reviewColumns)
+
+	resolveQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			state = $1,
+			resolution = $2,
+			resolved_by = $3,
+			resolved_at = NOW()
+		WHERE 
+			review_id = $4
+		AND
+			state = $5
+	`, reportsTable)
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	err = scanReview(tx.QueryRow(ctx, removeQuery,
+		entity.ReviewRemoved,
+		review.HiddenBy,
+		review.HiddenReason,
+		review.ID,
+		entity.ReviewDeleted,
+	), review)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return repository.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, resolveQuery, entity.ReportActioned, entity.ReportActionDelete, review.HiddenBy, review.ID, entity.ReportOpen)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func scanReview(row pgx.Row, review *entity.Review) error {
+	return row.Scan(
+		&review.ID,
+		&review.Content,
+		&review.Rating,
+		&review.UserID,
+		&review.BookID,
+		&review.Visibility,
+		&review.HiddenBy,
+		&review.HiddenReason,
+		&review.HiddenAt,
+		&review.DeletedAt,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+}
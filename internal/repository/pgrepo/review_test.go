@@ -0,0 +1,38 @@
+package pgrepo
+
+import (
+	"context"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveReview(t *testing.T) {
+	p := newTestPostgres(t)
+	ctx := context.Background()
+
+	author := createTestUser(t, p, entity.USER)
+	reporter := createTestUser(t, p, entity.USER)
+	moderator := createTestUser(t, p, entity.MODERATOR)
+
+	review := createTestReview(t, p, author, entity.ReviewVisible)
+
+	report := &entity.ReviewReport{ReviewID: review.ID, ReporterID: reporter.ID, Category: entity.ReportSpam}
+	assert.Nil(t, p.CreateReviewReport(ctx, report))
+
+	removed := &entity.Review{ID: review.ID, HiddenBy: &moderator.ID}
+	assert.Nil(t, p.RemoveReview(ctx, removed))
+	assert.Equal(t, entity.ReviewRemoved, removed.Visibility)
+	assert.NotNil(t, removed.DeletedAt)
+
+	assert.ErrorIs(t, p.RemoveReview(ctx, &entity.Review{ID: review.ID, HiddenBy: &moderator.ID}), repository.ErrRecordNotFound)
+
+	reports, err := p.GetReviewReports(ctx, review.ID)
+	assert.Nil(t, err)
+	if assert.Len(t, reports, 1, "reports must survive removal of the review") {
+		assert.Equal(t, entity.ReportActioned, reports[0].State)
+		assert.Equal(t, &moderator.ID, reports[0].ResolvedBy)
+	}
+}
@@ -0,0 +1,265 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+
+	"github.com/jackc/pgx/v4"
+)
+
+const roleColumns = `
+	name,
+	description,
+	permissions,
+	built_in,
+	created_at,
+	updated_at
+`
+
+func (p *Postgres) CreateRole(ctx context.Context, role *entity.RoleDefinition) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (
+			name,
+			description,
+			permissions
+		)
+		VALUES ($1, $2, $3)
+		RETURNING built_in, created_at, updated_at
+	`, rolesTable)
+
+	err := p.Pool.QueryRow(ctx, query, role.Name.String(), role.Description, permissionsToStrings(role.Permissions)).Scan(&role.BuiltIn, &role.CreatedAt, &role.UpdatedAt)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+
+	return nil
+}
+
+func (p *Postgres) GetRole(ctx context.Context, name entity.Role) (*entity.RoleDefinition, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE 
+			name = $1
+	`, roleColumns, rolesTable)
+
+	role, err := scanRole(p.Pool.QueryRow(ctx, query, name.String()))
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return role, nil
+}
+
+func (p *Postgres) GetRoles(ctx context.Context) ([]*entity.RoleDefinition, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		ORDER BY built_in DESC, created_at, name
+	`, roleColumns, rolesTable)
+
+	rows, err := p.Pool.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	roles := make([]*entity.RoleDefinition, 0)
+
+	for rows.Next() {
+		role, err := scanRole(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+// UpdateRole replaces description and permissions of custom role, built-in
+// roles are not found
+func (p *Postgres) UpdateRole(ctx context.Context, role *entity.RoleDefinition) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			description = $1,
+			permissions = $2,
+			updated_at = NOW()
+		WHERE 
+			name = $3
+		AND
+			NOT built_in
+		RETURNING built_in, created_at, updated_at
+	`, rolesTable)
+
+	err := p.Pool.QueryRow(ctx, query, role.Description, permissionsToStrings(role.Permissions), role.Name.String()).Scan(&role.BuiltIn, &role.CreatedAt, &role.UpdatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return repository.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteRole removes custom role which is not assigned to anyone, built-in
+// roles are not found
+func (p *Postgres) DeleteRole(ctx context.Context, name entity.Role) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE 
+			name = $1
+		AND
+			NOT built_in
+	`, rolesTable)
+
+	tag, err := p.Pool.Exec(ctx, query, name.String())
+	if err != nil {
+		return foreignKeyViolation(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetRoleHistory returns role changes of the user, username of actor is
+// empty when the change was made by the system
+func (p *Postgres) GetRoleHistory(ctx context.Context, userID int64, filter util.Filter) ([]*entity.RoleChange, *util.Metadata, error) {
+	totalQuery := fmt.Sprintf(`
+	SELECT 
+		count(*) AS total_count
+	FROM %s
+	WHERE 
+		user_id = $1
+	`, roleChangesTable)
+
+	dataQuery := fmt.Sprintf(`
+	SELECT 
+		c.id,
+		c.user_id,
+		c.actor_id,
+		a.username,
+		c.old_role,
+		c.new_role,
+		c.reason,
+		c.created_at
+	FROM %[1]s c
+	LEFT JOIN %[2]s a ON a.id = c.actor_id
+	WHERE 
+		c.user_id = $1
+	ORDER BY c.%[3]s %[4]s, c.id %[4]s
+	LIMIT $2 OFFSET $3
+	`, roleChangesTable, usersTable, filter.FormatSort(), filter.SortDirection())
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var totalCount int
+	changes := make([]*entity.RoleChange, 0)
+
+	err = tx.QueryRow(ctx, totalQuery, userID).Scan(&totalCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := tx.Query(ctx, dataQuery, userID, filter.Limit(), filter.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var change entity.RoleChange
+		var oldRole, newRole string
+
+		err = rows.Scan(
+			&change.ID,
+			&change.UserID,
+			&change.ActorID,
+			&change.ActorUsername,
+			&oldRole,
+			&newRole,
+			&change.Reason,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		change.OldRole = entity.Role(oldRole)
+		change.NewRole = entity.Role(newRole)
+
+		changes = append(changes, &change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	metadata := filter.CalculateMetadata(totalCount)
+
+	return changes, &metadata, nil
+}
+
+func scanRole(row pgx.Row) (*entity.RoleDefinition, error) {
+	var role entity.RoleDefinition
+	var name string
+	var permissions []string
+
+	err := row.Scan(
+		&name,
+		&role.Description,
+		&permissions,
+		&role.BuiltIn,
+		&role.CreatedAt,
+		&role.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	role.Name = entity.Role(name)
+	role.Permissions = stringsToPermissions(permissions)
+
+	return &role, nil
+}
+
+func permissionsToStrings(permissions []entity.Permission) []string {
+	result := make([]string, len(permissions))
+	for i, permission := range permissions {
+		result[i] = string(permission)
+	}
+
+	return result
+}
+
+func stringsToPermissions(permissions []string) []entity.Permission {
+	result := make([]entity.Permission, len(permissions))
+	for i, permission := range permissions {
+		result[i] = entity.Permission(permission)
+	}
+
+	return result
+}
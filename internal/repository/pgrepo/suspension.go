@@ -5,9 +5,44 @@ import (
 	"errors"
 	"fmt"
 	"one-lab-final/internal/entity"
-	"time"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+
+	"github.com/jackc/pgx/v4"
 )
 
+const suspensionColumns = `
+	id,
+	reason,
+	user_id,
+	moderator_id,
+	expires_in,
+	created_at + expires_in AS expires_at,
+	lifted_at IS NULL AND created_at + expires_in > NOW() AS active,
+	updated_by,
+	lifted_at,
+	lifted_by,
+	lift_reason,
+	created_at,
+	updated_at
+`
+
+// Suspension is active while it is not lifted and not expired
+const activeSuspension = `lifted_at IS NULL AND created_at + expires_in > NOW()`
+
+// Conditions of suspension list shared by count and data queries
+const suspensionListConditions = `
+	($1::boolean IS NULL OR $1 = (` + activeSuspension + `))
+	AND
+		($2::bigint IS NULL OR moderator_id = $2)
+	AND
+		($3::bigint IS NULL OR user_id = $3)
+	AND
+		($4::timestamptz IS NULL OR created_at >= $4)
+	AND
+		($5::timestamptz IS NULL OR created_at < $5)
+`
+
 func (p *Postgres) NewSuspension(ctx context.Context, suspension *entity.Suspension) error {
 	query := fmt.Sprintf(`
 		INSERT INTO %s (
@@ -17,90 +52,226 @@ func (p *Postgres) NewSuspension(ctx context.Context, suspension *entity.Suspens
 			expires_in
 		)
 		VALUES ($1, $2, $3, $4)
-		RETURNING id
-	`, suspensionsTable)
+		RETURNING %s
+	`, suspensionsTable, suspensionColumns)
 
-	err := p.Pool.QueryRow(ctx, query, suspension.Reason, suspension.UserID, suspension.ModeratorID, suspension.ExpiresIn).Scan(&suspension.ID)
+	err := scanSuspension(p.Pool.QueryRow(ctx, query, suspension.Reason, suspension.UserID, suspension.ModeratorID, suspension.ExpiresIn), suspension)
 	if err != nil {
-		return err
+		return foreignKeyViolation(err)
 	}
 
 	return nil
 }
 
+func (p *Postgres) GetSuspension(ctx context.Context, suspensionID int64) (*entity.Suspension, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE 
+			id = $1
+	`, suspensionColumns, suspensionsTable)
+
+	var suspension entity.Suspension
+
+	err := scanSuspension(p.Pool.QueryRow(ctx, query, suspensionID), &suspension)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &suspension, nil
+}
+
+// CheckSuspension returns active suspensions of the user
 func (p *Postgres) CheckSuspension(ctx context.Context, userID int64) ([]*entity.Suspension, error) {
 	query := fmt.Sprintf(`
-		SELECT 
-			reason,
-			moderator_id,
-			expires_in,
-			created_at,
-			updated_at
-		FROM %[1]s 
+		SELECT %s
+		FROM %s 
 		WHERE 
 			user_id = $1 
 		AND 
-			(created_at + expires_in) > $2
-	`, suspensionsTable)
+			%s
+		ORDER BY created_at, id
+	`, suspensionColumns, suspensionsTable, activeSuspension)
+
+	return p.querySuspensions(ctx, query, userID)
+}
 
+// GetSuspensionsByUserID returns every suspension of the user, expired included
+func (p *Postgres) GetSuspensionsByUserID(ctx context.Context, userID int64) ([]*entity.Suspension, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s 
+		WHERE 
+			user_id = $1 
+		ORDER BY created_at, id
+	`, suspensionColumns, suspensionsTable)
+
+	return p.querySuspensions(ctx, query, userID)
+}
+
+func (p *Postgres) GetSuspensions(ctx context.Context, search entity.SuspensionSearch, filter util.Filter) ([]*entity.Suspension, *util.Metadata, error) {
+	totalQuery := fmt.Sprintf(`
+	SELECT 
+		count(*) AS total_count
+	FROM %s
+	WHERE %s
+	`, suspensionsTable, suspensionListConditions)
+
+	dataQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		WHERE %s
+		ORDER BY %s %s, id ASC
+		LIMIT $6 OFFSET $7
+	`, suspensionColumns, suspensionsTable, suspensionListConditions, filter.FormatSort(), filter.SortDirection())
+
+	args := []any{search.Active, search.ModeratorID, search.UserID, search.CreatedAfter, search.CreatedBefore}
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var totalCount int
 	suspensions := make([]*entity.Suspension, 0)
 
-	rows, err := p.Pool.Query(ctx, query, userID, time.Now())
+	err = tx.QueryRow(ctx, totalQuery, args...).Scan(&totalCount)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	rows, err := tx.Query(ctx, dataQuery, append(args, filter.Limit(), filter.Offset())...)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	defer rows.Close()
 
 	for rows.Next() {
 		var suspension entity.Suspension
-		err = rows.Scan(
-			&suspension.Reason,
-			&suspension.ModeratorID,
-			&suspension.ExpiresIn,
-			&suspension.CreatedAt,
-			&suspension.UpdatedAt,
-		)
+		err = scanSuspension(rows, &suspension)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		suspensions = append(suspensions, &suspension)
 	}
 
-	return suspensions, nil
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	metadata := filter.CalculateMetadata(totalCount)
+
+	return suspensions, &metadata, nil
 }
 
+// UpdateSuspension changes reason or duration of active suspension, nil
+// fields are kept
 func (p *Postgres) UpdateSuspension(ctx context.Context, suspension *entity.Suspension) error {
 	query := fmt.Sprintf(`
 		UPDATE %s SET
 			reason = COALESCE($1, reason),
 			expires_in = COALESCE($2, expires_in),
-			moderator_id = COALESCE($3, moderator_id),
-			updated_at = $4
+			updated_by = $3,
+			updated_at = NOW()
 		WHERE 
-			(expires_in + created_at) > $4
+			id = $4
 		AND
-			id = $5
-		AND 
-			active
-		
-	`, suspensionsTable)
+			%s
+		RETURNING %s
+	`, suspensionsTable, activeSuspension, suspensionColumns)
 
-	tag, err := p.Pool.Exec(ctx, query,
+	err := scanSuspension(p.Pool.QueryRow(ctx, query,
 		suspension.Reason,
 		suspension.ExpiresIn,
-		suspension.ModeratorID,
-		time.Now(),
+		suspension.UpdatedBy,
 		suspension.ID,
-	)
+	), suspension)
 	if err != nil {
-		return err
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return repository.ErrRecordNotFound
+		default:
+			return err
+		}
 	}
 
-	if tag.RowsAffected() == 0 {
-		return errors.New("suspension either expired or does not exists")
+	return nil
+}
+
+// LiftSuspension ends active suspension before it expires
+func (p *Postgres) LiftSuspension(ctx context.Context, suspension *entity.Suspension) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			lifted_at = NOW(),
+			lifted_by = $1,
+			lift_reason = $2,
+			updated_at = NOW()
+		WHERE 
+			id = $3
+		AND
+			%s
+		RETURNING %s
+	`, suspensionsTable, activeSuspension, suspensionColumns)
+
+	err := scanSuspension(p.Pool.QueryRow(ctx, query, suspension.LiftedBy, suspension.LiftReason, suspension.ID), suspension)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return repository.ErrRecordNotFound
+		default:
+			return err
+		}
 	}
 
 	return nil
 }
+
+func (p *Postgres) querySuspensions(ctx context.Context, query string, args ...any) ([]*entity.Suspension, error) {
+	rows, err := p.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	suspensions := make([]*entity.Suspension, 0)
+
+	for rows.Next() {
+		var suspension entity.Suspension
+		err = scanSuspension(rows, &suspension)
+		if err != nil {
+			return nil, err
+		}
+
+		suspensions = append(suspensions, &suspension)
+	}
+
+	return suspensions, rows.Err()
+}
+
+func scanSuspension(row pgx.Row, suspension *entity.Suspension) error {
+	return row.Scan(
+		&suspension.ID,
+		&suspension.Reason,
+		&suspension.UserID,
+		&suspension.ModeratorID,
+		&suspension.ExpiresIn,
+		&suspension.ExpiresAt,
+		&suspension.Active,
+		&suspension.UpdatedBy,
+		&suspension.LiftedAt,
+		&suspension.LiftedBy,
+		&suspension.LiftReason,
+		&suspension.CreatedAt,
+		&suspension.UpdatedAt,
+	)
+}
@@ -2,38 +2,423 @@ package pgrepo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
 	"time"
+
+	"github.com/jackc/pgx/v4"
 )
 
+// CreateToken also records last login of the user when the token starts
+// a new session
 func (p *Postgres) CreateToken(ctx context.Context, token *entity.Token) error {
 	query := fmt.Sprintf(`
-		INSERT INTO %s (
-			hash,
-			user_id,
-			expiry
+		WITH t AS (
+			INSERT INTO %[1]s (
+				hash,
+				user_id,
+				expiry,
+				user_agent,
+				ip,
+				jti,
+				scope
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id, user_id, scope, created_at
+		), u AS (
+			UPDATE %[2]s SET
+				last_login_at = t.created_at
+			FROM t
+			WHERE %[2]s.id = t.user_id
+			AND t.scope = '%[3]s'
 		)
-		VALUES ($1, $2, $3)
+		SELECT id, created_at FROM t
+	`, tokensTable, usersTable, entity.ScopeAuthentication)
+
+	if token.Scope == "" {
+		token.Scope = entity.ScopeAuthentication
+	}
+
+	err := p.Pool.QueryRow(ctx, query, token.Hash, token.UserID, token.Expiry, token.UserAgent, token.IP, token.JTI, token.Scope).Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *Postgres) GetSessionsByUserID(ctx context.Context, userID int64) ([]*entity.Session, error) {
+	query := fmt.Sprintf(`
+		SELECT 
+			t.id,
+			t.hash,
+			t.user_agent,
+			t.ip,
+			GREATEST(t.expiry, r.expiry) AS expiry,
+			t.last_used_at,
+			t.created_at
+		FROM %[1]s t
+		LEFT JOIN %[2]s r
+		ON r.session_id = t.id AND NOT r.used
+		WHERE 
+			t.user_id = $1
+		AND 
+			t.scope = $3
+		AND 
+			GREATEST(t.expiry, r.expiry) > $2
+		ORDER BY t.created_at DESC, t.id DESC
+	`, tokensTable, refreshTokensTable)
+
+	rows, err := p.Pool.Query(ctx, query, userID, time.Now(), entity.ScopeAuthentication)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	sessions := make([]*entity.Session, 0)
+
+	for rows.Next() {
+		var session entity.Session
+		err = rows.Scan(
+			&session.ID,
+			&session.Hash,
+			&session.UserAgent,
+			&session.IP,
+			&session.Expiry,
+			&session.LastUsedAt,
+			&session.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		sessions = append(sessions, &session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (p *Postgres) DeleteToken(ctx context.Context, token string) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE 
+			hash = $1
+		AND
+			scope = $2
 	`, tokensTable)
 
-	_, err := p.Pool.Exec(ctx, query, token.Hash, token.UserID, token.Expiry)
+	tag, err := p.Pool.Exec(ctx, query, util.HashToken(token), entity.ScopeAuthentication)
 	if err != nil {
 		return err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
 	return nil
 }
 
-func (p *Postgres) DeleteExpiredTokens(ctx context.Context) error {
+func (p *Postgres) DeleteSession(ctx context.Context, sessionID int64, userID int64) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE 
+			id = $1
+		AND
+			user_id = $2
+		AND
+			scope = $3
+	`, tokensTable)
+
+	tag, err := p.Pool.Exec(ctx, query, sessionID, userID, entity.ScopeAuthentication)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// DeleteUserTokens revokes every token of the user. If exceptToken is not empty
+// the token is kept, so the caller stays logged in
+func (p *Postgres) DeleteUserTokens(ctx context.Context, userID int64, exceptToken string) error {
 	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE 
+			user_id = $1
+		AND
+			scope = $3
+		AND
+			($2::bytea IS NULL OR hash <> $2)
+	`, tokensTable)
+
+	var exceptHash []byte
+	if exceptToken != "" {
+		exceptHash = util.HashToken(exceptToken)
+	}
+
+	_, err := p.Pool.Exec(ctx, query, userID, exceptHash, entity.ScopeAuthentication)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteExpiredTokens removes sessions whose access token has expired and
+// that can no longer be refreshed, together with outdated denylist entries
+// and abandoned logins with external providers
+func (p *Postgres) DeleteExpiredTokens(ctx context.Context) error {
+	tokensQuery := fmt.Sprintf(`
+		DELETE FROM %[1]s t
+		WHERE t.expiry < $1
+		AND NOT EXISTS (
+			SELECT 1 FROM %[2]s r 
+			WHERE r.session_id = t.id 
+			AND NOT r.used 
+			AND r.expiry > $1
+		)
+	`, tokensTable, refreshTokensTable)
+
+	denylistQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE expiry < $1
+	`, tokenDenylistTable)
+
+	oidcStatesQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE expiry < $1
+	`, oidcStatesTable)
+
+	emailChangesQuery := fmt.Sprintf(`
 		DELETE FROM %s
 		WHERE expiry < $1
+	`, emailChangesTable)
+
+	now := time.Now()
+
+	_, err := p.Pool.Exec(ctx, tokensQuery, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.Pool.Exec(ctx, denylistQuery, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.Pool.Exec(ctx, oidcStatesQuery, now)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.Pool.Exec(ctx, emailChangesQuery, now)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetTokenStatus reports whether the token with given ID was revoked and
+// the current state of its owner. Every lookup uses primary key or index only
+func (p *Postgres) GetTokenStatus(ctx context.Context, jti string, userID int64) (*entity.TokenStatus, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			EXISTS(SELECT 1 FROM %[1]s d WHERE d.jti = $1),
+			EXISTS(SELECT 1 FROM %[2]s s WHERE s.user_id = $2 AND s.lifted_at IS NULL AND (s.created_at + s.expires_in) > $3),
+			COALESCE((SELECT u.activated FROM %[3]s u WHERE u.id = $2), false),
+			COALESCE((SELECT u.totp_enabled FROM %[3]s u WHERE u.id = $2), false),
+			COALESCE((SELECT u.role FROM %[3]s u WHERE u.id = $2), ''),
+			COALESCE((SELECT r.permissions FROM %[3]s u JOIN %[4]s r ON r.name = u.role WHERE u.id = $2), '{}')
+	`, tokenDenylistTable, suspensionsTable, usersTable, rolesTable)
+
+	status := new(entity.TokenStatus)
+	var role string
+	var permissions []string
+
+	err := p.Pool.QueryRow(ctx, query, jti, userID, time.Now()).Scan(&status.Revoked, &status.Suspended, &status.Activated, &status.TwoFactor, &role, &permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	status.Role = entity.Role(role)
+	status.Permissions = stringsToPermissions(permissions)
+
+	return status, nil
+}
+
+func (p *Postgres) DeleteScopedTokens(ctx context.Context, userID int64, scope string) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE 
+			user_id = $1
+		AND
+			scope = $2
+	`, tokensTable)
+
+	_, err := p.Pool.Exec(ctx, query, userID, scope)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (p *Postgres) CountScopedTokens(ctx context.Context, userID int64, scope string, since time.Time) (int, error) {
+	query := fmt.Sprintf(`
+		SELECT COUNT(*) FROM %s
+		WHERE 
+			user_id = $1
+		AND
+			scope = $2
+		AND
+			created_at > $3
 	`, tokensTable)
 
-	_, err := p.Pool.Exec(ctx, query, time.Now())
+	var count int
+
+	err := p.Pool.QueryRow(ctx, query, userID, scope, since).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (p *Postgres) CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s (
+			hash,
+			session_id,
+			expiry
+		)
+		VALUES ($1, $2, $3)
+	`, refreshTokensTable)
+
+	_, err := p.Pool.Exec(ctx, query, token.Hash, token.SessionID, token.Expiry)
 	if err != nil {
 		return err
 	}
+
 	return nil
 }
+
+func (p *Postgres) GetRefreshToken(ctx context.Context, token string) (*entity.RefreshToken, error) {
+	query := fmt.Sprintf(`
+		SELECT 
+			r.hash,
+			r.session_id,
+			t.user_id,
+			r.used,
+			r.expiry
+		FROM %[1]s r
+		INNER JOIN %[2]s t
+		ON t.id = r.session_id
+		WHERE r.hash = $1
+	`, refreshTokensTable, tokensTable)
+
+	refreshToken := new(entity.RefreshToken)
+
+	err := p.Pool.QueryRow(ctx, query, util.HashToken(token)).Scan(
+		&refreshToken.Hash,
+		&refreshToken.SessionID,
+		&refreshToken.UserID,
+		&refreshToken.Used,
+		&refreshToken.Expiry,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return refreshToken, nil
+}
+
+// RotateRefreshToken marks used refresh token, replaces access token of the session
+// and stores the next refresh token in one transaction. If the refresh token
+// was used concurrently repository.ErrRecordNotFound is returned
+func (p *Postgres) RotateRefreshToken(ctx context.Context, used *entity.RefreshToken, access *entity.Token, refresh *entity.RefreshToken) error {
+	markQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			used = true
+		WHERE 
+			hash = $1
+		AND
+			NOT used
+	`, refreshTokensTable)
+
+	sessionQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			hash = $1,
+			expiry = $2,
+			user_agent = $3,
+			ip = $4,
+			jti = $5,
+			last_used_at = $6
+		WHERE 
+			id = $7
+		RETURNING created_at
+	`, tokensTable)
+
+	refreshQuery := fmt.Sprintf(`
+		INSERT INTO %s (
+			hash,
+			session_id,
+			expiry
+		)
+		VALUES ($1, $2, $3)
+	`, refreshTokensTable)
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, markQuery, used.Hash)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	err = tx.QueryRow(ctx, sessionQuery,
+		access.Hash,
+		access.Expiry,
+		access.UserAgent,
+		access.IP,
+		access.JTI,
+		time.Now(),
+		access.ID,
+	).Scan(&access.CreatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return repository.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, refreshQuery, refresh.Hash, refresh.SessionID, refresh.Expiry)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,194 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+
+	"github.com/jackc/pgx/v4"
+)
+
+func (p *Postgres) GetTwoFactor(ctx context.Context, userID int64) (*entity.TwoFactor, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			COALESCE(totp_secret, ''),
+			totp_enabled
+		FROM %s
+		WHERE 
+			id = $1
+	`, usersTable)
+
+	twoFactor := new(entity.TwoFactor)
+
+	err := p.Pool.QueryRow(ctx, query, userID).Scan(&twoFactor.Secret, &twoFactor.Enabled)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return twoFactor, nil
+}
+
+// SetTwoFactorSecret stores secret of not yet confirmed enrollment
+func (p *Postgres) SetTwoFactorSecret(ctx context.Context, userID int64, secret string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			totp_secret = $2
+		WHERE 
+			id = $1
+		AND
+			NOT totp_enabled
+	`, usersTable)
+
+	tag, err := p.Pool.Exec(ctx, query, userID, secret)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// EnableTwoFactor turns on 2FA and replaces recovery codes of the user
+func (p *Postgres) EnableTwoFactor(ctx context.Context, userID int64, recoveryCodes [][]byte) error {
+	enableQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			totp_enabled = true
+		WHERE 
+			id = $1
+	`, usersTable)
+
+	deleteQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE 
+			user_id = $1
+	`, recoveryCodesTable)
+
+	insertQuery := fmt.Sprintf(`
+		INSERT INTO %s (
+			hash,
+			user_id
+		)
+		SELECT unnest($2::bytea[]), $1
+	`, recoveryCodesTable)
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, enableQuery, userID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	_, err = tx.Exec(ctx, deleteQuery, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, insertQuery, userID, recoveryCodes)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (p *Postgres) DisableTwoFactor(ctx context.Context, userID int64) error {
+	disableQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			totp_enabled = false,
+			totp_secret = NULL,
+			totp_last_step = NULL
+		WHERE 
+			id = $1
+	`, usersTable)
+
+	deleteQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE 
+			user_id = $1
+	`, recoveryCodesTable)
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, disableQuery, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, deleteQuery, userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UseRecoveryCode deletes the code, so it can be used only once
+func (p *Postgres) UseRecoveryCode(ctx context.Context, userID int64, code string) error {
+	query := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE 
+			hash = $1
+		AND
+			user_id = $2
+	`, recoveryCodesTable)
+
+	tag, err := p.Pool.Exec(ctx, query, util.HashToken(code), userID)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// UseTOTPStep records time step of accepted TOTP code. Codes of the same or
+// earlier steps are rejected, so each code can be used only once
+func (p *Postgres) UseTOTPStep(ctx context.Context, userID int64, step int64) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			totp_last_step = $2
+		WHERE 
+			id = $1
+		AND
+			(totp_last_step IS NULL OR totp_last_step < $2)
+	`, usersTable)
+
+	tag, err := p.Pool.Exec(ctx, query, userID, step)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
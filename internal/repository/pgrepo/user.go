@@ -2,14 +2,13 @@ package pgrepo
 
 import (
 	"context"
-	"crypto/sha256"
-	"database/sql"
 	"errors"
 	"fmt"
 	"time"
 
 	"one-lab-final/internal/entity"
 	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
 
 	"github.com/jackc/pgx/v4"
 )
@@ -22,13 +21,14 @@ func (p *Postgres) CreateUser(ctx context.Context, u *entity.User) error {
 			first_name,
 			last_name,
 			role,
-			password_hash
+			password_hash,
+			activated
 		)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id
 		`, usersTable)
 
-	err := p.Pool.QueryRow(ctx, query, u.Username, u.Email, u.FirstName, u.LastName, u.Role.String(), u.Password.Hash).Scan(&u.ID)
+	err := p.Pool.QueryRow(ctx, query, u.Username, u.Email, u.FirstName, u.LastName, u.Role.String(), u.Password.Hash, u.Activated).Scan(&u.ID)
 	if err != nil {
 		return err
 	}
@@ -44,10 +44,15 @@ func (p *Postgres) GetUserByUsername(ctx context.Context, username string) (*ent
 				email,
 				first_name,
 				last_name,
+				bio,
+				location,
+				website,
+				avatar_key,
 				created_at
 			FROM %s
 			WHERE 
 				username = $1
+			AND deleted_at IS NULL
 			`, usersTable)
 
 	user := &entity.User{}
@@ -58,6 +63,10 @@ func (p *Postgres) GetUserByUsername(ctx context.Context, username string) (*ent
 		&user.Email,
 		&user.FirstName,
 		&user.LastName,
+		&user.Bio,
+		&user.Location,
+		&user.Website,
+		&user.AvatarKey,
 		&user.CreatedAt,
 	)
 	if err != nil {
@@ -80,7 +89,10 @@ func (p *Postgres) GetUserByCredentials(ctx context.Context, credentials string)
 				email,
 				first_name,
 				last_name,
-				password_hash
+				password_hash,
+				role,
+				totp_enabled,
+				deleted_at
 			FROM %s
 			WHERE 
 				username = $1
@@ -89,6 +101,7 @@ func (p *Postgres) GetUserByCredentials(ctx context.Context, credentials string)
 			`, usersTable)
 
 	user := &entity.User{}
+	var roleString string
 
 	err := p.Pool.QueryRow(ctx, query, credentials).Scan(
 		&user.ID,
@@ -97,6 +110,9 @@ func (p *Postgres) GetUserByCredentials(ctx context.Context, credentials string)
 		&user.FirstName,
 		&user.LastName,
 		&user.Password.Hash,
+		&roleString,
+		&user.TwoFactor,
+		&user.DeletedAt,
 	)
 	if err != nil {
 		switch {
@@ -107,13 +123,94 @@ func (p *Postgres) GetUserByCredentials(ctx context.Context, credentials string)
 		}
 	}
 
+	role, ok := entity.StringToRole(roleString)
+	if !ok {
+		return nil, errors.New("error while parsing role")
+	}
+
+	user.Role = role
+
 	return user, nil
 }
 
-func (p *Postgres) GetUserByToken(ctx context.Context, token string) (*entity.User, error) {
-	hash := sha256.Sum256([]byte(token))
+func (p *Postgres) GetUserByID(ctx context.Context, userID int64) (*entity.User, error) {
+	query := fmt.Sprintf(`
+			SELECT 
+				id,
+				username,
+				email,
+				first_name,
+				last_name,
+				role,
+				activated,
+				totp_enabled,
+				bio,
+				location,
+				website,
+				avatar_key,
+				username_changed_at,
+				deleted_at,
+				created_at,
+				updated_at,
+				COALESCE((SELECT r.permissions FROM %[2]s r WHERE r.name = u.role), '{}')
+			FROM %[1]s u
+			WHERE 
+				id = $1
+			`, usersTable, rolesTable)
 
+	user := &entity.User{}
+	var roleString string
+	var permissions []string
+
+	err := p.Pool.QueryRow(ctx, query, userID).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.FirstName,
+		&user.LastName,
+		&roleString,
+		&user.Activated,
+		&user.TwoFactor,
+		&user.Bio,
+		&user.Location,
+		&user.Website,
+		&user.AvatarKey,
+		&user.UsernameChangedAt,
+		&user.DeletedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&permissions,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	role, ok := entity.StringToRole(roleString)
+	if !ok {
+		return nil, errors.New("error while parsing role")
+	}
+
+	user.Role = role
+	user.Permissions = stringsToPermissions(permissions)
+
+	return user, nil
+}
+
+func (p *Postgres) GetUserByToken(ctx context.Context, token string) (*entity.User, error) {
 	query := fmt.Sprintf(`
+		WITH t AS (
+			UPDATE %[2]s SET
+				last_used_at = $2
+			WHERE hash = $1
+			AND expiry > $2
+			AND scope = $3
+			RETURNING user_id
+		)
 		SELECT 
 			u.id,
 			u.username,
@@ -124,18 +221,20 @@ func (p *Postgres) GetUserByToken(ctx context.Context, token string) (*entity.Us
 			u.created_at,
 			u.updated_at,
 			u.role,
-			(SELECT EXISTS(SELECT * FROM %[3]s s WHERE s.user_id=u.id AND (s.created_at + s.expires_in) > $2)) AS suspended
+			u.activated,
+			u.totp_enabled,
+			(SELECT EXISTS(SELECT * FROM %[3]s s WHERE s.user_id=u.id AND s.lifted_at IS NULL AND (s.created_at + s.expires_in) > $2)) AS suspended,
+			COALESCE((SELECT r.permissions FROM %[4]s r WHERE r.name = u.role), '{}')
 		FROM %[1]s u
-		INNER JOIN %[2]s  t
+		INNER JOIN t
 		ON u.id = t.user_id
-		WHERE t.hash = $1
-		AND t.expiry > $2
-	`, usersTable, tokensTable, suspensionsTable)
+	`, usersTable, tokensTable, suspensionsTable, rolesTable)
 
 	user := new(entity.User)
 	var roleString string
+	var permissions []string
 
-	err := p.Pool.QueryRow(ctx, query, hash[:], time.Now()).Scan(
+	err := p.Pool.QueryRow(ctx, query, util.HashToken(token), time.Now(), entity.ScopeAuthentication).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Email,
@@ -145,11 +244,14 @@ func (p *Postgres) GetUserByToken(ctx context.Context, token string) (*entity.Us
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&roleString,
+		&user.Activated,
+		&user.TwoFactor,
 		&user.Suspended,
+		&permissions,
 	)
 	if err != nil {
 		switch {
-		case errors.Is(err, sql.ErrNoRows):
+		case errors.Is(err, pgx.ErrNoRows):
 			return nil, repository.ErrRecordNotFound
 		default:
 			return nil, err
@@ -162,6 +264,112 @@ func (p *Postgres) GetUserByToken(ctx context.Context, token string) (*entity.Us
 	}
 
 	user.Role = role
+	user.Permissions = stringsToPermissions(permissions)
+
+	return user, nil
+}
+
+func (p *Postgres) GetUserByScopedToken(ctx context.Context, token string, scope string) (*entity.User, error) {
+	query := fmt.Sprintf(`
+		SELECT 
+			u.id,
+			u.username,
+			u.email,
+			u.first_name,
+			u.last_name,
+			u.activated
+		FROM %[1]s u
+		INNER JOIN %[2]s t
+		ON u.id = t.user_id
+		WHERE t.hash = $1
+		AND t.scope = $2
+		AND t.expiry > $3
+	`, usersTable, tokensTable)
+
+	user := new(entity.User)
+
+	err := p.Pool.QueryRow(ctx, query, util.HashToken(token), scope, time.Now()).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.FirstName,
+		&user.LastName,
+		&user.Activated,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+func (p *Postgres) ActivateUser(ctx context.Context, userID int64) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			activated = true,
+			updated_at = $2
+		WHERE 
+			id = $1
+	`, usersTable)
+
+	tag, err := p.Pool.Exec(ctx, query, userID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ConsumeScopedToken deletes the token and returns its owner, so the same
+// token can not be used twice even by concurrent requests
+func (p *Postgres) ConsumeScopedToken(ctx context.Context, token string, scope string) (*entity.User, error) {
+	query := fmt.Sprintf(`
+		WITH t AS (
+			DELETE FROM %[2]s
+			WHERE hash = $1
+			AND scope = $2
+			AND expiry > $3
+			RETURNING user_id
+		)
+		SELECT 
+			u.id,
+			u.username,
+			u.email,
+			u.first_name,
+			u.last_name,
+			u.activated
+		FROM %[1]s u
+		INNER JOIN t
+		ON u.id = t.user_id
+	`, usersTable, tokensTable)
+
+	user := new(entity.User)
+
+	err := p.Pool.QueryRow(ctx, query, util.HashToken(token), scope, time.Now()).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.FirstName,
+		&user.LastName,
+		&user.Activated,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
 
 	return user, nil
 }
@@ -171,12 +379,15 @@ func (p *Postgres) UpdateUser(ctx context.Context, u *entity.User) error {
 		UPDATE %s SET
 			first_name = COALESCE($2, first_name),
 			last_name = COALESCE($3, last_name),
-			password_hash = COALESCE($4, password_hash)
+			password_hash = COALESCE($4, password_hash),
+			bio = COALESCE($5, bio),
+			location = COALESCE($6, location),
+			website = COALESCE($7, website)
 		WHERE 
 			id = $1
 	`, usersTable)
 
-	_, err := p.Pool.Exec(ctx, query, u.ID, u.FirstName, u.LastName, u.Password.Hash)
+	_, err := p.Pool.Exec(ctx, query, u.ID, u.FirstName, u.LastName, u.Password.Hash, u.Bio, u.Location, u.Website)
 	if err != nil {
 		return err
 	}
@@ -184,33 +395,201 @@ func (p *Postgres) UpdateUser(ctx context.Context, u *entity.User) error {
 	return nil
 }
 
+// DeleteUser only marks the user as deleted, the profile is anonymized by
+// AnonymizeDeletedUsers after grace period. The last admin is not deleted,
+// ErrEditConflict is returned instead
 func (p *Postgres) DeleteUser(ctx context.Context, userID int64) error {
+	lockQuery := fmt.Sprintf(`
+		SELECT id
+		FROM %s
+		WHERE 
+			role = $1
+		AND
+			deleted_at IS NULL
+		FOR UPDATE
+	`, usersTable)
+
 	query := fmt.Sprintf(`
-		DELETE FROM %s
+		UPDATE %[1]s SET
+			deleted_at = NOW()
 		WHERE 
 			id = $1
+		AND deleted_at IS NULL
+		AND (
+			role <> $2
+			OR
+			EXISTS (SELECT 1 FROM %[1]s WHERE role = $2 AND deleted_at IS NULL AND id <> $1)
+		)
 	`, usersTable)
 
-	_, err := p.Pool.Exec(ctx, query, userID)
+	adminQuery := fmt.Sprintf(`
+		SELECT EXISTS (
+			SELECT 1
+			FROM %s
+			WHERE 
+				id = $1
+			AND
+				role = $2
+			AND
+				deleted_at IS NULL
+		)
+	`, usersTable)
+
+	tx, err := p.Pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	defer tx.Rollback(ctx)
+
+	// Admins are locked the same way as in GrantRoleToUser, so the last two
+	// admins can not leave at the same time
+	_, err = tx.Exec(ctx, lockQuery, entity.ADMIN.String())
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, query, userID, entity.ADMIN.String())
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		var lastAdmin bool
+
+		err = tx.QueryRow(ctx, adminQuery, userID, entity.ADMIN.String()).Scan(&lastAdmin)
+		if err != nil {
+			return err
+		}
+
+		if lastAdmin {
+			return repository.ErrEditConflict
+		}
+	}
+
+	return tx.Commit(ctx)
 }
 
-func (p *Postgres) GrantRoleToUser(ctx context.Context, userID int64, role entity.Role) error {
+// RestoreUser undoes DeleteUser if the user was not anonymized yet
+func (p *Postgres) RestoreUser(ctx context.Context, userID int64) error {
 	query := fmt.Sprintf(`
 		UPDATE %s SET
-			role = $1
+			deleted_at = NULL
 		WHERE 
-			id = $2
+			id = $1
+		AND anonymized_at IS NULL
 	`, usersTable)
 
-	_, err := p.Pool.Exec(ctx, query, role.String(), userID)
+	tag, err := p.Pool.Exec(ctx, query, userID)
 	if err != nil {
 		return err
 	}
 
+	if tag.RowsAffected() == 0 {
+		return repository.ErrRecordNotFound
+	}
+
 	return nil
 }
+
+// GrantRoleToUser changes role of the user from change.OldRole to
+// change.NewRole and records the change. When an admin is demoted, rows of
+// admins are locked first so concurrent demotions can't remove the last one.
+// ErrEditConflict is returned when role of the user was changed meanwhile or
+// the user is the last admin
+func (p *Postgres) GrantRoleToUser(ctx context.Context, change *entity.RoleChange) error {
+	lockQuery := fmt.Sprintf(`
+		SELECT id
+		FROM %s
+		WHERE 
+			role = $1
+		AND
+			deleted_at IS NULL
+		FOR UPDATE
+	`, usersTable)
+
+	query := fmt.Sprintf(`
+		WITH updated AS (
+			UPDATE %[1]s SET
+				role = $1
+			WHERE 
+				id = $2
+			AND
+				role = $3
+			AND (
+				NOT $6::boolean
+				OR
+				EXISTS (SELECT 1 FROM %[1]s WHERE role = $3 AND deleted_at IS NULL AND id <> $2)
+			)
+			RETURNING id
+		)
+		INSERT INTO %[2]s (
+			user_id,
+			actor_id,
+			old_role,
+			new_role,
+			reason
+		)
+		SELECT id, $4, $3, $1, $5
+		FROM updated
+		RETURNING id, created_at
+	`, usersTable, roleChangesTable)
+
+	demotion := change.OldRole == entity.ADMIN && change.NewRole != entity.ADMIN
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if demotion {
+		_, err = tx.Exec(ctx, lockQuery, entity.ADMIN.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	args := []any{
+		change.NewRole.String(),
+		change.UserID,
+		change.OldRole.String(),
+		change.ActorID,
+		change.Reason,
+		demotion,
+	}
+
+	err = tx.QueryRow(ctx, query, args...).Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return repository.ErrEditConflict
+		default:
+			return foreignKeyViolation(err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// CountUsersWithRole counts users with the role, deleted accounts are skipped
+func (p *Postgres) CountUsersWithRole(ctx context.Context, role entity.Role) (int, error) {
+	query := fmt.Sprintf(`
+		SELECT count(*)
+		FROM %s
+		WHERE 
+			role = $1
+		AND
+			deleted_at IS NULL
+	`, usersTable)
+
+	var count int
+
+	err := p.Pool.QueryRow(ctx, query, role.String()).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
@@ -0,0 +1,126 @@
+package pgrepo
+
+import (
+	"context"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/pkg/util"
+	"strings"
+	"time"
+)
+
+// Conditions of admin user directory shared by count and data queries
+const userDirectoryConditions = `
+	u.anonymized_at IS NULL
+	AND
+		($1::text IS NULL OR lower(u.username) LIKE $1 OR lower(u.email::text) LIKE $1 OR lower(u.first_name) LIKE $1 OR lower(u.last_name) LIKE $1)
+	AND
+		($2::text IS NULL OR u.role::text = $2)
+	AND
+		($3::boolean IS NULL OR $3 = EXISTS(SELECT * FROM %[2]s s WHERE s.user_id = u.id AND s.lifted_at IS NULL AND (s.created_at + s.expires_in) > $7))
+	AND
+		($4::boolean IS NULL OR u.activated = $4)
+	AND
+		($5::timestamptz IS NULL OR u.created_at >= $5)
+	AND
+		($6::timestamptz IS NULL OR u.created_at < $6)
+`
+
+func (p *Postgres) GetUsers(ctx context.Context, search entity.UserSearch, filter util.Filter) ([]*entity.UserOverview, *util.Metadata, error) {
+	conditions := fmt.Sprintf(userDirectoryConditions, usersTable, suspensionsTable)
+
+	totalQuery := fmt.Sprintf(`
+	SELECT 
+		count(*) AS total_count
+	FROM %s u
+	WHERE %s
+	`, usersTable, conditions)
+
+	dataQuery := fmt.Sprintf(`
+		SELECT 
+			u.id,
+			u.username,
+			u.email,
+			u.first_name,
+			u.last_name,
+			u.role,
+			u.activated,
+			EXISTS(SELECT * FROM %[2]s s WHERE s.user_id = u.id AND s.lifted_at IS NULL AND (s.created_at + s.expires_in) > $7) AS suspended,
+			(SELECT count(*) FROM %[3]s r WHERE r.user_id = u.id AND r.visibility NOT IN ('deleted', 'removed')) AS review_count,
+			u.last_login_at,
+			u.deleted_at,
+			u.created_at
+		FROM %[1]s u
+		WHERE %[4]s
+		ORDER BY %[5]s %[6]s NULLS LAST, u.id ASC
+		LIMIT $8 OFFSET $9
+	`, usersTable, suspensionsTable, reviewsTable, conditions, filter.FormatSort(), filter.SortDirection())
+
+	var pattern *string
+	if search.Search != nil {
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.ToLower(*search.Search))
+		escaped += "%"
+		pattern = &escaped
+	}
+
+	var role *string
+	if search.Role != nil {
+		name := search.Role.String()
+		role = &name
+	}
+
+	args := []any{pattern, role, search.Suspended, search.Activated, search.CreatedAfter, search.CreatedBefore, time.Now()}
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var totalCount int
+	users := make([]*entity.UserOverview, 0)
+
+	err = tx.QueryRow(ctx, totalQuery, args...).Scan(&totalCount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := tx.Query(ctx, dataQuery, append(args, filter.Limit(), filter.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var user entity.UserOverview
+		err = rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.Email,
+			&user.FirstName,
+			&user.LastName,
+			&user.Role,
+			&user.Activated,
+			&user.Suspended,
+			&user.ReviewCount,
+			&user.LastLoginAt,
+			&user.DeletedAt,
+			&user.CreatedAt,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		users = append(users, &user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	metadata := filter.CalculateMetadata(totalCount)
+
+	return users, &metadata, nil
+}
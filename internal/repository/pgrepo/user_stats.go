@@ -0,0 +1,77 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"sort"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// GetUserStats returns precomputed statistics of the user, users without
+// reviews have no statistics. Only months with reviews are returned
+func (p *Postgres) GetUserStats(ctx context.Context, userID int64) (*entity.UserStats, error) {
+	query := fmt.Sprintf(`
+		SELECT 
+			review_count,
+			average_rating,
+			rating_distribution,
+			top_tags,
+			first_review_at,
+			last_review_at,
+			monthly_reviews,
+			refreshed_at
+		FROM %s
+		WHERE 
+			user_id = $1
+	`, userReviewStatsView)
+
+	stats := &entity.UserStats{}
+	monthly := make(map[string]int64)
+
+	err := p.Pool.QueryRow(ctx, query, userID).Scan(
+		&stats.ReviewCount,
+		&stats.AverageRating,
+		&stats.RatingDistribution,
+		&stats.TopTags,
+		&stats.FirstReviewAt,
+		&stats.LastReviewAt,
+		&monthly,
+		&stats.RefreshedAt,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, repository.ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	stats.MonthlyReviews = make([]entity.MonthCount, 0, len(monthly))
+	for month, count := range monthly {
+		stats.MonthlyReviews = append(stats.MonthlyReviews, entity.MonthCount{Month: month, Count: count})
+	}
+
+	sort.Slice(stats.MonthlyReviews, func(i, j int) bool {
+		return stats.MonthlyReviews[i].Month < stats.MonthlyReviews[j].Month
+	})
+
+	return stats, nil
+}
+
+func (p *Postgres) RefreshUserStats(ctx context.Context) error {
+	query := fmt.Sprintf(`
+		REFRESH MATERIALIZED VIEW CONCURRENTLY %s;
+	`, userReviewStatsView)
+
+	_, err := p.Pool.Exec(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
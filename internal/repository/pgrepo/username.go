@@ -0,0 +1,110 @@
+package pgrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/repository"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// ChangeUsername renames the user and keeps a redirect from the old username.
+// Redirect is dropped once somebody takes the old username
+func (p *Postgres) ChangeUsername(ctx context.Context, userID int64, username string) error {
+	selectQuery := fmt.Sprintf(`
+		SELECT username
+		FROM %s
+		WHERE 
+			id = $1
+		FOR UPDATE
+	`, usersTable)
+
+	updateQuery := fmt.Sprintf(`
+		UPDATE %s SET
+			username = $2,
+			username_changed_at = NOW(),
+			updated_at = NOW()
+		WHERE 
+			id = $1
+	`, usersTable)
+
+	deleteRedirectQuery := fmt.Sprintf(`
+		DELETE FROM %s
+		WHERE username = $1
+	`, redirectsTable)
+
+	insertRedirectQuery := fmt.Sprintf(`
+		INSERT INTO %s (
+			username,
+			user_id
+		)
+		VALUES ($1, $2)
+		ON CONFLICT (username) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			created_at = NOW()
+	`, redirectsTable)
+
+	tx, err := p.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var oldUsername string
+
+	err = tx.QueryRow(ctx, selectQuery, userID).Scan(&oldUsername)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return repository.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, updateQuery, userID, username)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+
+	_, err = tx.Exec(ctx, deleteRedirectQuery, username)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, insertRedirectQuery, oldUsername, userID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetUsernameRedirect returns current username of the user who used to have
+// the given one
+func (p *Postgres) GetUsernameRedirect(ctx context.Context, username string) (string, error) {
+	query := fmt.Sprintf(`
+		SELECT u.username
+		FROM %[1]s r
+		INNER JOIN %[2]s u
+		ON u.id = r.user_id
+		WHERE r.username = $1
+		AND u.deleted_at IS NULL
+	`, redirectsTable, usersTable)
+
+	var current string
+
+	err := p.Pool.QueryRow(ctx, query, username).Scan(&current)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return "", repository.ErrRecordNotFound
+		default:
+			return "", err
+		}
+	}
+
+	return current, nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+	"strings"
+	"time"
+)
+
+// RequestEmailChange sends confirmation token to the new address and a notice
+// with cancellation token to the current one. Email is changed only after
+// the new address is confirmed
+func (m *Manager) RequestEmailChange(ctx context.Context, userID int64, email string) error {
+	user, err := m.Repository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(*user.Email, email) {
+		return &ValidationError{
+			Fields: map[string]string{
+				"email": "must differ from current email",
+			},
+		}
+	}
+
+	_, err = m.Repository.GetUserByCredentials(ctx, email)
+	switch {
+	case err == nil:
+		return ErrEmailTaken
+	case !errors.Is(err, repository.ErrRecordNotFound):
+		return err
+	}
+
+	confirm, err := util.GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	cancel, err := util.GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	expiration := 24 * time.Hour
+	if m.Config != nil && m.Config.AUTH.EmailChangeExpiration != 0 {
+		expiration = m.Config.AUTH.EmailChangeExpiration
+	}
+
+	err = m.Repository.CreateEmailChange(ctx, &entity.EmailChange{
+		UserID:      user.ID,
+		Email:       email,
+		ConfirmHash: confirm.Hash,
+		CancelHash:  cancel.Hash,
+		Expiry:      time.Now().Add(expiration),
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrEditConflict) {
+			return ErrEmailChangeRevertible
+		}
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"To confirm your new email send the following token to PUT /api/v1/users/email/confirm:\n\n%s\n\nThe token expires in %s.",
+		confirm.Plaintext,
+		expiration,
+	)
+
+	err = m.Mailer.Send(ctx, email, "Confirm your new email", body)
+	if err != nil {
+		return err
+	}
+
+	body = fmt.Sprintf(
+		"A change of your email to %s was requested. If it was not you, send the following token to PUT /api/v1/users/email/cancel to cancel the change and log out all sessions:\n\n%s\n\nThe token restores this email even after the change was confirmed, until it expires in %s.",
+		email,
+		cancel.Plaintext,
+		expiration,
+	)
+
+	return m.Mailer.Send(ctx, *user.Email, "Your email is being changed", body)
+}
+
+func (m *Manager) ConfirmEmailChange(ctx context.Context, token string) error {
+	_, err := m.Repository.ConfirmEmailChange(ctx, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateRecord) {
+			return ErrEmailTaken
+		}
+		return err
+	}
+
+	return nil
+}
+
+// CancelEmailChange drops pending change or reverts confirmed one and revokes
+// all sessions of the user, since the change was likely requested by someone
+// else
+func (m *Manager) CancelEmailChange(ctx context.Context, token string) error {
+	change, err := m.Repository.CancelEmailChange(ctx, token)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateRecord) {
+			return ErrEmailTaken
+		}
+		return err
+	}
+
+	return m.Repository.DeleteUserTokens(ctx, change.UserID, "")
+}
+
+// ChangeUsername renames the user no more often than once per cooldown.
+// Old username keeps redirecting to the profile
+func (m *Manager) ChangeUsername(ctx context.Context, userID int64, username string) error {
+	user, err := m.Repository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if *user.Username == username {
+		return &ValidationError{
+			Fields: map[string]string{
+				"username": "must differ from current username",
+			},
+		}
+	}
+
+	cooldown := 30 * 24 * time.Hour
+	if m.Config != nil && m.Config.AUTH.UsernameChangeCooldown != 0 {
+		cooldown = m.Config.AUTH.UsernameChangeCooldown
+	}
+
+	if user.UsernameChangedAt != nil && time.Since(*user.UsernameChangedAt) < cooldown {
+		return ErrUsernameChangeCooldown
+	}
+
+	err = m.Repository.ChangeUsername(ctx, userID, username)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateRecord) {
+			return ErrUsernameTaken
+		}
+		return err
+	}
+
+	return nil
+}
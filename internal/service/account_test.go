@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/repository/mocks"
+	mailerMocks "one-lab-final/pkg/mailer/mocks"
+	"one-lab-final/pkg/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestEmailChange(t *testing.T) {
+	var userID int64 = 15
+	tests := []struct {
+		Name          string
+		Email         string
+		MockTakenErr  error
+		MockChangeErr error
+		ExpectChange  bool
+		ExpectedErr   error
+		ExpectedField string
+	}{
+		{
+			Name:         "Tokens sent to both addresses",
+			Email:        "new@gmail.com",
+			MockTakenErr: repository.ErrRecordNotFound,
+			ExpectChange: true,
+		},
+		{
+			Name:          "Previous change can still be cancelled",
+			Email:         "new@gmail.com",
+			MockTakenErr:  repository.ErrRecordNotFound,
+			MockChangeErr: repository.ErrEditConflict,
+			ExpectChange:  true,
+			ExpectedErr:   ErrEmailChangeRevertible,
+		},
+		{
+			Name:          "Email is the same",
+			Email:         "Old@gmail.com",
+			ExpectedField: "email",
+		},
+		{
+			Name:        "Email is taken",
+			Email:       "new@gmail.com",
+			ExpectedErr: ErrEmailTaken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			mailer := mailerMocks.NewMailer(t)
+			service := New(repo, nil, WithMailer(mailer))
+			ctx := context.Background()
+
+			repo.On("GetUserByID", ctx, userID).Return(&entity.User{ID: userID, Email: util.StringToPointer("old@gmail.com")}, nil)
+			if test.ExpectedField == "" {
+				repo.On("GetUserByCredentials", ctx, test.Email).Return(&entity.User{}, test.MockTakenErr)
+			}
+			if test.ExpectChange {
+				repo.On("CreateEmailChange", ctx, mock.MatchedBy(func(change *entity.EmailChange) bool {
+					return change.UserID == userID && change.Email == test.Email && change.Expiry.After(time.Now())
+				})).Return(test.MockChangeErr)
+			}
+			if test.ExpectChange && test.MockChangeErr == nil {
+				mailer.On("Send", ctx, "new@gmail.com", "Confirm your new email", mock.AnythingOfType("string")).Return(nil)
+				mailer.On("Send", ctx, "old@gmail.com", "Your email is being changed", mock.AnythingOfType("string")).Return(nil)
+			}
+
+			err := service.RequestEmailChange(ctx, userID, test.Email)
+
+			if test.ExpectedField != "" {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				assert.Contains(t, validationErr.Fields, test.ExpectedField)
+				return
+			}
+
+			assert.ErrorIs(t, err, test.ExpectedErr)
+		})
+	}
+}
+
+func TestConfirmEmailChange(t *testing.T) {
+	tests := []struct {
+		Name        string
+		MockErr     error
+		ExpectedErr error
+	}{
+		{
+			Name: "Email changed successfully",
+		},
+		{
+			Name:        "Invalid token",
+			MockErr:     repository.ErrRecordNotFound,
+			ExpectedErr: repository.ErrRecordNotFound,
+		},
+		{
+			Name:        "Email was taken meanwhile",
+			MockErr:     repository.ErrDuplicateRecord,
+			ExpectedErr: ErrEmailTaken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("ConfirmEmailChange", ctx, "token").Return(&entity.EmailChange{}, test.MockErr)
+
+			err := service.ConfirmEmailChange(ctx, "token")
+			assert.ErrorIs(t, err, test.ExpectedErr)
+		})
+	}
+}
+
+func TestCancelEmailChange(t *testing.T) {
+	var userID int64 = 15
+	confirmedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		Name          string
+		MockChange    *entity.EmailChange
+		MockCancelErr error
+		ExpectedErr   error
+	}{
+		{
+			Name:       "Pending change cancelled",
+			MockChange: &entity.EmailChange{UserID: userID},
+		},
+		{
+			Name: "Confirmed change reverted",
+			MockChange: &entity.EmailChange{
+				UserID:      userID,
+				Email:       "new@gmail.com",
+				OldEmail:    util.StringToPointer("old@gmail.com"),
+				ConfirmedAt: &confirmedAt,
+			},
+		},
+		{
+			Name:          "Old email was taken meanwhile",
+			MockCancelErr: repository.ErrDuplicateRecord,
+			ExpectedErr:   ErrEmailTaken,
+		},
+		{
+			Name:          "Change expired",
+			MockCancelErr: repository.ErrRecordNotFound,
+			ExpectedErr:   repository.ErrRecordNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("CancelEmailChange", ctx, "token").Return(test.MockChange, test.MockCancelErr)
+			if test.ExpectedErr == nil {
+				repo.On("DeleteUserTokens", ctx, userID, "").Return(nil)
+			}
+
+			err := service.CancelEmailChange(ctx, "token")
+			assert.ErrorIs(t, err, test.ExpectedErr)
+		})
+	}
+}
+
+func TestChangeUsername(t *testing.T) {
+	var userID int64 = 15
+	recently := time.Now().Add(-time.Hour)
+	longAgo := time.Now().Add(-60 * 24 * time.Hour)
+
+	tests := []struct {
+		Name          string
+		ChangedAt     *time.Time
+		Username      string
+		MockErr       error
+		ExpectChange  bool
+		ExpectedErr   error
+		ExpectedField string
+	}{
+		{
+			Name:         "Username changed for the first time",
+			Username:     "newname",
+			ExpectChange: true,
+		},
+		{
+			Name:         "Username changed after cooldown",
+			ChangedAt:    &longAgo,
+			Username:     "newname",
+			ExpectChange: true,
+		},
+		{
+			Name:        "Username changed recently",
+			ChangedAt:   &recently,
+			Username:    "newname",
+			ExpectedErr: ErrUsernameChangeCooldown,
+		},
+		{
+			Name:          "Username is the same",
+			Username:      "oldname",
+			ExpectedField: "username",
+		},
+		{
+			Name:         "Username is taken",
+			Username:     "newname",
+			MockErr:      repository.ErrDuplicateRecord,
+			ExpectChange: true,
+			ExpectedErr:  ErrUsernameTaken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("GetUserByID", ctx, userID).Return(&entity.User{
+				ID:                userID,
+				Username:          util.StringToPointer("oldname"),
+				UsernameChangedAt: test.ChangedAt,
+			}, nil)
+			if test.ExpectChange {
+				repo.On("ChangeUsername", ctx, userID, test.Username).Return(test.MockErr)
+			}
+
+			err := service.ChangeUsername(ctx, userID, test.Username)
+
+			if test.ExpectedField != "" {
+				var validationErr *ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				assert.Contains(t, validationErr.Fields, test.ExpectedField)
+				return
+			}
+
+			assert.ErrorIs(t, err, test.ExpectedErr)
+			assert.False(t, errors.Is(err, repository.ErrDuplicateRecord))
+		})
+	}
+}
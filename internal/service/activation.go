@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/pkg/util"
+	"time"
+)
+
+func (m *Manager) ActivateUser(ctx context.Context, token string) error {
+	user, err := m.Repository.GetUserByScopedToken(ctx, token, entity.ScopeActivation)
+	if err != nil {
+		return err
+	}
+
+	err = m.Repository.ActivateUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	return m.Repository.DeleteScopedTokens(ctx, user.ID, entity.ScopeActivation)
+}
+
+func (m *Manager) ResendActivation(ctx context.Context, userID int64) error {
+	user, err := m.Repository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Activated {
+		return ErrUserAlreadyActivated
+	}
+
+	err = m.Repository.DeleteScopedTokens(ctx, user.ID, entity.ScopeActivation)
+	if err != nil {
+		return err
+	}
+
+	return m.sendActivationToken(ctx, user)
+}
+
+func (m *Manager) sendActivationToken(ctx context.Context, user *entity.User) error {
+	token, err := util.GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	expiration := 72 * time.Hour
+	if m.Config != nil && m.Config.AUTH.ActivationExpiration != 0 {
+		expiration = m.Config.AUTH.ActivationExpiration
+	}
+
+	err = m.Repository.CreateToken(ctx, &entity.Token{
+		Hash:   token.Hash,
+		UserID: user.ID,
+		Expiry: time.Now().Add(expiration),
+		Scope:  entity.ScopeActivation,
+	})
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"To activate your account send the following token to PUT /api/v1/users/activate:\n\n%s\n\nThe token expires in %s.",
+		token.Plaintext,
+		expiration,
+	)
+
+	return m.Mailer.Send(ctx, *user.Email, "Activate your account", body)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/repository/mocks"
+	mailerMocks "one-lab-final/pkg/mailer/mocks"
+	"one-lab-final/pkg/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestActivateUser(t *testing.T) {
+	var userID int64 = 15
+	tests := []struct {
+		Name          string
+		MockUser      *entity.User
+		MockUserErr   error
+		MockUpdateErr error
+		ExpectErr     bool
+	}{
+		{
+			Name:     "User activated successfully",
+			MockUser: &entity.User{ID: userID},
+		},
+		{
+			Name:        "Invalid token",
+			MockUserErr: repository.ErrRecordNotFound,
+			ExpectErr:   true,
+		},
+		{
+			Name:          "Some error ocurred while updating",
+			MockUser:      &entity.User{ID: userID},
+			MockUpdateErr: errors.New("critical error"),
+			ExpectErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("GetUserByScopedToken", ctx, "token", entity.ScopeActivation).Return(test.MockUser, test.MockUserErr)
+			if test.MockUserErr == nil {
+				repo.On("ActivateUser", ctx, userID).Return(test.MockUpdateErr)
+			}
+			if test.MockUserErr == nil && test.MockUpdateErr == nil {
+				repo.On("DeleteScopedTokens", ctx, userID, entity.ScopeActivation).Return(nil)
+			}
+
+			err := service.ActivateUser(ctx, "token")
+
+			if test.ExpectErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestResendActivation(t *testing.T) {
+	var userID int64 = 15
+	tests := []struct {
+		Name        string
+		MockUser    *entity.User
+		MockUserErr error
+		ExpectSend  bool
+		ExpectedErr error
+	}{
+		{
+			Name: "Token sent successfully",
+			MockUser: &entity.User{
+				ID:    userID,
+				Email: util.StringToPointer("example@gmail.com"),
+			},
+			ExpectSend: true,
+		},
+		{
+			Name: "User is already activated",
+			MockUser: &entity.User{
+				ID:        userID,
+				Activated: true,
+			},
+			ExpectedErr: ErrUserAlreadyActivated,
+		},
+		{
+			Name:        "User does not exist",
+			MockUserErr: repository.ErrRecordNotFound,
+			ExpectedErr: repository.ErrRecordNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			mailer := mailerMocks.NewMailer(t)
+			service := New(repo, nil, WithMailer(mailer))
+			ctx := context.Background()
+
+			repo.On("GetUserByID", ctx, userID).Return(test.MockUser, test.MockUserErr)
+			if test.ExpectSend {
+				repo.On("DeleteScopedTokens", ctx, userID, entity.ScopeActivation).Return(nil)
+				repo.On("CreateToken", ctx, mock.AnythingOfType("*entity.Token")).Return(nil)
+				mailer.On("Send", ctx, "example@gmail.com", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+			}
+
+			err := service.ResendActivation(ctx, userID)
+			assert.ErrorIs(t, err, test.ExpectedErr)
+		})
+	}
+}
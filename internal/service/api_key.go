@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"one-lab-final/internal/entity"
+	"one-lab-final/pkg/util"
+	"time"
+)
+
+const (
+	apiKeyPrefix       = "ak_"
+	apiKeyPrefixLength = 10
+)
+
+// CreateAPIKey issues a new key for the user. Scopes are checked against
+// current role of the owner, plaintext key is returned only once
+func (m *Manager) CreateAPIKey(ctx context.Context, userID int64, name string, scopes []entity.APIScope, expiry *time.Time) (*entity.APIKey, error) {
+	if expiry != nil && !expiry.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+
+	user, err := m.Repository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scope := range scopes {
+		permissions, ok := entity.APIScopePermissions[scope]
+		if !ok {
+			return nil, ErrUnknownScope
+		}
+
+		if !scopeAllowed(user.Permissions, permissions) {
+			return nil, ErrScopeNotAllowed
+		}
+	}
+
+	token, err := util.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext := apiKeyPrefix + token.Plaintext
+
+	key := &entity.APIKey{
+		UserID:    userID,
+		Name:      name,
+		Plaintext: plaintext,
+		Prefix:    plaintext[:apiKeyPrefixLength],
+		Hash:      util.HashToken(plaintext),
+		Scopes:    scopes,
+		Expiry:    expiry,
+	}
+
+	err = m.Repository.CreateAPIKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (m *Manager) GetAPIKeys(ctx context.Context, userID int64) ([]*entity.APIKey, error) {
+	return m.Repository.GetAPIKeysByUserID(ctx, userID)
+}
+
+func (m *Manager) DeleteAPIKey(ctx context.Context, keyID int64, userID int64) error {
+	return m.Repository.DeleteAPIKey(ctx, keyID, userID)
+}
+
+// scopeAllowed reports whether the owner has at least one permission covered
+// by the scope, scopes without permissions are allowed to everyone
+func scopeAllowed(owned []entity.Permission, required []entity.Permission) bool {
+	if len(required) == 0 {
+		return true
+	}
+
+	for _, permission := range required {
+		if entity.HasPermission(owned, permission) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/repository/mocks"
+	"one-lab-final/pkg/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	var userID int64 = 15
+	past := time.Now().Add(-time.Hour)
+	moderator := []entity.Permission{entity.PermissionBooksCreate, entity.PermissionBooksUpdate, entity.PermissionUsersSuspend}
+
+	tests := []struct {
+		Name        string
+		MockUser    *entity.User
+		MockUserErr error
+		Scopes      []entity.APIScope
+		Expiry      *time.Time
+		ExpectedErr error
+	}{
+		{
+			Name:     "Key created successfully",
+			MockUser: &entity.User{ID: userID, Role: entity.MODERATOR, Permissions: moderator},
+			Scopes:   []entity.APIScope{entity.APIScopeBooksWrite, entity.APIScopeReviewsWrite},
+		},
+		{
+			Name:        "Unknown scope",
+			MockUser:    &entity.User{ID: userID, Role: entity.ADMIN},
+			Scopes:      []entity.APIScope{"books:delete"},
+			ExpectedErr: ErrUnknownScope,
+		},
+		{
+			Name:        "Scope exceeds role of the owner",
+			MockUser:    &entity.User{ID: userID, Role: entity.MODERATOR, Permissions: moderator},
+			Scopes:      []entity.APIScope{entity.APIScopeModRoles},
+			ExpectedErr: ErrScopeNotAllowed,
+		},
+		{
+			Name:        "Expiry in the past",
+			Scopes:      []entity.APIScope{entity.APIScopeReviewsWrite},
+			Expiry:      &past,
+			ExpectedErr: ErrInvalidExpiry,
+		},
+		{
+			Name:        "User does not exist",
+			MockUserErr: repository.ErrRecordNotFound,
+			Scopes:      []entity.APIScope{entity.APIScopeReviewsWrite},
+			ExpectedErr: repository.ErrRecordNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			if test.Expiry == nil {
+				repo.On("GetUserByID", ctx, userID).Return(test.MockUser, test.MockUserErr)
+			}
+			if test.ExpectedErr == nil {
+				repo.On("CreateAPIKey", ctx, mock.AnythingOfType("*entity.APIKey")).Return(nil)
+			}
+
+			key, err := service.CreateAPIKey(ctx, userID, "ingestion", test.Scopes, test.Expiry)
+
+			assert.ErrorIs(t, err, test.ExpectedErr)
+			if test.ExpectedErr == nil {
+				assert.True(t, strings.HasPrefix(key.Plaintext, apiKeyPrefix))
+				assert.True(t, strings.HasPrefix(key.Plaintext, key.Prefix))
+				assert.Equal(t, util.HashToken(key.Plaintext), key.Hash)
+			}
+		})
+	}
+}
+
+func TestGetUserByAPIKey(t *testing.T) {
+	repo := mocks.NewRepository(t)
+	service := New(repo, nil)
+	ctx := context.Background()
+
+	user := &entity.User{ID: 15, APIKey: &entity.APIKey{ID: 1}}
+	repo.On("GetUserByAPIKey", ctx, "ak_token").Return(user, nil)
+
+	result, err := service.GetUserByToken(ctx, "ak_token")
+
+	assert.Nil(t, err)
+	assert.Equal(t, user, result)
+}
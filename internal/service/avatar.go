@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"one-lab-final/internal/entity"
+	"one-lab-final/pkg/imaging"
+)
+
+const avatarQuality = 85
+
+// UpdateAvatar decodes uploaded image, stores it re-encoded in every avatar
+// size and removes the previous avatar. Every upload gets a new key, so
+// cached URLs of the old avatar never show the new one
+func (m *Manager) UpdateAvatar(ctx context.Context, userID int64, file io.Reader) (*entity.Avatar, error) {
+	maxSize := int64(5 << 20)
+	maxDimension := 4096
+	if m.Config != nil && m.Config.AVATAR.MaxSize != 0 {
+		maxSize = m.Config.AVATAR.MaxSize
+	}
+	if m.Config != nil && m.Config.AVATAR.MaxDimension != 0 {
+		maxDimension = m.Config.AVATAR.MaxDimension
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, ErrImageTooLarge
+	}
+
+	img, err := imaging.Decode(data, maxDimension)
+	if err != nil {
+		switch {
+		case errors.Is(err, imaging.ErrTooLarge):
+			return nil, ErrImageTooLarge
+		default:
+			return nil, ErrUnsupportedImage
+		}
+	}
+
+	suffix := make([]byte, 8)
+	_, err = rand.Read(suffix)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("avatars/%d/%s", userID, hex.EncodeToString(suffix))
+
+	for _, size := range entity.AvatarSizes {
+		thumbnail, err := imaging.EncodeJPEG(imaging.Thumbnail(img, size), avatarQuality)
+		if err != nil {
+			m.deleteAvatarBlobs(ctx, key)
+			return nil, err
+		}
+
+		err = m.Blob.Put(ctx, entity.AvatarBlobKey(key, size), thumbnail, "image/jpeg")
+		if err != nil {
+			m.deleteAvatarBlobs(ctx, key)
+			return nil, err
+		}
+	}
+
+	previous, err := m.Repository.SetUserAvatar(ctx, userID, &key)
+	if err != nil {
+		m.deleteAvatarBlobs(ctx, key)
+		return nil, err
+	}
+
+	if previous != nil {
+		m.deleteAvatarBlobs(ctx, *previous)
+	}
+
+	return m.avatarURLs(key), nil
+}
+
+func (m *Manager) DeleteAvatar(ctx context.Context, userID int64) error {
+	previous, err := m.Repository.SetUserAvatar(ctx, userID, nil)
+	if err != nil {
+		return err
+	}
+
+	if previous != nil {
+		m.deleteAvatarBlobs(ctx, *previous)
+	}
+
+	return nil
+}
+
+func (m *Manager) avatarURLs(key string) *entity.Avatar {
+	return &entity.Avatar{
+		Small:  m.Blob.URL(entity.AvatarBlobKey(key, entity.AvatarSmall)),
+		Medium: m.Blob.URL(entity.AvatarBlobKey(key, entity.AvatarMedium)),
+		Large:  m.Blob.URL(entity.AvatarBlobKey(key, entity.AvatarLarge)),
+	}
+}
+
+// deleteAvatarBlobs only logs failures, since avatar is already detached from
+// the user and leftover blobs do not break anything
+func (m *Manager) deleteAvatarBlobs(ctx context.Context, key string) {
+	for _, size := range entity.AvatarSizes {
+		err := m.Blob.Delete(ctx, entity.AvatarBlobKey(key, size))
+		if err != nil {
+			log.Printf("deleting avatar blob err: %s", err.Error())
+		}
+	}
+}
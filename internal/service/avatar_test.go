@@ -0,0 +1,197 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"one-lab-final/internal/config"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository/mocks"
+	"one-lab-final/pkg/blob"
+	blobMocks "one-lab-final/pkg/blob/mocks"
+	"one-lab-final/pkg/util"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testImage(width int, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+func TestUpdateAvatar(t *testing.T) {
+	var userID int64 = 15
+	tests := []struct {
+		Name        string
+		File        []byte
+		MaxSize     int64
+		MockOldKey  *string
+		ExpectSet   bool
+		ExpectedErr error
+	}{
+		{
+			Name:      "First avatar",
+			File:      testImage(300, 200),
+			ExpectSet: true,
+		},
+		{
+			Name:       "Previous avatar is replaced",
+			File:       testImage(32, 32),
+			MockOldKey: util.StringToPointer("avatars/15/old"),
+			ExpectSet:  true,
+		},
+		{
+			Name:        "File is too large",
+			File:        make([]byte, 2048),
+			MaxSize:     1024,
+			ExpectedErr: ErrImageTooLarge,
+		},
+		{
+			Name:        "Dimensions are too large",
+			File:        testImage(600, 10),
+			ExpectedErr: ErrImageTooLarge,
+		},
+		{
+			Name:        "Not an image",
+			File:        []byte("<html><body>hello</body></html>"),
+			ExpectedErr: ErrUnsupportedImage,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			directory := t.TempDir()
+			store, err := blob.NewLocalStore(directory, "/media")
+			assert.Nil(t, err)
+
+			for _, size := range entity.AvatarSizes {
+				store.Put(context.Background(), entity.AvatarBlobKey("avatars/15/old", size), []byte("old"), "image/jpeg")
+			}
+
+			repo := mocks.NewRepository(t)
+			cfg := &config.Config{AVATAR: config.AvatarConfig{MaxSize: 1 << 20, MaxDimension: 512}}
+			if test.MaxSize != 0 {
+				cfg.AVATAR.MaxSize = test.MaxSize
+			}
+			service := New(repo, cfg, WithBlobStore(store))
+			ctx := context.Background()
+
+			var key string
+			if test.ExpectSet {
+				repo.On("SetUserAvatar", ctx, userID, mock.MatchedBy(func(k *string) bool {
+					key = *k
+					return strings.HasPrefix(*k, "avatars/15/")
+				})).Return(test.MockOldKey, nil)
+			}
+
+			avatar, err := service.UpdateAvatar(ctx, userID, bytes.NewReader(test.File))
+			assert.ErrorIs(t, err, test.ExpectedErr)
+
+			if !test.ExpectSet {
+				assert.Nil(t, avatar)
+				return
+			}
+
+			assert.Equal(t, "/media/"+key+"/64.jpg", avatar.Small)
+			assert.Equal(t, "/media/"+key+"/256.jpg", avatar.Large)
+
+			for _, size := range entity.AvatarSizes {
+				data, err := os.ReadFile(filepath.Join(directory, entity.AvatarBlobKey(key, size)))
+				assert.Nil(t, err)
+
+				decoded, _, err := image.DecodeConfig(bytes.NewReader(data))
+				assert.Nil(t, err)
+				assert.Equal(t, size, decoded.Width)
+				assert.Equal(t, size, decoded.Height)
+
+				_, err = os.Stat(filepath.Join(directory, entity.AvatarBlobKey("avatars/15/old", size)))
+				assert.Equal(t, test.MockOldKey != nil, os.IsNotExist(err))
+			}
+		})
+	}
+}
+
+func TestUpdateAvatarStoreFailure(t *testing.T) {
+	var userID int64 = 15
+
+	repo := mocks.NewRepository(t)
+	store := blobMocks.NewStore(t)
+	service := New(repo, nil, WithBlobStore(store))
+	ctx := context.Background()
+
+	// Blobs written before the failure are removed
+	store.On("Put", ctx, mock.MatchedBy(func(key string) bool { return strings.HasSuffix(key, "/64.jpg") }), mock.Anything, "image/jpeg").Return(nil)
+	store.On("Put", ctx, mock.MatchedBy(func(key string) bool { return strings.HasSuffix(key, "/128.jpg") }), mock.Anything, "image/jpeg").Return(errors.New("storage is down"))
+	store.On("Delete", ctx, mock.AnythingOfType("string")).Return(nil).Times(len(entity.AvatarSizes))
+
+	_, err := service.UpdateAvatar(ctx, userID, bytes.NewReader(testImage(16, 16)))
+	assert.NotNil(t, err)
+}
+
+func TestDeleteAvatar(t *testing.T) {
+	var userID int64 = 15
+	tests := []struct {
+		Name       string
+		MockOldKey *string
+	}{
+		{
+			Name:       "Avatar is deleted",
+			MockOldKey: util.StringToPointer("avatars/15/old"),
+		},
+		{
+			Name: "User has no avatar",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			store := blobMocks.NewStore(t)
+			service := New(repo, nil, WithBlobStore(store))
+			ctx := context.Background()
+
+			repo.On("SetUserAvatar", ctx, userID, (*string)(nil)).Return(test.MockOldKey, nil)
+			if test.MockOldKey != nil {
+				for _, size := range entity.AvatarSizes {
+					store.On("Delete", ctx, entity.AvatarBlobKey(*test.MockOldKey, size)).Return(nil)
+				}
+			}
+
+			err := service.DeleteAvatar(ctx, userID)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestGetUserByUsernameWithAvatar(t *testing.T) {
+	repo := mocks.NewRepository(t)
+	store, _ := blob.NewLocalStore(t.TempDir(), "/media")
+	service := New(repo, nil, WithBlobStore(store))
+	ctx := context.Background()
+
+	repo.On("GetUserByUsername", ctx, "username").Return(&entity.User{AvatarKey: util.StringToPointer("avatars/15/abc")}, nil)
+
+	user, err := service.GetUserByUsername(ctx, "username")
+	assert.Nil(t, err)
+	assert.Equal(t, &entity.Avatar{
+		Small:  "/media/avatars/15/abc/64.jpg",
+		Medium: "/media/avatars/15/abc/128.jpg",
+		Large:  "/media/avatars/15/abc/256.jpg",
+	}, user.Avatar)
+}
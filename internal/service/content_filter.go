@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// contentCheck screens review text with one kind of content rules. New kinds
+// of rules are supported by adding their check
+type contentCheck struct {
+	// Valid reports whether pattern and threshold suit the kind of the rule
+	Valid func(rule *entity.ContentRule) bool
+	Match func(text *screenedText, rule *entity.ContentRule) bool
+	// Problem is shown to the author of rejected or quarantined review
+	Problem func(rule *entity.ContentRule) string
+}
+
+var contentChecks = map[entity.ContentRuleKind]contentCheck{
+	entity.ContentRuleTerm: {
+		Valid: func(rule *entity.ContentRule) bool {
+			return rule.Pattern != nil && len(contentWords(*rule.Pattern)) != 0
+		},
+		Match: func(text *screenedText, rule *entity.ContentRule) bool {
+			return containsWords(text.Words, contentWords(*rule.Pattern))
+		},
+		Problem: func(rule *entity.ContentRule) string {
+			return "contains banned words"
+		},
+	},
+	entity.ContentRuleLinks: {
+		Valid: func(rule *entity.ContentRule) bool {
+			return rule.Threshold != nil && *rule.Threshold >= 0
+		},
+		Match: func(text *screenedText, rule *entity.ContentRule) bool {
+			return len(linkRX.FindAllString(text.Raw, -1)) > *rule.Threshold
+		},
+		Problem: func(rule *entity.ContentRule) string {
+			return fmt.Sprintf("must not contain more than %d links", *rule.Threshold)
+		},
+	},
+	entity.ContentRuleRepeat: {
+		Valid: func(rule *entity.ContentRule) bool {
+			return rule.Threshold != nil && *rule.Threshold >= 1
+		},
+		Match: func(text *screenedText, rule *entity.ContentRule) bool {
+			return longestRun(text.Raw) > *rule.Threshold
+		},
+		Problem: func(rule *entity.ContentRule) string {
+			return fmt.Sprintf("must not repeat the same character more than %d times in a row", *rule.Threshold)
+		},
+	},
+}
+
+// Links with scheme or "www." and bare domains of popular zones
+var linkRX = regexp.MustCompile(`(?i)(?:https?://|www\.)\S+|\b[a-z0-9-]+(?:\.[a-z0-9-]+)*\.(?:com|net|org|info|biz|io|ru|xyz|top|ly)\b`)
+
+// Characters commonly used to disguise letters of banned words
+var leetspeak = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'7': 't',
+	'@': 'a',
+	'$': 's',
+}
+
+// screenedText is review text prepared once for every rule
+type screenedText struct {
+	Raw   string
+	Words []string
+}
+
+// screenContent runs enabled content rules against the text. Rejecting rule
+// returns ValidationError, otherwise the returned problem is set when the
+// review must be quarantined until moderator approves it
+func (m *Manager) screenContent(ctx context.Context, content string) (*string, error) {
+	rules, err := m.Repository.GetContentRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	text := &screenedText{
+		Raw:   content,
+		Words: contentWords(content),
+	}
+
+	var quarantine *string
+
+	for _, rule := range rules {
+		check, ok := contentChecks[rule.Kind]
+		if !ok || !rule.Enabled || !check.Valid(rule) || !check.Match(text, rule) {
+			continue
+		}
+
+		problem := check.Problem(rule)
+
+		if rule.Severity == entity.ContentReject {
+			return nil, &ValidationError{
+				Fields: map[string]string{
+					"content": problem,
+				},
+			}
+		}
+
+		if quarantine == nil {
+			quarantine = &problem
+		}
+	}
+
+	return quarantine, nil
+}
+
+// contentWords splits text into lower case words with leetspeak replaced,
+// so "B4D w0rd" and "bad word" are the same
+func contentWords(text string) []string {
+	mapped := strings.Map(func(r rune) rune {
+		if letter, ok := leetspeak[r]; ok {
+			return letter
+		}
+
+		return unicode.ToLower(r)
+	}, text)
+
+	return strings.FieldsFunc(mapped, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsWords reports whether term appears in words as whole words, so
+// "ass" is not found in "class"
+func containsWords(words []string, term []string) bool {
+	for i := 0; i+len(term) <= len(words); i++ {
+		matched := true
+		for j := range term {
+			if words[i+j] != term[j] {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// longestRun returns length of the longest run of the same character,
+// case is ignored and whitespace breaks the run
+func longestRun(text string) int {
+	longest, run := 0, 0
+	var last rune
+
+	for _, r := range text {
+		r = unicode.ToLower(r)
+
+		switch {
+		case unicode.IsSpace(r):
+			run = 0
+		case run > 0 && r == last:
+			run++
+		default:
+			run = 1
+		}
+
+		last = r
+		if run > longest {
+			longest = run
+		}
+	}
+
+	return longest
+}
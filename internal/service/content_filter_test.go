@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/repository/mocks"
+	"one-lab-final/pkg/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestScreenContent(t *testing.T) {
+	rules := []*entity.ContentRule{
+		{ID: 1, Kind: entity.ContentRuleTerm, Pattern: util.StringToPointer("buy now"), Severity: entity.ContentReject, Enabled: true},
+		{ID: 2, Kind: entity.ContentRuleTerm, Pattern: util.StringToPointer("ass"), Severity: entity.ContentReject, Enabled: true},
+		{ID: 3, Kind: entity.ContentRuleLinks, Threshold: intPointer(1), Severity: entity.ContentQuarantine, Enabled: true},
+		{ID: 4, Kind: entity.ContentRuleRepeat, Threshold: intPointer(5), Severity: entity.ContentReject, Enabled: true},
+		{ID: 5, Kind: entity.ContentRuleTerm, Pattern: util.StringToPointer("boring"), Severity: entity.ContentReject, Enabled: false},
+	}
+
+	tests := []struct {
+		Name            string
+		Content         string
+		ExpectedProblem *string
+		ExpectedField   string
+	}{
+		{
+			Name:    "Clean text",
+			Content: "A classic, well worth reading. Boring only in the middle",
+		},
+		{
+			Name:          "Banned phrase disguised with leetspeak",
+			Content:       "B0oks are cheap, BUY-N0W!",
+			ExpectedField: "contains banned words",
+		},
+		{
+			Name:            "Too many links",
+			Content:         "See https://example.com/a and www.example.org or shop.ru",
+			ExpectedProblem: util.StringToPointer("must not contain more than 1 links"),
+		},
+		{
+			Name:            "Single link is allowed",
+			Content:         "Author's blog https://example.com",
+			ExpectedProblem: nil,
+		},
+		{
+			Name:          "Repeated characters",
+			Content:       "Greaaaaaat book!!!",
+			ExpectedField: "must not repeat the same character more than 5 times in a row",
+		},
+		{
+			Name:          "Reject wins over quarantine",
+			Content:       "https://a.com https://b.com buy now",
+			ExpectedField: "contains banned words",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("GetContentRules", ctx).Return(rules, nil)
+
+			problem, err := service.screenContent(ctx, test.Content)
+
+			if test.ExpectedField != "" {
+				var validationErr *ValidationError
+				assert.True(t, errors.As(err, &validationErr))
+				assert.Equal(t, test.ExpectedField, validationErr.Fields["content"])
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.ExpectedProblem, problem)
+		})
+	}
+}
+
+func TestCreateReviewQuarantined(t *testing.T) {
+	repo := mocks.NewRepository(t)
+	service := New(repo, nil)
+	ctx := context.Background()
+
+	review := &entity.Review{
+		Content: util.StringToPointer("Cheap copies at https://a.com and https://b.com"),
+		Rating:  util.IntToPointer(100),
+		UserID:  5,
+		BookID:  42,
+	}
+
+	repo.On("GetContentRules", ctx).Return([]*entity.ContentRule{
+		{ID: 1, Kind: entity.ContentRuleLinks, Threshold: intPointer(1), Severity: entity.ContentQuarantine, Enabled: true},
+	}, nil)
+	repo.On("CreateReview", ctx, review).Return(nil)
+
+	assert.NoError(t, service.CreateReview(ctx, review))
+	assert.Equal(t, entity.ReviewPending, review.Visibility)
+	assert.Equal(t, util.StringToPointer("must not contain more than 1 links"), review.HiddenReason)
+}
+
+func TestUpdateContentRule(t *testing.T) {
+	quarantine := entity.ContentQuarantine
+
+	tests := []struct {
+		Name         string
+		Update       entity.ContentRuleUpdate
+		MockGetErr   error
+		Expected     error
+		ExpectedRule *entity.ContentRule
+	}{
+		{
+			Name:   "Severity and pattern changed",
+			Update: entity.ContentRuleUpdate{ID: 1, Pattern: util.StringToPointer("spam"), Severity: &quarantine},
+			ExpectedRule: &entity.ContentRule{
+				ID: 1, Kind: entity.ContentRuleTerm, Pattern: util.StringToPointer("spam"), Severity: entity.ContentQuarantine, Enabled: true,
+			},
+		},
+		{
+			Name:     "Pattern without words",
+			Update:   entity.ContentRuleUpdate{ID: 1, Pattern: util.StringToPointer(" !? ")},
+			Expected: ErrInvalidContentRule,
+		},
+		{
+			Name:       "Rule does not exist",
+			Update:     entity.ContentRuleUpdate{ID: 1},
+			MockGetErr: repository.ErrRecordNotFound,
+			Expected:   repository.ErrRecordNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			current := &entity.ContentRule{
+				ID: 1, Kind: entity.ContentRuleTerm, Pattern: util.StringToPointer("buy now"), Severity: entity.ContentReject, Enabled: true,
+			}
+
+			repo.On("GetContentRule", ctx, test.Update.ID).Return(current, test.MockGetErr)
+			if test.ExpectedRule != nil {
+				repo.On("UpdateContentRule", ctx, mock.Anything).Return(nil)
+			}
+
+			rule, err := service.UpdateContentRule(ctx, test.Update)
+
+			assert.Equal(t, test.Expected, err)
+			assert.Equal(t, test.ExpectedRule, rule)
+		})
+	}
+}
+
+func TestCreateContentRule(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Rule     *entity.ContentRule
+		Expected error
+	}{
+		{
+			Name:     "Links rule created",
+			Rule:     &entity.ContentRule{Kind: entity.ContentRuleLinks, Threshold: intPointer(0), Severity: entity.ContentQuarantine},
+			Expected: nil,
+		},
+		{
+			Name:     "Unknown kind",
+			Rule:     &entity.ContentRule{Kind: "regex", Severity: entity.ContentReject},
+			Expected: ErrUnknownRuleKind,
+		},
+		{
+			Name:     "Unknown severity",
+			Rule:     &entity.ContentRule{Kind: entity.ContentRuleRepeat, Threshold: intPointer(3), Severity: "ban"},
+			Expected: ErrUnknownSeverity,
+		},
+		{
+			Name:     "Repeat rule without threshold",
+			Rule:     &entity.ContentRule{Kind: entity.ContentRuleRepeat, Severity: entity.ContentReject},
+			Expected: ErrInvalidContentRule,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			if test.Expected == nil {
+				repo.On("CreateContentRule", ctx, test.Rule).Return(nil)
+			}
+
+			assert.Equal(t, test.Expected, service.CreateContentRule(ctx, test.Rule))
+		})
+	}
+}
+
+func intPointer(v int) *int {
+	return &v
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"one-lab-final/internal/entity"
+)
+
+func (m *Manager) GetContentRules(ctx context.Context) ([]*entity.ContentRule, error) {
+	return m.Repository.GetContentRules(ctx)
+}
+
+func (m *Manager) CreateContentRule(ctx context.Context, rule *entity.ContentRule) error {
+	err := validateContentRule(rule)
+	if err != nil {
+		return err
+	}
+
+	return m.Repository.CreateContentRule(ctx, rule)
+}
+
+// UpdateContentRule changes pattern, threshold, severity or state of the
+// rule. Kind of the rule can not be changed
+func (m *Manager) UpdateContentRule(ctx context.Context, update entity.ContentRuleUpdate) (*entity.ContentRule, error) {
+	rule, err := m.Repository.GetContentRule(ctx, update.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Pattern != nil {
+		rule.Pattern = update.Pattern
+	}
+	if update.Threshold != nil {
+		rule.Threshold = update.Threshold
+	}
+	if update.Severity != nil {
+		rule.Severity = *update.Severity
+	}
+	if update.Enabled != nil {
+		rule.Enabled = *update.Enabled
+	}
+
+	err = validateContentRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.Repository.UpdateContentRule(ctx, rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (m *Manager) DeleteContentRule(ctx context.Context, ruleID int64) error {
+	return m.Repository.DeleteContentRule(ctx, ruleID)
+}
+
+func validateContentRule(rule *entity.ContentRule) error {
+	check, ok := contentChecks[rule.Kind]
+	if !ok {
+		return ErrUnknownRuleKind
+	}
+
+	if !rule.Severity.Valid() {
+		return ErrUnknownSeverity
+	}
+
+	if !check.Valid(rule) {
+		return ErrInvalidContentRule
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/config"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository/mocks"
+	blobMocks "one-lab-final/pkg/blob/mocks"
+	"one-lab-final/pkg/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPurgeDeletedUsers(t *testing.T) {
+	tests := []struct {
+		Name                string
+		Reviews             string
+		MockAvatarKeys      []string
+		MockError           error
+		ExpectDeleteReviews bool
+		ExpectErr           bool
+	}{
+		{
+			Name:           "Reviews are kept",
+			Reviews:        "keep",
+			MockAvatarKeys: []string{"avatars/15/abc"},
+		},
+		{
+			Name:                "Reviews are deleted",
+			Reviews:             "delete",
+			MockAvatarKeys:      []string{},
+			ExpectDeleteReviews: true,
+		},
+		{
+			Name:      "Some error ocurred while anonymizing",
+			Reviews:   "keep",
+			MockError: errors.New("critical error"),
+			ExpectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			store := blobMocks.NewStore(t)
+			cfg := &config.Config{AUTH: config.AuthConfig{DeletionGracePeriod: 48 * time.Hour, DeletedUserReviews: test.Reviews}}
+			service := New(repo, cfg, WithBlobStore(store))
+			ctx := context.Background()
+
+			repo.On("AnonymizeDeletedUsers", ctx, mock.MatchedBy(func(before time.Time) bool {
+				return time.Until(before.Add(48*time.Hour)).Abs() < time.Minute
+			}), test.ExpectDeleteReviews).Return(test.MockAvatarKeys, test.MockError)
+			for _, key := range test.MockAvatarKeys {
+				for _, size := range entity.AvatarSizes {
+					store.On("Delete", ctx, entity.AvatarBlobKey(key, size)).Return(nil)
+				}
+			}
+
+			err := service.PurgeDeletedUsers(ctx)
+
+			if test.ExpectErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestLoginRestoresDeletedUser(t *testing.T) {
+	password := "password"
+	hash, _ := util.HashPassword(password)
+	deletedAt := time.Now().Add(-time.Hour)
+
+	repo := mocks.NewRepository(t)
+	service := New(repo, &config.Config{})
+	ctx := context.Background()
+
+	user := &entity.User{ID: 15, Password: entity.Password{Hash: &hash}, DeletedAt: &deletedAt}
+
+	repo.On("GetUserByCredentials", ctx, "username").Return(user, nil)
+	repo.On("GetLoginAttempts", ctx, mock.AnythingOfType("[]string")).Return([]*entity.LoginAttempt{}, nil)
+	repo.On("ResetLoginAttempts", ctx, "user:15").Return(nil)
+	repo.On("RestoreUser", ctx, int64(15)).Return(nil)
+	repo.On("CreateToken", ctx, mock.AnythingOfType("*entity.Token")).Return(nil)
+	repo.On("CreateRefreshToken", ctx, mock.AnythingOfType("*entity.RefreshToken")).Return(nil)
+
+	_, err := service.Login(ctx, "username", password, entity.Client{})
+	assert.Nil(t, err)
+}
@@ -1,7 +1,114 @@
 package service
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
 
 var (
-	ErrInvalidSortValue = errors.New("invalid sort value")
+	ErrInvalidSortValue     = errors.New("invalid sort value")
+	ErrRefreshTokenReused   = errors.New("refresh token was already used, session is revoked")
+	ErrUserAlreadyActivated = errors.New("user is already activated")
+
+	ErrInvalidTwoFactorCode    = errors.New("two-factor code is invalid")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor authentication was not enrolled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorRequired       = errors.New("two-factor authentication is required for this role")
+
+	ErrUnknownProvider    = errors.New("identity provider does not exist")
+	ErrIdentityEmailTaken = errors.New("user with email from identity provider already exists")
+	ErrOIDCEmailRequired  = errors.New("identity provider did not return email")
+
+	ErrEmailTaken             = errors.New("user with this email already exists")
+	ErrEmailChangeRevertible  = errors.New("email was changed recently, new change is possible once the previous one can no longer be cancelled")
+	ErrUsernameTaken          = errors.New("user with this username already exists")
+	ErrUsernameChangeCooldown = errors.New("username was changed recently, try again later")
+
+	ErrUnsupportedImage = errors.New("image must be JPEG, PNG or GIF")
+	ErrImageTooLarge    = errors.New("image is too large")
+
+	ErrExportInProgress = errors.New("previous export is still in progress")
+	ErrInvalidSignature = errors.New("download link is invalid or expired")
+
+	ErrCannotFollowSelf = errors.New("user cannot follow themselves")
+	ErrInvalidCursor    = errors.New("invalid cursor value")
+
+	ErrUnknownRole       = errors.New("role does not exist")
+	ErrUnknownPermission = errors.New("permission does not exist")
+	ErrRoleExists        = errors.New("role with this name already exists")
+	ErrRoleInUse         = errors.New("role is assigned to users")
+	ErrBuiltInRole       = errors.New("built-in role cannot be changed")
+	ErrLastAdmin         = errors.New("at least one admin must remain")
+	ErrRoleAboveOwn      = errors.New("role has permissions which granting user does not have")
+
+	ErrReviewHidden      = errors.New("review is already hidden")
+	ErrReviewNotHidden   = errors.New("review is not hidden")
+	ErrUnknownVisibility = errors.New("visibility must be visible, pending, hidden, deleted or removed")
+
+	ErrUnknownRuleKind    = errors.New("content rule kind does not exist")
+	ErrUnknownSeverity    = errors.New("severity must be reject or quarantine")
+	ErrInvalidContentRule = errors.New("term rules require pattern, links and repeat rules require threshold")
+
+	ErrUnknownReportCategory = errors.New("report category does not exist")
+	ErrCannotReportOwnReview = errors.New("user cannot report their own review")
+	ErrAlreadyReported       = errors.New("review was already reported by user")
+	ErrNoOpenReports         = errors.New("review has no open reports")
+	ErrSuspensionRequired    = errors.New("suspend action requires suspension duration")
+	ErrDismissWithSuspension = errors.New("author can not be suspended when reports are dismissed")
+
+	ErrSuspensionNotActive = errors.New("suspension is expired or lifted")
+	ErrSuspensionTooShort  = errors.New("suspension would end in the past, lift it instead")
+
+	ErrUnknownScope    = errors.New("scope does not exist")
+	ErrScopeNotAllowed = errors.New("scope requires higher role")
+	ErrInvalidExpiry   = errors.New("expiry must be in the future")
 )
+
+// LoginLimitError is returned by Login when too many attempts failed. Locked
+// is set when the threshold was reached, otherwise the client is only delayed
+type LoginLimitError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginLimitError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("too many failed login attempts, try again in %s", e.RetryAfter.Round(time.Second))
+	}
+
+	return fmt.Sprintf("login attempted too early, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+// UsernameMovedError is returned when the user was looked up by one of
+// previous usernames
+type UsernameMovedError struct {
+	Username string
+}
+
+func (e *UsernameMovedError) Error() string {
+	return fmt.Sprintf("user was renamed to %s", e.Username)
+}
+
+// ValidationError describes rejected input field by field, e.g.
+// {"password": "must be at least 8 characters long"}
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for i, field := range fields {
+		fields[i] = fmt.Sprintf("%s %s", field, e.Fields[field])
+	}
+
+	return strings.Join(fields, ", ")
+}
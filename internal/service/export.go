@@ -0,0 +1,391 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+	"strconv"
+	"time"
+)
+
+// exportSection is a file of the data export archive. Records of a new per-user
+// entity are exported by adding a section with its repository method
+type exportSection struct {
+	Name    string
+	Collect func(ctx context.Context, repo repository.Repository, userID int64) (any, error)
+}
+
+var exportSections = []exportSection{
+	{
+		Name:    "profile.json",
+		Collect: exportProfile,
+	},
+	{
+		Name: "reviews.json",
+		Collect: func(ctx context.Context, repo repository.Repository, userID int64) (any, error) {
+			return repo.GetReviewsByUserID(ctx, userID)
+		},
+	},
+	{
+		Name: "sessions.json",
+		Collect: func(ctx context.Context, repo repository.Repository, userID int64) (any, error) {
+			return repo.GetSessionsByUserID(ctx, userID)
+		},
+	},
+	{
+		Name: "suspensions.json",
+		Collect: func(ctx context.Context, repo repository.Repository, userID int64) (any, error) {
+			return repo.GetSuspensionsByUserID(ctx, userID)
+		},
+	},
+	{
+		Name: "api_keys.json",
+		Collect: func(ctx context.Context, repo repository.Repository, userID int64) (any, error) {
+			return repo.GetAPIKeysByUserID(ctx, userID)
+		},
+	},
+	{
+		Name: "identities.json",
+		Collect: func(ctx context.Context, repo repository.Repository, userID int64) (any, error) {
+			return repo.GetIdentitiesByUserID(ctx, userID)
+		},
+	},
+	{
+		Name: "email_changes.json",
+		Collect: func(ctx context.Context, repo repository.Repository, userID int64) (any, error) {
+			return repo.GetEmailChangesByUserID(ctx, userID)
+		},
+	},
+	{
+		// Failed logins are counted per account, attempts with unknown
+		// credentials or from the client IP are not tied to the user
+		Name: "login_attempts.json",
+		Collect: func(ctx context.Context, repo repository.Repository, userID int64) (any, error) {
+			return repo.GetLoginAttempts(ctx, []string{accountKey(&entity.User{ID: userID}, "")})
+		},
+	},
+	{
+		Name:    "follows.json",
+		Collect: exportFollows,
+	},
+	{
+		Name:    "activities.json",
+		Collect: exportActivities,
+	},
+	{
+		Name: "role_changes.json",
+		Collect: func(ctx context.Context, repo repository.Repository, userID int64) (any, error) {
+			return collectPages(ctx, repo.GetRoleHistory, userID)
+		},
+	},
+	{
+		Name: "review_reports.json",
+		Collect: func(ctx context.Context, repo repository.Repository, userID int64) (any, error) {
+			return repo.GetReviewReportsByReporterID(ctx, userID)
+		},
+	},
+}
+
+// exportPageSize is the page size used to collect paginated records
+const exportPageSize = 100
+
+// exportedProfile includes fields hidden from public profile, e.g. email
+type exportedProfile struct {
+	ID        int64      `json:"id"`
+	Username  *string    `json:"username"`
+	Email     *string    `json:"email"`
+	FirstName *string    `json:"first_name"`
+	LastName  *string    `json:"last_name"`
+	Bio       *string    `json:"bio"`
+	Location  *string    `json:"location"`
+	Website   *string    `json:"website"`
+	Role      string     `json:"role"`
+	Activated bool       `json:"activated"`
+	TwoFactor bool       `json:"two_factor"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func exportProfile(ctx context.Context, repo repository.Repository, userID int64) (any, error) {
+	user, err := repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &exportedProfile{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Bio:       user.Bio,
+		Location:  user.Location,
+		Website:   user.Website,
+		Role:      user.Role.String(),
+		Activated: user.Activated,
+		TwoFactor: user.TwoFactor,
+		DeletedAt: user.DeletedAt,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}, nil
+}
+
+type exportedFollows struct {
+	Following []*entity.Follow `json:"following"`
+	Followers []*entity.Follow `json:"followers"`
+}
+
+func exportFollows(ctx context.Context, repo repository.Repository, userID int64) (any, error) {
+	following, err := collectPages(ctx, repo.GetFollowing, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	followers, err := collectPages(ctx, repo.GetFollowers, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &exportedFollows{Following: following, Followers: followers}, nil
+}
+
+// collectPages collects every page of paginated records of the user, oldest
+// first
+func collectPages[T any](ctx context.Context, list func(context.Context, int64, util.Filter) ([]T, *util.Metadata, error), userID int64) ([]T, error) {
+	all := make([]T, 0)
+
+	for page := 1; ; page++ {
+		records, _, err := list(ctx, userID, util.NewFilter(page, exportPageSize, "created_at"))
+		if err != nil {
+			return nil, err
+		}
+
+		all = append(all, records...)
+
+		if len(records) < exportPageSize {
+			return all, nil
+		}
+	}
+}
+
+// exportActivities collects the whole feed of the user page by page
+func exportActivities(ctx context.Context, repo repository.Repository, userID int64) (any, error) {
+	var after *entity.FeedCursor
+	activities := make([]*entity.Activity, 0)
+
+	for {
+		records, err := repo.GetFeed(ctx, userID, after, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		activities = append(activities, records...)
+
+		if len(records) < exportPageSize {
+			return activities, nil
+		}
+
+		last := records[len(records)-1]
+		after = &entity.FeedCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+// RequestDataExport queues export of user's data, it is built by
+// ProcessDataExports
+func (m *Manager) RequestDataExport(ctx context.Context, userID int64) (*entity.DataExport, error) {
+	export := &entity.DataExport{
+		UserID: userID,
+		Status: entity.ExportPending,
+	}
+
+	err := m.Repository.CreateDataExport(ctx, export)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateRecord):
+			return nil, ErrExportInProgress
+		default:
+			return nil, err
+		}
+	}
+
+	return export, nil
+}
+
+// GetDataExport returns export of the user, ready export gets a new signed
+// download URL
+func (m *Manager) GetDataExport(ctx context.Context, exportID int64, userID int64) (*entity.DataExport, error) {
+	export, err := m.Repository.GetDataExport(ctx, exportID)
+	if err != nil {
+		return nil, err
+	}
+
+	if export.UserID != userID {
+		return nil, repository.ErrRecordNotFound
+	}
+
+	if export.Status == entity.ExportReady {
+		expiry := time.Now().Add(m.exportURLExpiration()).Truncate(time.Second)
+		if export.Expiry != nil && export.Expiry.Before(expiry) {
+			expiry = *export.Expiry
+		}
+
+		url := fmt.Sprintf("/api/v1/users/export/%d/download?expires=%d&signature=%s", export.ID, expiry.Unix(), m.signExport(export.ID, expiry.Unix()))
+		export.DownloadURL = &url
+		export.DownloadExpiry = &expiry
+	}
+
+	return export, nil
+}
+
+// DownloadDataExport opens archive of the export if the signature of download
+// URL is valid and not expired
+func (m *Manager) DownloadDataExport(ctx context.Context, exportID int64, expires int64, signature string) (io.ReadCloser, error) {
+	expected := m.signExport(exportID, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) || time.Now().Unix() > expires {
+		return nil, ErrInvalidSignature
+	}
+
+	export, err := m.Repository.GetDataExport(ctx, exportID)
+	if err != nil {
+		return nil, err
+	}
+
+	if export.Status != entity.ExportReady || export.BlobKey == nil {
+		return nil, repository.ErrRecordNotFound
+	}
+
+	return m.Blob.Get(ctx, *export.BlobKey)
+}
+
+// ProcessDataExports builds every queued export and removes expired ones
+func (m *Manager) ProcessDataExports(ctx context.Context) error {
+	keys, err := m.Repository.DeleteExpiredDataExports(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		err = m.Blob.Delete(ctx, key)
+		if err != nil {
+			log.Printf("deleting data export err: %s", err.Error())
+		}
+	}
+
+	for {
+		export, err := m.Repository.ClaimDataExport(ctx, time.Hour)
+		if err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				return nil
+			}
+
+			return err
+		}
+
+		err = m.buildDataExport(ctx, export)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// buildDataExport stores the archive and records the result. Failure of
+// a single export is recorded in the export itself, only failure to record
+// the result is returned
+func (m *Manager) buildDataExport(ctx context.Context, export *entity.DataExport) error {
+	expiry := time.Now().Add(m.exportExpiration())
+	export.Expiry = &expiry
+
+	key, err := m.writeDataExport(ctx, export.UserID)
+	if err != nil {
+		log.Printf("data export %d err: %s", export.ID, err.Error())
+
+		message := "export could not be built, request a new one"
+		export.Status = entity.ExportFailed
+		export.Error = &message
+	} else {
+		export.Status = entity.ExportReady
+		export.BlobKey = &key
+	}
+
+	return m.Repository.FinishDataExport(ctx, export)
+}
+
+func (m *Manager) writeDataExport(ctx context.Context, userID int64) (string, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	for _, section := range exportSections {
+		records, err := section.Collect(ctx, m.Repository, userID)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", section.Name, err)
+		}
+
+		file, err := archive.Create(section.Name)
+		if err != nil {
+			return "", err
+		}
+
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(records)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", section.Name, err)
+		}
+	}
+
+	err := archive.Close()
+	if err != nil {
+		return "", err
+	}
+
+	suffix := make([]byte, 16)
+	_, err = rand.Read(suffix)
+	if err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("exports/%d/%s.zip", userID, hex.EncodeToString(suffix))
+
+	err = m.Blob.Put(ctx, key, buf.Bytes(), "application/zip")
+	if err != nil {
+		return "", err
+	}
+
+	return key, nil
+}
+
+func (m *Manager) signExport(exportID int64, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(m.Config.AUTH.SigningKey))
+	mac.Write([]byte("export:" + strconv.FormatInt(exportID, 10) + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (m *Manager) exportExpiration() time.Duration {
+	if m.Config != nil && m.Config.EXPORT.Expiration != 0 {
+		return m.Config.EXPORT.Expiration
+	}
+
+	return 7 * 24 * time.Hour
+}
+
+func (m *Manager) exportURLExpiration() time.Duration {
+	if m.Config != nil && m.Config.EXPORT.URLExpiration != 0 {
+		return m.Config.EXPORT.URLExpiration
+	}
+
+	return time.Hour
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"one-lab-final/internal/config"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/repository/mocks"
+	"one-lab-final/pkg/blob"
+	"one-lab-final/pkg/util"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestDataExport(t *testing.T) {
+	var userID int64 = 15
+	tests := []struct {
+		Name        string
+		MockError   error
+		ExpectedErr error
+	}{
+		{
+			Name: "Export queued",
+		},
+		{
+			Name:        "Previous export in progress",
+			MockError:   repository.ErrDuplicateRecord,
+			ExpectedErr: ErrExportInProgress,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("CreateDataExport", ctx, &entity.DataExport{UserID: userID, Status: entity.ExportPending}).Return(test.MockError)
+
+			export, err := service.RequestDataExport(ctx, userID)
+			assert.ErrorIs(t, err, test.ExpectedErr)
+			if test.ExpectedErr == nil {
+				assert.Equal(t, entity.ExportPending, export.Status)
+			}
+		})
+	}
+}
+
+func TestGetDataExport(t *testing.T) {
+	var userID int64 = 15
+	expiry := time.Now().Add(24 * time.Hour)
+	tests := []struct {
+		Name        string
+		MockResult  *entity.DataExport
+		ExpectURL   bool
+		ExpectedErr error
+	}{
+		{
+			Name:       "Export is pending",
+			MockResult: &entity.DataExport{ID: 1, UserID: userID, Status: entity.ExportPending},
+		},
+		{
+			Name:       "Export is ready",
+			MockResult: &entity.DataExport{ID: 1, UserID: userID, Status: entity.ExportReady, Expiry: &expiry},
+			ExpectURL:  true,
+		},
+		{
+			Name:        "Export of another user",
+			MockResult:  &entity.DataExport{ID: 1, UserID: 16, Status: entity.ExportReady, Expiry: &expiry},
+			ExpectedErr: repository.ErrRecordNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, &config.Config{AUTH: config.AuthConfig{SigningKey: "key"}})
+			ctx := context.Background()
+
+			repo.On("GetDataExport", ctx, int64(1)).Return(test.MockResult, nil)
+
+			export, err := service.GetDataExport(ctx, 1, userID)
+			assert.ErrorIs(t, err, test.ExpectedErr)
+			if err != nil {
+				return
+			}
+
+			if !test.ExpectURL {
+				assert.Nil(t, export.DownloadURL)
+				return
+			}
+
+			// Signed URL is accepted by DownloadDataExport
+			link, err := url.Parse(*export.DownloadURL)
+			assert.Nil(t, err)
+			assert.Equal(t, "/api/v1/users/export/1/download", link.Path)
+
+			expires, _ := strconv.ParseInt(link.Query().Get("expires"), 10, 64)
+			assert.Equal(t, export.DownloadExpiry.Unix(), expires)
+			assert.Equal(t, service.signExport(1, expires), link.Query().Get("signature"))
+		})
+	}
+}
+
+func TestDownloadDataExport(t *testing.T) {
+	store, _ := blob.NewLocalStore(t.TempDir(), "/media")
+	store.Put(context.Background(), "exports/15/abc.zip", []byte("archive"), "application/zip")
+
+	valid := time.Now().Add(time.Hour).Unix()
+	expired := time.Now().Add(-time.Minute).Unix()
+
+	tests := []struct {
+		Name        string
+		Expires     int64
+		Signature   func(s *Manager, expires int64) string
+		MockResult  *entity.DataExport
+		ExpectedErr error
+	}{
+		{
+			Name:       "Archive is downloaded",
+			Expires:    valid,
+			Signature:  func(s *Manager, expires int64) string { return s.signExport(1, expires) },
+			MockResult: &entity.DataExport{ID: 1, Status: entity.ExportReady, BlobKey: util.StringToPointer("exports/15/abc.zip")},
+		},
+		{
+			Name:        "Signature is invalid",
+			Expires:     valid,
+			Signature:   func(s *Manager, expires int64) string { return s.signExport(2, expires) },
+			ExpectedErr: ErrInvalidSignature,
+		},
+		{
+			Name:        "Link is expired",
+			Expires:     expired,
+			Signature:   func(s *Manager, expires int64) string { return s.signExport(1, expires) },
+			ExpectedErr: ErrInvalidSignature,
+		},
+		{
+			Name:        "Export is not ready",
+			Expires:     valid,
+			Signature:   func(s *Manager, expires int64) string { return s.signExport(1, expires) },
+			MockResult:  &entity.DataExport{ID: 1, Status: entity.ExportFailed},
+			ExpectedErr: repository.ErrRecordNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, &config.Config{AUTH: config.AuthConfig{SigningKey: "key"}}, WithBlobStore(store))
+			ctx := context.Background()
+
+			if test.MockResult != nil {
+				repo.On("GetDataExport", ctx, int64(1)).Return(test.MockResult, nil)
+			}
+
+			archive, err := service.DownloadDataExport(ctx, 1, test.Expires, test.Signature(service, test.Expires))
+			assert.ErrorIs(t, err, test.ExpectedErr)
+			if err != nil {
+				return
+			}
+
+			defer archive.Close()
+			data, _ := io.ReadAll(archive)
+			assert.Equal(t, "archive", string(data))
+		})
+	}
+}
+
+func TestProcessDataExports(t *testing.T) {
+	var userID int64 = 15
+	tests := []struct {
+		Name           string
+		MockReviewsErr error
+		ExpectedStatus string
+	}{
+		{
+			Name:           "Archive is built",
+			ExpectedStatus: entity.ExportReady,
+		},
+		{
+			Name:           "Collecting records failed",
+			MockReviewsErr: errors.New("critical error"),
+			ExpectedStatus: entity.ExportFailed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			store, _ := blob.NewLocalStore(t.TempDir(), "/media")
+			store.Put(context.Background(), "exports/15/old.zip", []byte("old"), "application/zip")
+
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil, WithBlobStore(store))
+			ctx := context.Background()
+
+			var finished *entity.DataExport
+
+			repo.On("DeleteExpiredDataExports", ctx).Return([]string{"exports/15/old.zip"}, nil)
+			repo.On("ClaimDataExport", ctx, time.Hour).Return(&entity.DataExport{ID: 1, UserID: userID, Status: entity.ExportRunning}, nil).Once()
+			repo.On("ClaimDataExport", ctx, time.Hour).Return(nil, repository.ErrRecordNotFound).Once()
+			repo.On("GetUserByID", ctx, userID).Return(&entity.User{ID: userID, Email: util.StringToPointer("user@gmail.com"), Role: entity.USER}, nil)
+			repo.On("GetReviewsByUserID", ctx, userID).Return([]*entity.Review{{ID: 3, UserID: userID}}, test.MockReviewsErr)
+			if test.MockReviewsErr == nil {
+				repo.On("GetSessionsByUserID", ctx, userID).Return([]*entity.Session{}, nil)
+				repo.On("GetSuspensionsByUserID", ctx, userID).Return([]*entity.Suspension{}, nil)
+				repo.On("GetAPIKeysByUserID", ctx, userID).Return([]*entity.APIKey{}, nil)
+				repo.On("GetIdentitiesByUserID", ctx, userID).Return([]*entity.Identity{}, nil)
+				repo.On("GetEmailChangesByUserID", ctx, userID).Return([]*entity.EmailChange{}, nil)
+				repo.On("GetLoginAttempts", ctx, []string{"user:15"}).Return([]*entity.LoginAttempt{}, nil)
+				repo.On("GetFollowing", ctx, userID, util.NewFilter(1, exportPageSize, "created_at")).Return([]*entity.Follow{{UserID: 7}}, &util.Metadata{}, nil)
+				repo.On("GetFollowers", ctx, userID, util.NewFilter(1, exportPageSize, "created_at")).Return([]*entity.Follow{}, &util.Metadata{}, nil)
+				repo.On("GetFeed", ctx, userID, (*entity.FeedCursor)(nil), exportPageSize).Return([]*entity.Activity{{ID: 4, UserID: 7}}, nil)
+				repo.On("GetRoleHistory", ctx, userID, util.NewFilter(1, exportPageSize, "created_at")).Return([]*entity.RoleChange{{ID: 2, UserID: userID}}, &util.Metadata{}, nil)
+				repo.On("GetReviewReportsByReporterID", ctx, userID).Return([]*entity.ReviewReport{{ID: 8, ReporterID: userID}}, nil)
+			}
+			repo.On("FinishDataExport", ctx, mock.MatchedBy(func(export *entity.DataExport) bool {
+				finished = export
+				return export.ID == 1
+			})).Return(nil)
+
+			err := service.ProcessDataExports(ctx)
+			assert.Nil(t, err)
+
+			_, err = store.Get(ctx, "exports/15/old.zip")
+			assert.ErrorIs(t, err, blob.ErrNotFound)
+
+			assert.Equal(t, test.ExpectedStatus, finished.Status)
+			assert.NotNil(t, finished.Expiry)
+			if test.ExpectedStatus != entity.ExportReady {
+				assert.NotNil(t, finished.Error)
+				return
+			}
+
+			file, err := store.Get(ctx, *finished.BlobKey)
+			assert.Nil(t, err)
+			data, _ := io.ReadAll(file)
+			file.Close()
+
+			archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+			assert.Nil(t, err)
+
+			names := make([]string, 0)
+			for _, f := range archive.File {
+				names = append(names, f.Name)
+			}
+			assert.Equal(t, []string{"profile.json", "reviews.json", "sessions.json", "suspensions.json", "api_keys.json", "identities.json", "email_changes.json", "login_attempts.json", "follows.json", "activities.json", "role_changes.json", "review_reports.json"}, names)
+
+			profile, _ := archive.File[0].Open()
+			content, _ := io.ReadAll(profile)
+			assert.Contains(t, string(content), `"email": "user@gmail.com"`)
+			assert.Contains(t, string(content), `"role": "USER"`)
+		})
+	}
+}
+
+func TestExportActivities(t *testing.T) {
+	var userID int64 = 15
+	repo := mocks.NewRepository(t)
+	ctx := context.Background()
+
+	page := make([]*entity.Activity, exportPageSize)
+	for i := range page {
+		page[i] = &entity.Activity{ID: int64(exportPageSize - i), CreatedAt: time.Unix(int64(exportPageSize-i), 0)}
+	}
+
+	repo.On("GetFeed", ctx, userID, (*entity.FeedCursor)(nil), exportPageSize).Return(page, nil)
+	repo.On("GetFeed", ctx, userID, &entity.FeedCursor{CreatedAt: time.Unix(1, 0), ID: 1}, exportPageSize).Return([]*entity.Activity{{ID: 0}}, nil)
+
+	activities, err := exportActivities(ctx, repo, userID)
+	assert.Nil(t, err)
+	assert.Len(t, activities, exportPageSize+1)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/pkg/util"
+	"time"
+)
+
+func (m *Manager) FollowUser(ctx context.Context, followerID int64, username string) error {
+	user, err := m.GetUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if user.ID == followerID {
+		return ErrCannotFollowSelf
+	}
+
+	return m.Repository.FollowUser(ctx, followerID, user.ID)
+}
+
+func (m *Manager) UnfollowUser(ctx context.Context, followerID int64, username string) error {
+	user, err := m.GetUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return m.Repository.UnfollowUser(ctx, followerID, user.ID)
+}
+
+func (m *Manager) GetFollowers(ctx context.Context, username string, filter util.Filter) ([]*entity.Follow, *util.Metadata, error) {
+	filterSafeList := []string{"username", "created_at"}
+
+	if !filter.ValidateSort(filterSafeList) {
+		return nil, nil, ErrInvalidSortValue
+	}
+
+	user, err := m.GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return m.Repository.GetFollowers(ctx, user.ID, filter)
+}
+
+func (m *Manager) GetFollowing(ctx context.Context, username string, filter util.Filter) ([]*entity.Follow, *util.Metadata, error) {
+	filterSafeList := []string{"username", "created_at"}
+
+	if !filter.ValidateSort(filterSafeList) {
+		return nil, nil, ErrInvalidSortValue
+	}
+
+	user, err := m.GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return m.Repository.GetFollowing(ctx, user.ID, filter)
+}
+
+// GetFeed returns up to limit activities after the cursor and the cursor of
+// the next page, which is empty when the feed is exhausted
+func (m *Manager) GetFeed(ctx context.Context, userID int64, cursor string, limit int) ([]*entity.Activity, string, error) {
+	var after *entity.FeedCursor
+
+	if cursor != "" {
+		decoded, err := decodeFeedCursor(cursor)
+		if err != nil {
+			return nil, "", ErrInvalidCursor
+		}
+
+		after = decoded
+	}
+
+	// One extra activity tells whether the next page exists
+	activities, err := m.Repository.GetFeed(ctx, userID, after, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(activities) <= limit {
+		return activities, "", nil
+	}
+
+	activities = activities[:limit]
+	last := activities[limit-1]
+
+	return activities, encodeFeedCursor(entity.FeedCursor{CreatedAt: last.CreatedAt, ID: last.ID}), nil
+}
+
+func encodeFeedCursor(cursor entity.FeedCursor) string {
+	raw := fmt.Sprintf("%d:%d", cursor.CreatedAt.UnixNano(), cursor.ID)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeFeedCursor(cursor string) (*entity.FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var nanos, id int64
+
+	_, err = fmt.Sscanf(string(raw), "%d:%d", &nanos, &id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.FeedCursor{CreatedAt: time.Unix(0, nanos), ID: id}, nil
+}
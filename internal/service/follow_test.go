@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/repository/mocks"
+	"one-lab-final/pkg/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFollowUser(t *testing.T) {
+	var followerID int64 = 1
+	tests := []struct {
+		Name          string
+		Username      string
+		MockUser      *entity.User
+		MockUserError error
+		ExpectFollow  bool
+		ExpectErr     error
+	}{
+		{
+			Name:         "User followed",
+			Username:     "john_doe",
+			MockUser:     &entity.User{ID: 2},
+			ExpectFollow: true,
+		},
+		{
+			Name:      "User follows themselves",
+			Username:  "jane_doe",
+			MockUser:  &entity.User{ID: followerID},
+			ExpectErr: ErrCannotFollowSelf,
+		},
+		{
+			Name:          "User does not exist",
+			Username:      "nobody",
+			MockUserError: repository.ErrRecordNotFound,
+			ExpectErr:     repository.ErrRecordNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("GetUserByUsername", ctx, test.Username).Return(test.MockUser, test.MockUserError)
+			if test.MockUserError != nil {
+				repo.On("GetUsernameRedirect", ctx, test.Username).Return("", test.MockUserError)
+			}
+			if test.ExpectFollow {
+				repo.On("FollowUser", ctx, followerID, test.MockUser.ID).Return(nil)
+			}
+
+			err := service.FollowUser(ctx, followerID, test.Username)
+			assert.ErrorIs(t, err, test.ExpectErr)
+		})
+	}
+}
+
+func TestGetFollowers(t *testing.T) {
+	repo := mocks.NewRepository(t)
+	service := New(repo, nil)
+	ctx := context.Background()
+
+	_, _, err := service.GetFollowers(ctx, "john_doe", util.NewFilter(1, 50, "password_hash"))
+	assert.ErrorIs(t, err, ErrInvalidSortValue)
+
+	filter := util.NewFilter(1, 50, "username")
+	repo.On("GetUserByUsername", ctx, "john_doe").Return(&entity.User{ID: 2}, nil)
+	repo.On("GetFollowers", ctx, int64(2), filter).Return([]*entity.Follow{{UserID: 3}}, &util.Metadata{}, nil)
+
+	follows, _, err := service.GetFollowers(ctx, "john_doe", filter)
+	assert.NoError(t, err)
+	assert.Len(t, follows, 1)
+}
+
+func TestGetFeed(t *testing.T) {
+	var userID int64 = 1
+	now := time.Now().Truncate(time.Second)
+	activities := []*entity.Activity{
+		{ID: 9, Kind: entity.ActivityReviewUpdated, CreatedAt: now},
+		{ID: 7, Kind: entity.ActivityReviewCreated, CreatedAt: now},
+		{ID: 8, Kind: entity.ActivityUserFollowed, CreatedAt: now.Add(-time.Minute)},
+	}
+
+	t.Run("First page", func(t *testing.T) {
+		repo := mocks.NewRepository(t)
+		service := New(repo, nil)
+		ctx := context.Background()
+
+		repo.On("GetFeed", ctx, userID, (*entity.FeedCursor)(nil), 3).Return(activities, nil)
+
+		page, next, err := service.GetFeed(ctx, userID, "", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, activities[:2], page)
+		assert.NotEmpty(t, next)
+
+		cursor, err := decodeFeedCursor(next)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), cursor.ID)
+		assert.True(t, now.Equal(cursor.CreatedAt))
+	})
+
+	t.Run("Last page", func(t *testing.T) {
+		repo := mocks.NewRepository(t)
+		service := New(repo, nil)
+		ctx := context.Background()
+
+		cursor := entity.FeedCursor{CreatedAt: now, ID: 7}
+		repo.On("GetFeed", ctx, userID, &cursor, 3).Return(activities[2:], nil)
+
+		page, next, err := service.GetFeed(ctx, userID, encodeFeedCursor(cursor), 2)
+		assert.NoError(t, err)
+		assert.Equal(t, activities[2:], page)
+		assert.Empty(t, next)
+	})
+
+	t.Run("Non-valid cursor", func(t *testing.T) {
+		repo := mocks.NewRepository(t)
+		service := New(repo, nil)
+
+		_, _, err := service.GetFeed(context.Background(), userID, "not a cursor", 2)
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("Error while retrieving", func(t *testing.T) {
+		repo := mocks.NewRepository(t)
+		service := New(repo, nil)
+		ctx := context.Background()
+
+		repo.On("GetFeed", ctx, userID, (*entity.FeedCursor)(nil), 21).Return(nil, errors.New("critical error"))
+
+		_, _, err := service.GetFeed(ctx, userID, "", 20)
+		assert.Error(t, err)
+	})
+}
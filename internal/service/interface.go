@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"io"
 	"one-lab-final/internal/entity"
 	"one-lab-final/pkg/util"
+	"time"
 )
 
 type Service interface {
@@ -11,8 +13,28 @@ type Service interface {
 	GetUserByToken(ctx context.Context, token string) (*entity.User, error)
 	GetUserByUsername(ctx context.Context, username string) (*entity.User, error)
 	GetUserByCredentials(ctx context.Context, credentials string) (*entity.User, error)
-	UpdateUser(ctx context.Context, user *entity.User) error
+	UpdateUser(ctx context.Context, user *entity.User, currentToken string) error
 	DeleteUser(ctx context.Context, id int64) error
+	PurgeDeletedUsers(ctx context.Context) error
+
+	UpdateAvatar(ctx context.Context, userID int64, file io.Reader) (*entity.Avatar, error)
+	DeleteAvatar(ctx context.Context, userID int64) error
+
+	RequestDataExport(ctx context.Context, userID int64) (*entity.DataExport, error)
+	GetDataExport(ctx context.Context, exportID int64, userID int64) (*entity.DataExport, error)
+	DownloadDataExport(ctx context.Context, exportID int64, expires int64, signature string) (io.ReadCloser, error)
+	ProcessDataExports(ctx context.Context) error
+
+	ActivateUser(ctx context.Context, token string) error
+	ResendActivation(ctx context.Context, userID int64) error
+
+	RequestEmailChange(ctx context.Context, userID int64, email string) error
+	ConfirmEmailChange(ctx context.Context, token string) error
+	CancelEmailChange(ctx context.Context, token string) error
+	ChangeUsername(ctx context.Context, userID int64, username string) error
+
+	RequestPasswordReset(ctx context.Context, credentials string) error
+	ResetPassword(ctx context.Context, token string, password string) error
 
 	CreateBook(ctx context.Context, book *entity.Book) error
 	GetBookByID(ctx context.Context, bookID int64) (*entity.Book, error)
@@ -21,18 +43,68 @@ type Service interface {
 	DeleteBook(ctx context.Context, articleID int64) error
 
 	RefreshBooksRating(ctx context.Context) error
+	RefreshUserStats(ctx context.Context) error
 
 	CreateReview(ctx context.Context, review *entity.Review) error
 	GetReviewsByBookID(ctx context.Context, bookID int64, filter util.Filter) ([]*entity.Review, *util.Metadata, error)
+	GetOwnReviews(ctx context.Context, userID int64) ([]*entity.Review, error)
+	GetUserStats(ctx context.Context, username string) (*entity.UserStats, error)
 	UpdateReview(ctx context.Context, review *entity.Review) error
 	DeleteReview(ctx context.Context, reviewID int64, userID int64) error
+	RemoveReview(ctx context.Context, reviewID int64, moderatorID int64) error
+	HideReview(ctx context.Context, review *entity.Review) error
+	RestoreReview(ctx context.Context, review *entity.Review) error
+	GetReviews(ctx context.Context, search entity.ReviewSearch, filter util.Filter) ([]*entity.Review, *util.Metadata, error)
 
-	Login(ctx context.Context, credentials string, password string) (*entity.Token, error)
+	GetContentRules(ctx context.Context) ([]*entity.ContentRule, error)
+	CreateContentRule(ctx context.Context, rule *entity.ContentRule) error
+	UpdateContentRule(ctx context.Context, update entity.ContentRuleUpdate) (*entity.ContentRule, error)
+	DeleteContentRule(ctx context.Context, ruleID int64) error
+
+	ReportReview(ctx context.Context, report *entity.ReviewReport) error
+	GetReviewReports(ctx context.Context, reviewID int64) ([]*entity.ReviewReport, error)
+	GetReportedReviews(ctx context.Context, search entity.ReportSearch, filter util.Filter) ([]*entity.ReportedReview, *util.Metadata, error)
+	ResolveReviewReports(ctx context.Context, resolution *entity.ReportResolution) error
+
+	FollowUser(ctx context.Context, followerID int64, username string) error
+	UnfollowUser(ctx context.Context, followerID int64, username string) error
+	GetFollowers(ctx context.Context, username string, filter util.Filter) ([]*entity.Follow, *util.Metadata, error)
+	GetFollowing(ctx context.Context, username string, filter util.Filter) ([]*entity.Follow, *util.Metadata, error)
+	GetFeed(ctx context.Context, userID int64, cursor string, limit int) ([]*entity.Activity, string, error)
+
+	Login(ctx context.Context, credentials string, password string, client entity.Client) (*entity.Token, error)
+	RefreshToken(ctx context.Context, refreshToken string, client entity.Client) (*entity.Token, error)
+	OIDCLoginURL(ctx context.Context, provider string) (string, error)
+	OIDCLogin(ctx context.Context, provider string, code string, state string, client entity.Client) (*entity.Token, error)
+	Logout(ctx context.Context, token string) error
+	GetSessions(ctx context.Context, userID int64, currentToken string) ([]*entity.Session, error)
+	DeleteSession(ctx context.Context, sessionID int64, userID int64) error
+	DeleteAllSessions(ctx context.Context, userID int64) error
 	DeleteExpiredTokens(ctx context.Context) error
+	DeleteStaleLoginAttempts(ctx context.Context) error
+
+	CreateAPIKey(ctx context.Context, userID int64, name string, scopes []entity.APIScope, expiry *time.Time) (*entity.APIKey, error)
+	GetAPIKeys(ctx context.Context, userID int64) ([]*entity.APIKey, error)
+	DeleteAPIKey(ctx context.Context, keyID int64, userID int64) error
+
+	LoginTwoFactor(ctx context.Context, token string, code string, client entity.Client) (*entity.Token, error)
+	EnrollTwoFactor(ctx context.Context, userID int64) (*entity.TwoFactor, error)
+	ConfirmTwoFactor(ctx context.Context, userID int64, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userID int64, code string) error
 
 	NewSuspension(ctx context.Context, suspension *entity.Suspension) error
+	GetSuspension(ctx context.Context, suspensionID int64) (*entity.Suspension, error)
+	GetSuspensions(ctx context.Context, search entity.SuspensionSearch, filter util.Filter) ([]*entity.Suspension, *util.Metadata, error)
 	UpdateSuspension(ctx context.Context, suspension *entity.Suspension) error
+	LiftSuspension(ctx context.Context, suspension *entity.Suspension) error
 	CheckSuspension(ctx context.Context, userID int64) ([]*entity.Suspension, error)
 
-	GrantRoleToUser(ctx context.Context, userID int64, role entity.Role) error
+	GrantRoleToUser(ctx context.Context, change *entity.RoleChange) error
+	GetRoleHistory(ctx context.Context, userID int64, filter util.Filter) ([]*entity.RoleChange, *util.Metadata, error)
+	GetRoles(ctx context.Context) ([]*entity.RoleDefinition, error)
+	GetRole(ctx context.Context, name entity.Role) (*entity.RoleDefinition, error)
+	CreateRole(ctx context.Context, actorID int64, role *entity.RoleDefinition) error
+	UpdateRole(ctx context.Context, actorID int64, role *entity.RoleDefinition) error
+	DeleteRole(ctx context.Context, actorID int64, name entity.Role) error
+	GetUsers(ctx context.Context, search entity.UserSearch, filter util.Filter) ([]*entity.UserOverview, *util.Metadata, error)
 }
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"one-lab-final/internal/entity"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Exposed on /metrics together with gin metrics, alert on increase of lockouts
+var loginLockouts = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "auth_login_lockouts_total",
+	Help: "Number of times an account or a client IP was locked out after failed logins",
+}, []string{"scope"})
+
+type loginLimits struct {
+	threshold   int
+	ipThreshold int
+	window      time.Duration
+	delay       time.Duration
+}
+
+func (m *Manager) loginLimits() loginLimits {
+	limits := loginLimits{
+		threshold:   5,
+		ipThreshold: 50,
+		window:      15 * time.Minute,
+		delay:       time.Second,
+	}
+
+	if m.Config == nil {
+		return limits
+	}
+
+	if m.Config.AUTH.LoginLockoutThreshold != 0 {
+		limits.threshold = m.Config.AUTH.LoginLockoutThreshold
+	}
+	if m.Config.AUTH.LoginIPLockoutThreshold != 0 {
+		limits.ipThreshold = m.Config.AUTH.LoginIPLockoutThreshold
+	}
+	if m.Config.AUTH.LoginLockoutDuration != 0 {
+		limits.window = m.Config.AUTH.LoginLockoutDuration
+	}
+	if m.Config.AUTH.LoginDelay != 0 {
+		limits.delay = m.Config.AUTH.LoginDelay
+	}
+
+	return limits
+}
+
+// accountKey identifies an account for counting failed logins. Unknown
+// accounts are counted by credentials, so they are limited the same way
+func accountKey(user *entity.User, credentials string) string {
+	if user != nil {
+		return fmt.Sprintf("user:%d", user.ID)
+	}
+
+	return "credentials:" + strings.ToLower(strings.TrimSpace(credentials))
+}
+
+func ipKey(client entity.Client) string {
+	return "ip:" + client.IP
+}
+
+// checkLoginLimits returns LoginLimitError if any of keys is locked or
+// was used for a failed login too recently
+func (m *Manager) checkLoginLimits(ctx context.Context, keys ...string) error {
+	attempts, err := m.Repository.GetLoginAttempts(ctx, keys)
+	if err != nil {
+		return err
+	}
+
+	limits := m.loginLimits()
+	now := time.Now()
+
+	var limitErr *LoginLimitError
+	for _, attempt := range attempts {
+		// Failures outside of the window are forgotten on the next failure
+		if attempt.LastFailureAt.Add(limits.window).Before(now) {
+			continue
+		}
+
+		if attempt.LockedUntil != nil && attempt.LockedUntil.After(now) {
+			retryAfter := attempt.LockedUntil.Sub(now)
+			if limitErr == nil || !limitErr.Locked || retryAfter > limitErr.RetryAfter {
+				limitErr = &LoginLimitError{RetryAfter: retryAfter, Locked: true}
+			}
+			continue
+		}
+
+		retryAfter := attempt.LastFailureAt.Add(limits.delayAfter(attempt.Failures)).Sub(now)
+		if retryAfter > 0 && (limitErr == nil || (!limitErr.Locked && retryAfter > limitErr.RetryAfter)) {
+			limitErr = &LoginLimitError{RetryAfter: retryAfter}
+		}
+	}
+
+	if limitErr != nil {
+		return limitErr
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts the failure for the account and the client IP
+func (m *Manager) recordLoginFailure(ctx context.Context, account string, ip string) error {
+	limits := m.loginLimits()
+
+	for _, key := range []struct {
+		value     string
+		scope     string
+		threshold int
+	}{
+		{value: account, scope: "account", threshold: limits.threshold},
+		{value: ip, scope: "ip", threshold: limits.ipThreshold},
+	} {
+		attempt, err := m.Repository.RecordLoginFailure(ctx, key.value, key.threshold, limits.window)
+		if err != nil {
+			return err
+		}
+
+		if attempt.Failures == key.threshold {
+			loginLockouts.WithLabelValues(key.scope).Inc()
+			log.Printf("login lockout: %s is locked until %s after %d failed attempts", key.value, attempt.LockedUntil.Format(time.RFC3339), attempt.Failures)
+		}
+	}
+
+	return nil
+}
+
+// delayAfter doubles the delay with every failed attempt, but never exceeds the window
+func (l loginLimits) delayAfter(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+
+	delay := float64(l.delay) * math.Pow(2, float64(failures-1))
+	if delay > float64(l.window) {
+		return l.window
+	}
+
+	return time.Duration(delay)
+}
+
+func (m *Manager) DeleteStaleLoginAttempts(ctx context.Context) error {
+	return m.Repository.DeleteStaleLoginAttempts(ctx, time.Now().Add(-m.loginLimits().window))
+}
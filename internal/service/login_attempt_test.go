@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/config"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/repository/mocks"
+	"one-lab-final/pkg/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoginLimits(t *testing.T) {
+	password := "password"
+	hash, _ := util.HashPassword(password)
+	now := time.Now()
+	lockedUntil := now.Add(10 * time.Minute)
+
+	tests := []struct {
+		Name          string
+		MockAttempts  []*entity.LoginAttempt
+		Password      string
+		ExpectRecord  bool
+		ExpectLocked  bool
+		ExpectDelayed bool
+		ExpectedErr   error
+	}{
+		{
+			Name: "Account is locked",
+			MockAttempts: []*entity.LoginAttempt{
+				{Key: "user:15", Failures: 5, LastFailureAt: now, LockedUntil: &lockedUntil},
+			},
+			Password:     password,
+			ExpectLocked: true,
+		},
+		{
+			Name: "Attempted too early after failure",
+			MockAttempts: []*entity.LoginAttempt{
+				{Key: "ip:127.0.0.1", Failures: 3, LastFailureAt: now},
+			},
+			Password:      password,
+			ExpectDelayed: true,
+		},
+		{
+			Name: "Failures outside of window are ignored",
+			MockAttempts: []*entity.LoginAttempt{
+				{Key: "user:15", Failures: 5, LastFailureAt: now.Add(-time.Hour), LockedUntil: &lockedUntil},
+			},
+			Password:     "wrong password",
+			ExpectRecord: true,
+			ExpectedErr:  util.ErrMismatchedPassword,
+		},
+		{
+			Name:         "Wrong password is recorded",
+			MockAttempts: []*entity.LoginAttempt{},
+			Password:     "wrong password",
+			ExpectRecord: true,
+			ExpectedErr:  util.ErrMismatchedPassword,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, &config.Config{
+				AUTH: config.AuthConfig{
+					LoginLockoutThreshold:   5,
+					LoginIPLockoutThreshold: 50,
+					LoginLockoutDuration:    15 * time.Minute,
+					LoginDelay:              time.Second,
+				},
+			})
+			ctx := context.Background()
+
+			repo.On("GetUserByCredentials", ctx, "username").Return(&entity.User{
+				ID: 15,
+				Password: entity.Password{
+					Hash: &hash,
+				},
+			}, nil)
+			repo.On("GetLoginAttempts", ctx, []string{"user:15", "ip:127.0.0.1"}).Return(test.MockAttempts, nil)
+			if test.ExpectRecord {
+				repo.On("RecordLoginFailure", ctx, "user:15", 5, 15*time.Minute).Return(&entity.LoginAttempt{Failures: 1}, nil)
+				repo.On("RecordLoginFailure", ctx, "ip:127.0.0.1", 50, 15*time.Minute).Return(&entity.LoginAttempt{Failures: 1}, nil)
+			}
+
+			_, err := service.Login(ctx, "username", test.Password, entity.Client{IP: "127.0.0.1"})
+
+			var limitErr *LoginLimitError
+			switch {
+			case test.ExpectLocked:
+				assert.True(t, errors.As(err, &limitErr))
+				assert.True(t, limitErr.Locked)
+				assert.InDelta(t, 10*time.Minute, limitErr.RetryAfter, float64(time.Second))
+			case test.ExpectDelayed:
+				assert.True(t, errors.As(err, &limitErr))
+				assert.False(t, limitErr.Locked)
+				assert.InDelta(t, 4*time.Second, limitErr.RetryAfter, float64(time.Second))
+			default:
+				assert.ErrorIs(t, err, test.ExpectedErr)
+			}
+		})
+	}
+}
+
+func TestLoginUnknownAccountIsRecorded(t *testing.T) {
+	repo := mocks.NewRepository(t)
+	service := New(repo, nil)
+	ctx := context.Background()
+
+	repo.On("GetUserByCredentials", ctx, "Unknown").Return(nil, repository.ErrRecordNotFound)
+	repo.On("GetLoginAttempts", ctx, []string{"credentials:unknown", "ip:"}).Return([]*entity.LoginAttempt{}, nil)
+	repo.On("RecordLoginFailure", ctx, "credentials:unknown", mock.AnythingOfType("int"), mock.AnythingOfType("time.Duration")).Return(&entity.LoginAttempt{Failures: 1}, nil)
+	repo.On("RecordLoginFailure", ctx, "ip:", mock.AnythingOfType("int"), mock.AnythingOfType("time.Duration")).Return(&entity.LoginAttempt{Failures: 1}, nil)
+
+	_, err := service.Login(ctx, "Unknown", "password", entity.Client{})
+	assert.ErrorIs(t, err, repository.ErrRecordNotFound)
+}
+
+func TestDelayAfter(t *testing.T) {
+	limits := loginLimits{delay: time.Second, window: 15 * time.Second}
+
+	assert.Equal(t, time.Duration(0), limits.delayAfter(0))
+	assert.Equal(t, time.Second, limits.delayAfter(1))
+	assert.Equal(t, 4*time.Second, limits.delayAfter(3))
+	assert.Equal(t, 15*time.Second, limits.delayAfter(10), "must not exceed window")
+}
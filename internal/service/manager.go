@@ -1,18 +1,48 @@
 package service
 
 import (
+	"log"
 	"one-lab-final/internal/config"
 	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/blob"
+	"one-lab-final/pkg/mailer"
+	"one-lab-final/pkg/oidc"
+	"one-lab-final/pkg/util"
 )
 
 type Manager struct {
 	Repository repository.Repository
 	Config     *config.Config
+	Mailer     mailer.Mailer
+	Hasher     util.PasswordHasher
+
+	// Keeps uploaded files such as avatars
+	Blob blob.Store
+
+	// Passwords from the list are rejected, screening is skipped when nil
+	BreachedPasswords *util.BreachedPasswords
+
+	OIDCProviders map[string]*OIDCProvider
+}
+
+type OIDCProvider struct {
+	*oidc.Provider
+	TrustEmail bool
 }
 
-func New(repository repository.Repository, config *config.Config) *Manager {
-	return &Manager{
+func New(repository repository.Repository, config *config.Config, opts ...Option) *Manager {
+	m := &Manager{
 		Repository: repository,
 		Config:     config,
+		Mailer:     mailer.NewLogMailer(log.Default()),
+		Hasher:     util.DefaultPasswordHasher,
+
+		OIDCProviders: make(map[string]*OIDCProvider),
+	}
+
+	for _, opt := range opts {
+		opt(m)
 	}
+
+	return m
 }
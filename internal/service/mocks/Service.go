@@ -4,10 +4,13 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 	entity "one-lab-final/internal/entity"
 
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	util "one-lab-final/pkg/util"
 )
 
@@ -16,6 +19,48 @@ type Service struct {
 	mock.Mock
 }
 
+// ActivateUser provides a mock function with given fields: ctx, token
+func (_m *Service) ActivateUser(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CancelEmailChange provides a mock function with given fields: ctx, token
+func (_m *Service) CancelEmailChange(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ChangeUsername provides a mock function with given fields: ctx, userID, username
+func (_m *Service) ChangeUsername(ctx context.Context, userID int64, username string) error {
+	ret := _m.Called(ctx, userID, username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CheckSuspension provides a mock function with given fields: ctx, userID
 func (_m *Service) CheckSuspension(ctx context.Context, userID int64) ([]*entity.Suspension, error) {
 	ret := _m.Called(ctx, userID)
@@ -42,6 +87,72 @@ func (_m *Service) CheckSuspension(ctx context.Context, userID int64) ([]*entity
 	return r0, r1
 }
 
+// ConfirmEmailChange provides a mock function with given fields: ctx, token
+func (_m *Service) ConfirmEmailChange(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmTwoFactor provides a mock function with given fields: ctx, userID, code
+func (_m *Service) ConfirmTwoFactor(ctx context.Context, userID int64, code string) ([]string, error) {
+	ret := _m.Called(ctx, userID, code)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) ([]string, error)); ok {
+		return rf(ctx, userID, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []string); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAPIKey provides a mock function with given fields: ctx, userID, name, scopes, expiry
+func (_m *Service) CreateAPIKey(ctx context.Context, userID int64, name string, scopes []entity.APIScope, expiry *time.Time) (*entity.APIKey, error) {
+	ret := _m.Called(ctx, userID, name, scopes, expiry)
+
+	var r0 *entity.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, []entity.APIScope, *time.Time) (*entity.APIKey, error)); ok {
+		return rf(ctx, userID, name, scopes, expiry)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, []entity.APIScope, *time.Time) *entity.APIKey); ok {
+		r0 = rf(ctx, userID, name, scopes, expiry)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, []entity.APIScope, *time.Time) error); ok {
+		r1 = rf(ctx, userID, name, scopes, expiry)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateBook provides a mock function with given fields: ctx, book
 func (_m *Service) CreateBook(ctx context.Context, book *entity.Book) error {
 	ret := _m.Called(ctx, book)
@@ -56,6 +167,20 @@ func (_m *Service) CreateBook(ctx context.Context, book *entity.Book) error {
 	return r0
 }
 
+// CreateContentRule provides a mock function with given fields: ctx, rule
+func (_m *Service) CreateContentRule(ctx context.Context, rule *entity.ContentRule) error {
+	ret := _m.Called(ctx, rule)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ContentRule) error); ok {
+		r0 = rf(ctx, rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateReview provides a mock function with given fields: ctx, review
 func (_m *Service) CreateReview(ctx context.Context, review *entity.Review) error {
 	ret := _m.Called(ctx, review)
@@ -70,6 +195,20 @@ func (_m *Service) CreateReview(ctx context.Context, review *entity.Review) erro
 	return r0
 }
 
+// CreateRole provides a mock function with given fields: ctx, actorID, role
+func (_m *Service) CreateRole(ctx context.Context, actorID int64, role *entity.RoleDefinition) error {
+	ret := _m.Called(ctx, actorID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *entity.RoleDefinition) error); ok {
+		r0 = rf(ctx, actorID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateUser provides a mock function with given fields: ctx, user
 func (_m *Service) CreateUser(ctx context.Context, user *entity.User) error {
 	ret := _m.Called(ctx, user)
@@ -84,6 +223,48 @@ func (_m *Service) CreateUser(ctx context.Context, user *entity.User) error {
 	return r0
 }
 
+// DeleteAPIKey provides a mock function with given fields: ctx, keyID, userID
+func (_m *Service) DeleteAPIKey(ctx context.Context, keyID int64, userID int64) error {
+	ret := _m.Called(ctx, keyID, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, keyID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAllSessions provides a mock function with given fields: ctx, userID
+func (_m *Service) DeleteAllSessions(ctx context.Context, userID int64) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAvatar provides a mock function with given fields: ctx, userID
+func (_m *Service) DeleteAvatar(ctx context.Context, userID int64) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteBook provides a mock function with given fields: ctx, articleID
 func (_m *Service) DeleteBook(ctx context.Context, articleID int64) error {
 	ret := _m.Called(ctx, articleID)
@@ -98,6 +279,20 @@ func (_m *Service) DeleteBook(ctx context.Context, articleID int64) error {
 	return r0
 }
 
+// DeleteContentRule provides a mock function with given fields: ctx, ruleID
+func (_m *Service) DeleteContentRule(ctx context.Context, ruleID int64) error {
+	ret := _m.Called(ctx, ruleID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, ruleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteExpiredTokens provides a mock function with given fields: ctx
 func (_m *Service) DeleteExpiredTokens(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -126,6 +321,48 @@ func (_m *Service) DeleteReview(ctx context.Context, reviewID int64, userID int6
 	return r0
 }
 
+// DeleteRole provides a mock function with given fields: ctx, actorID, name
+func (_m *Service) DeleteRole(ctx context.Context, actorID int64, name entity.Role) error {
+	ret := _m.Called(ctx, actorID, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, entity.Role) error); ok {
+		r0 = rf(ctx, actorID, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSession provides a mock function with given fields: ctx, sessionID, userID
+func (_m *Service) DeleteSession(ctx context.Context, sessionID int64, userID int64) error {
+	ret := _m.Called(ctx, sessionID, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, sessionID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteStaleLoginAttempts provides a mock function with given fields: ctx
+func (_m *Service) DeleteStaleLoginAttempts(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteUser provides a mock function with given fields: ctx, id
 func (_m *Service) DeleteUser(ctx context.Context, id int64) error {
 	ret := _m.Called(ctx, id)
@@ -140,6 +377,112 @@ func (_m *Service) DeleteUser(ctx context.Context, id int64) error {
 	return r0
 }
 
+// DisableTwoFactor provides a mock function with given fields: ctx, userID, code
+func (_m *Service) DisableTwoFactor(ctx context.Context, userID int64, code string) error {
+	ret := _m.Called(ctx, userID, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DownloadDataExport provides a mock function with given fields: ctx, exportID, expires, signature
+func (_m *Service) DownloadDataExport(ctx context.Context, exportID int64, expires int64, signature string) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, exportID, expires, signature)
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) (io.ReadCloser, error)); ok {
+		return rf(ctx, exportID, expires, signature)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) io.ReadCloser); ok {
+		r0 = rf(ctx, exportID, expires, signature)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, string) error); ok {
+		r1 = rf(ctx, exportID, expires, signature)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EnrollTwoFactor provides a mock function with given fields: ctx, userID
+func (_m *Service) EnrollTwoFactor(ctx context.Context, userID int64) (*entity.TwoFactor, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 *entity.TwoFactor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*entity.TwoFactor, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *entity.TwoFactor); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.TwoFactor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FollowUser provides a mock function with given fields: ctx, followerID, username
+func (_m *Service) FollowUser(ctx context.Context, followerID int64, username string) error {
+	ret := _m.Called(ctx, followerID, username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, followerID, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAPIKeys provides a mock function with given fields: ctx, userID
+func (_m *Service) GetAPIKeys(ctx context.Context, userID int64) ([]*entity.APIKey, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []*entity.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*entity.APIKey, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*entity.APIKey); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.APIKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBookByID provides a mock function with given fields: ctx, bookID
 func (_m *Service) GetBookByID(ctx context.Context, bookID int64) (*entity.Book, error) {
 	ret := _m.Called(ctx, bookID)
@@ -201,60 +544,718 @@ func (_m *Service) GetBooks(ctx context.Context, title *string, author *string,
 	return r0, r1, r2
 }
 
-// GetReviewsByBookID provides a mock function with given fields: ctx, bookID, filter
-func (_m *Service) GetReviewsByBookID(ctx context.Context, bookID int64, filter util.Filter) ([]*entity.Review, *util.Metadata, error) {
-	ret := _m.Called(ctx, bookID, filter)
+// GetContentRules provides a mock function with given fields: ctx
+func (_m *Service) GetContentRules(ctx context.Context) ([]*entity.ContentRule, error) {
+	ret := _m.Called(ctx)
 
-	var r0 []*entity.Review
-	var r1 *util.Metadata
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) ([]*entity.Review, *util.Metadata, error)); ok {
-		return rf(ctx, bookID, filter)
+	var r0 []*entity.ContentRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*entity.ContentRule, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) []*entity.Review); ok {
-		r0 = rf(ctx, bookID, filter)
+	if rf, ok := ret.Get(0).(func(context.Context) []*entity.ContentRule); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*entity.Review)
+			r0 = ret.Get(0).([]*entity.ContentRule)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64, util.Filter) *util.Metadata); ok {
-		r1 = rf(ctx, bookID, filter)
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDataExport provides a mock function with given fields: ctx, exportID, userID
+func (_m *Service) GetDataExport(ctx context.Context, exportID int64, userID int64) (*entity.DataExport, error) {
+	ret := _m.Called(ctx, exportID, userID)
+
+	var r0 *entity.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (*entity.DataExport, error)); ok {
+		return rf(ctx, exportID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) *entity.DataExport); ok {
+		r0 = rf(ctx, exportID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, exportID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFeed provides a mock function with given fields: ctx, userID, cursor, limit
+func (_m *Service) GetFeed(ctx context.Context, userID int64, cursor string, limit int) ([]*entity.Activity, string, error) {
+	ret := _m.Called(ctx, userID, cursor, limit)
+
+	var r0 []*entity.Activity
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, int) ([]*entity.Activity, string, error)); ok {
+		return rf(ctx, userID, cursor, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, int) []*entity.Activity); ok {
+		r0 = rf(ctx, userID, cursor, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Activity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, int) string); ok {
+		r1 = rf(ctx, userID, cursor, limit)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, string, int) error); ok {
+		r2 = rf(ctx, userID, cursor, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetFollowers provides a mock function with given fields: ctx, username, filter
+func (_m *Service) GetFollowers(ctx context.Context, username string, filter util.Filter) ([]*entity.Follow, *util.Metadata, error) {
+	ret := _m.Called(ctx, username, filter)
+
+	var r0 []*entity.Follow
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, util.Filter) ([]*entity.Follow, *util.Metadata, error)); ok {
+		return rf(ctx, username, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, util.Filter) []*entity.Follow); ok {
+		r0 = rf(ctx, username, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Follow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, username, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, util.Filter) error); ok {
+		r2 = rf(ctx, username, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetFollowing provides a mock function with given fields: ctx, username, filter
+func (_m *Service) GetFollowing(ctx context.Context, username string, filter util.Filter) ([]*entity.Follow, *util.Metadata, error) {
+	ret := _m.Called(ctx, username, filter)
+
+	var r0 []*entity.Follow
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, util.Filter) ([]*entity.Follow, *util.Metadata, error)); ok {
+		return rf(ctx, username, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, util.Filter) []*entity.Follow); ok {
+		r0 = rf(ctx, username, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Follow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, username, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, util.Filter) error); ok {
+		r2 = rf(ctx, username, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetOwnReviews provides a mock function with given fields: ctx, userID
+func (_m *Service) GetOwnReviews(ctx context.Context, userID int64) ([]*entity.Review, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []*entity.Review
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*entity.Review, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*entity.Review); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReportedReviews provides a mock function with given fields: ctx, search, filter
+func (_m *Service) GetReportedReviews(ctx context.Context, search entity.ReportSearch, filter util.Filter) ([]*entity.ReportedReview, *util.Metadata, error) {
+	ret := _m.Called(ctx, search, filter)
+
+	var r0 []*entity.ReportedReview
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReportSearch, util.Filter) ([]*entity.ReportedReview, *util.Metadata, error)); ok {
+		return rf(ctx, search, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReportSearch, util.Filter) []*entity.ReportedReview); ok {
+		r0 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ReportedReview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ReportSearch, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.ReportSearch, util.Filter) error); ok {
+		r2 = rf(ctx, search, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetReviewReports provides a mock function with given fields: ctx, reviewID
+func (_m *Service) GetReviewReports(ctx context.Context, reviewID int64) ([]*entity.ReviewReport, error) {
+	ret := _m.Called(ctx, reviewID)
+
+	var r0 []*entity.ReviewReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*entity.ReviewReport, error)); ok {
+		return rf(ctx, reviewID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*entity.ReviewReport); ok {
+		r0 = rf(ctx, reviewID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.ReviewReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, reviewID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReviews provides a mock function with given fields: ctx, search, filter
+func (_m *Service) GetReviews(ctx context.Context, search entity.ReviewSearch, filter util.Filter) ([]*entity.Review, *util.Metadata, error) {
+	ret := _m.Called(ctx, search, filter)
+
+	var r0 []*entity.Review
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReviewSearch, util.Filter) ([]*entity.Review, *util.Metadata, error)); ok {
+		return rf(ctx, search, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ReviewSearch, util.Filter) []*entity.Review); ok {
+		r0 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ReviewSearch, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.ReviewSearch, util.Filter) error); ok {
+		r2 = rf(ctx, search, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetReviewsByBookID provides a mock function with given fields: ctx, bookID, filter
+func (_m *Service) GetReviewsByBookID(ctx context.Context, bookID int64, filter util.Filter) ([]*entity.Review, *util.Metadata, error) {
+	ret := _m.Called(ctx, bookID, filter)
+
+	var r0 []*entity.Review
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) ([]*entity.Review, *util.Metadata, error)); ok {
+		return rf(ctx, bookID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) []*entity.Review); ok {
+		r0 = rf(ctx, bookID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, bookID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, util.Filter) error); ok {
+		r2 = rf(ctx, bookID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRole provides a mock function with given fields: ctx, name
+func (_m *Service) GetRole(ctx context.Context, name entity.Role) (*entity.RoleDefinition, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *entity.RoleDefinition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Role) (*entity.RoleDefinition, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Role) *entity.RoleDefinition); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RoleDefinition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Role) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRoleHistory provides a mock function with given fields: ctx, userID, filter
+func (_m *Service) GetRoleHistory(ctx context.Context, userID int64, filter util.Filter) ([]*entity.RoleChange, *util.Metadata, error) {
+	ret := _m.Called(ctx, userID, filter)
+
+	var r0 []*entity.RoleChange
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) ([]*entity.RoleChange, *util.Metadata, error)); ok {
+		return rf(ctx, userID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, util.Filter) []*entity.RoleChange); ok {
+		r0 = rf(ctx, userID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.RoleChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, userID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, util.Filter) error); ok {
+		r2 = rf(ctx, userID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRoles provides a mock function with given fields: ctx
+func (_m *Service) GetRoles(ctx context.Context) ([]*entity.RoleDefinition, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*entity.RoleDefinition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*entity.RoleDefinition, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*entity.RoleDefinition); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.RoleDefinition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSessions provides a mock function with given fields: ctx, userID, currentToken
+func (_m *Service) GetSessions(ctx context.Context, userID int64, currentToken string) ([]*entity.Session, error) {
+	ret := _m.Called(ctx, userID, currentToken)
+
+	var r0 []*entity.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) ([]*entity.Session, error)); ok {
+		return rf(ctx, userID, currentToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []*entity.Session); ok {
+		r0 = rf(ctx, userID, currentToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userID, currentToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSuspension provides a mock function with given fields: ctx, suspensionID
+func (_m *Service) GetSuspension(ctx context.Context, suspensionID int64) (*entity.Suspension, error) {
+	ret := _m.Called(ctx, suspensionID)
+
+	var r0 *entity.Suspension
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*entity.Suspension, error)); ok {
+		return rf(ctx, suspensionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *entity.Suspension); ok {
+		r0 = rf(ctx, suspensionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Suspension)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, suspensionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSuspensions provides a mock function with given fields: ctx, search, filter
+func (_m *Service) GetSuspensions(ctx context.Context, search entity.SuspensionSearch, filter util.Filter) ([]*entity.Suspension, *util.Metadata, error) {
+	ret := _m.Called(ctx, search, filter)
+
+	var r0 []*entity.Suspension
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.SuspensionSearch, util.Filter) ([]*entity.Suspension, *util.Metadata, error)); ok {
+		return rf(ctx, search, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.SuspensionSearch, util.Filter) []*entity.Suspension); ok {
+		r0 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.Suspension)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.SuspensionSearch, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*util.Metadata)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, entity.SuspensionSearch, util.Filter) error); ok {
+		r2 = rf(ctx, search, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetUserByCredentials provides a mock function with given fields: ctx, credentials
+func (_m *Service) GetUserByCredentials(ctx context.Context, credentials string) (*entity.User, error) {
+	ret := _m.Called(ctx, credentials)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
+		return rf(ctx, credentials)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
+		r0 = rf(ctx, credentials)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, credentials)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByToken provides a mock function with given fields: ctx, token
+func (_m *Service) GetUserByToken(ctx context.Context, token string) (*entity.User, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByUsername provides a mock function with given fields: ctx, username
+func (_m *Service) GetUserByUsername(ctx context.Context, username string) (*entity.User, error) {
+	ret := _m.Called(ctx, username)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserStats provides a mock function with given fields: ctx, username
+func (_m *Service) GetUserStats(ctx context.Context, username string) (*entity.UserStats, error) {
+	ret := _m.Called(ctx, username)
+
+	var r0 *entity.UserStats
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.UserStats, error)); ok {
+		return rf(ctx, username)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.UserStats); ok {
+		r0 = rf(ctx, username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.UserStats)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUsers provides a mock function with given fields: ctx, search, filter
+func (_m *Service) GetUsers(ctx context.Context, search entity.UserSearch, filter util.Filter) ([]*entity.UserOverview, *util.Metadata, error) {
+	ret := _m.Called(ctx, search, filter)
+
+	var r0 []*entity.UserOverview
+	var r1 *util.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserSearch, util.Filter) ([]*entity.UserOverview, *util.Metadata, error)); ok {
+		return rf(ctx, search, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.UserSearch, util.Filter) []*entity.UserOverview); ok {
+		r0 = rf(ctx, search, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entity.UserOverview)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.UserSearch, util.Filter) *util.Metadata); ok {
+		r1 = rf(ctx, search, filter)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*util.Metadata)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, int64, util.Filter) error); ok {
-		r2 = rf(ctx, bookID, filter)
+	if rf, ok := ret.Get(2).(func(context.Context, entity.UserSearch, util.Filter) error); ok {
+		r2 = rf(ctx, search, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GrantRoleToUser provides a mock function with given fields: ctx, change
+func (_m *Service) GrantRoleToUser(ctx context.Context, change *entity.RoleChange) error {
+	ret := _m.Called(ctx, change)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.RoleChange) error); ok {
+		r0 = rf(ctx, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// HideReview provides a mock function with given fields: ctx, review
+func (_m *Service) HideReview(ctx context.Context, review *entity.Review) error {
+	ret := _m.Called(ctx, review)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Review) error); ok {
+		r0 = rf(ctx, review)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LiftSuspension provides a mock function with given fields: ctx, suspension
+func (_m *Service) LiftSuspension(ctx context.Context, suspension *entity.Suspension) error {
+	ret := _m.Called(ctx, suspension)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Suspension) error); ok {
+		r0 = rf(ctx, suspension)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Login provides a mock function with given fields: ctx, credentials, password, client
+func (_m *Service) Login(ctx context.Context, credentials string, password string, client entity.Client) (*entity.Token, error) {
+	ret := _m.Called(ctx, credentials, password, client)
+
+	var r0 *entity.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, entity.Client) (*entity.Token, error)); ok {
+		return rf(ctx, credentials, password, client)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, entity.Client) *entity.Token); ok {
+		r0 = rf(ctx, credentials, password, client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, entity.Client) error); ok {
+		r1 = rf(ctx, credentials, password, client)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// GetUserByCredentials provides a mock function with given fields: ctx, credentials
-func (_m *Service) GetUserByCredentials(ctx context.Context, credentials string) (*entity.User, error) {
-	ret := _m.Called(ctx, credentials)
+// LoginTwoFactor provides a mock function with given fields: ctx, token, code, client
+func (_m *Service) LoginTwoFactor(ctx context.Context, token string, code string, client entity.Client) (*entity.Token, error) {
+	ret := _m.Called(ctx, token, code, client)
 
-	var r0 *entity.User
+	var r0 *entity.Token
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
-		return rf(ctx, credentials)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, entity.Client) (*entity.Token, error)); ok {
+		return rf(ctx, token, code, client)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
-		r0 = rf(ctx, credentials)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, entity.Client) *entity.Token); ok {
+		r0 = rf(ctx, token, code, client)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.User)
+			r0 = ret.Get(0).(*entity.Token)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, credentials)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, entity.Client) error); ok {
+		r1 = rf(ctx, token, code, client)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -262,25 +1263,53 @@ func (_m *Service) GetUserByCredentials(ctx context.Context, credentials string)
 	return r0, r1
 }
 
-// GetUserByToken provides a mock function with given fields: ctx, token
-func (_m *Service) GetUserByToken(ctx context.Context, token string) (*entity.User, error) {
+// Logout provides a mock function with given fields: ctx, token
+func (_m *Service) Logout(ctx context.Context, token string) error {
 	ret := _m.Called(ctx, token)
 
-	var r0 *entity.User
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSuspension provides a mock function with given fields: ctx, suspension
+func (_m *Service) NewSuspension(ctx context.Context, suspension *entity.Suspension) error {
+	ret := _m.Called(ctx, suspension)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Suspension) error); ok {
+		r0 = rf(ctx, suspension)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OIDCLogin provides a mock function with given fields: ctx, provider, code, state, client
+func (_m *Service) OIDCLogin(ctx context.Context, provider string, code string, state string, client entity.Client) (*entity.Token, error) {
+	ret := _m.Called(ctx, provider, code, state, client)
+
+	var r0 *entity.Token
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
-		return rf(ctx, token)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, entity.Client) (*entity.Token, error)); ok {
+		return rf(ctx, provider, code, state, client)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
-		r0 = rf(ctx, token)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, entity.Client) *entity.Token); ok {
+		r0 = rf(ctx, provider, code, state, client)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.User)
+			r0 = ret.Get(0).(*entity.Token)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, token)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, entity.Client) error); ok {
+		r1 = rf(ctx, provider, code, state, client)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -288,25 +1317,23 @@ func (_m *Service) GetUserByToken(ctx context.Context, token string) (*entity.Us
 	return r0, r1
 }
 
-// GetUserByUsername provides a mock function with given fields: ctx, username
-func (_m *Service) GetUserByUsername(ctx context.Context, username string) (*entity.User, error) {
-	ret := _m.Called(ctx, username)
+// OIDCLoginURL provides a mock function with given fields: ctx, provider
+func (_m *Service) OIDCLoginURL(ctx context.Context, provider string) (string, error) {
+	ret := _m.Called(ctx, provider)
 
-	var r0 *entity.User
+	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.User, error)); ok {
-		return rf(ctx, username)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, provider)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.User); ok {
-		r0 = rf(ctx, username)
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, provider)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.User)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, username)
+		r1 = rf(ctx, provider)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -314,13 +1341,41 @@ func (_m *Service) GetUserByUsername(ctx context.Context, username string) (*ent
 	return r0, r1
 }
 
-// GrantRoleToUser provides a mock function with given fields: ctx, userID, role
-func (_m *Service) GrantRoleToUser(ctx context.Context, userID int64, role entity.Role) error {
-	ret := _m.Called(ctx, userID, role)
+// ProcessDataExports provides a mock function with given fields: ctx
+func (_m *Service) ProcessDataExports(ctx context.Context) error {
+	ret := _m.Called(ctx)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, entity.Role) error); ok {
-		r0 = rf(ctx, userID, role)
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeDeletedUsers provides a mock function with given fields: ctx
+func (_m *Service) PurgeDeletedUsers(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshBooksRating provides a mock function with given fields: ctx
+func (_m *Service) RefreshBooksRating(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -328,25 +1383,25 @@ func (_m *Service) GrantRoleToUser(ctx context.Context, userID int64, role entit
 	return r0
 }
 
-// Login provides a mock function with given fields: ctx, credentials, password
-func (_m *Service) Login(ctx context.Context, credentials string, password string) (*entity.Token, error) {
-	ret := _m.Called(ctx, credentials, password)
+// RefreshToken provides a mock function with given fields: ctx, refreshToken, client
+func (_m *Service) RefreshToken(ctx context.Context, refreshToken string, client entity.Client) (*entity.Token, error) {
+	ret := _m.Called(ctx, refreshToken, client)
 
 	var r0 *entity.Token
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*entity.Token, error)); ok {
-		return rf(ctx, credentials, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.Client) (*entity.Token, error)); ok {
+		return rf(ctx, refreshToken, client)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *entity.Token); ok {
-		r0 = rf(ctx, credentials, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.Client) *entity.Token); ok {
+		r0 = rf(ctx, refreshToken, client)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.Token)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, credentials, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, entity.Client) error); ok {
+		r1 = rf(ctx, refreshToken, client)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -354,13 +1409,13 @@ func (_m *Service) Login(ctx context.Context, credentials string, password strin
 	return r0, r1
 }
 
-// NewSuspension provides a mock function with given fields: ctx, suspension
-func (_m *Service) NewSuspension(ctx context.Context, suspension *entity.Suspension) error {
-	ret := _m.Called(ctx, suspension)
+// RefreshUserStats provides a mock function with given fields: ctx
+func (_m *Service) RefreshUserStats(ctx context.Context) error {
+	ret := _m.Called(ctx)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.Suspension) error); ok {
-		r0 = rf(ctx, suspension)
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -368,13 +1423,151 @@ func (_m *Service) NewSuspension(ctx context.Context, suspension *entity.Suspens
 	return r0
 }
 
-// RefreshBooksRating provides a mock function with given fields: ctx
-func (_m *Service) RefreshBooksRating(ctx context.Context) error {
-	ret := _m.Called(ctx)
+// RemoveReview provides a mock function with given fields: ctx, reviewID, moderatorID
+func (_m *Service) RemoveReview(ctx context.Context, reviewID int64, moderatorID int64) error {
+	ret := _m.Called(ctx, reviewID, moderatorID)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, reviewID, moderatorID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReportReview provides a mock function with given fields: ctx, report
+func (_m *Service) ReportReview(ctx context.Context, report *entity.ReviewReport) error {
+	ret := _m.Called(ctx, report)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ReviewReport) error); ok {
+		r0 = rf(ctx, report)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequestDataExport provides a mock function with given fields: ctx, userID
+func (_m *Service) RequestDataExport(ctx context.Context, userID int64) (*entity.DataExport, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 *entity.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*entity.DataExport, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *entity.DataExport); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.DataExport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequestEmailChange provides a mock function with given fields: ctx, userID, email
+func (_m *Service) RequestEmailChange(ctx context.Context, userID int64, email string) error {
+	ret := _m.Called(ctx, userID, email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userID, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequestPasswordReset provides a mock function with given fields: ctx, credentials
+func (_m *Service) RequestPasswordReset(ctx context.Context, credentials string) error {
+	ret := _m.Called(ctx, credentials)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, credentials)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResendActivation provides a mock function with given fields: ctx, userID
+func (_m *Service) ResendActivation(ctx context.Context, userID int64) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetPassword provides a mock function with given fields: ctx, token, password
+func (_m *Service) ResetPassword(ctx context.Context, token string, password string) error {
+	ret := _m.Called(ctx, token, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, token, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResolveReviewReports provides a mock function with given fields: ctx, resolution
+func (_m *Service) ResolveReviewReports(ctx context.Context, resolution *entity.ReportResolution) error {
+	ret := _m.Called(ctx, resolution)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.ReportResolution) error); ok {
+		r0 = rf(ctx, resolution)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreReview provides a mock function with given fields: ctx, review
+func (_m *Service) RestoreReview(ctx context.Context, review *entity.Review) error {
+	ret := _m.Called(ctx, review)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.Review) error); ok {
+		r0 = rf(ctx, review)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UnfollowUser provides a mock function with given fields: ctx, followerID, username
+func (_m *Service) UnfollowUser(ctx context.Context, followerID int64, username string) error {
+	ret := _m.Called(ctx, followerID, username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, followerID, username)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -382,6 +1575,32 @@ func (_m *Service) RefreshBooksRating(ctx context.Context) error {
 	return r0
 }
 
+// UpdateAvatar provides a mock function with given fields: ctx, userID, file
+func (_m *Service) UpdateAvatar(ctx context.Context, userID int64, file io.Reader) (*entity.Avatar, error) {
+	ret := _m.Called(ctx, userID, file)
+
+	var r0 *entity.Avatar
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, io.Reader) (*entity.Avatar, error)); ok {
+		return rf(ctx, userID, file)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, io.Reader) *entity.Avatar); ok {
+		r0 = rf(ctx, userID, file)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Avatar)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, io.Reader) error); ok {
+		r1 = rf(ctx, userID, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateBook provides a mock function with given fields: ctx, book
 func (_m *Service) UpdateBook(ctx context.Context, book *entity.Book) error {
 	ret := _m.Called(ctx, book)
@@ -396,6 +1615,32 @@ func (_m *Service) UpdateBook(ctx context.Context, book *entity.Book) error {
 	return r0
 }
 
+// UpdateContentRule provides a mock function with given fields: ctx, update
+func (_m *Service) UpdateContentRule(ctx context.Context, update entity.ContentRuleUpdate) (*entity.ContentRule, error) {
+	ret := _m.Called(ctx, update)
+
+	var r0 *entity.ContentRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ContentRuleUpdate) (*entity.ContentRule, error)); ok {
+		return rf(ctx, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ContentRuleUpdate) *entity.ContentRule); ok {
+		r0 = rf(ctx, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.ContentRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ContentRuleUpdate) error); ok {
+		r1 = rf(ctx, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateReview provides a mock function with given fields: ctx, review
 func (_m *Service) UpdateReview(ctx context.Context, review *entity.Review) error {
 	ret := _m.Called(ctx, review)
@@ -410,6 +1655,20 @@ func (_m *Service) UpdateReview(ctx context.Context, review *entity.Review) erro
 	return r0
 }
 
+// UpdateRole provides a mock function with given fields: ctx, actorID, role
+func (_m *Service) UpdateRole(ctx context.Context, actorID int64, role *entity.RoleDefinition) error {
+	ret := _m.Called(ctx, actorID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *entity.RoleDefinition) error); ok {
+		r0 = rf(ctx, actorID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateSuspension provides a mock function with given fields: ctx, suspension
 func (_m *Service) UpdateSuspension(ctx context.Context, suspension *entity.Suspension) error {
 	ret := _m.Called(ctx, suspension)
@@ -424,13 +1683,13 @@ func (_m *Service) UpdateSuspension(ctx context.Context, suspension *entity.Susp
 	return r0
 }
 
-// UpdateUser provides a mock function with given fields: ctx, user
-func (_m *Service) UpdateUser(ctx context.Context, user *entity.User) error {
-	ret := _m.Called(ctx, user)
+// UpdateUser provides a mock function with given fields: ctx, user, currentToken
+func (_m *Service) UpdateUser(ctx context.Context, user *entity.User, currentToken string) error {
+	ret := _m.Called(ctx, user, currentToken)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entity.User) error); ok {
-		r0 = rf(ctx, user)
+	if rf, ok := ret.Get(0).(func(context.Context, *entity.User, string) error); ok {
+		r0 = rf(ctx, user, currentToken)
 	} else {
 		r0 = ret.Error(0)
 	}
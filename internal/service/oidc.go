@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/oidc"
+	"one-lab-final/pkg/util"
+	"strings"
+	"time"
+)
+
+// OIDCLoginURL starts authorization code flow with PKCE. State, nonce and
+// code verifier are kept in database until the user is redirected back
+func (m *Manager) OIDCLoginURL(ctx context.Context, provider string) (string, error) {
+	p, ok := m.OIDCProviders[provider]
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+
+	state, err := oidc.GenerateVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := oidc.GenerateVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	verifier, err := oidc.GenerateVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	expiration := 10 * time.Minute
+	if m.Config != nil && m.Config.OIDC.StateExpiration != 0 {
+		expiration = m.Config.OIDC.StateExpiration
+	}
+
+	err = m.Repository.CreateOIDCState(ctx, &entity.OIDCState{
+		Hash:         util.HashToken(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		Expiry:       time.Now().Add(expiration),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return p.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(verifier))
+}
+
+// OIDCLogin finishes the flow started by OIDCLoginURL and issues usual
+// tokens. User is created on the first login
+func (m *Manager) OIDCLogin(ctx context.Context, provider string, code string, state string, client entity.Client) (*entity.Token, error) {
+	p, ok := m.OIDCProviders[provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	s, err := m.Repository.ConsumeOIDCState(ctx, state, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := p.Exchange(ctx, code, s.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := p.VerifyIDToken(ctx, tokens.IDToken, s.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := m.getUserByIdentity(ctx, provider, p, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactor {
+		return m.createPartialToken(ctx, user)
+	}
+
+	return m.createSession(ctx, user, client)
+}
+
+// getUserByIdentity finds user linked to the identity, or links it to
+// existing user when email is trusted, or creates a new user
+func (m *Manager) getUserByIdentity(ctx context.Context, provider string, p *OIDCProvider, claims *oidc.Claims) (*entity.User, error) {
+	user, err := m.Repository.GetUserByIdentity(ctx, provider, claims.Subject)
+	if err == nil {
+		return user, nil
+	}
+
+	if !errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if claims.Email == "" {
+		return nil, ErrOIDCEmailRequired
+	}
+
+	// Existing user with the same email is linked only if provider is trusted
+	user, err = m.Repository.GetUserByCredentials(ctx, claims.Email)
+	switch {
+	case err == nil && !(p.TrustEmail && claims.EmailVerified):
+		return nil, ErrIdentityEmailTaken
+	case errors.Is(err, repository.ErrRecordNotFound):
+		user, err = m.createOIDCUser(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	err = m.Repository.CreateIdentity(ctx, &entity.Identity{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    &claims.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createOIDCUser registers user with random password, it can be
+// replaced later with password reset
+func (m *Manager) createOIDCUser(ctx context.Context, claims *oidc.Claims) (*entity.User, error) {
+	username, err := m.availableUsername(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := util.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	firstName, lastName := claims.GivenName, claims.FamilyName
+	if firstName == "" && lastName == "" {
+		firstName, lastName, _ = strings.Cut(claims.Name, " ")
+	}
+
+	user := &entity.User{
+		Username:  &username,
+		Email:     &claims.Email,
+		FirstName: &firstName,
+		LastName:  &lastName,
+		Password: entity.Password{
+			Plaintext: &password.Plaintext,
+		},
+		Role:      entity.USER,
+		Activated: claims.EmailVerified,
+	}
+
+	err = m.CreateUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// availableUsername uses preferred username or local part of email, random
+// suffix is added when the name is taken
+func (m *Manager) availableUsername(ctx context.Context, claims *oidc.Claims) (string, error) {
+	username := claims.PreferredUsername
+	if username == "" {
+		username, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	_, err := m.Repository.GetUserByUsername(ctx, username)
+	if errors.Is(err, repository.ErrRecordNotFound) && len(username) >= 5 {
+		return username, nil
+	}
+
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return "", err
+	}
+
+	suffix, err := oidc.GenerateVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	return username + "-" + strings.ToLower(suffix[:6]), nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"one-lab-final/internal/config"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/repository/mocks"
+	"one-lab-final/pkg/oidc"
+	"one-lab-final/pkg/oidc/oidctest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOIDCLogin(t *testing.T) {
+	var userID int64 = 15
+	tests := []struct {
+		Name             string
+		TrustEmail       bool
+		MockIdentityUser *entity.User
+		MockEmailUser    *entity.User
+		ExpectCreate     bool
+		ExpectLink       bool
+		ExpectedErr      error
+	}{
+		{
+			Name:             "Identity is already linked",
+			MockIdentityUser: &entity.User{ID: userID},
+		},
+		{
+			Name:         "User is created on first login",
+			ExpectCreate: true,
+			ExpectLink:   true,
+		},
+		{
+			Name:          "Identity is linked to user with trusted email",
+			TrustEmail:    true,
+			MockEmailUser: &entity.User{ID: userID},
+			ExpectLink:    true,
+		},
+		{
+			Name:          "Email is taken and provider is not trusted",
+			MockEmailUser: &entity.User{ID: userID},
+			ExpectedErr:   ErrIdentityEmailTaken,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			server := oidctest.NewServer("client")
+			defer server.Close()
+
+			repo := mocks.NewRepository(t)
+			service := New(repo, &config.Config{
+				AUTH: config.AuthConfig{
+					SigningKey: "BLEH",
+				},
+			}, WithOIDCProvider("company", oidc.NewProvider(server.Issuer(), "client", oidc.WithRedirectURL("http://localhost/callback")), test.TrustEmail))
+			ctx := context.Background()
+
+			var state *entity.OIDCState
+			repo.On("CreateOIDCState", ctx, mock.AnythingOfType("*entity.OIDCState")).Run(func(args mock.Arguments) {
+				state = args.Get(1).(*entity.OIDCState)
+			}).Return(nil)
+
+			url, err := service.OIDCLoginURL(ctx, "company")
+			assert.Nil(t, err)
+
+			code, plainState, err := server.Authorize(url)
+			assert.Nil(t, err)
+
+			repo.On("ConsumeOIDCState", ctx, plainState, "company").Return(state, nil)
+			if test.MockIdentityUser != nil {
+				repo.On("GetUserByIdentity", ctx, "company", "subject").Return(test.MockIdentityUser, nil)
+			} else {
+				repo.On("GetUserByIdentity", ctx, "company", "subject").Return(nil, repository.ErrRecordNotFound)
+				if test.MockEmailUser != nil {
+					repo.On("GetUserByCredentials", ctx, "user@example.com").Return(test.MockEmailUser, nil)
+				} else {
+					repo.On("GetUserByCredentials", ctx, "user@example.com").Return(nil, repository.ErrRecordNotFound)
+				}
+			}
+			if test.ExpectCreate {
+				repo.On("GetUserByUsername", ctx, "jsnow").Return(nil, repository.ErrRecordNotFound)
+				repo.On("CreateUser", ctx, mock.MatchedBy(func(user *entity.User) bool {
+					return *user.Username == "jsnow" && *user.FirstName == "John" && user.Activated
+				})).Return(nil)
+			}
+			if test.ExpectLink {
+				repo.On("CreateIdentity", ctx, mock.MatchedBy(func(identity *entity.Identity) bool {
+					return identity.Provider == "company" && identity.Subject == "subject"
+				})).Return(nil)
+			}
+			if test.ExpectedErr == nil {
+				repo.On("CreateToken", ctx, mock.AnythingOfType("*entity.Token")).Return(nil)
+				repo.On("CreateRefreshToken", ctx, mock.AnythingOfType("*entity.RefreshToken")).Return(nil)
+			}
+
+			token, err := service.OIDCLogin(ctx, "company", code, plainState, entity.Client{})
+			assert.ErrorIs(t, err, test.ExpectedErr)
+			if test.ExpectedErr == nil {
+				assert.NotNil(t, token.Refresh)
+			}
+		})
+	}
+}
+
+func TestOIDCLoginInvalidState(t *testing.T) {
+	server := oidctest.NewServer("client")
+	defer server.Close()
+
+	repo := mocks.NewRepository(t)
+	service := New(repo, nil, WithOIDCProvider("company", oidc.NewProvider(server.Issuer(), "client"), false))
+	ctx := context.Background()
+
+	repo.On("ConsumeOIDCState", ctx, "state", "company").Return(nil, repository.ErrRecordNotFound)
+
+	_, err := service.OIDCLogin(ctx, "company", "code", "state", entity.Client{})
+	assert.ErrorIs(t, err, repository.ErrRecordNotFound)
+}
+
+func TestOIDCLoginNonceMismatch(t *testing.T) {
+	server := oidctest.NewServer("client")
+	defer server.Close()
+
+	repo := mocks.NewRepository(t)
+	service := New(repo, nil, WithOIDCProvider("company", oidc.NewProvider(server.Issuer(), "client"), false))
+	ctx := context.Background()
+
+	verifier, _ := oidc.GenerateVerifier()
+	url, _ := service.OIDCProviders["company"].AuthCodeURL(ctx, "state", "nonce", oidc.CodeChallenge(verifier))
+	code, _, _ := server.Authorize(url)
+
+	repo.On("ConsumeOIDCState", ctx, "state", "company").Return(&entity.OIDCState{
+		Nonce:        "other nonce",
+		CodeVerifier: verifier,
+		Expiry:       time.Now().Add(time.Minute),
+	}, nil)
+
+	_, err := service.OIDCLogin(ctx, "company", code, "state", entity.Client{})
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+}
+
+func TestOIDCUnknownProvider(t *testing.T) {
+	repo := mocks.NewRepository(t)
+	service := New(repo, nil)
+	ctx := context.Background()
+
+	_, err := service.OIDCLoginURL(ctx, "company")
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+
+	_, err = service.OIDCLogin(ctx, "company", "code", "state", entity.Client{})
+	assert.ErrorIs(t, err, ErrUnknownProvider)
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"one-lab-final/pkg/blob"
+	"one-lab-final/pkg/mailer"
+	"one-lab-final/pkg/oidc"
+	"one-lab-final/pkg/util"
+)
+
+type Option func(*Manager)
+
+func WithMailer(mailer mailer.Mailer) Option {
+	return func(m *Manager) {
+		m.Mailer = mailer
+	}
+}
+
+func WithBlobStore(store blob.Store) Option {
+	return func(m *Manager) {
+		m.Blob = store
+	}
+}
+
+func WithPasswordHasher(hasher util.PasswordHasher) Option {
+	return func(m *Manager) {
+		m.Hasher = hasher
+	}
+}
+
+func WithBreachedPasswords(list *util.BreachedPasswords) Option {
+	return func(m *Manager) {
+		m.BreachedPasswords = list
+	}
+}
+
+// WithOIDCProvider enables login with external provider. If trustEmail is set,
+// verified email links the identity to existing user with the same email
+func WithOIDCProvider(name string, provider *oidc.Provider, trustEmail bool) Option {
+	return func(m *Manager) {
+		m.OIDCProviders[name] = &OIDCProvider{
+			Provider:   provider,
+			TrustEmail: trustEmail,
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+	"time"
+)
+
+// RequestPasswordReset sends a one-time token to the email of the account.
+// Unknown accounts and throttled requests are not reported to the caller,
+// so the response does not reveal whether the account exists
+func (m *Manager) RequestPasswordReset(ctx context.Context, credentials string) error {
+	user, err := m.Repository.GetUserByCredentials(ctx, credentials)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	expiration, interval := 15*time.Minute, 5*time.Minute
+	if m.Config != nil && m.Config.AUTH.PasswordResetExpiration != 0 {
+		expiration = m.Config.AUTH.PasswordResetExpiration
+	}
+	if m.Config != nil && m.Config.AUTH.PasswordResetInterval != 0 {
+		interval = m.Config.AUTH.PasswordResetInterval
+	}
+
+	count, err := m.Repository.CountScopedTokens(ctx, user.ID, entity.ScopePasswordReset, time.Now().Add(-interval))
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	token, err := util.GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	err = m.Repository.CreateToken(ctx, &entity.Token{
+		Hash:   token.Hash,
+		UserID: user.ID,
+		Expiry: time.Now().Add(expiration),
+		Scope:  entity.ScopePasswordReset,
+	})
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf(
+		"To set a new password send the following token to PUT /api/v1/users/password:\n\n%s\n\nThe token expires in %s. If you did not request a password reset, ignore this email.",
+		token.Plaintext,
+		expiration,
+	)
+
+	return m.Mailer.Send(ctx, *user.Email, "Reset your password", body)
+}
+
+// ResetPassword consumes the token, sets new password and revokes all sessions of the user.
+// Token is consumed only after the password passes the policy, so it can be retried
+func (m *Manager) ResetPassword(ctx context.Context, token string, password string) error {
+	user, err := m.Repository.GetUserByScopedToken(ctx, token, entity.ScopePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	err = m.validatePassword(password, user)
+	if err != nil {
+		return err
+	}
+
+	user, err = m.Repository.ConsumeScopedToken(ctx, token, entity.ScopePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	hash, err := m.Hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	err = m.Repository.UpdateUser(ctx, &entity.User{
+		ID: user.ID,
+		Password: entity.Password{
+			Hash: &hash,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = m.Repository.DeleteScopedTokens(ctx, user.ID, entity.ScopePasswordReset)
+	if err != nil {
+		return err
+	}
+
+	return m.Repository.DeleteUserTokens(ctx, user.ID, "")
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+	"one-lab-final/internal/entity"
+	"one-lab-final/pkg/util"
+	"strings"
+	"unicode"
+)
+
+// Shorter parts of username or email are too common to be rejected
+const minDerivedLength = 3
+
+// validatePassword checks password against the policy. User is used to reject
+// passwords derived from username or email, it may be nil
+func (m *Manager) validatePassword(password string, user *entity.User) error {
+	minLength := 8
+	if m.Config != nil && m.Config.AUTH.PasswordPolicy.MinLength != 0 {
+		minLength = m.Config.AUTH.PasswordPolicy.MinLength
+	}
+
+	var problem string
+
+	// Hasher would fail with longer password, so it is rejected as invalid
+	maxLength := 0
+	if limited, ok := m.Hasher.(util.LengthLimitedHasher); ok {
+		maxLength = limited.MaxLength()
+	}
+
+	switch {
+	case len([]rune(password)) < minLength:
+		problem = fmt.Sprintf("must be at least %d characters long", minLength)
+	case maxLength != 0 && len(password) > maxLength:
+		problem = fmt.Sprintf("must be at most %d bytes long", maxLength)
+	case derivedFromUser(password, user):
+		problem = "must not be based on username or email"
+	case m.BreachedPasswords != nil && m.BreachedPasswords.Contains(password):
+		problem = "was found in a data breach, choose another one"
+	default:
+		return nil
+	}
+
+	return &ValidationError{
+		Fields: map[string]string{
+			"password": problem,
+		},
+	}
+}
+
+func derivedFromUser(password string, user *entity.User) bool {
+	if user == nil {
+		return false
+	}
+
+	var parts []string
+	if user.Username != nil {
+		parts = append(parts, *user.Username)
+	}
+	if user.Email != nil {
+		local, _, _ := strings.Cut(*user.Email, "@")
+		parts = append(parts, *user.Email, local)
+	}
+
+	password = normalize(password)
+	if password == "" {
+		return false
+	}
+
+	for _, part := range parts {
+		part = normalize(part)
+		if len(part) < minDerivedLength {
+			continue
+		}
+
+		if strings.Contains(password, part) || strings.Contains(part, password) || strings.Contains(password, reverse(part)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalize drops case and everything except letters and digits, so
+// "J.Snow_1" and "jsnow1" are considered the same
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"one-lab-final/internal/config"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository/mocks"
+	"one-lab-final/pkg/util"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePassword(t *testing.T) {
+	// SHA-1 of "correcthorse"
+	breached, _ := util.LoadBreachedPasswords(strings.NewReader("0E4CECB0F76C0600F8FC5995FA087260BA91640B:42"))
+
+	user := &entity.User{
+		Username: util.StringToPointer("J.Snow"),
+		Email:    util.StringToPointer("winter.is.coming@example.com"),
+	}
+
+	tests := []struct {
+		Name          string
+		Password      string
+		ExpectedField bool
+	}{
+		{
+			Name:     "Password is accepted",
+			Password: "tr0ub4dor&3",
+		},
+		{
+			Name:          "Password is too short",
+			Password:      "abc123",
+			ExpectedField: true,
+		},
+		{
+			Name:          "Password contains username",
+			Password:      "jsnow2024!",
+			ExpectedField: true,
+		},
+		{
+			Name:          "Password contains reversed username",
+			Password:      "wonsj-secret",
+			ExpectedField: true,
+		},
+		{
+			Name:          "Password is based on email",
+			Password:      "WinterIsComing",
+			ExpectedField: true,
+		},
+		{
+			Name:          "Password is breached",
+			Password:      "correcthorse",
+			ExpectedField: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			service := New(mocks.NewRepository(t), &config.Config{
+				AUTH: config.AuthConfig{
+					PasswordPolicy: config.PasswordPolicyConfig{MinLength: 10},
+				},
+			}, WithBreachedPasswords(breached))
+
+			err := service.validatePassword(test.Password, user)
+
+			if !test.ExpectedField {
+				assert.Nil(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Contains(t, validationErr.Fields, "password")
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/repository/mocks"
+	mailerMocks "one-lab-final/pkg/mailer/mocks"
+	"one-lab-final/pkg/util"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequestPasswordReset(t *testing.T) {
+	var userID int64 = 15
+	tests := []struct {
+		Name        string
+		MockUser    *entity.User
+		MockUserErr error
+		MockCount   int
+		ExpectCount bool
+		ExpectSend  bool
+		ExpectErr   bool
+	}{
+		{
+			Name: "Token sent successfully",
+			MockUser: &entity.User{
+				ID:    userID,
+				Email: util.StringToPointer("example@gmail.com"),
+			},
+			ExpectCount: true,
+			ExpectSend:  true,
+		},
+		{
+			Name: "Request is throttled",
+			MockUser: &entity.User{
+				ID:    userID,
+				Email: util.StringToPointer("example@gmail.com"),
+			},
+			MockCount:   1,
+			ExpectCount: true,
+		},
+		{
+			Name:        "User does not exist",
+			MockUserErr: repository.ErrRecordNotFound,
+		},
+		{
+			Name:        "Some error ocurred while searching user",
+			MockUserErr: errors.New("critical error"),
+			ExpectErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			mailer := mailerMocks.NewMailer(t)
+			service := New(repo, nil, WithMailer(mailer))
+			ctx := context.Background()
+
+			repo.On("GetUserByCredentials", ctx, "username").Return(test.MockUser, test.MockUserErr)
+			if test.ExpectCount {
+				repo.On("CountScopedTokens", ctx, userID, entity.ScopePasswordReset, mock.AnythingOfType("time.Time")).Return(test.MockCount, nil)
+			}
+			if test.ExpectSend {
+				repo.On("CreateToken", ctx, mock.MatchedBy(func(token *entity.Token) bool {
+					return token.Scope == entity.ScopePasswordReset && token.UserID == userID
+				})).Return(nil)
+				mailer.On("Send", ctx, "example@gmail.com", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+			}
+
+			err := service.RequestPasswordReset(ctx, "username")
+
+			if test.ExpectErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	var userID int64 = 15
+	tests := []struct {
+		Name          string
+		MockUser      *entity.User
+		MockUserErr   error
+		MockUpdateErr error
+		ExpectErr     bool
+	}{
+		{
+			Name:     "Password reset successfully",
+			MockUser: &entity.User{ID: userID},
+		},
+		{
+			Name:        "Invalid token",
+			MockUserErr: repository.ErrRecordNotFound,
+			ExpectErr:   true,
+		},
+		{
+			Name:          "Some error ocurred while updating",
+			MockUser:      &entity.User{ID: userID},
+			MockUpdateErr: errors.New("critical error"),
+			ExpectErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("GetUserByScopedToken", ctx, "token", entity.ScopePasswordReset).Return(test.MockUser, test.MockUserErr)
+			if test.MockUserErr == nil {
+				repo.On("ConsumeScopedToken", ctx, "token", entity.ScopePasswordReset).Return(test.MockUser, nil)
+				repo.On("UpdateUser", ctx, mock.MatchedBy(func(user *entity.User) bool {
+					return user.ID == userID && util.CheckPassword("password", *user.Password.Hash) == nil
+				})).Return(test.MockUpdateErr)
+			}
+			if test.MockUserErr == nil && test.MockUpdateErr == nil {
+				repo.On("DeleteScopedTokens", ctx, userID, entity.ScopePasswordReset).Return(nil)
+				repo.On("DeleteUserTokens", ctx, userID, "").Return(nil)
+			}
+
+			err := service.ResetPassword(ctx, "token", "password")
+
+			if test.ExpectErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestResetPasswordRejectedByPolicy(t *testing.T) {
+	repo := mocks.NewRepository(t)
+	service := New(repo, nil)
+	ctx := context.Background()
+
+	repo.On("GetUserByScopedToken", ctx, "token", entity.ScopePasswordReset).Return(&entity.User{ID: 15}, nil)
+
+	err := service.ResetPassword(ctx, "token", "short")
+
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, validationErr.Fields, "password")
+}
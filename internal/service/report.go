@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+)
+
+// ReportReview flags the review for moderators. A reader can have only one
+// open report of the review
+func (m *Manager) ReportReview(ctx context.Context, report *entity.ReviewReport) error {
+	if !report.Category.Valid() {
+		return ErrUnknownReportCategory
+	}
+
+	review, err := m.Repository.GetReviewByID(ctx, report.ReviewID)
+	if err != nil {
+		return err
+	}
+
+	if review.Visibility.Deleted() {
+		return repository.ErrRecordNotFound
+	}
+
+	if review.UserID == report.ReporterID {
+		return ErrCannotReportOwnReview
+	}
+
+	err = m.Repository.CreateReviewReport(ctx, report)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicateRecord):
+			return ErrAlreadyReported
+		case errors.Is(err, repository.ErrForeignKey):
+			return repository.ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) GetReviewReports(ctx context.Context, reviewID int64) ([]*entity.ReviewReport, error) {
+	_, err := m.Repository.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Repository.GetReviewReports(ctx, reviewID)
+}
+
+func (m *Manager) GetReportedReviews(ctx context.Context, search entity.ReportSearch, filter util.Filter) ([]*entity.ReportedReview, *util.Metadata, error) {
+	filterSafeList := []string{
+		"reports",
+		"created_at",
+		"last_reported_at",
+	}
+
+	if !filter.ValidateSort(filterSafeList) {
+		return nil, nil, ErrInvalidSortValue
+	}
+
+	if search.Category != nil && !search.Category.Valid() {
+		return nil, nil, ErrUnknownReportCategory
+	}
+
+	if search.State == "" {
+		search.State = entity.ReportOpen
+	}
+
+	return m.Repository.GetReportedReviews(ctx, search, filter)
+}
+
+// ResolveReviewReports closes open reports of the review. Hiding or deleting
+// the review and suspending its author are done in one step, so the author
+// is suspended only when the action succeeds
+func (m *Manager) ResolveReviewReports(ctx context.Context, resolution *entity.ReportResolution) error {
+	switch {
+	case resolution.Action == entity.ReportActionSuspend && resolution.Suspension == nil:
+		return ErrSuspensionRequired
+	case resolution.Action == entity.ReportActionDismiss && resolution.Suspension != nil:
+		return ErrDismissWithSuspension
+	}
+
+	if resolution.Suspension != nil && resolution.Suspension.Reason == nil {
+		resolution.Suspension.Reason = &resolution.Reason
+	}
+
+	review, err := m.Repository.GetReviewByID(ctx, resolution.ReviewID)
+	if err != nil {
+		return err
+	}
+
+	if resolution.Action == entity.ReportActionHide && review.Visibility == entity.ReviewHidden {
+		return ErrReviewHidden
+	}
+
+	err = m.Repository.ResolveReviewReports(ctx, resolution)
+	if err != nil {
+		// The review exists, so its reports were resolved meanwhile
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrNoOpenReports
+		}
+
+		return err
+	}
+
+	return nil
+}
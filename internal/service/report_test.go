@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/repository/mocks"
+	"one-lab-final/pkg/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReportReview(t *testing.T) {
+	var reporterID int64 = 2
+	review := &entity.Review{ID: 10, UserID: 1}
+	tests := []struct {
+		Name          string
+		Category      entity.ReportCategory
+		Review        *entity.Review
+		GetReviewErr  error
+		ExpectCreate  bool
+		MockCreateErr error
+		Expected      error
+	}{
+		{
+			Name:         "Review successfully reported",
+			Category:     entity.ReportSpam,
+			Review:       review,
+			ExpectCreate: true,
+		},
+		{
+			Name:     "Unknown category",
+			Category: entity.ReportCategory("boring"),
+			Expected: ErrUnknownReportCategory,
+		},
+		{
+			Name:         "Review does not exist",
+			Category:     entity.ReportSpam,
+			GetReviewErr: repository.ErrRecordNotFound,
+			Expected:     repository.ErrRecordNotFound,
+		},
+		{
+			Name:     "Own review",
+			Category: entity.ReportSpam,
+			Review:   &entity.Review{ID: 10, UserID: reporterID},
+			Expected: ErrCannotReportOwnReview,
+		},
+		{
+			Name:          "Review already reported by user",
+			Category:      entity.ReportSpam,
+			Review:        review,
+			ExpectCreate:  true,
+			MockCreateErr: repository.ErrDuplicateRecord,
+			Expected:      ErrAlreadyReported,
+		},
+		{
+			Name:          "Review deleted meanwhile",
+			Category:      entity.ReportSpam,
+			Review:        review,
+			ExpectCreate:  true,
+			MockCreateErr: repository.ErrForeignKey,
+			Expected:      repository.ErrRecordNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			report := &entity.ReviewReport{ReviewID: 10, ReporterID: reporterID, Category: test.Category}
+
+			if test.Review != nil || test.GetReviewErr != nil {
+				repo.On("GetReviewByID", ctx, int64(10)).Return(test.Review, test.GetReviewErr)
+			}
+			if test.ExpectCreate {
+				repo.On("CreateReviewReport", ctx, report).Return(test.MockCreateErr)
+			}
+
+			assert.Equal(t, test.Expected, service.ReportReview(ctx, report))
+		})
+	}
+}
+
+func TestGetReportedReviews(t *testing.T) {
+	unknown := entity.ReportCategory("boring")
+	tests := []struct {
+		Name          string
+		Search        entity.ReportSearch
+		Sort          string
+		ExpectedState entity.ReportState
+		Expected      error
+	}{
+		{
+			Name:          "Open reports by default",
+			Sort:          "-reports",
+			ExpectedState: entity.ReportOpen,
+		},
+		{
+			Name:          "Dismissed reports",
+			Search:        entity.ReportSearch{State: entity.ReportDismissed},
+			Sort:          "last_reported_at",
+			ExpectedState: entity.ReportDismissed,
+		},
+		{
+			Name:     "Invalid sort",
+			Sort:     "content",
+			Expected: ErrInvalidSortValue,
+		},
+		{
+			Name:     "Unknown category",
+			Search:   entity.ReportSearch{Category: &unknown},
+			Sort:     "created_at",
+			Expected: ErrUnknownReportCategory,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+			filter := util.NewFilter(1, 50, test.Sort)
+
+			if test.ExpectedState != "" {
+				search := test.Search
+				search.State = test.ExpectedState
+				repo.On("GetReportedReviews", ctx, search, filter).Return([]*entity.ReportedReview{}, &util.Metadata{}, nil)
+			}
+
+			_, _, err := service.GetReportedReviews(ctx, test.Search, filter)
+			assert.Equal(t, test.Expected, err)
+		})
+	}
+}
+
+func TestResolveReviewReports(t *testing.T) {
+	expiresIn := time.Hour
+	tests := []struct {
+		Name          string
+		Action        entity.ReportAction
+		Visibility    entity.ReviewVisibility
+		Suspension    *entity.Suspension
+		GetReviewErr  error
+		ExpectGet     bool
+		ExpectResolve bool
+		MockResolve   error
+		Expected      error
+	}{
+		{
+			Name:          "Review hidden",
+			Action:        entity.ReportActionHide,
+			ExpectGet:     true,
+			ExpectResolve: true,
+		},
+		{
+			Name:          "Pending review hidden",
+			Action:        entity.ReportActionHide,
+			Visibility:    entity.ReviewPending,
+			ExpectGet:     true,
+			ExpectResolve: true,
+		},
+		{
+			Name:       "Review already hidden",
+			Action:     entity.ReportActionHide,
+			Visibility: entity.ReviewHidden,
+			ExpectGet:  true,
+			Expected:   ErrReviewHidden,
+		},
+		{
+			Name:          "Review changed meanwhile",
+			Action:        entity.ReportActionHide,
+			ExpectGet:     true,
+			ExpectResolve: true,
+			MockResolve:   repository.ErrEditConflict,
+			Expected:      repository.ErrEditConflict,
+		},
+		{
+			Name:          "Review deleted and author suspended",
+			Action:        entity.ReportActionDelete,
+			Suspension:    &entity.Suspension{ExpiresIn: &expiresIn},
+			ExpectGet:     true,
+			ExpectResolve: true,
+		},
+		{
+			Name:     "Suspend action without duration",
+			Action:   entity.ReportActionSuspend,
+			Expected: ErrSuspensionRequired,
+		},
+		{
+			Name:       "Dismiss with suspension",
+			Action:     entity.ReportActionDismiss,
+			Suspension: &entity.Suspension{ExpiresIn: &expiresIn},
+			Expected:   ErrDismissWithSuspension,
+		},
+		{
+			Name:         "Review does not exist",
+			Action:       entity.ReportActionDismiss,
+			GetReviewErr: repository.ErrRecordNotFound,
+			ExpectGet:    true,
+			Expected:     repository.ErrRecordNotFound,
+		},
+		{
+			Name:          "Reports were already resolved",
+			Action:        entity.ReportActionDismiss,
+			ExpectGet:     true,
+			ExpectResolve: true,
+			MockResolve:   repository.ErrRecordNotFound,
+			Expected:      ErrNoOpenReports,
+		},
+		{
+			Name:          "Some error ocurred while saving",
+			Action:        entity.ReportActionHide,
+			ExpectGet:     true,
+			ExpectResolve: true,
+			MockResolve:   errors.New("critical error"),
+			Expected:      errors.New("critical error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			resolution := &entity.ReportResolution{
+				ReviewID:    10,
+				ModeratorID: 1,
+				Action:      test.Action,
+				Reason:      "Advertisement",
+				Suspension:  test.Suspension,
+			}
+
+			if test.ExpectGet {
+				var review *entity.Review
+				if test.GetReviewErr == nil {
+					review = &entity.Review{ID: 10, UserID: 3, Visibility: test.Visibility}
+				}
+				repo.On("GetReviewByID", ctx, int64(10)).Return(review, test.GetReviewErr)
+			}
+			if test.ExpectResolve {
+				repo.On("ResolveReviewReports", ctx, mock.MatchedBy(func(r *entity.ReportResolution) bool {
+					// Reason of the resolution is used for the suspension
+					return r.Suspension == nil || *r.Suspension.Reason == r.Reason
+				})).Return(test.MockResolve)
+			}
+
+			assert.Equal(t, test.Expected, service.ResolveReviewReports(ctx, resolution))
+		})
+	}
+}
@@ -2,12 +2,22 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
 	"one-lab-final/pkg/util"
 )
 
+// CreateReview screens content of the review with content rules first, the
+// review is rejected or created pending moderator approval when rules match
 func (m *Manager) CreateReview(ctx context.Context, review *entity.Review) error {
-	err := m.Repository.CreateReview(ctx, review)
+	err := m.screenReview(ctx, review)
+	if err != nil {
+		return err
+	}
+
+	err = m.Repository.CreateReview(ctx, review)
 	if err != nil {
 		return err
 	}
@@ -15,6 +25,21 @@ func (m *Manager) CreateReview(ctx context.Context, review *entity.Review) error
 	return nil
 }
 
+// GetReviews returns reviews for moderators, any visibility included
+func (m *Manager) GetReviews(ctx context.Context, search entity.ReviewSearch, filter util.Filter) ([]*entity.Review, *util.Metadata, error) {
+	filterSafeList := []string{"id", "rating", "created_at", "updated_at"}
+
+	if !filter.ValidateSort(filterSafeList) {
+		return nil, nil, ErrInvalidSortValue
+	}
+
+	if search.Visibility != nil && !search.Visibility.Valid() {
+		return nil, nil, ErrUnknownVisibility
+	}
+
+	return m.Repository.GetReviews(ctx, search, filter)
+}
+
 func (m *Manager) GetReviewsByBookID(ctx context.Context, bookID int64, filter util.Filter) ([]*entity.Review, *util.Metadata, error) {
 	filterSafeList := []string{"rating", "date", "created_at", "updated_at"}
 
@@ -30,8 +55,52 @@ func (m *Manager) GetReviewsByBookID(ctx context.Context, bookID int64, filter u
 	return reviews, meta, nil
 }
 
+// GetOwnReviews returns reviews of the author, hidden, pending and removed
+// ones included with a notice. Deleted reviews are skipped
+func (m *Manager) GetOwnReviews(ctx context.Context, userID int64) ([]*entity.Review, error) {
+	reviews, err := m.Repository.GetReviewsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	own := make([]*entity.Review, 0, len(reviews))
+
+	for _, review := range reviews {
+		switch review.Visibility {
+		case entity.ReviewDeleted:
+			continue
+		case entity.ReviewHidden:
+			review.Notice = "review was hidden by moderator and is visible only to you"
+			if review.HiddenReason != nil {
+				review.Notice = fmt.Sprintf("%s, reason: %s", review.Notice, *review.HiddenReason)
+			}
+		case entity.ReviewRemoved:
+			review.Notice = "review was removed by moderator and is visible only to you"
+			if review.HiddenReason != nil {
+				review.Notice = fmt.Sprintf("%s, reason: %s", review.Notice, *review.HiddenReason)
+			}
+		case entity.ReviewPending:
+			review.Notice = "review is waiting for moderator approval"
+			if review.HiddenReason != nil {
+				review.Notice = fmt.Sprintf("%s, it %s", review.Notice, *review.HiddenReason)
+			}
+		}
+
+		own = append(own, review)
+	}
+
+	return own, nil
+}
+
+// UpdateReview screens changed content like CreateReview, visible review goes
+// back to moderation when its new content is quarantined
 func (m *Manager) UpdateReview(ctx context.Context, review *entity.Review) error {
-	err := m.Repository.UpdateReview(ctx, review)
+	err := m.screenReview(ctx, review)
+	if err != nil {
+		return err
+	}
+
+	err = m.Repository.UpdateReview(ctx, review)
 	if err != nil {
 		return err
 	}
@@ -47,3 +116,93 @@ func (m *Manager) DeleteReview(ctx context.Context, reviewID int64, userID int64
 
 	return nil
 }
+
+// RemoveReview removes review of any user by moderator, own review of the
+// moderator is deleted like by any other author
+func (m *Manager) RemoveReview(ctx context.Context, reviewID int64, moderatorID int64) error {
+	current, err := m.Repository.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case current.Visibility.Deleted():
+		return repository.ErrRecordNotFound
+	case current.UserID == moderatorID:
+		return m.DeleteReview(ctx, reviewID, moderatorID)
+	}
+
+	return m.Repository.RemoveReview(ctx, &entity.Review{ID: reviewID, HiddenBy: &moderatorID})
+}
+
+// HideReview removes visible or pending review from public listings and
+// ratings, open reports of the review are closed as actioned
+func (m *Manager) HideReview(ctx context.Context, review *entity.Review) error {
+	current, err := m.Repository.GetReviewByID(ctx, review.ID)
+	if err != nil {
+		return err
+	}
+
+	switch current.Visibility {
+	case entity.ReviewDeleted, entity.ReviewRemoved:
+		return repository.ErrRecordNotFound
+	case entity.ReviewHidden:
+		return ErrReviewHidden
+	}
+
+	err = m.Repository.HideReview(ctx, review)
+	if err != nil {
+		// The review was visible, so it was changed meanwhile
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return repository.ErrEditConflict
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// RestoreReview makes hidden review visible again, pending review is approved
+func (m *Manager) RestoreReview(ctx context.Context, review *entity.Review) error {
+	current, err := m.Repository.GetReviewByID(ctx, review.ID)
+	if err != nil {
+		return err
+	}
+
+	switch current.Visibility {
+	case entity.ReviewDeleted, entity.ReviewRemoved:
+		return repository.ErrRecordNotFound
+	case entity.ReviewVisible:
+		return ErrReviewNotHidden
+	}
+
+	err = m.Repository.RestoreReview(ctx, review)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return repository.ErrEditConflict
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (m *Manager) screenReview(ctx context.Context, review *entity.Review) error {
+	if review.Content == nil {
+		return nil
+	}
+
+	problem, err := m.screenContent(ctx, *review.Content)
+	if err != nil {
+		return err
+	}
+
+	if problem != nil {
+		review.Visibility = entity.ReviewPending
+		review.HiddenReason = problem
+	}
+
+	return nil
+}
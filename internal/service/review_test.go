@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
 	"one-lab-final/internal/repository/mocks"
 	"one-lab-final/pkg/util"
 	"testing"
@@ -47,6 +48,7 @@ func TestCreateReview(t *testing.T) {
 			service := New(repo, nil)
 			ctx := context.Background()
 
+			repo.On("GetContentRules", ctx).Return([]*entity.ContentRule{}, nil)
 			repo.On("CreateReview", ctx, test.ExpectedReview).Return(test.MockResult)
 
 			assert.Equal(t, service.CreateReview(ctx, test.ExpectedReview), test.MockResult)
@@ -161,6 +163,7 @@ func TestUpdateReview(t *testing.T) {
 			service := New(repo, nil)
 			ctx := context.Background()
 
+			repo.On("GetContentRules", ctx).Return([]*entity.ContentRule{}, nil)
 			repo.On("UpdateReview", ctx, test.ExpectedReview).Return(test.MockResult)
 
 			assert.Equal(t, service.UpdateReview(ctx, test.ExpectedReview), test.MockResult)
@@ -197,3 +200,199 @@ func TestDeleteReview(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoveReview(t *testing.T) {
+	var moderatorID int64 = 7
+	var reviewID int64 = 42
+	tests := []struct {
+		Name         string
+		AuthorID     int64
+		Visibility   entity.ReviewVisibility
+		GetReviewErr error
+		ExpectRemove bool
+		ExpectDelete bool
+		MockResult   error
+		Expected     error
+	}{
+		{
+			Name:         "Review of other user removed",
+			AuthorID:     5,
+			Visibility:   entity.ReviewVisible,
+			ExpectRemove: true,
+		},
+		{
+			Name:         "Own review of moderator deleted",
+			AuthorID:     moderatorID,
+			Visibility:   entity.ReviewVisible,
+			ExpectDelete: true,
+		},
+		{
+			Name:         "Review does not exist",
+			GetReviewErr: repository.ErrRecordNotFound,
+			Expected:     repository.ErrRecordNotFound,
+		},
+		{
+			Name:       "Review already removed",
+			AuthorID:   5,
+			Visibility: entity.ReviewRemoved,
+			Expected:   repository.ErrRecordNotFound,
+		},
+		{
+			Name:         "Some error ocurred while removing",
+			AuthorID:     5,
+			Visibility:   entity.ReviewHidden,
+			ExpectRemove: true,
+			MockResult:   errors.New("critical error"),
+			Expected:     errors.New("critical error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			var current *entity.Review
+			if test.GetReviewErr == nil {
+				current = &entity.Review{ID: reviewID, UserID: test.AuthorID, Visibility: test.Visibility}
+			}
+
+			repo.On("GetReviewByID", ctx, reviewID).Return(current, test.GetReviewErr)
+			if test.ExpectRemove {
+				repo.On("RemoveReview", ctx, &entity.Review{ID: reviewID, HiddenBy: &moderatorID}).Return(test.MockResult)
+			}
+			if test.ExpectDelete {
+				repo.On("DeleteReview", ctx, reviewID, moderatorID).Return(test.MockResult)
+			}
+
+			assert.Equal(t, test.Expected, service.RemoveReview(ctx, reviewID, moderatorID))
+		})
+	}
+}
+
+func TestGetOwnReviews(t *testing.T) {
+	var userID int64 = 5
+	reason := "Spoilers"
+
+	repo := mocks.NewRepository(t)
+	service := New(repo, nil)
+	ctx := context.Background()
+
+	repo.On("GetReviewsByUserID", ctx, userID).Return([]*entity.Review{
+		{ID: 1, UserID: userID, Visibility: entity.ReviewVisible},
+		{ID: 2, UserID: userID, Visibility: entity.ReviewHidden, HiddenReason: &reason},
+		{ID: 3, UserID: userID, Visibility: entity.ReviewDeleted},
+		{ID: 4, UserID: userID, Visibility: entity.ReviewRemoved},
+	}, nil)
+
+	reviews, err := service.GetOwnReviews(ctx, userID)
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 3)
+	assert.Empty(t, reviews[0].Notice)
+	assert.Contains(t, reviews[1].Notice, reason)
+	assert.Contains(t, reviews[2].Notice, "removed by moderator")
+}
+
+func TestHideReview(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Visibility   entity.ReviewVisibility
+		GetReviewErr error
+		ExpectHide   bool
+		MockHideErr  error
+		Expected     error
+	}{
+		{
+			Name:       "Review successfully hidden",
+			Visibility: entity.ReviewVisible,
+			ExpectHide: true,
+		},
+		{
+			Name:         "Review does not exist",
+			GetReviewErr: repository.ErrRecordNotFound,
+			Expected:     repository.ErrRecordNotFound,
+		},
+		{
+			Name:       "Review deleted by author",
+			Visibility: entity.ReviewDeleted,
+			Expected:   repository.ErrRecordNotFound,
+		},
+		{
+			Name:       "Review already hidden",
+			Visibility: entity.ReviewHidden,
+			Expected:   ErrReviewHidden,
+		},
+		{
+			Name:        "Review changed meanwhile",
+			Visibility:  entity.ReviewVisible,
+			ExpectHide:  true,
+			MockHideErr: repository.ErrRecordNotFound,
+			Expected:    repository.ErrEditConflict,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			var current *entity.Review
+			if test.GetReviewErr == nil {
+				current = &entity.Review{ID: 10, Visibility: test.Visibility}
+			}
+
+			review := &entity.Review{ID: 10}
+
+			repo.On("GetReviewByID", ctx, int64(10)).Return(current, test.GetReviewErr)
+			if test.ExpectHide {
+				repo.On("HideReview", ctx, review).Return(test.MockHideErr)
+			}
+
+			assert.Equal(t, test.Expected, service.HideReview(ctx, review))
+		})
+	}
+}
+
+func TestRestoreReview(t *testing.T) {
+	tests := []struct {
+		Name          string
+		Visibility    entity.ReviewVisibility
+		ExpectRestore bool
+		Expected      error
+	}{
+		{
+			Name:          "Review successfully restored",
+			Visibility:    entity.ReviewHidden,
+			ExpectRestore: true,
+		},
+		{
+			Name:       "Review is not hidden",
+			Visibility: entity.ReviewVisible,
+			Expected:   ErrReviewNotHidden,
+		},
+		{
+			Name:       "Review deleted by author",
+			Visibility: entity.ReviewDeleted,
+			Expected:   repository.ErrRecordNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			review := &entity.Review{ID: 10}
+
+			repo.On("GetReviewByID", ctx, int64(10)).Return(&entity.Review{ID: 10, Visibility: test.Visibility}, nil)
+			if test.ExpectRestore {
+				repo.On("RestoreReview", ctx, review).Return(nil)
+			}
+
+			assert.Equal(t, test.Expected, service.RestoreReview(ctx, review))
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+)
+
+func (m *Manager) GetRoles(ctx context.Context) ([]*entity.RoleDefinition, error) {
+	return m.Repository.GetRoles(ctx)
+}
+
+func (m *Manager) GetRole(ctx context.Context, name entity.Role) (*entity.RoleDefinition, error) {
+	return m.Repository.GetRole(ctx, name)
+}
+
+// CreateRole creates custom role. The actor must have every permission of
+// the role, so nobody creates a role above their own
+func (m *Manager) CreateRole(ctx context.Context, actorID int64, role *entity.RoleDefinition) error {
+	permissions, err := normalizePermissions(role.Permissions)
+	if err != nil {
+		return err
+	}
+
+	role.Permissions = permissions
+
+	err = m.checkRoleActor(ctx, actorID, role.Permissions)
+	if err != nil {
+		return err
+	}
+
+	err = m.Repository.CreateRole(ctx, role)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicateRecord) {
+			return ErrRoleExists
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// UpdateRole replaces description and permissions of custom role, users
+// with the role get new permissions with the next request. The actor must
+// have every permission of both current and new role
+func (m *Manager) UpdateRole(ctx context.Context, actorID int64, role *entity.RoleDefinition) error {
+	if role.Name.BuiltIn() {
+		return ErrBuiltInRole
+	}
+
+	permissions, err := normalizePermissions(role.Permissions)
+	if err != nil {
+		return err
+	}
+
+	role.Permissions = permissions
+
+	current, err := m.Repository.GetRole(ctx, role.Name)
+	if err != nil {
+		return err
+	}
+
+	err = m.checkRoleActor(ctx, actorID, current.Permissions, role.Permissions)
+	if err != nil {
+		return err
+	}
+
+	return m.Repository.UpdateRole(ctx, role)
+}
+
+// DeleteRole removes custom role, the actor must have every permission of it
+func (m *Manager) DeleteRole(ctx context.Context, actorID int64, name entity.Role) error {
+	if name.BuiltIn() {
+		return ErrBuiltInRole
+	}
+
+	current, err := m.Repository.GetRole(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	err = m.checkRoleActor(ctx, actorID, current.Permissions)
+	if err != nil {
+		return err
+	}
+
+	err = m.Repository.DeleteRole(ctx, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrForeignKey) {
+			return ErrRoleInUse
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// GrantRoleToUser changes role of the user and records it in role history.
+// When ActorID is set, the actor must have every permission of both current
+// and new role of the user. At least one admin always remains
+func (m *Manager) GrantRoleToUser(ctx context.Context, change *entity.RoleChange) error {
+	role, err := m.Repository.GetRole(ctx, change.NewRole)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrUnknownRole
+		}
+
+		return err
+	}
+
+	user, err := m.Repository.GetUserByID(ctx, change.UserID)
+	if err != nil {
+		return err
+	}
+
+	change.OldRole = user.Role
+
+	if change.ActorID != nil {
+		actor, err := m.Repository.GetUserByID(ctx, *change.ActorID)
+		if err != nil {
+			return err
+		}
+
+		if !entity.HasPermissions(actor.Permissions, role.Permissions) || !entity.HasPermissions(actor.Permissions, user.Permissions) {
+			return ErrRoleAboveOwn
+		}
+	}
+
+	if change.OldRole == change.NewRole {
+		return nil
+	}
+
+	if change.OldRole == entity.ADMIN {
+		admins, err := m.Repository.CountUsersWithRole(ctx, entity.ADMIN)
+		if err != nil {
+			return err
+		}
+
+		if admins <= 1 {
+			return ErrLastAdmin
+		}
+	}
+
+	return m.Repository.GrantRoleToUser(ctx, change)
+}
+
+func (m *Manager) GetRoleHistory(ctx context.Context, userID int64, filter util.Filter) ([]*entity.RoleChange, *util.Metadata, error) {
+	filterSafeList := []string{"created_at"}
+
+	if !filter.ValidateSort(filterSafeList) {
+		return nil, nil, ErrInvalidSortValue
+	}
+
+	_, err := m.Repository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return m.Repository.GetRoleHistory(ctx, userID, filter)
+}
+
+// normalizePermissions rejects unknown permissions and drops duplicates
+func normalizePermissions(permissions []entity.Permission) ([]entity.Permission, error) {
+	result := make([]entity.Permission, 0, len(permissions))
+
+	for _, permission := range permissions {
+		if !entity.HasPermission(entity.Permissions, permission) {
+			return nil, ErrUnknownPermission
+		}
+
+		if !entity.HasPermission(result, permission) {
+			result = append(result, permission)
+		}
+	}
+
+	return result, nil
+}
+
+// checkRoleActor returns ErrRoleAboveOwn unless the actor has all of the
+// permissions
+func (m *Manager) checkRoleActor(ctx context.Context, actorID int64, permissions ...[]entity.Permission) error {
+	actor, err := m.Repository.GetUserByID(ctx, actorID)
+	if err != nil {
+		return err
+	}
+
+	for _, required := range permissions {
+		if !entity.HasPermissions(actor.Permissions, required) {
+			return ErrRoleAboveOwn
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/repository/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRole(t *testing.T) {
+	var actorID int64 = 1
+	librarian := &entity.User{ID: actorID, Permissions: []entity.Permission{entity.PermissionBooksCreate, entity.PermissionBooksUpdate, entity.PermissionRolesManage}}
+
+	tests := []struct {
+		Name                string
+		Permissions         []entity.Permission
+		MockError           error
+		ExpectActor         bool
+		ExpectMock          bool
+		ExpectedPermissions []entity.Permission
+		ExpectErr           error
+	}{
+		{
+			Name:                "Role created",
+			Permissions:         []entity.Permission{entity.PermissionBooksCreate, entity.PermissionBooksUpdate, entity.PermissionBooksCreate},
+			ExpectActor:         true,
+			ExpectMock:          true,
+			ExpectedPermissions: []entity.Permission{entity.PermissionBooksCreate, entity.PermissionBooksUpdate},
+		},
+		{
+			Name:        "Unknown permission",
+			Permissions: []entity.Permission{"books.burn"},
+			ExpectErr:   ErrUnknownPermission,
+		},
+		{
+			Name:        "Permission above own",
+			Permissions: []entity.Permission{entity.PermissionBooksCreate, entity.PermissionRolesGrant},
+			ExpectActor: true,
+			ExpectErr:   ErrRoleAboveOwn,
+		},
+		{
+			Name:                "Role already exists",
+			Permissions:         []entity.Permission{},
+			MockError:           repository.ErrDuplicateRecord,
+			ExpectActor:         true,
+			ExpectMock:          true,
+			ExpectedPermissions: []entity.Permission{},
+			ExpectErr:           ErrRoleExists,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			role := &entity.RoleDefinition{Name: "LIBRARIAN", Permissions: test.Permissions}
+
+			if test.ExpectActor {
+				repo.On("GetUserByID", ctx, actorID).Return(librarian, nil)
+			}
+			if test.ExpectMock {
+				expected := &entity.RoleDefinition{Name: "LIBRARIAN", Permissions: test.ExpectedPermissions}
+				repo.On("CreateRole", ctx, expected).Return(test.MockError)
+			}
+
+			err := service.CreateRole(ctx, actorID, role)
+			assert.ErrorIs(t, err, test.ExpectErr)
+		})
+	}
+}
+
+func TestUpdateRole(t *testing.T) {
+	var actorID int64 = 1
+	librarian := &entity.User{ID: actorID, Permissions: []entity.Permission{entity.PermissionBooksCreate, entity.PermissionBooksUpdate, entity.PermissionRolesManage}}
+
+	tests := []struct {
+		Name           string
+		Role           entity.Role
+		Permissions    []entity.Permission
+		MockCurrent    *entity.RoleDefinition
+		MockCurrentErr error
+		MockError      error
+		ExpectCurrent  bool
+		ExpectActor    bool
+		ExpectMock     bool
+		ExpectErr      error
+	}{
+		{
+			Name:          "Role updated",
+			Role:          "LIBRARIAN",
+			Permissions:   []entity.Permission{entity.PermissionBooksUpdate},
+			MockCurrent:   &entity.RoleDefinition{Name: "LIBRARIAN", Permissions: []entity.Permission{entity.PermissionBooksCreate}},
+			ExpectCurrent: true,
+			ExpectActor:   true,
+			ExpectMock:    true,
+		},
+		{
+			Name:        "Built-in role",
+			Role:        entity.MODERATOR,
+			Permissions: []entity.Permission{entity.PermissionBooksUpdate},
+			ExpectErr:   ErrBuiltInRole,
+		},
+		{
+			Name:           "Role does not exist",
+			Role:           "LIBRARIAN",
+			Permissions:    []entity.Permission{entity.PermissionBooksUpdate},
+			MockCurrentErr: repository.ErrRecordNotFound,
+			ExpectCurrent:  true,
+			ExpectErr:      repository.ErrRecordNotFound,
+		},
+		{
+			Name:          "Permission added above own",
+			Role:          "LIBRARIAN",
+			Permissions:   []entity.Permission{entity.PermissionBooksUpdate, entity.PermissionUsersSuspend},
+			MockCurrent:   &entity.RoleDefinition{Name: "LIBRARIAN", Permissions: []entity.Permission{entity.PermissionBooksUpdate}},
+			ExpectCurrent: true,
+			ExpectActor:   true,
+			ExpectErr:     ErrRoleAboveOwn,
+		},
+		{
+			Name:          "Current role above own",
+			Role:          "SENIOR",
+			Permissions:   []entity.Permission{entity.PermissionBooksUpdate},
+			MockCurrent:   &entity.RoleDefinition{Name: "SENIOR", Permissions: []entity.Permission{entity.PermissionUsersSuspend}},
+			ExpectCurrent: true,
+			ExpectActor:   true,
+			ExpectErr:     ErrRoleAboveOwn,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			role := &entity.RoleDefinition{Name: test.Role, Permissions: test.Permissions}
+
+			if test.ExpectCurrent {
+				repo.On("GetRole", ctx, test.Role).Return(test.MockCurrent, test.MockCurrentErr)
+			}
+			if test.ExpectActor {
+				repo.On("GetUserByID", ctx, actorID).Return(librarian, nil)
+			}
+			if test.ExpectMock {
+				repo.On("UpdateRole", ctx, role).Return(test.MockError)
+			}
+
+			err := service.UpdateRole(ctx, actorID, role)
+			assert.ErrorIs(t, err, test.ExpectErr)
+		})
+	}
+}
+
+func TestDeleteRole(t *testing.T) {
+	var actorID int64 = 1
+	librarian := &entity.User{ID: actorID, Permissions: []entity.Permission{entity.PermissionBooksCreate, entity.PermissionBooksUpdate, entity.PermissionRolesManage}}
+
+	tests := []struct {
+		Name           string
+		Role           entity.Role
+		MockCurrent    *entity.RoleDefinition
+		MockCurrentErr error
+		MockError      error
+		ExpectCurrent  bool
+		ExpectMock     bool
+		ExpectErr      error
+	}{
+		{
+			Name:          "Role deleted",
+			Role:          "LIBRARIAN",
+			MockCurrent:   &entity.RoleDefinition{Name: "LIBRARIAN", Permissions: []entity.Permission{entity.PermissionBooksUpdate}},
+			ExpectCurrent: true,
+			ExpectMock:    true,
+		},
+		{
+			Name:      "Built-in role",
+			Role:      entity.ADMIN,
+			ExpectErr: ErrBuiltInRole,
+		},
+		{
+			Name:           "Role does not exist",
+			Role:           "LIBRARIAN",
+			MockCurrentErr: repository.ErrRecordNotFound,
+			ExpectCurrent:  true,
+			ExpectErr:      repository.ErrRecordNotFound,
+		},
+		{
+			Name:          "Role above own",
+			Role:          "SENIOR",
+			MockCurrent:   &entity.RoleDefinition{Name: "SENIOR", Permissions: []entity.Permission{entity.PermissionUsersSuspend}},
+			ExpectCurrent: true,
+			ExpectErr:     ErrRoleAboveOwn,
+		},
+		{
+			Name:          "Role is assigned to users",
+			Role:          "LIBRARIAN",
+			MockCurrent:   &entity.RoleDefinition{Name: "LIBRARIAN", Permissions: []entity.Permission{entity.PermissionBooksUpdate}},
+			MockError:     repository.ErrForeignKey,
+			ExpectCurrent: true,
+			ExpectMock:    true,
+			ExpectErr:     ErrRoleInUse,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			if test.ExpectCurrent {
+				repo.On("GetRole", ctx, test.Role).Return(test.MockCurrent, test.MockCurrentErr)
+			}
+			if test.MockCurrent != nil {
+				repo.On("GetUserByID", ctx, actorID).Return(librarian, nil)
+			}
+			if test.ExpectMock {
+				repo.On("DeleteRole", ctx, test.Role).Return(test.MockError)
+			}
+
+			err := service.DeleteRole(ctx, actorID, test.Role)
+			assert.ErrorIs(t, err, test.ExpectErr)
+		})
+	}
+}
+
+func TestGrantRoleToUser(t *testing.T) {
+	var userID, actorID int64 = 15, 1
+	errCritical := errors.New("critical error")
+
+	admin := &entity.User{ID: actorID, Role: entity.ADMIN, Permissions: entity.Permissions}
+	granter := &entity.User{ID: actorID, Role: "GRANTER", Permissions: []entity.Permission{entity.PermissionRolesGrant, entity.PermissionUsersSuspend}}
+	moderator := &entity.RoleDefinition{Name: entity.MODERATOR, Permissions: []entity.Permission{entity.PermissionUsersSuspend}}
+	user := &entity.RoleDefinition{Name: entity.USER, Permissions: []entity.Permission{}}
+
+	tests := []struct {
+		Name        string
+		Role        *entity.RoleDefinition
+		MockRoleErr error
+		Target      *entity.User
+		Actor       *entity.User
+		Admins      int
+		ExpectCount bool
+		ExpectGrant bool
+		MockGrant   error
+		ExpectErr   error
+	}{
+		{
+			Name:        "Role granted by admin",
+			Role:        moderator,
+			Target:      &entity.User{ID: userID, Role: entity.USER},
+			Actor:       admin,
+			ExpectGrant: true,
+		},
+		{
+			Name:        "Role granted by system",
+			Role:        &entity.RoleDefinition{Name: entity.ADMIN, Permissions: entity.Permissions},
+			Target:      &entity.User{ID: userID, Role: entity.USER},
+			ExpectGrant: true,
+		},
+		{
+			Name:        "Role does not exist",
+			Role:        &entity.RoleDefinition{Name: "OWNER"},
+			MockRoleErr: repository.ErrRecordNotFound,
+			ExpectErr:   ErrUnknownRole,
+		},
+		{
+			Name:      "User does not exist",
+			Role:      moderator,
+			ExpectErr: repository.ErrRecordNotFound,
+		},
+		{
+			Name:      "Role above own",
+			Role:      &entity.RoleDefinition{Name: entity.ADMIN, Permissions: entity.Permissions},
+			Target:    &entity.User{ID: userID, Role: entity.USER},
+			Actor:     granter,
+			ExpectErr: ErrRoleAboveOwn,
+		},
+		{
+			Name:      "Current role above own",
+			Role:      user,
+			Target:    &entity.User{ID: userID, Role: entity.ADMIN, Permissions: entity.Permissions},
+			Actor:     granter,
+			ExpectErr: ErrRoleAboveOwn,
+		},
+		{
+			Name:        "Role granted within own permissions",
+			Role:        moderator,
+			Target:      &entity.User{ID: userID, Role: entity.USER},
+			Actor:       granter,
+			ExpectGrant: true,
+		},
+		{
+			Name:   "Role is not changed",
+			Role:   moderator,
+			Target: &entity.User{ID: userID, Role: entity.MODERATOR, Permissions: moderator.Permissions},
+			Actor:  admin,
+		},
+		{
+			Name:        "Last admin demotes themselves",
+			Role:        user,
+			Target:      admin,
+			Actor:       admin,
+			Admins:      1,
+			ExpectCount: true,
+			ExpectErr:   ErrLastAdmin,
+		},
+		{
+			Name:        "Admin demoted while other admins remain",
+			Role:        user,
+			Target:      &entity.User{ID: userID, Role: entity.ADMIN, Permissions: entity.Permissions},
+			Actor:       admin,
+			Admins:      2,
+			ExpectCount: true,
+			ExpectGrant: true,
+		},
+		{
+			Name:        "Role was changed meanwhile",
+			Role:        user,
+			Target:      &entity.User{ID: userID, Role: entity.ADMIN, Permissions: entity.Permissions},
+			Actor:       admin,
+			Admins:      2,
+			ExpectCount: true,
+			ExpectGrant: true,
+			MockGrant:   repository.ErrEditConflict,
+			ExpectErr:   repository.ErrEditConflict,
+		},
+		{
+			Name:        "Some error ocurred while updating",
+			Role:        moderator,
+			Target:      &entity.User{ID: userID, Role: entity.USER},
+			Actor:       admin,
+			ExpectGrant: true,
+			MockGrant:   errCritical,
+			ExpectErr:   errCritical,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			change := &entity.RoleChange{UserID: userID, NewRole: test.Role.Name}
+			if test.Actor != nil {
+				change.ActorID = &test.Actor.ID
+			}
+
+			if test.MockRoleErr != nil {
+				repo.On("GetRole", ctx, test.Role.Name).Return(nil, test.MockRoleErr)
+			} else {
+				repo.On("GetRole", ctx, test.Role.Name).Return(test.Role, nil)
+
+				if test.Target != nil {
+					repo.On("GetUserByID", ctx, userID).Return(test.Target, nil).Once()
+				} else {
+					repo.On("GetUserByID", ctx, userID).Return(nil, repository.ErrRecordNotFound).Once()
+				}
+			}
+
+			if test.Actor != nil && test.Target != nil {
+				repo.On("GetUserByID", ctx, actorID).Return(test.Actor, nil).Once()
+			}
+
+			if test.ExpectCount {
+				repo.On("CountUsersWithRole", ctx, entity.ADMIN).Return(test.Admins, nil)
+			}
+
+			if test.ExpectGrant {
+				repo.On("GrantRoleToUser", ctx, change).Return(test.MockGrant)
+			}
+
+			err := service.GrantRoleToUser(ctx, change)
+			assert.ErrorIs(t, err, test.ExpectErr)
+
+			if test.Target != nil {
+				assert.Equal(t, test.Target.Role, change.OldRole)
+			}
+		})
+	}
+}
@@ -2,15 +2,94 @@ package service
 
 import (
 	"context"
+	"errors"
 	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+	"time"
 )
 
 func (m *Manager) NewSuspension(ctx context.Context, suspension *entity.Suspension) error {
-	return m.Repository.NewSuspension(ctx, suspension)
+	err := m.Repository.NewSuspension(ctx, suspension)
+	if err != nil {
+		if errors.Is(err, repository.ErrForeignKey) {
+			return repository.ErrRecordNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (m *Manager) GetSuspension(ctx context.Context, suspensionID int64) (*entity.Suspension, error) {
+	return m.Repository.GetSuspension(ctx, suspensionID)
+}
+
+func (m *Manager) GetSuspensions(ctx context.Context, search entity.SuspensionSearch, filter util.Filter) ([]*entity.Suspension, *util.Metadata, error) {
+	filterSafeList := []string{
+		"id",
+		"created_at",
+		"expires_at",
+	}
+
+	if !filter.ValidateSort(filterSafeList) {
+		return nil, nil, ErrInvalidSortValue
+	}
+
+	return m.Repository.GetSuspensions(ctx, search, filter)
 }
 
+// UpdateSuspension changes reason or duration of active suspension. The new
+// duration is counted from creation and must end in the future, suspensions
+// are ended early with LiftSuspension
 func (m *Manager) UpdateSuspension(ctx context.Context, suspension *entity.Suspension) error {
-	return m.Repository.UpdateSuspension(ctx, suspension)
+	current, err := m.Repository.GetSuspension(ctx, suspension.ID)
+	if err != nil {
+		return err
+	}
+
+	if !current.Active {
+		return ErrSuspensionNotActive
+	}
+
+	if suspension.ExpiresIn != nil && !current.CreatedAt.Add(*suspension.ExpiresIn).After(time.Now()) {
+		return ErrSuspensionTooShort
+	}
+
+	err = m.Repository.UpdateSuspension(ctx, suspension)
+	if err != nil {
+		// Suspension has expired or was lifted after it was read
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrSuspensionNotActive
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (m *Manager) LiftSuspension(ctx context.Context, suspension *entity.Suspension) error {
+	current, err := m.Repository.GetSuspension(ctx, suspension.ID)
+	if err != nil {
+		return err
+	}
+
+	if !current.Active {
+		return ErrSuspensionNotActive
+	}
+
+	err = m.Repository.LiftSuspension(ctx, suspension)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return ErrSuspensionNotActive
+		}
+
+		return err
+	}
+
+	return nil
 }
 
 func (m *Manager) CheckSuspension(ctx context.Context, userID int64) ([]*entity.Suspension, error) {
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
 	"one-lab-final/internal/repository/mocks"
+	"one-lab-final/pkg/util"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -14,6 +17,7 @@ func TestNewSuspension(t *testing.T) {
 	tests := []struct {
 		Name       string
 		MockResult any
+		Expected   any
 		Suspension *entity.Suspension
 	}{
 		{
@@ -26,6 +30,12 @@ func TestNewSuspension(t *testing.T) {
 			MockResult: errors.New("critical error"),
 			Suspension: &entity.Suspension{},
 		},
+		{
+			Name:       "User does not exist",
+			MockResult: repository.ErrForeignKey,
+			Expected:   repository.ErrRecordNotFound,
+			Suspension: &entity.Suspension{},
+		},
 	}
 
 	for _, test := range tests {
@@ -36,7 +46,12 @@ func TestNewSuspension(t *testing.T) {
 
 			repo.On("NewSuspension", ctx, test.Suspension).Return(test.MockResult)
 
-			assert.Equal(t, service.NewSuspension(ctx, test.Suspension), test.MockResult)
+			expected := test.Expected
+			if expected == nil {
+				expected = test.MockResult
+			}
+
+			assert.Equal(t, expected, service.NewSuspension(ctx, test.Suspension))
 		})
 	}
 }
@@ -81,20 +96,159 @@ func TestCheckSuspension(t *testing.T) {
 }
 
 func TestUpdateSuspension(t *testing.T) {
+	var suspensionID int64 = 2
+	errCritical := errors.New("critical error")
+	day := 24 * time.Hour
+	minute := time.Minute
+
+	tests := []struct {
+		Name         string
+		Current      *entity.Suspension
+		MockGetError error
+		ExpiresIn    *time.Duration
+		ExpectUpdate bool
+		MockResult   error
+		ExpectErr    error
+	}{
+		{
+			Name:         "Suspension extended",
+			Current:      &entity.Suspension{ID: suspensionID, Active: true, CreatedAt: time.Now().Add(-time.Hour)},
+			ExpiresIn:    &day,
+			ExpectUpdate: true,
+		},
+		{
+			Name:         "Only reason is changed",
+			Current:      &entity.Suspension{ID: suspensionID, Active: true, CreatedAt: time.Now().Add(-time.Hour)},
+			ExpectUpdate: true,
+		},
+		{
+			Name:         "Suspension does not exist",
+			MockGetError: repository.ErrRecordNotFound,
+			ExpiresIn:    &day,
+			ExpectErr:    repository.ErrRecordNotFound,
+		},
+		{
+			Name:      "Suspension is not active",
+			Current:   &entity.Suspension{ID: suspensionID, Active: false, CreatedAt: time.Now().Add(-time.Hour)},
+			ExpiresIn: &day,
+			ExpectErr: ErrSuspensionNotActive,
+		},
+		{
+			Name:      "Suspension would end in the past",
+			Current:   &entity.Suspension{ID: suspensionID, Active: true, CreatedAt: time.Now().Add(-time.Hour)},
+			ExpiresIn: &minute,
+			ExpectErr: ErrSuspensionTooShort,
+		},
+		{
+			Name:         "Suspension ended meanwhile",
+			Current:      &entity.Suspension{ID: suspensionID, Active: true, CreatedAt: time.Now().Add(-time.Hour)},
+			ExpiresIn:    &day,
+			ExpectUpdate: true,
+			MockResult:   repository.ErrRecordNotFound,
+			ExpectErr:    ErrSuspensionNotActive,
+		},
+		{
+			Name:         "Some error ocurred while updating",
+			Current:      &entity.Suspension{ID: suspensionID, Active: true, CreatedAt: time.Now().Add(-time.Hour)},
+			ExpiresIn:    &day,
+			ExpectUpdate: true,
+			MockResult:   errCritical,
+			ExpectErr:    errCritical,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			suspension := &entity.Suspension{ID: suspensionID, Reason: util.StringToPointer("Spam"), ExpiresIn: test.ExpiresIn}
+
+			repo.On("GetSuspension", ctx, suspensionID).Return(test.Current, test.MockGetError)
+			if test.ExpectUpdate {
+				repo.On("UpdateSuspension", ctx, suspension).Return(test.MockResult)
+			}
+
+			err := service.UpdateSuspension(ctx, suspension)
+			assert.ErrorIs(t, err, test.ExpectErr)
+		})
+	}
+}
+
+func TestLiftSuspension(t *testing.T) {
+	var suspensionID int64 = 2
+	errCritical := errors.New("critical error")
+
 	tests := []struct {
 		Name       string
-		MockResult any
-		Suspension *entity.Suspension
+		Current    *entity.Suspension
+		ExpectLift bool
+		MockResult error
+		ExpectErr  error
 	}{
 		{
-			Name:       "Suspension updated successfully",
-			MockResult: nil,
-			Suspension: &entity.Suspension{},
+			Name:       "Suspension lifted",
+			Current:    &entity.Suspension{ID: suspensionID, Active: true},
+			ExpectLift: true,
 		},
 		{
-			Name:       "Some error ocurred while updating",
-			MockResult: errors.New("critical error"),
-			Suspension: &entity.Suspension{},
+			Name:      "Suspension is not active",
+			Current:   &entity.Suspension{ID: suspensionID, Active: false},
+			ExpectErr: ErrSuspensionNotActive,
+		},
+		{
+			Name:       "Suspension ended meanwhile",
+			Current:    &entity.Suspension{ID: suspensionID, Active: true},
+			ExpectLift: true,
+			MockResult: repository.ErrRecordNotFound,
+			ExpectErr:  ErrSuspensionNotActive,
+		},
+		{
+			Name:       "Some error ocurred while lifting",
+			Current:    &entity.Suspension{ID: suspensionID, Active: true},
+			ExpectLift: true,
+			MockResult: errCritical,
+			ExpectErr:  errCritical,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			var moderatorID int64 = 1
+			suspension := &entity.Suspension{ID: suspensionID, LiftedBy: &moderatorID, LiftReason: util.StringToPointer("Appeal accepted")}
+
+			repo.On("GetSuspension", ctx, suspensionID).Return(test.Current, nil)
+			if test.ExpectLift {
+				repo.On("LiftSuspension", ctx, suspension).Return(test.MockResult)
+			}
+
+			err := service.LiftSuspension(ctx, suspension)
+			assert.ErrorIs(t, err, test.ExpectErr)
+		})
+	}
+}
+
+func TestGetSuspensions(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Sort       string
+		ExpectMock bool
+		ExpectErr  error
+	}{
+		{
+			Name:       "Sorted by expiry",
+			Sort:       "-expires_at",
+			ExpectMock: true,
+		},
+		{
+			Name:      "Invalid sort",
+			Sort:      "reason",
+			ExpectErr: ErrInvalidSortValue,
 		},
 	}
 
@@ -104,9 +258,16 @@ func TestUpdateSuspension(t *testing.T) {
 			service := New(repo, nil)
 			ctx := context.Background()
 
-			repo.On("UpdateSuspension", ctx, test.Suspension).Return(test.MockResult)
+			active := true
+			search := entity.SuspensionSearch{Active: &active}
+			filter := util.NewFilter(1, 20, test.Sort)
+
+			if test.ExpectMock {
+				repo.On("GetSuspensions", ctx, search, filter).Return([]*entity.Suspension{}, &util.Metadata{}, nil)
+			}
 
-			assert.Equal(t, service.UpdateSuspension(ctx, test.Suspension), test.MockResult)
+			_, _, err := service.GetSuspensions(ctx, search, filter)
+			assert.ErrorIs(t, err, test.ExpectErr)
 		})
 	}
 }
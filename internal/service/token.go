@@ -1,41 +1,300 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"log"
 	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
 	"one-lab-final/pkg/util"
 	"time"
 )
 
-func (m *Manager) Login(ctx context.Context, credentials string, password string) (*entity.Token, error) {
+func (m *Manager) Login(ctx context.Context, credentials string, password string, client entity.Client) (*entity.Token, error) {
 	user, err := m.Repository.GetUserByCredentials(ctx, credentials)
-	if err != nil {
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
 		return nil, err
 	}
 
+	account := accountKey(user, credentials)
+
+	limitErr := m.checkLoginLimits(ctx, account, ipKey(client))
+	if limitErr != nil {
+		return nil, limitErr
+	}
+
+	if user == nil {
+		return nil, m.loginFailed(ctx, account, client, repository.ErrRecordNotFound)
+	}
+
 	err = util.CheckPassword(password, *user.Password.Hash)
+	if err != nil {
+		return nil, m.loginFailed(ctx, account, client, err)
+	}
+
+	err = m.Repository.ResetLoginAttempts(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	m.rehashPassword(ctx, user, password)
+
+	if user.TwoFactor {
+		return m.createPartialToken(ctx, user)
+	}
+
+	return m.createSession(ctx, user, client)
+}
+
+// rehashPassword replaces hash made with outdated algorithm or cost. Login
+// does not fail if the new hash can not be stored, it is retried next time
+func (m *Manager) rehashPassword(ctx context.Context, user *entity.User, password string) {
+	if !m.Hasher.NeedsRehash(*user.Password.Hash) {
+		return
+	}
+
+	hash, err := m.Hasher.Hash(password)
+	if err != nil {
+		log.Printf("password rehash err: %s", err.Error())
+		return
+	}
+
+	err = m.Repository.UpdateUser(ctx, &entity.User{
+		ID:       user.ID,
+		Password: entity.Password{Hash: &hash},
+	})
+	if err != nil {
+		log.Printf("password rehash err: %s", err.Error())
+		return
+	}
+
+	user.Password.Hash = &hash
+}
+
+func (m *Manager) loginFailed(ctx context.Context, account string, client entity.Client, cause error) error {
+	err := m.recordLoginFailure(ctx, account, ipKey(client))
+	if err != nil {
+		return err
+	}
+
+	return cause
+}
+
+func (m *Manager) createSession(ctx context.Context, user *entity.User, client entity.Client) (*entity.Token, error) {
+	// Logging in during grace period cancels deletion of the account
+	if user.DeletedAt != nil {
+		err := m.Repository.RestoreUser(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	access, refresh, err := m.generateTokenPair(user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.Repository.CreateToken(ctx, access)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh.SessionID = access.ID
+
+	err = m.Repository.CreateRefreshToken(ctx, refresh)
+	if err != nil {
+		return nil, err
+	}
+
+	access.Refresh = refresh
+
+	return access, nil
+}
+
+// RefreshToken exchanges refresh token for a new pair of tokens. Refresh token
+// can be used only once, presenting it again revokes the whole session
+func (m *Manager) RefreshToken(ctx context.Context, refreshToken string, client entity.Client) (*entity.Token, error) {
+	used, err := m.Repository.GetRefreshToken(ctx, refreshToken)
 	if err != nil {
 		return nil, err
 	}
 
-	token, err := util.GenerateToken()
+	if used.Used {
+		return nil, m.revokeTokenFamily(ctx, used)
+	}
+
+	if used.Expiry.Before(time.Now()) {
+		return nil, repository.ErrRecordNotFound
+	}
+
+	user, err := m.Repository.GetUserByID(ctx, used.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	tokenEntity := &entity.Token{
-		Plaintext: token.Plaintext,
-		Hash:      token.Hash,
+	access, refresh, err := m.generateTokenPair(user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	access.ID = used.SessionID
+	refresh.SessionID = used.SessionID
+
+	err = m.Repository.RotateRefreshToken(ctx, used, access, refresh)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			return nil, m.revokeTokenFamily(ctx, used)
+		default:
+			return nil, err
+		}
+	}
+
+	access.Refresh = refresh
+
+	return access, nil
+}
+
+func (m *Manager) revokeTokenFamily(ctx context.Context, token *entity.RefreshToken) error {
+	err := m.Repository.DeleteSession(ctx, token.SessionID, token.UserID)
+	if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+		return err
+	}
+
+	return ErrRefreshTokenReused
+}
+
+func (m *Manager) generateTokenPair(user *entity.User, client entity.Client) (*entity.Token, *entity.RefreshToken, error) {
+	accessToken, err := util.GenerateToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, err := util.GenerateToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	access := &entity.Token{
+		Plaintext: accessToken.Plaintext,
+		Hash:      accessToken.Hash,
+		UserID:    user.ID,
+		Expiry:    time.Now().Add(m.Config.AUTH.AccessTokenExpiration),
+		UserAgent: client.UserAgent,
+		IP:        client.IP,
+	}
+
+	if m.jwtEnabled() {
+		err = m.signAccessToken(access, user)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	refresh := &entity.RefreshToken{
+		Plaintext: refreshToken.Plaintext,
+		Hash:      refreshToken.Hash,
 		UserID:    user.ID,
-		Expiry:    time.Now().Add(m.Config.AUTH.TokenExpiration),
+		Expiry:    time.Now().Add(m.Config.AUTH.RefreshTokenExpiration),
 	}
 
-	err = m.Repository.CreateToken(ctx, tokenEntity)
+	return access, refresh, nil
+}
+
+// signAccessToken replaces opaque access token with JWT. Random part of the
+// opaque token becomes token ID, so the session can be put on the denylist
+func (m *Manager) signAccessToken(access *entity.Token, user *entity.User) error {
+	jti := access.Plaintext
+
+	signed, err := util.GenerateJWT(user.ID, user.Role.String(), jti, access.Expiry, m.Config.AUTH.SigningKeyID, []byte(m.Config.AUTH.SigningKey))
+	if err != nil {
+		return err
+	}
+
+	access.Plaintext = signed
+	access.Hash = util.HashToken(signed)
+	access.JTI = &jti
+
+	return nil
+}
+
+// getUserByJWT verifies access token without looking up the session, only
+// denylist and suspensions are checked. Last usage of such sessions
+// is tracked on refresh
+func (m *Manager) getUserByJWT(ctx context.Context, token string) (*entity.User, error) {
+	claims, err := util.ParseJWT(token, m.verificationKeys())
+	if err != nil {
+		return nil, repository.ErrRecordNotFound
+	}
+
+	status, err := m.Repository.GetTokenStatus(ctx, claims.ID, claims.UserID)
 	if err != nil {
 		return nil, err
 	}
 
-	return tokenEntity, nil
+	if status.Revoked {
+		return nil, repository.ErrRecordNotFound
+	}
+
+	role, ok := entity.StringToRole(claims.Role)
+	if !ok {
+		return nil, repository.ErrRecordNotFound
+	}
+
+	// Role could be changed after the token was issued
+	if status.Role != "" {
+		role = status.Role
+	}
+
+	return &entity.User{
+		ID:          claims.UserID,
+		Role:        role,
+		Permissions: status.Permissions,
+		Suspended:   status.Suspended,
+		Activated:   status.Activated,
+		TwoFactor:   status.TwoFactor,
+	}, nil
+}
+
+func (m *Manager) jwtEnabled() bool {
+	return m.Config != nil && m.Config.AUTH.TokenFormat == "jwt"
+}
+
+func (m *Manager) verificationKeys() map[string][]byte {
+	keys := make(map[string][]byte, len(m.Config.AUTH.VerificationKeys)+1)
+	for id, key := range m.Config.AUTH.VerificationKeys {
+		keys[id] = []byte(key)
+	}
+	keys[m.Config.AUTH.SigningKeyID] = []byte(m.Config.AUTH.SigningKey)
+
+	return keys
+}
+
+func (m *Manager) Logout(ctx context.Context, token string) error {
+	return m.Repository.DeleteToken(ctx, token)
+}
+
+func (m *Manager) GetSessions(ctx context.Context, userID int64, currentToken string) ([]*entity.Session, error) {
+	sessions, err := m.Repository.GetSessionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentHash := util.HashToken(currentToken)
+	for _, session := range sessions {
+		session.Current = bytes.Equal(session.Hash, currentHash)
+	}
+
+	return sessions, nil
+}
+
+func (m *Manager) DeleteSession(ctx context.Context, sessionID int64, userID int64) error {
+	return m.Repository.DeleteSession(ctx, sessionID, userID)
+}
+
+func (m *Manager) DeleteAllSessions(ctx context.Context, userID int64) error {
+	return m.Repository.DeleteUserTokens(ctx, userID, "")
 }
 
 func (m *Manager) DeleteExpiredTokens(ctx context.Context) error {
@@ -9,9 +9,11 @@ import (
 	"one-lab-final/internal/repository/mocks"
 	"one-lab-final/pkg/util"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestLogin(t *testing.T) {
@@ -72,11 +74,18 @@ func TestLogin(t *testing.T) {
 			ctx := context.Background()
 
 			repo.On("GetUserByCredentials", ctx, test.Credentials).Return(test.MockUser, test.MockUserErr)
+			repo.On("GetLoginAttempts", ctx, mock.AnythingOfType("[]string")).Return([]*entity.LoginAttempt{}, nil)
 			if test.MockUserErr == nil {
+				repo.On("ResetLoginAttempts", ctx, "user:0").Return(nil)
 				repo.On("CreateToken", ctx, mock.AnythingOfType("*entity.Token")).Return(test.MockTokenResult)
+			} else {
+				repo.On("RecordLoginFailure", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("int"), mock.AnythingOfType("time.Duration")).Return(&entity.LoginAttempt{Failures: 1}, nil)
+			}
+			if test.MockUserErr == nil && test.MockTokenResult == nil {
+				repo.On("CreateRefreshToken", ctx, mock.AnythingOfType("*entity.RefreshToken")).Return(nil)
 			}
 
-			_, err := service.Login(ctx, test.Credentials, test.Password)
+			_, err := service.Login(ctx, test.Credentials, test.Password, entity.Client{})
 			if test.ExpectErr {
 				assert.NotNil(t, err)
 			} else {
@@ -86,6 +95,151 @@ func TestLogin(t *testing.T) {
 	}
 }
 
+func TestLoginJWT(t *testing.T) {
+	password := "password"
+	hash, _ := util.HashPassword(password)
+
+	repo := mocks.NewRepository(t)
+	service := New(repo, &config.Config{
+		AUTH: config.AuthConfig{
+			TokenFormat:           "jwt",
+			AccessTokenExpiration: time.Minute,
+			SigningKey:            "BLEH",
+			SigningKeyID:          "current",
+		},
+	})
+	ctx := context.Background()
+
+	repo.On("GetUserByCredentials", ctx, "username").Return(&entity.User{
+		ID:   7,
+		Role: entity.MODERATOR,
+		Password: entity.Password{
+			Hash: &hash,
+		},
+	}, nil)
+	repo.On("GetLoginAttempts", ctx, []string{"user:7", "ip:"}).Return([]*entity.LoginAttempt{}, nil)
+	repo.On("ResetLoginAttempts", ctx, "user:7").Return(nil)
+	repo.On("CreateToken", ctx, mock.AnythingOfType("*entity.Token")).Return(nil)
+	repo.On("CreateRefreshToken", ctx, mock.AnythingOfType("*entity.RefreshToken")).Return(nil)
+
+	token, err := service.Login(ctx, "username", password, entity.Client{})
+	assert.Nil(t, err)
+
+	claims, err := util.ParseJWT(token.Plaintext, map[string][]byte{"current": []byte("BLEH")})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), claims.UserID)
+	assert.Equal(t, "MODERATOR", claims.Role)
+	assert.Equal(t, *token.JTI, claims.ID)
+	assert.Equal(t, util.HashToken(token.Plaintext), token.Hash)
+}
+
+func TestRefreshToken(t *testing.T) {
+	var userID int64 = 15
+	var sessionID int64 = 3
+	tests := []struct {
+		Name           string
+		MockToken      *entity.RefreshToken
+		MockTokenErr   error
+		MockRotateErr  error
+		ExpectRotate   bool
+		ExpectRevoke   bool
+		ExpectedErr    error
+		ExpectAnyError bool
+	}{
+		{
+			Name: "Token rotated successfully",
+			MockToken: &entity.RefreshToken{
+				SessionID: sessionID,
+				UserID:    userID,
+				Expiry:    time.Now().Add(time.Hour),
+			},
+			ExpectRotate: true,
+		},
+		{
+			Name:         "Unknown refresh token",
+			MockTokenErr: repository.ErrRecordNotFound,
+			ExpectedErr:  repository.ErrRecordNotFound,
+		},
+		{
+			Name: "Expired refresh token",
+			MockToken: &entity.RefreshToken{
+				SessionID: sessionID,
+				UserID:    userID,
+				Expiry:    time.Now().Add(-time.Hour),
+			},
+			ExpectedErr: repository.ErrRecordNotFound,
+		},
+		{
+			Name: "Reused refresh token revokes family",
+			MockToken: &entity.RefreshToken{
+				SessionID: sessionID,
+				UserID:    userID,
+				Used:      true,
+				Expiry:    time.Now().Add(time.Hour),
+			},
+			ExpectRevoke: true,
+			ExpectedErr:  ErrRefreshTokenReused,
+		},
+		{
+			Name: "Concurrent reuse revokes family",
+			MockToken: &entity.RefreshToken{
+				SessionID: sessionID,
+				UserID:    userID,
+				Expiry:    time.Now().Add(time.Hour),
+			},
+			MockRotateErr: repository.ErrRecordNotFound,
+			ExpectRotate:  true,
+			ExpectRevoke:  true,
+			ExpectedErr:   ErrRefreshTokenReused,
+		},
+		{
+			Name: "Some error ocurred while rotating",
+			MockToken: &entity.RefreshToken{
+				SessionID: sessionID,
+				UserID:    userID,
+				Expiry:    time.Now().Add(time.Hour),
+			},
+			MockRotateErr:  errors.New("critical error"),
+			ExpectRotate:   true,
+			ExpectAnyError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, &config.Config{
+				AUTH: config.AuthConfig{
+					AccessTokenExpiration:  time.Minute,
+					RefreshTokenExpiration: time.Hour,
+				},
+			})
+			ctx := context.Background()
+
+			repo.On("GetRefreshToken", ctx, "refresh").Return(test.MockToken, test.MockTokenErr)
+			if test.ExpectRotate {
+				repo.On("GetUserByID", ctx, userID).Return(&entity.User{ID: userID}, nil)
+				repo.On("RotateRefreshToken", ctx, test.MockToken, mock.AnythingOfType("*entity.Token"), mock.AnythingOfType("*entity.RefreshToken")).Return(test.MockRotateErr)
+			}
+			if test.ExpectRevoke {
+				repo.On("DeleteSession", ctx, sessionID, userID).Return(nil)
+			}
+
+			token, err := service.RefreshToken(ctx, "refresh", entity.Client{})
+			switch {
+			case test.ExpectAnyError:
+				assert.NotNil(t, err)
+			case test.ExpectedErr != nil:
+				assert.ErrorIs(t, err, test.ExpectedErr)
+			default:
+				assert.Nil(t, err)
+				assert.Equal(t, sessionID, token.ID)
+				assert.NotNil(t, token.Refresh)
+			}
+		})
+	}
+}
+
 func TestDeleteExpiredTokens(t *testing.T) {
 	tests := []struct {
 		Name       string
@@ -113,3 +267,183 @@ func TestDeleteExpiredTokens(t *testing.T) {
 		})
 	}
 }
+
+func TestLogout(t *testing.T) {
+	tests := []struct {
+		Name       string
+		MockResult error
+		Token      string
+	}{
+		{
+			Name:  "Logged out successfully",
+			Token: "token",
+		},
+		{
+			Name:       "Token does not exist",
+			MockResult: repository.ErrRecordNotFound,
+			Token:      "token",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("DeleteToken", ctx, test.Token).Return(test.MockResult)
+
+			assert.Equal(t, test.MockResult, service.Logout(ctx, test.Token))
+		})
+	}
+}
+
+func TestGetSessions(t *testing.T) {
+	var userID int64 = 15
+	current := util.HashToken("current")
+	tests := []struct {
+		Name            string
+		MockResult      []*entity.Session
+		MockError       error
+		ExpectedCurrent []bool
+		ExpectErr       bool
+	}{
+		{
+			Name: "Current session is marked",
+			MockResult: []*entity.Session{
+				{ID: 1, Hash: util.HashToken("other")},
+				{ID: 2, Hash: current},
+			},
+			ExpectedCurrent: []bool{false, true},
+		},
+		{
+			Name:      "Some error ocurred while retrieving",
+			MockError: errors.New("critical error"),
+			ExpectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("GetSessionsByUserID", ctx, userID).Return(test.MockResult, test.MockError)
+
+			sessions, err := service.GetSessions(ctx, userID, "current")
+			if test.ExpectErr {
+				assert.NotNil(t, err)
+				return
+			}
+
+			assert.Nil(t, err)
+			for i, session := range sessions {
+				assert.Equal(t, test.ExpectedCurrent[i], session.Current)
+			}
+		})
+	}
+}
+
+func TestDeleteSession(t *testing.T) {
+	var userID int64 = 15
+	var sessionID int64 = 3
+	tests := []struct {
+		Name       string
+		MockResult error
+	}{
+		{
+			Name: "Session revoked successfully",
+		},
+		{
+			Name:       "Session does not belong to user",
+			MockResult: repository.ErrRecordNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("DeleteSession", ctx, sessionID, userID).Return(test.MockResult)
+
+			assert.Equal(t, test.MockResult, service.DeleteSession(ctx, sessionID, userID))
+		})
+	}
+}
+
+func TestDeleteAllSessions(t *testing.T) {
+	var userID int64 = 15
+	tests := []struct {
+		Name       string
+		MockResult error
+	}{
+		{
+			Name: "Logged out everywhere",
+		},
+		{
+			Name:       "Some error ocurred while deleting",
+			MockResult: errors.New("critical error"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("DeleteUserTokens", ctx, userID, "").Return(test.MockResult)
+
+			assert.Equal(t, test.MockResult, service.DeleteAllSessions(ctx, userID))
+		})
+	}
+}
+
+func TestLoginRehashesOutdatedPassword(t *testing.T) {
+	password := "password"
+	bcryptHash, _ := (&util.BcryptHasher{Cost: bcrypt.MinCost}).Hash(password)
+	argon2idHash, _ := util.HashPassword(password)
+
+	tests := []struct {
+		Name         string
+		Hash         []byte
+		ExpectRehash bool
+	}{
+		{
+			Name:         "Bcrypt hash is replaced",
+			Hash:         bcryptHash,
+			ExpectRehash: true,
+		},
+		{
+			Name: "Current hash is kept",
+			Hash: argon2idHash,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, &config.Config{})
+			ctx := context.Background()
+
+			user := &entity.User{ID: 15, Password: entity.Password{Hash: &test.Hash}}
+
+			repo.On("GetUserByCredentials", ctx, "username").Return(user, nil)
+			repo.On("GetLoginAttempts", ctx, mock.AnythingOfType("[]string")).Return([]*entity.LoginAttempt{}, nil)
+			repo.On("ResetLoginAttempts", ctx, "user:15").Return(nil)
+			repo.On("CreateToken", ctx, mock.AnythingOfType("*entity.Token")).Return(nil)
+			repo.On("CreateRefreshToken", ctx, mock.AnythingOfType("*entity.RefreshToken")).Return(nil)
+			if test.ExpectRehash {
+				repo.On("UpdateUser", ctx, mock.MatchedBy(func(u *entity.User) bool {
+					return u.ID == 15 && util.CheckPassword(password, *u.Password.Hash) == nil && !service.Hasher.NeedsRehash(*u.Password.Hash)
+				})).Return(nil)
+			}
+
+			_, err := service.Login(ctx, "username", password, entity.Client{})
+			assert.Nil(t, err)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/pkg/util"
+	"strings"
+	"time"
+)
+
+const recoveryCodesCount = 10
+
+// LoginTwoFactor exchanges partial token returned by Login for a session.
+// Partial token is consumed even if the code is wrong, so every guess
+// requires the password again
+func (m *Manager) LoginTwoFactor(ctx context.Context, token string, code string, client entity.Client) (*entity.Token, error) {
+	partial, err := m.Repository.ConsumeScopedToken(ctx, token, entity.ScopeTwoFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.verifyTwoFactorCode(ctx, partial.ID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := m.Repository.GetUserByID(ctx, partial.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.createSession(ctx, user, client)
+}
+
+// EnrollTwoFactor generates new secret. 2FA stays disabled until
+// the first code is confirmed
+func (m *Manager) EnrollTwoFactor(ctx context.Context, userID int64) (*entity.TwoFactor, error) {
+	user, err := m.Repository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactor {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.Repository.SetTwoFactorSecret(ctx, userID, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := "Library"
+	if m.Config != nil && m.Config.AUTH.TwoFactorIssuer != "" {
+		issuer = m.Config.AUTH.TwoFactorIssuer
+	}
+
+	return &entity.TwoFactor{
+		Secret: secret,
+		URI:    util.TOTPURI(issuer, *user.Username, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor enables 2FA and returns recovery codes, they are shown only once
+func (m *Manager) ConfirmTwoFactor(ctx context.Context, userID int64, code string) ([]string, error) {
+	twoFactor, err := m.Repository.GetTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if twoFactor.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	if twoFactor.Secret == "" {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+
+	step, ok := util.MatchTOTP(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	err = m.useTOTPStep(ctx, userID, step)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := util.GenerateRecoveryCodes(recoveryCodesCount)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make([][]byte, len(codes))
+	for i, recoveryCode := range codes {
+		hashes[i] = util.HashToken(recoveryCode)
+	}
+
+	err = m.Repository.EnableTwoFactor(ctx, userID, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (m *Manager) DisableTwoFactor(ctx context.Context, userID int64, code string) error {
+	user, err := m.Repository.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.TwoFactor {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if m.twoFactorRequired(user.Permissions) {
+		return ErrTwoFactorRequired
+	}
+
+	err = m.verifyTwoFactorCode(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+
+	return m.Repository.DisableTwoFactor(ctx, userID)
+}
+
+// verifyTwoFactorCode accepts either TOTP code or one of recovery codes
+func (m *Manager) verifyTwoFactorCode(ctx context.Context, userID int64, code string) error {
+	twoFactor, err := m.Repository.GetTwoFactor(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !twoFactor.Enabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	step, ok := util.MatchTOTP(twoFactor.Secret, code, time.Now())
+	if ok {
+		return m.useTOTPStep(ctx, userID, step)
+	}
+
+	err = m.Repository.UseRecoveryCode(ctx, userID, strings.ToLower(strings.TrimSpace(code)))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			return ErrInvalidTwoFactorCode
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// useTOTPStep rejects TOTP code which was already used, RFC 6238 section 5.2
+func (m *Manager) useTOTPStep(ctx context.Context, userID int64, step int64) error {
+	err := m.Repository.UseTOTPStep(ctx, userID, step)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRecordNotFound):
+			return ErrInvalidTwoFactorCode
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Manager) createPartialToken(ctx context.Context, user *entity.User) (*entity.Token, error) {
+	token, err := util.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	expiration := 5 * time.Minute
+	if m.Config != nil && m.Config.AUTH.TwoFactorExpiration != 0 {
+		expiration = m.Config.AUTH.TwoFactorExpiration
+	}
+
+	partial := &entity.Token{
+		Plaintext: token.Plaintext,
+		Hash:      token.Hash,
+		UserID:    user.ID,
+		Expiry:    time.Now().Add(expiration),
+		Scope:     entity.ScopeTwoFactor,
+		Partial:   true,
+	}
+
+	err = m.Repository.CreateToken(ctx, partial)
+	if err != nil {
+		return nil, err
+	}
+
+	return partial, nil
+}
+
+// twoFactorRequired reports whether the role with given permissions is
+// privileged, users without any permission may skip two-factor
+func (m *Manager) twoFactorRequired(permissions []entity.Permission) bool {
+	return m.Config != nil && m.Config.AUTH.RequireTwoFactor && len(permissions) > 0
+}
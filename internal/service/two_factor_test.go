@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"one-lab-final/internal/config"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/repository/mocks"
+	"one-lab-final/pkg/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoginPartialToken(t *testing.T) {
+	password := "password"
+	hash, _ := util.HashPassword(password)
+
+	repo := mocks.NewRepository(t)
+	service := New(repo, nil)
+	ctx := context.Background()
+
+	repo.On("GetUserByCredentials", ctx, "username").Return(&entity.User{
+		ID:        15,
+		TwoFactor: true,
+		Password: entity.Password{
+			Hash: &hash,
+		},
+	}, nil)
+	repo.On("GetLoginAttempts", ctx, []string{"user:15", "ip:"}).Return([]*entity.LoginAttempt{}, nil)
+	repo.On("ResetLoginAttempts", ctx, "user:15").Return(nil)
+	repo.On("CreateToken", ctx, mock.MatchedBy(func(token *entity.Token) bool {
+		return token.Scope == entity.ScopeTwoFactor
+	})).Return(nil)
+
+	token, err := service.Login(ctx, "username", password, entity.Client{})
+	assert.Nil(t, err)
+	assert.True(t, token.Partial)
+	assert.Nil(t, token.Refresh)
+}
+
+func TestLoginTwoFactor(t *testing.T) {
+	var userID int64 = 15
+	secret, _ := util.GenerateTOTPSecret()
+	code, _ := util.GenerateTOTP(secret, time.Now())
+
+	tests := []struct {
+		Name            string
+		Code            string
+		MockTokenErr    error
+		MockRecoveryErr error
+		MockStepErr     error
+		ExpectStep      bool
+		ExpectRecovery  bool
+		ExpectSession   bool
+		ExpectedErr     error
+	}{
+		{
+			Name:          "Logged in with TOTP code",
+			Code:          code,
+			ExpectStep:    true,
+			ExpectSession: true,
+		},
+		{
+			Name:        "Replayed TOTP code",
+			Code:        code,
+			ExpectStep:  true,
+			MockStepErr: repository.ErrRecordNotFound,
+			ExpectedErr: ErrInvalidTwoFactorCode,
+		},
+		{
+			Name:           "Logged in with recovery code",
+			Code:           "abcde-fghij",
+			ExpectRecovery: true,
+			ExpectSession:  true,
+		},
+		{
+			Name:            "Invalid code",
+			Code:            "000000",
+			ExpectRecovery:  true,
+			MockRecoveryErr: repository.ErrRecordNotFound,
+			ExpectedErr:     ErrInvalidTwoFactorCode,
+		},
+		{
+			Name:         "Invalid partial token",
+			Code:         code,
+			MockTokenErr: repository.ErrRecordNotFound,
+			ExpectedErr:  repository.ErrRecordNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, &config.Config{
+				AUTH: config.AuthConfig{
+					SigningKey: "BLEH",
+				},
+			})
+			ctx := context.Background()
+
+			var partialUser *entity.User
+			if test.MockTokenErr == nil {
+				partialUser = &entity.User{ID: userID}
+				repo.On("GetTwoFactor", ctx, userID).Return(&entity.TwoFactor{Secret: secret, Enabled: true}, nil)
+			}
+			repo.On("ConsumeScopedToken", ctx, "token", entity.ScopeTwoFactor).Return(partialUser, test.MockTokenErr)
+			if test.ExpectStep {
+				repo.On("UseTOTPStep", ctx, userID, mock.AnythingOfType("int64")).Return(test.MockStepErr)
+			}
+			if test.ExpectRecovery {
+				repo.On("UseRecoveryCode", ctx, userID, test.Code).Return(test.MockRecoveryErr)
+			}
+			if test.ExpectSession {
+				repo.On("GetUserByID", ctx, userID).Return(&entity.User{ID: userID}, nil)
+				repo.On("CreateToken", ctx, mock.AnythingOfType("*entity.Token")).Return(nil)
+				repo.On("CreateRefreshToken", ctx, mock.AnythingOfType("*entity.RefreshToken")).Return(nil)
+			}
+
+			token, err := service.LoginTwoFactor(ctx, "token", test.Code, entity.Client{})
+			assert.ErrorIs(t, err, test.ExpectedErr)
+			if test.ExpectSession {
+				assert.NotNil(t, token.Refresh)
+			}
+		})
+	}
+}
+
+func TestEnrollTwoFactor(t *testing.T) {
+	var userID int64 = 15
+	tests := []struct {
+		Name        string
+		MockUser    *entity.User
+		ExpectedErr error
+	}{
+		{
+			Name: "Secret generated successfully",
+			MockUser: &entity.User{
+				ID:       userID,
+				Username: util.StringToPointer("username"),
+			},
+		},
+		{
+			Name: "Already enabled",
+			MockUser: &entity.User{
+				ID:        userID,
+				Username:  util.StringToPointer("username"),
+				TwoFactor: true,
+			},
+			ExpectedErr: ErrTwoFactorAlreadyEnabled,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("GetUserByID", ctx, userID).Return(test.MockUser, nil)
+			if test.ExpectedErr == nil {
+				repo.On("SetTwoFactorSecret", ctx, userID, mock.AnythingOfType("string")).Return(nil)
+			}
+
+			twoFactor, err := service.EnrollTwoFactor(ctx, userID)
+			assert.ErrorIs(t, err, test.ExpectedErr)
+			if test.ExpectedErr == nil {
+				assert.NotEmpty(t, twoFactor.Secret)
+				assert.Contains(t, twoFactor.URI, "otpauth://totp/Library:username")
+			}
+		})
+	}
+}
+
+func TestConfirmTwoFactor(t *testing.T) {
+	var userID int64 = 15
+	secret, _ := util.GenerateTOTPSecret()
+	code, _ := util.GenerateTOTP(secret, time.Now())
+
+	tests := []struct {
+		Name          string
+		MockTwoFactor *entity.TwoFactor
+		Code          string
+		ExpectedErr   error
+	}{
+		{
+			Name:          "Enabled successfully",
+			MockTwoFactor: &entity.TwoFactor{Secret: secret},
+			Code:          code,
+		},
+		{
+			Name:          "Invalid code",
+			MockTwoFactor: &entity.TwoFactor{Secret: secret},
+			Code:          "abcdef",
+			ExpectedErr:   ErrInvalidTwoFactorCode,
+		},
+		{
+			Name:          "Not enrolled",
+			MockTwoFactor: &entity.TwoFactor{},
+			Code:          code,
+			ExpectedErr:   ErrTwoFactorNotEnrolled,
+		},
+		{
+			Name:          "Already enabled",
+			MockTwoFactor: &entity.TwoFactor{Secret: secret, Enabled: true},
+			Code:          code,
+			ExpectedErr:   ErrTwoFactorAlreadyEnabled,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("GetTwoFactor", ctx, userID).Return(test.MockTwoFactor, nil)
+			if test.ExpectedErr == nil {
+				repo.On("UseTOTPStep", ctx, userID, mock.AnythingOfType("int64")).Return(nil)
+				repo.On("EnableTwoFactor", ctx, userID, mock.MatchedBy(func(hashes [][]byte) bool {
+					return len(hashes) == recoveryCodesCount
+				})).Return(nil)
+			}
+
+			codes, err := service.ConfirmTwoFactor(ctx, userID, test.Code)
+			assert.ErrorIs(t, err, test.ExpectedErr)
+			if test.ExpectedErr == nil {
+				assert.Len(t, codes, recoveryCodesCount)
+			}
+		})
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	var userID int64 = 15
+	secret, _ := util.GenerateTOTPSecret()
+	code, _ := util.GenerateTOTP(secret, time.Now())
+
+	tests := []struct {
+		Name        string
+		MockUser    *entity.User
+		Required    bool
+		ExpectedErr error
+	}{
+		{
+			Name:     "Disabled successfully",
+			MockUser: &entity.User{ID: userID, Role: entity.MODERATOR, TwoFactor: true},
+		},
+		{
+			Name:        "Required for role",
+			MockUser:    &entity.User{ID: userID, Role: entity.MODERATOR, Permissions: []entity.Permission{entity.PermissionBooksCreate}, TwoFactor: true},
+			Required:    true,
+			ExpectedErr: ErrTwoFactorRequired,
+		},
+		{
+			Name:        "Not enabled",
+			MockUser:    &entity.User{ID: userID},
+			ExpectedErr: ErrTwoFactorNotEnabled,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, &config.Config{
+				AUTH: config.AuthConfig{
+					RequireTwoFactor: test.Required,
+				},
+			})
+			ctx := context.Background()
+
+			repo.On("GetUserByID", ctx, userID).Return(test.MockUser, nil)
+			if test.ExpectedErr == nil {
+				repo.On("GetTwoFactor", ctx, userID).Return(&entity.TwoFactor{Secret: secret, Enabled: true}, nil)
+				repo.On("UseTOTPStep", ctx, userID, mock.AnythingOfType("int64")).Return(nil)
+				repo.On("DisableTwoFactor", ctx, userID).Return(nil)
+			}
+
+			err := service.DisableTwoFactor(ctx, userID, code)
+			assert.ErrorIs(t, err, test.ExpectedErr)
+		})
+	}
+}
@@ -2,23 +2,60 @@ package service
 
 import (
 	"context"
+	"errors"
 	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
 	"one-lab-final/pkg/util"
+	"strings"
+	"time"
 )
 
 func (m *Manager) CreateUser(ctx context.Context, u *entity.User) error {
-	hash, err := util.HashPassword(*u.Password.Plaintext)
+	err := m.validatePassword(*u.Password.Plaintext, u)
+	if err != nil {
+		return err
+	}
+
+	hash, err := m.Hasher.Hash(*u.Password.Plaintext)
 	if err != nil {
 		return err
 	}
 
 	u.Password.Hash = &hash
 
-	return m.Repository.CreateUser(ctx, u)
+	err = m.Repository.CreateUser(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	if !u.Activated {
+		return m.sendActivationToken(ctx, u)
+	}
+
+	return nil
 }
 
+// GetUserByUsername returns UsernameMovedError if the user was renamed
 func (m *Manager) GetUserByUsername(ctx context.Context, username string) (*entity.User, error) {
-	return m.Repository.GetUserByUsername(ctx, username)
+	user, err := m.Repository.GetUserByUsername(ctx, username)
+	if err == nil {
+		if user.AvatarKey != nil {
+			user.Avatar = m.avatarURLs(*user.AvatarKey)
+		}
+
+		return user, nil
+	}
+
+	if !errors.Is(err, repository.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	current, err := m.Repository.GetUsernameRedirect(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, &UsernameMovedError{Username: current}
 }
 
 func (m *Manager) GetUserByCredentials(ctx context.Context, credentials string) (*entity.User, error) {
@@ -26,12 +63,31 @@ func (m *Manager) GetUserByCredentials(ctx context.Context, credentials string)
 }
 
 func (m *Manager) GetUserByToken(ctx context.Context, token string) (*entity.User, error) {
+	if strings.HasPrefix(token, apiKeyPrefix) {
+		return m.Repository.GetUserByAPIKey(ctx, token)
+	}
+
+	// Opaque tokens issued before switching to JWT are still accepted
+	if m.jwtEnabled() && strings.Count(token, ".") == 2 {
+		return m.getUserByJWT(ctx, token)
+	}
+
 	return m.Repository.GetUserByToken(ctx, token)
 }
 
-func (m *Manager) UpdateUser(ctx context.Context, u *entity.User) error {
+func (m *Manager) UpdateUser(ctx context.Context, u *entity.User, currentToken string) error {
 	if u.Password.Plaintext != nil {
-		hash, err := util.HashPassword(*u.Password.Plaintext)
+		current, err := m.Repository.GetUserByID(ctx, u.ID)
+		if err != nil {
+			return err
+		}
+
+		err = m.validatePassword(*u.Password.Plaintext, current)
+		if err != nil {
+			return err
+		}
+
+		hash, err := m.Hasher.Hash(*u.Password.Plaintext)
 		if err != nil {
 			return err
 		}
@@ -39,13 +95,69 @@ func (m *Manager) UpdateUser(ctx context.Context, u *entity.User) error {
 		u.Password.Hash = &hash
 	}
 
-	return m.Repository.UpdateUser(ctx, u)
+	err := m.Repository.UpdateUser(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	// Changing password logs user out everywhere except the current session
+	if u.Password.Plaintext != nil {
+		return m.Repository.DeleteUserTokens(ctx, u.ID, currentToken)
+	}
+
+	return nil
 }
 
+// DeleteUser logs the user out everywhere and starts grace period, during
+// which logging in restores the account. The last admin can not be deleted
 func (m *Manager) DeleteUser(ctx context.Context, userID int64) error {
-	return m.Repository.DeleteUser(ctx, userID)
+	err := m.Repository.DeleteUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrEditConflict) {
+			return ErrLastAdmin
+		}
+		return err
+	}
+
+	return m.Repository.DeleteUserTokens(ctx, userID, "")
 }
 
-func (m *Manager) GrantRoleToUser(ctx context.Context, userID int64, role entity.Role) error {
-	return m.Repository.GrantRoleToUser(ctx, userID, role)
+// PurgeDeletedUsers anonymizes users whose grace period is over
+func (m *Manager) PurgeDeletedUsers(ctx context.Context) error {
+	gracePeriod := 720 * time.Hour
+	deleteReviews := false
+	if m.Config != nil && m.Config.AUTH.DeletionGracePeriod != 0 {
+		gracePeriod = m.Config.AUTH.DeletionGracePeriod
+	}
+	if m.Config != nil {
+		deleteReviews = m.Config.AUTH.DeletedUserReviews == "delete"
+	}
+
+	avatarKeys, err := m.Repository.AnonymizeDeletedUsers(ctx, time.Now().Add(-gracePeriod), deleteReviews)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range avatarKeys {
+		m.deleteAvatarBlobs(ctx, key)
+	}
+
+	return nil
+}
+
+func (m *Manager) GetUsers(ctx context.Context, search entity.UserSearch, filter util.Filter) ([]*entity.UserOverview, *util.Metadata, error) {
+	filterSafeList := []string{
+		"id",
+		"username",
+		"email",
+		"review_count",
+		"last_login_at",
+		"created_at",
+	}
+
+	if !filter.ValidateSort(filterSafeList) {
+		return nil, nil, ErrInvalidSortValue
+	}
+
+	return m.Repository.GetUsers(ctx, search, filter)
 }
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"time"
+)
+
+// statsMonths is the number of past months in reviewer statistics, the
+// current month is added to them
+const statsMonths = 12
+
+func (m *Manager) GetUserStats(ctx context.Context, username string) (*entity.UserStats, error) {
+	user, err := m.GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := m.Repository.GetUserStats(ctx, user.ID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		stats = &entity.UserStats{
+			RatingDistribution: make(map[int64]int64),
+			TopTags:            make([]entity.TagCount, 0),
+		}
+	}
+
+	stats.MonthlyReviews = monthlyReviews(stats.MonthlyReviews, time.Now())
+
+	return stats, nil
+}
+
+func (m *Manager) RefreshUserStats(ctx context.Context) error {
+	return m.Repository.RefreshUserStats(ctx)
+}
+
+// monthlyReviews spreads counts over the window ending with the month of now,
+// months without reviews are included with zero count
+func monthlyReviews(counts []entity.MonthCount, now time.Time) []entity.MonthCount {
+	byMonth := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		byMonth[count.Month] = count.Count
+	}
+
+	months := make([]entity.MonthCount, 0, statsMonths+1)
+	for i := statsMonths; i >= 0; i-- {
+		month := time.Date(now.Year(), now.Month()-time.Month(i), 1, 0, 0, 0, 0, now.Location()).Format("2006-01")
+		months = append(months, entity.MonthCount{Month: month, Count: byMonth[month]})
+	}
+
+	return months
+}
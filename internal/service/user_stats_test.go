@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
+	"one-lab-final/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserStats(t *testing.T) {
+	rating := 4.5
+	tests := []struct {
+		Name            string
+		MockStats       *entity.UserStats
+		MockError       error
+		ExpectedCount   int64
+		ExpectedMonthly int64
+		ExpectErr       bool
+	}{
+		{
+			Name: "Stats of reviewer",
+			MockStats: &entity.UserStats{
+				ReviewCount:        2,
+				AverageRating:      &rating,
+				RatingDistribution: map[int64]int64{4: 1, 5: 1},
+				TopTags:            []entity.TagCount{{Tag: "fantasy", Count: 2}},
+				MonthlyReviews:     []entity.MonthCount{{Month: time.Now().Format("2006-01"), Count: 2}},
+			},
+			ExpectedCount:   2,
+			ExpectedMonthly: 2,
+		},
+		{
+			Name:      "User without reviews",
+			MockError: repository.ErrRecordNotFound,
+		},
+		{
+			Name:      "Error while retrieving",
+			MockError: errors.New("critical error"),
+			ExpectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil)
+			ctx := context.Background()
+
+			repo.On("GetUserByUsername", ctx, "john_doe").Return(&entity.User{ID: 2}, nil)
+			repo.On("GetUserStats", ctx, int64(2)).Return(test.MockStats, test.MockError)
+
+			stats, err := service.GetUserStats(ctx, "john_doe")
+			if test.ExpectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.ExpectedCount, stats.ReviewCount)
+			assert.NotNil(t, stats.RatingDistribution)
+			assert.NotNil(t, stats.TopTags)
+			assert.Len(t, stats.MonthlyReviews, statsMonths+1)
+			assert.Equal(t, test.ExpectedMonthly, stats.MonthlyReviews[statsMonths].Count)
+		})
+	}
+}
+
+func TestMonthlyReviews(t *testing.T) {
+	now := time.Date(2024, time.March, 31, 12, 0, 0, 0, time.UTC)
+	counts := []entity.MonthCount{
+		{Month: "2023-02", Count: 7},
+		{Month: "2023-03", Count: 1},
+		{Month: "2024-02", Count: 3},
+	}
+
+	months := monthlyReviews(counts, now)
+
+	assert.Len(t, months, statsMonths+1)
+	assert.Equal(t, entity.MonthCount{Month: "2023-03", Count: 1}, months[0])
+	assert.Equal(t, entity.MonthCount{Month: "2023-04", Count: 0}, months[1])
+	assert.Equal(t, entity.MonthCount{Month: "2024-02", Count: 3}, months[statsMonths-1])
+	assert.Equal(t, entity.MonthCount{Month: "2024-03", Count: 0}, months[statsMonths])
+}
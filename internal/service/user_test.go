@@ -3,25 +3,46 @@ package service
 import (
 	"context"
 	"errors"
+	"one-lab-final/internal/config"
 	"one-lab-final/internal/entity"
+	"one-lab-final/internal/repository"
 	"one-lab-final/internal/repository/mocks"
+	mailerMocks "one-lab-final/pkg/mailer/mocks"
 	"one-lab-final/pkg/util"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func TestCreateUser(t *testing.T) {
 	tests := []struct {
-		Name       string
-		MockResult any
-		User       *entity.User
-		ExpectErr  bool
+		Name             string
+		MockResult       any
+		User             *entity.User
+		ExpectActivation bool
+		ExpectErr        bool
 	}{
 		{
 			Name:       "User created successfully",
 			MockResult: nil,
 			User: &entity.User{
+				Email: util.StringToPointer("example@gmail.com"),
+				Password: entity.Password{
+					Plaintext: util.StringToPointer("password"),
+				},
+			},
+			ExpectActivation: true,
+		},
+		{
+			Name:       "Activated user does not receive token",
+			MockResult: nil,
+			User: &entity.User{
+				Email:     util.StringToPointer("example@gmail.com"),
+				Activated: true,
 				Password: entity.Password{
 					Plaintext: util.StringToPointer("password"),
 				},
@@ -42,10 +63,17 @@ func TestCreateUser(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.Name, func(t *testing.T) {
 			repo := mocks.NewRepository(t)
-			service := New(repo, nil)
+			mailer := mailerMocks.NewMailer(t)
+			service := New(repo, nil, WithMailer(mailer))
 			ctx := context.Background()
 
 			repo.On("CreateUser", ctx, test.User).Return(test.MockResult)
+			if test.ExpectActivation {
+				repo.On("CreateToken", ctx, mock.MatchedBy(func(token *entity.Token) bool {
+					return token.Scope == entity.ScopeActivation
+				})).Return(nil)
+				mailer.On("Send", ctx, "example@gmail.com", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+			}
 
 			err := service.CreateUser(ctx, test.User)
 
@@ -58,13 +86,69 @@ func TestCreateUser(t *testing.T) {
 	}
 }
 
+func TestCreateUserBcryptLongPassword(t *testing.T) {
+	tests := []struct {
+		Name      string
+		Password  string
+		ExpectErr bool
+	}{
+		{
+			Name:     "Password of 72 bytes is accepted",
+			Password: strings.Repeat("a", 72),
+		},
+		{
+			Name:      "Password over 72 bytes is rejected",
+			Password:  strings.Repeat("a", 73),
+			ExpectErr: true,
+		},
+		{
+			Name:      "Multibyte password over 72 bytes is rejected",
+			Password:  strings.Repeat("ж", 40),
+			ExpectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, nil, WithPasswordHasher(&util.BcryptHasher{Cost: bcrypt.MinCost}))
+			ctx := context.Background()
+
+			user := &entity.User{
+				Activated: true,
+				Password: entity.Password{
+					Plaintext: util.StringToPointer(test.Password),
+				},
+			}
+
+			if !test.ExpectErr {
+				repo.On("CreateUser", ctx, user).Return(nil)
+			}
+
+			err := service.CreateUser(ctx, user)
+
+			if !test.ExpectErr {
+				assert.Nil(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			assert.ErrorAs(t, err, &validationErr)
+			assert.Contains(t, validationErr.Fields, "password")
+		})
+	}
+}
+
 func TestGetUserByUsername(t *testing.T) {
 	tests := []struct {
-		Name       string
-		MockResult any
-		MockError  error
-		Username   string
-		ExpectErr  bool
+		Name             string
+		MockResult       any
+		MockError        error
+		MockRedirect     string
+		MockRedirectErr  error
+		Username         string
+		ExpectedRedirect string
+		ExpectErr        bool
 	}{
 		{
 			Name:       "User exists",
@@ -77,6 +161,21 @@ func TestGetUserByUsername(t *testing.T) {
 			Username:  "username",
 			ExpectErr: true,
 		},
+		{
+			Name:             "User was renamed",
+			MockError:        repository.ErrRecordNotFound,
+			MockRedirect:     "newname",
+			Username:         "username",
+			ExpectedRedirect: "newname",
+			ExpectErr:        true,
+		},
+		{
+			Name:            "User does not exist",
+			MockError:       repository.ErrRecordNotFound,
+			MockRedirectErr: repository.ErrRecordNotFound,
+			Username:        "username",
+			ExpectErr:       true,
+		},
 	}
 
 	for _, test := range tests {
@@ -86,6 +185,9 @@ func TestGetUserByUsername(t *testing.T) {
 			ctx := context.Background()
 
 			repo.On("GetUserByUsername", ctx, test.Username).Return(test.MockResult, test.MockError)
+			if errors.Is(test.MockError, repository.ErrRecordNotFound) {
+				repo.On("GetUsernameRedirect", ctx, test.Username).Return(test.MockRedirect, test.MockRedirectErr)
+			}
 
 			_, err := service.GetUserByUsername(ctx, test.Username)
 
@@ -94,6 +196,14 @@ func TestGetUserByUsername(t *testing.T) {
 			} else {
 				assert.Nil(t, err)
 			}
+
+			var movedErr *UsernameMovedError
+			if test.ExpectedRedirect != "" {
+				assert.ErrorAs(t, err, &movedErr)
+				assert.Equal(t, test.ExpectedRedirect, movedErr.Username)
+			} else {
+				assert.False(t, errors.As(err, &movedErr))
+			}
 		})
 	}
 }
@@ -183,6 +293,100 @@ func TestGetUserByToken(t *testing.T) {
 	}
 }
 
+func TestGetUserByJWT(t *testing.T) {
+	cfg := &config.Config{
+		AUTH: config.AuthConfig{
+			TokenFormat:      "jwt",
+			SigningKey:       "current key",
+			SigningKeyID:     "current",
+			VerificationKeys: map[string]string{"previous": "previous key"},
+		},
+	}
+	expiry := time.Now().Add(time.Minute)
+	current, _ := util.GenerateJWT(7, "ADMIN", "jti", expiry, "current", []byte("current key"))
+	previous, _ := util.GenerateJWT(7, "ADMIN", "jti", expiry, "previous", []byte("previous key"))
+	forged, _ := util.GenerateJWT(7, "ADMIN", "jti", expiry, "current", []byte("wrong key"))
+
+	tests := []struct {
+		Name          string
+		Token         string
+		ExpectLookup  bool
+		MockRevoked   bool
+		MockSuspended bool
+		MockError     error
+		ExpectedErr   error
+		ExpectAnyErr  bool
+	}{
+		{
+			Name:         "Token signed with current key",
+			Token:        current,
+			ExpectLookup: true,
+		},
+		{
+			Name:         "Token signed with rotated key",
+			Token:        previous,
+			ExpectLookup: true,
+		},
+		{
+			Name:          "Suspended user",
+			Token:         current,
+			ExpectLookup:  true,
+			MockSuspended: true,
+		},
+		{
+			Name:        "Forged token",
+			Token:       forged,
+			ExpectedErr: repository.ErrRecordNotFound,
+		},
+		{
+			Name:         "Revoked token",
+			Token:        current,
+			ExpectLookup: true,
+			MockRevoked:  true,
+			ExpectedErr:  repository.ErrRecordNotFound,
+		},
+		{
+			Name:         "Some error ocurred while checking denylist",
+			Token:        current,
+			ExpectLookup: true,
+			MockError:    errors.New("critical error"),
+			ExpectAnyErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			service := New(repo, cfg)
+			ctx := context.Background()
+
+			if test.ExpectLookup {
+				var status *entity.TokenStatus
+				if test.MockError == nil {
+					status = &entity.TokenStatus{
+						Revoked:   test.MockRevoked,
+						Suspended: test.MockSuspended,
+					}
+				}
+				repo.On("GetTokenStatus", ctx, "jti", int64(7)).Return(status, test.MockError)
+			}
+
+			user, err := service.GetUserByToken(ctx, test.Token)
+			switch {
+			case test.ExpectAnyErr:
+				assert.NotNil(t, err)
+			case test.ExpectedErr != nil:
+				assert.ErrorIs(t, err, test.ExpectedErr)
+			default:
+				assert.Nil(t, err)
+				assert.Equal(t, int64(7), user.ID)
+				assert.Equal(t, entity.ADMIN, user.Role)
+				assert.Equal(t, test.MockSuspended, user.Suspended)
+			}
+		})
+	}
+}
+
 func TestUpdateUser(t *testing.T) {
 	tests := []struct {
 		Name       string
@@ -217,9 +421,13 @@ func TestUpdateUser(t *testing.T) {
 			service := New(repo, nil)
 			ctx := context.Background()
 
+			repo.On("GetUserByID", ctx, test.User.ID).Return(&entity.User{ID: test.User.ID, Username: util.StringToPointer("username")}, nil)
 			repo.On("UpdateUser", ctx, test.User).Return(test.MockResult)
+			if test.MockResult == nil {
+				repo.On("DeleteUserTokens", ctx, test.User.ID, "token").Return(nil)
+			}
 
-			err := service.UpdateUser(ctx, test.User)
+			err := service.UpdateUser(ctx, test.User, "token")
 
 			if test.ExpectErr {
 				assert.NotNil(t, err)
@@ -236,6 +444,7 @@ func TestDeleteUser(t *testing.T) {
 		MockResult any
 		UserID     int64
 		ExpectErr  bool
+		Expected   error
 	}{
 		{
 			Name:       "User deleted successfully",
@@ -248,6 +457,13 @@ func TestDeleteUser(t *testing.T) {
 			UserID:     10,
 			ExpectErr:  true,
 		},
+		{
+			Name:       "Last admin can not be deleted",
+			MockResult: repository.ErrEditConflict,
+			UserID:     10,
+			ExpectErr:  true,
+			Expected:   ErrLastAdmin,
+		},
 	}
 
 	for _, test := range tests {
@@ -257,10 +473,15 @@ func TestDeleteUser(t *testing.T) {
 			ctx := context.Background()
 
 			repo.On("DeleteUser", ctx, test.UserID).Return(test.MockResult)
+			if test.MockResult == nil {
+				repo.On("DeleteUserTokens", ctx, test.UserID, "").Return(nil)
+			}
 
 			err := service.DeleteUser(ctx, test.UserID)
 
-			if test.ExpectErr {
+			if test.Expected != nil {
+				assert.ErrorIs(t, err, test.Expected)
+			} else if test.ExpectErr {
 				assert.NotNil(t, err)
 			} else {
 				assert.Nil(t, err)
@@ -269,27 +490,25 @@ func TestDeleteUser(t *testing.T) {
 	}
 }
 
-func TestGrantRoleToUser(t *testing.T) {
-	var userID int64 = 15
+func TestGetUsers(t *testing.T) {
 	tests := []struct {
 		Name       string
+		Sort       string
 		MockResult any
-		UserID     int64
-		UserRole   entity.Role
-		ExpectErr  bool
+		MockError  error
+		ExpectMock bool
+		ExpectErr  error
 	}{
 		{
-			Name:       "Role updated successfully",
-			MockResult: nil,
-			UserID:     userID,
-			UserRole:   entity.ADMIN,
+			Name:       "Users retrieved",
+			Sort:       "last_login_at",
+			MockResult: []*entity.UserOverview{{ID: 1}},
+			ExpectMock: true,
 		},
 		{
-			Name:       "Some error ocurred while updating",
-			MockResult: errors.New("critical error"),
-			UserID:     userID,
-			UserRole:   entity.ADMIN,
-			ExpectErr:  true,
+			Name:      "Non-valid sort",
+			Sort:      "password_hash",
+			ExpectErr: ErrInvalidSortValue,
 		},
 	}
 
@@ -299,15 +518,15 @@ func TestGrantRoleToUser(t *testing.T) {
 			service := New(repo, nil)
 			ctx := context.Background()
 
-			repo.On("GrantRoleToUser", ctx, test.UserID, test.UserRole).Return(test.MockResult)
+			search := entity.UserSearch{Search: util.StringToPointer("joh")}
+			filter := util.NewFilter(1, 50, test.Sort)
 
-			err := service.GrantRoleToUser(ctx, test.UserID, test.UserRole)
-
-			if test.ExpectErr {
-				assert.NotNil(t, err)
-			} else {
-				assert.Nil(t, err)
+			if test.ExpectMock {
+				repo.On("GetUsers", ctx, search, filter).Return(test.MockResult, &util.Metadata{}, test.MockError)
 			}
+
+			_, _, err := service.GetUsers(ctx, search, filter)
+			assert.ErrorIs(t, err, test.ExpectErr)
 		})
 	}
 }
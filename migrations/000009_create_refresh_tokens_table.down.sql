@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_session_id;
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    hash bytea PRIMARY KEY,
+    session_id bigint NOT NULL REFERENCES tokens (id) ON DELETE CASCADE,
+    used boolean NOT NULL DEFAULT false,
+    expiry timestamp(0) with time zone NOT NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_session_id ON refresh_tokens (session_id);
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS tokens_deny_revoked ON tokens;
+DROP FUNCTION IF EXISTS deny_revoked_token;
+
+DROP INDEX IF EXISTS idx_suspensions_user_id;
+DROP TABLE IF EXISTS token_denylist;
+
+ALTER TABLE tokens DROP COLUMN IF EXISTS jti;
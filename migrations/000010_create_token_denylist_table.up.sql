@@ -0,0 +1,23 @@
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS jti text UNIQUE;
+
+CREATE TABLE IF NOT EXISTS token_denylist (
+    jti text PRIMARY KEY,
+    expiry timestamp(0) with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_suspensions_user_id ON suspensions (user_id);
+
+-- JWT access tokens stay valid until they expire, so revoking a session
+-- (deleting the row) or rotating its access token puts the old ID on the denylist
+CREATE OR REPLACE FUNCTION deny_revoked_token() RETURNS trigger AS $$
+BEGIN
+    IF OLD.jti IS NOT NULL AND OLD.expiry > NOW() AND (TG_OP = 'DELETE' OR OLD.jti IS DISTINCT FROM NEW.jti) THEN
+        INSERT INTO token_denylist (jti, expiry) VALUES (OLD.jti, OLD.expiry) ON CONFLICT DO NOTHING;
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tokens_deny_revoked AFTER DELETE OR UPDATE OF jti ON tokens
+    FOR EACH ROW EXECUTE FUNCTION deny_revoked_token();
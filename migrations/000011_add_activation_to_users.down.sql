@@ -0,0 +1,4 @@
+ALTER TABLE users DROP COLUMN IF EXISTS activated;
+
+DELETE FROM tokens WHERE scope <> 'authentication';
+ALTER TABLE tokens DROP COLUMN IF EXISTS scope;
@@ -0,0 +1,5 @@
+ALTER TABLE tokens ADD COLUMN IF NOT EXISTS scope text NOT NULL DEFAULT 'authentication';
+
+-- Accounts created before email verification are considered activated
+ALTER TABLE users ADD COLUMN IF NOT EXISTS activated boolean NOT NULL DEFAULT true;
+ALTER TABLE users ALTER COLUMN activated SET DEFAULT false;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_recovery_codes_user_id;
+DROP TABLE IF EXISTS recovery_codes;
+
+DELETE FROM tokens WHERE scope = '2fa';
+ALTER TABLE users DROP COLUMN IF EXISTS totp_enabled;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_secret;
@@ -0,0 +1,8 @@
+-- Failed logins are counted per account ("user:<id>" or "credentials:<value>"
+-- for unknown accounts) and per client IP ("ip:<address>")
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key text PRIMARY KEY,
+    failures integer NOT NULL DEFAULT 0,
+    last_failure_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    locked_until timestamp(0) with time zone
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS oidc_states;
+
+DROP INDEX IF EXISTS idx_identities_user_id;
+DROP TABLE IF EXISTS identities;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS identities (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    provider text NOT NULL,
+    subject text NOT NULL,
+    email citext,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_identities_user_id ON identities (user_id);
+
+-- Login requests started with external provider, state is stored hashed
+CREATE TABLE IF NOT EXISTS oidc_states (
+    hash bytea PRIMARY KEY,
+    provider text NOT NULL,
+    nonce text NOT NULL,
+    code_verifier text NOT NULL,
+    expiry timestamp(0) with time zone NOT NULL
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS email_changes (
+    user_id bigint PRIMARY KEY REFERENCES users ON DELETE CASCADE,
+    email citext NOT NULL,
+    confirm_hash bytea UNIQUE NOT NULL,
+    cancel_hash bytea UNIQUE NOT NULL,
+    expiry timestamp(0) with time zone NOT NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
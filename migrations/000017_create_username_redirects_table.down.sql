@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_username_redirects_user_id;
+DROP TABLE IF EXISTS username_redirects;
+
+ALTER TABLE users DROP COLUMN IF EXISTS username_changed_at;
@@ -0,0 +1,9 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS username_changed_at timestamp(0) with time zone;
+
+CREATE TABLE IF NOT EXISTS username_redirects (
+    username text PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_username_redirects_user_id ON username_redirects (user_id);
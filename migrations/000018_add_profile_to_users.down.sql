@@ -0,0 +1,4 @@
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_key;
+ALTER TABLE users DROP COLUMN IF EXISTS website;
+ALTER TABLE users DROP COLUMN IF EXISTS location;
+ALTER TABLE users DROP COLUMN IF EXISTS bio;
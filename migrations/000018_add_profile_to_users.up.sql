@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bio text;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS location text;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS website text;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_key text;
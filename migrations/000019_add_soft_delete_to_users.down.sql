@@ -0,0 +1,19 @@
+ALTER TABLE suspensions DROP CONSTRAINT IF EXISTS suspensions_moderator_id_fkey;
+ALTER TABLE suspensions ADD CONSTRAINT suspensions_moderator_id_fkey FOREIGN KEY (moderator_id) REFERENCES users ON DELETE CASCADE;
+
+ALTER TABLE suspensions DROP CONSTRAINT IF EXISTS suspensions_user_id_fkey;
+ALTER TABLE suspensions ADD CONSTRAINT suspensions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users ON DELETE CASCADE;
+
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_user_id_fkey;
+ALTER TABLE reviews ADD CONSTRAINT reviews_user_id_fkey FOREIGN KEY (user_id) REFERENCES users ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_users_deleted_at;
+
+-- Anonymized users can not be restored to the previous schema
+DELETE FROM users WHERE anonymized_at IS NOT NULL;
+
+ALTER TABLE users ALTER COLUMN email SET NOT NULL;
+ALTER TABLE users ALTER COLUMN username SET NOT NULL;
+
+ALTER TABLE users DROP COLUMN IF EXISTS anonymized_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
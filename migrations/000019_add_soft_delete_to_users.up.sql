@@ -0,0 +1,20 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at timestamp(0) with time zone;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS anonymized_at timestamp(0) with time zone;
+
+-- Anonymized users keep their row as author of preserved reviews, but give up
+-- username and email so both can be registered again
+ALTER TABLE users ALTER COLUMN username DROP NOT NULL;
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at) WHERE anonymized_at IS NULL;
+
+-- Users are no longer deleted, removing a row must not silently erase reviews
+-- and moderation history
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_user_id_fkey;
+ALTER TABLE reviews ADD CONSTRAINT reviews_user_id_fkey FOREIGN KEY (user_id) REFERENCES users ON DELETE RESTRICT;
+
+ALTER TABLE suspensions DROP CONSTRAINT IF EXISTS suspensions_user_id_fkey;
+ALTER TABLE suspensions ADD CONSTRAINT suspensions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users ON DELETE RESTRICT;
+
+ALTER TABLE suspensions DROP CONSTRAINT IF EXISTS suspensions_moderator_id_fkey;
+ALTER TABLE suspensions ADD CONSTRAINT suspensions_moderator_id_fkey FOREIGN KEY (moderator_id) REFERENCES users ON DELETE RESTRICT;
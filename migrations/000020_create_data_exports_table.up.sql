@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS data_exports (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    -- One of "pending", "running", "ready" or "failed"
+    status text NOT NULL DEFAULT 'pending',
+    blob_key text,
+    error text,
+    expiry timestamp(0) with time zone,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports (user_id);
+
+-- Only one export of the user can be in progress
+CREATE UNIQUE INDEX IF NOT EXISTS idx_data_exports_in_progress ON data_exports (user_id) WHERE status IN ('pending', 'running');
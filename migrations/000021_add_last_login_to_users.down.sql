@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_reviews_user_id;
+DROP INDEX IF EXISTS idx_users_last_name_prefix;
+DROP INDEX IF EXISTS idx_users_first_name_prefix;
+DROP INDEX IF EXISTS idx_users_email_prefix;
+DROP INDEX IF EXISTS idx_users_username_prefix;
+
+ALTER TABLE users DROP COLUMN IF EXISTS last_login_at;
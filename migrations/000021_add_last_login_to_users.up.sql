@@ -0,0 +1,18 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_at timestamp(0) with time zone;
+
+UPDATE users u SET
+    last_login_at = t.last_login_at
+FROM (
+    SELECT user_id, MAX(created_at) AS last_login_at
+    FROM tokens
+    WHERE scope = 'authentication'
+    GROUP BY user_id
+) t
+WHERE u.id = t.user_id;
+
+-- Prefix search of admin user directory
+CREATE INDEX IF NOT EXISTS idx_users_username_prefix ON users (lower(username) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_users_email_prefix ON users (lower(email::text) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_users_first_name_prefix ON users (lower(first_name) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_users_last_name_prefix ON users (lower(last_name) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_reviews_user_id ON reviews (user_id);
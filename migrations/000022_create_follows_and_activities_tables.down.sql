@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_activities_user_id_created_at;
+DROP TABLE IF EXISTS activities;
+DROP INDEX IF EXISTS idx_follows_followee_id;
+DROP TABLE IF EXISTS follows;
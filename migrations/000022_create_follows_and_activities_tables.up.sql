@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    followee_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_follows_followee_id ON follows (followee_id);
+
+CREATE TABLE IF NOT EXISTS activities (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    -- One of "review_created", "review_updated" or "user_followed"
+    kind text NOT NULL,
+    review_id bigint REFERENCES reviews ON DELETE CASCADE,
+    target_user_id bigint REFERENCES users ON DELETE CASCADE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+-- Keyset pagination of feed walks activities of each followed user backwards
+CREATE INDEX IF NOT EXISTS idx_activities_user_id_created_at ON activities (user_id, created_at DESC, id DESC);
+
+INSERT INTO activities (user_id, kind, review_id, created_at)
+SELECT user_id, 'review_created', id, created_at
+FROM reviews
+ORDER BY created_at, id;
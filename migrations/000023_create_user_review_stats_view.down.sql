@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_user_review_stats_view_user_id;
+DROP MATERIALIZED VIEW IF EXISTS user_review_stats_view;
@@ -0,0 +1,47 @@
+-- Reviewer statistics of public profiles, refreshed periodically.
+-- Monthly counts cover the last 12 full months and the current one, the
+-- window itself is applied on read
+CREATE MATERIALIZED VIEW IF NOT EXISTS user_review_stats_view AS
+SELECT
+    r.user_id,
+    count(*) AS review_count,
+    AVG(r.rating)::double precision AS average_rating,
+    MIN(r.created_at) AS first_review_at,
+    MAX(r.created_at) AS last_review_at,
+    (
+        SELECT COALESCE(jsonb_object_agg(d.rating, d.count), '{}')
+        FROM (
+            SELECT rating, count(*) AS count
+            FROM reviews
+            WHERE user_id = r.user_id AND rating IS NOT NULL
+            GROUP BY rating
+        ) d
+    ) AS rating_distribution,
+    (
+        SELECT COALESCE(jsonb_agg(jsonb_build_object('tag', t.tag, 'count', t.count) ORDER BY t.count DESC, t.tag), '[]')
+        FROM (
+            SELECT lower(tag::text) AS tag, count(*) AS count
+            FROM reviews tr
+            JOIN books b ON b.id = tr.book_id
+            CROSS JOIN unnest(b.tags) AS tag
+            WHERE tr.user_id = r.user_id
+            GROUP BY lower(tag::text)
+            ORDER BY count DESC, tag
+            LIMIT 5
+        ) t
+    ) AS top_tags,
+    (
+        SELECT COALESCE(jsonb_object_agg(m.month, m.count), '{}')
+        FROM (
+            SELECT to_char(created_at, 'YYYY-MM') AS month, count(*) AS count
+            FROM reviews
+            WHERE user_id = r.user_id AND created_at >= date_trunc('month', NOW()) - interval '12 months'
+            GROUP BY to_char(created_at, 'YYYY-MM')
+        ) m
+    ) AS monthly_reviews,
+    NOW() AS refreshed_at
+FROM reviews r
+GROUP BY r.user_id;
+
+-- Unique index allows to refresh the view concurrently
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_review_stats_view_user_id ON user_review_stats_view (user_id);
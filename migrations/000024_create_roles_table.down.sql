@@ -0,0 +1,12 @@
+CREATE TYPE role AS ENUM('USER', 'MODERATOR', 'ADMIN');
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_fkey;
+
+-- Custom roles can not be represented by enum
+UPDATE users SET role = 'USER' WHERE role NOT IN ('USER', 'MODERATOR', 'ADMIN');
+
+ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN role TYPE role USING role::role;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'USER';
+
+DROP TABLE IF EXISTS roles;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS roles (
+    name text PRIMARY KEY,
+    description text,
+    permissions text[] NOT NULL DEFAULT '{}',
+    built_in boolean NOT NULL DEFAULT false,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+-- Built-in roles keep access of previously ordered roles
+INSERT INTO roles (name, description, permissions, built_in) VALUES
+    ('USER', 'Regular user', '{}', true),
+    ('MODERATOR', 'Manages books and suspends users', '{books.create,books.update,books.delete,reviews.moderate,users.suspend}', true),
+    ('ADMIN', 'Has every permission', '{books.create,books.update,books.delete,reviews.moderate,users.view,users.suspend,roles.grant,roles.manage}', true)
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users ALTER COLUMN role DROP DEFAULT;
+ALTER TABLE users ALTER COLUMN role TYPE text USING role::text;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'USER';
+ALTER TABLE users ADD CONSTRAINT users_role_fkey FOREIGN KEY (role) REFERENCES roles (name) ON DELETE RESTRICT;
+
+DROP TYPE IF EXISTS role;
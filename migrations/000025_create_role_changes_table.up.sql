@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS role_changes (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    -- NULL when the role was changed by the system, e.g. from command line
+    actor_id bigint REFERENCES users ON DELETE SET NULL,
+    -- Roles are kept as text so history outlives deleted custom roles
+    old_role text NOT NULL,
+    new_role text NOT NULL,
+    reason text,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_role_changes_user_id_created_at ON role_changes (user_id, created_at DESC);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_suspensions_created_at;
+DROP INDEX IF EXISTS idx_suspensions_moderator_id;
+
+-- Lifted suspensions end at the moment they were lifted
+UPDATE suspensions SET expires_in = lifted_at - created_at WHERE lifted_at IS NOT NULL AND lifted_at < created_at + expires_in;
+
+ALTER TABLE suspensions DROP COLUMN IF EXISTS lift_reason;
+ALTER TABLE suspensions DROP COLUMN IF EXISTS lifted_by;
+ALTER TABLE suspensions DROP COLUMN IF EXISTS lifted_at;
+ALTER TABLE suspensions DROP COLUMN IF EXISTS updated_by;
@@ -0,0 +1,7 @@
+ALTER TABLE suspensions ADD COLUMN IF NOT EXISTS updated_by bigint REFERENCES users ON DELETE RESTRICT;
+ALTER TABLE suspensions ADD COLUMN IF NOT EXISTS lifted_at timestamp(0) with time zone;
+ALTER TABLE suspensions ADD COLUMN IF NOT EXISTS lifted_by bigint REFERENCES users ON DELETE RESTRICT;
+ALTER TABLE suspensions ADD COLUMN IF NOT EXISTS lift_reason text;
+
+CREATE INDEX IF NOT EXISTS idx_suspensions_moderator_id ON suspensions (moderator_id);
+CREATE INDEX IF NOT EXISTS idx_suspensions_created_at ON suspensions (created_at);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_review_reports_state_review_id;
+DROP INDEX IF EXISTS idx_review_reports_open_reporter;
+DROP TABLE IF EXISTS review_reports;
+
+ALTER TABLE reviews DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE reviews DROP COLUMN IF EXISTS hidden_reason;
+ALTER TABLE reviews DROP COLUMN IF EXISTS hidden_by;
+ALTER TABLE reviews DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,23 @@
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS visibility text NOT NULL DEFAULT 'visible';
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS hidden_by bigint REFERENCES users ON DELETE SET NULL;
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS hidden_reason text;
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS hidden_at timestamp(0) with time zone;
+
+CREATE TABLE IF NOT EXISTS review_reports (
+    id bigserial PRIMARY KEY,
+    review_id bigint NOT NULL REFERENCES reviews ON DELETE CASCADE,
+    reporter_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    category text NOT NULL,
+    comment text,
+    -- open, dismissed or actioned
+    state text NOT NULL DEFAULT 'open',
+    -- Action taken by moderator, e.g. hide
+    resolution text,
+    resolved_by bigint REFERENCES users ON DELETE SET NULL,
+    resolved_at timestamp(0) with time zone,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+-- A reader can report the review again once previous report is resolved
+CREATE UNIQUE INDEX IF NOT EXISTS idx_review_reports_open_reporter ON review_reports (review_id, reporter_id) WHERE state = 'open';
+CREATE INDEX IF NOT EXISTS idx_review_reports_state_review_id ON review_reports (state, review_id);
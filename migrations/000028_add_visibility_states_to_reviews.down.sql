@@ -0,0 +1,62 @@
+DROP INDEX IF EXISTS idx_user_review_stats_view_user_id;
+DROP MATERIALIZED VIEW IF EXISTS user_review_stats_view;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS user_review_stats_view AS
+SELECT
+    r.user_id,
+    count(*) AS review_count,
+    AVG(r.rating)::double precision AS average_rating,
+    MIN(r.created_at) AS first_review_at,
+    MAX(r.created_at) AS last_review_at,
+    (
+        SELECT COALESCE(jsonb_object_agg(d.rating, d.count), '{}')
+        FROM (
+            SELECT rating, count(*) AS count
+            FROM reviews
+            WHERE user_id = r.user_id AND rating IS NOT NULL
+            GROUP BY rating
+        ) d
+    ) AS rating_distribution,
+    (
+        SELECT COALESCE(jsonb_agg(jsonb_build_object('tag', t.tag, 'count', t.count) ORDER BY t.count DESC, t.tag), '[]')
+        FROM (
+            SELECT lower(tag::text) AS tag, count(*) AS count
+            FROM reviews tr
+            JOIN books b ON b.id = tr.book_id
+            CROSS JOIN unnest(b.tags) AS tag
+            WHERE tr.user_id = r.user_id
+            GROUP BY lower(tag::text)
+            ORDER BY count DESC, tag
+            LIMIT 5
+        ) t
+    ) AS top_tags,
+    (
+        SELECT COALESCE(jsonb_object_agg(m.month, m.count), '{}')
+        FROM (
+            SELECT to_char(created_at, 'YYYY-MM') AS month, count(*) AS count
+            FROM reviews
+            WHERE user_id = r.user_id AND created_at >= date_trunc('month', NOW()) - interval '12 months'
+            GROUP BY to_char(created_at, 'YYYY-MM')
+        ) m
+    ) AS monthly_reviews,
+    NOW() AS refreshed_at
+FROM reviews r
+GROUP BY r.user_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_review_stats_view_user_id ON user_review_stats_view (user_id);
+
+DROP INDEX IF EXISTS idx_books_avg_rating_view;
+DROP MATERIALIZED VIEW IF EXISTS books_avg_rating_view;
+
+CREATE MATERIALIZED VIEW books_avg_rating_view AS SELECT AVG(rating) AS rating, book_id FROM reviews GROUP BY book_id;
+
+CREATE INDEX idx_books_avg_rating_view ON books_avg_rating_view (rating);
+
+-- Reviews deleted by author can't be kept without the partial index
+DELETE FROM reviews WHERE visibility = 'deleted';
+
+DROP INDEX IF EXISTS idx_reviews_user_id_book_id;
+ALTER TABLE reviews ADD CONSTRAINT reviews_user_id_book_id_key UNIQUE (user_id, book_id);
+
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_visibility_check;
+ALTER TABLE reviews DROP COLUMN IF EXISTS deleted_at;
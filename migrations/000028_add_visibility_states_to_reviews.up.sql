@@ -0,0 +1,62 @@
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS deleted_at timestamp(0) with time zone;
+ALTER TABLE reviews ADD CONSTRAINT reviews_visibility_check CHECK (visibility IN ('visible', 'hidden', 'deleted'));
+
+-- Reviews deleted by author don't prevent reviewing the book again
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_user_id_book_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_user_id_book_id ON reviews (user_id, book_id) WHERE visibility <> 'deleted';
+
+-- Ratings and reviewer statistics count only visible reviews
+DROP INDEX IF EXISTS idx_books_avg_rating_view;
+DROP MATERIALIZED VIEW IF EXISTS books_avg_rating_view;
+
+CREATE MATERIALIZED VIEW books_avg_rating_view AS SELECT AVG(rating) AS rating, book_id FROM reviews WHERE visibility = 'visible' GROUP BY book_id;
+
+CREATE INDEX idx_books_avg_rating_view ON books_avg_rating_view (rating);
+
+DROP INDEX IF EXISTS idx_user_review_stats_view_user_id;
+DROP MATERIALIZED VIEW IF EXISTS user_review_stats_view;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS user_review_stats_view AS
+SELECT
+    r.user_id,
+    count(*) AS review_count,
+    AVG(r.rating)::double precision AS average_rating,
+    MIN(r.created_at) AS first_review_at,
+    MAX(r.created_at) AS last_review_at,
+    (
+        SELECT COALESCE(jsonb_object_agg(d.rating, d.count), '{}')
+        FROM (
+            SELECT rating, count(*) AS count
+            FROM reviews
+            WHERE user_id = r.user_id AND rating IS NOT NULL AND visibility = 'visible'
+            GROUP BY rating
+        ) d
+    ) AS rating_distribution,
+    (
+        SELECT COALESCE(jsonb_agg(jsonb_build_object('tag', t.tag, 'count', t.count) ORDER BY t.count DESC, t.tag), '[]')
+        FROM (
+            SELECT lower(tag::text) AS tag, count(*) AS count
+            FROM reviews tr
+            JOIN books b ON b.id = tr.book_id
+            CROSS JOIN unnest(b.tags) AS tag
+            WHERE tr.user_id = r.user_id AND tr.visibility = 'visible'
+            GROUP BY lower(tag::text)
+            ORDER BY count DESC, tag
+            LIMIT 5
+        ) t
+    ) AS top_tags,
+    (
+        SELECT COALESCE(jsonb_object_agg(m.month, m.count), '{}')
+        FROM (
+            SELECT to_char(created_at, 'YYYY-MM') AS month, count(*) AS count
+            FROM reviews
+            WHERE user_id = r.user_id AND visibility = 'visible' AND created_at >= date_trunc('month', NOW()) - interval '12 months'
+            GROUP BY to_char(created_at, 'YYYY-MM')
+        ) m
+    ) AS monthly_reviews,
+    NOW() AS refreshed_at
+FROM reviews r
+WHERE r.visibility = 'visible'
+GROUP BY r.user_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_review_stats_view_user_id ON user_review_stats_view (user_id);
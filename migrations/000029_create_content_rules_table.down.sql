@@ -0,0 +1,6 @@
+UPDATE reviews SET visibility = 'hidden' WHERE visibility = 'pending';
+
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_visibility_check;
+ALTER TABLE reviews ADD CONSTRAINT reviews_visibility_check CHECK (visibility IN ('visible', 'hidden', 'deleted'));
+
+DROP TABLE IF EXISTS content_rules;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS content_rules (
+    id bigserial PRIMARY KEY,
+    -- term, links or repeat
+    kind text NOT NULL,
+    -- Banned word or phrase of term rules
+    pattern text,
+    -- Allowed number of links or length of repeated characters
+    threshold integer,
+    -- reject or quarantine
+    severity text NOT NULL,
+    enabled boolean NOT NULL DEFAULT true,
+    created_by bigint REFERENCES users ON DELETE SET NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+-- Banned terms depend on the community, so only spam rules are created
+INSERT INTO content_rules (kind, threshold, severity) VALUES
+    ('links', 2, 'quarantine'),
+    ('repeat', 10, 'reject');
+
+-- Quarantined reviews wait for moderator approval
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_visibility_check;
+ALTER TABLE reviews ADD CONSTRAINT reviews_visibility_check CHECK (visibility IN ('visible', 'hidden', 'deleted', 'pending'));
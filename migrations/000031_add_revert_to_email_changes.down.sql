@@ -0,0 +1,5 @@
+DELETE FROM email_changes WHERE confirmed_at IS NOT NULL;
+
+ALTER TABLE email_changes ALTER COLUMN confirm_hash SET NOT NULL;
+ALTER TABLE email_changes DROP COLUMN IF EXISTS confirmed_at;
+ALTER TABLE email_changes DROP COLUMN IF EXISTS old_email;
@@ -0,0 +1,5 @@
+-- Confirmed changes are kept until expiry, so the token sent to the old
+-- address can still revert them
+ALTER TABLE email_changes ADD COLUMN IF NOT EXISTS old_email citext;
+ALTER TABLE email_changes ADD COLUMN IF NOT EXISTS confirmed_at timestamp(0) with time zone;
+ALTER TABLE email_changes ALTER COLUMN confirm_hash DROP NOT NULL;
@@ -0,0 +1,7 @@
+UPDATE reviews SET visibility = 'deleted' WHERE visibility = 'removed';
+
+DROP INDEX IF EXISTS idx_reviews_user_id_book_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_user_id_book_id ON reviews (user_id, book_id) WHERE visibility <> 'deleted';
+
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_visibility_check;
+ALTER TABLE reviews ADD CONSTRAINT reviews_visibility_check CHECK (visibility IN ('visible', 'hidden', 'deleted', 'pending'));
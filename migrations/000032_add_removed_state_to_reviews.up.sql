@@ -0,0 +1,6 @@
+-- Reviews deleted by moderator are kept with their reports
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_visibility_check;
+ALTER TABLE reviews ADD CONSTRAINT reviews_visibility_check CHECK (visibility IN ('visible', 'hidden', 'deleted', 'pending', 'removed'));
+
+DROP INDEX IF EXISTS idx_reviews_user_id_book_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_user_id_book_id ON reviews (user_id, book_id) WHERE visibility NOT IN ('deleted', 'removed');
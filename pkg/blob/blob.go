@@ -0,0 +1,21 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob does not exist")
+
+// Store keeps files under slash separated keys, e.g. "avatars/15/large.jpg"
+//
+//go:generate mockery --name Store
+type Store interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete does not fail if the blob does not exist
+	Delete(ctx context.Context, key string) error
+	// URL returns public address of the blob
+	URL(key string) string
+}
@@ -0,0 +1,32 @@
+package blob
+
+import "net/http"
+
+type Option func(*S3Store)
+
+func WithRegion(region string) Option {
+	return func(s *S3Store) {
+		s.region = region
+	}
+}
+
+func WithCredentials(accessKey string, secretKey string) Option {
+	return func(s *S3Store) {
+		s.accessKey = accessKey
+		s.secretKey = secretKey
+	}
+}
+
+// WithPublicURL sets address the bucket is served from, e.g. CDN.
+// By default objects are addressed through the endpoint
+func WithPublicURL(url string) Option {
+	return func(s *S3Store) {
+		s.publicURL = url
+	}
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *S3Store) {
+		s.client = client
+	}
+}
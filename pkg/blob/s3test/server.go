@@ -0,0 +1,90 @@
+// Package s3test provides in-memory stand-in for S3 compatible storage,
+// similar to running MinIO locally
+package s3test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+type object struct {
+	data        []byte
+	contentType string
+}
+
+type Server struct {
+	*httptest.Server
+
+	bucket    string
+	accessKey string
+
+	mu      sync.Mutex
+	objects map[string]object
+}
+
+// NewServer starts server with a single bucket. Requests must be signed
+// with the access key
+func NewServer(bucket string, accessKey string) *Server {
+	s := &Server{
+		bucket:    bucket,
+		accessKey: accessKey,
+		objects:   make(map[string]object),
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// Object returns stored object and its content type
+func (s *Server) Object(key string) ([]byte, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.objects[key]
+	return obj.data, obj.contentType, ok
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential="+s.accessKey+"/") || r.Header.Get("X-Amz-Date") == "" {
+		http.Error(w, "AccessDenied", http.StatusForbidden)
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != s.bucket {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.objects[key] = object{data: data, contentType: r.Header.Get("Content-Type")}
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet:
+		obj, ok := s.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Write(obj.data)
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,138 @@
+// Package imaging decodes uploaded images and produces square thumbnails
+// using only the standard library
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif" // register GIF decoder
+	"image/jpeg"
+	_ "image/png" // register PNG decoder
+	"net/http"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("image format is not supported")
+	ErrTooLarge          = errors.New("image dimensions are too large")
+)
+
+// Supported content types, detected from the data rather than trusted from client
+var contentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// Decode checks content type and dimensions before decoding the whole
+// image, so huge images are rejected without allocating memory for them
+func Decode(data []byte, maxDimension int) (image.Image, error) {
+	if !contentTypes[http.DetectContentType(data)] {
+		return nil, ErrUnsupportedFormat
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	if config.Width > maxDimension || config.Height > maxDimension {
+		return nil, ErrTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	return img, nil
+}
+
+// Thumbnail crops the center square of the image and scales it to size x size.
+// Each pixel is averaged over the area it covers in the source image
+func Thumbnail(img image.Image, size int) *image.RGBA {
+	bounds := img.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	scale := float64(side) / float64(size)
+
+	for y := 0; y < size; y++ {
+		sy0, sy1 := span(y, scale, side)
+
+		for x := 0; x < size; x++ {
+			sx0, sx1 := span(x, scale, side)
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := img.At(x0+sx, y0+sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+
+			dst.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+
+	return dst
+}
+
+// span returns source pixels covered by destination pixel i, at least one
+func span(i int, scale float64, limit int) (int, int) {
+	start := int(float64(i) * scale)
+	end := int(float64(i+1) * scale)
+
+	if end <= start {
+		end = start + 1
+	}
+	if end > limit {
+		end = limit
+	}
+	if start >= end {
+		start = end - 1
+	}
+
+	return start, end
+}
+
+// EncodeJPEG flattens transparency onto white background, since JPEG has no alpha
+func EncodeJPEG(img *image.RGBA, quality int) ([]byte, error) {
+	bounds := img.Bounds()
+	flat := image.NewRGBA(bounds)
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			// Premultiplied alpha, so adding white scaled by transparency is enough
+			flat.SetRGBA(x, y, color.RGBA{
+				R: c.R + 255 - c.A,
+				G: c.G + 255 - c.A,
+				B: c.B + 255 - c.A,
+				A: 255,
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+
+	err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: quality})
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
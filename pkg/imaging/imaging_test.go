@@ -0,0 +1,73 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPNG(width int, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			// Left half is red, right half is blue
+			if x < width/2 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	return buf.Bytes()
+}
+
+func TestDecode(t *testing.T) {
+	_, err := Decode(testPNG(40, 20), 100)
+	assert.Nil(t, err)
+
+	_, err = Decode(testPNG(200, 20), 100)
+	assert.ErrorIs(t, err, ErrTooLarge)
+
+	_, err = Decode([]byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"), 100)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	// PNG signature followed by garbage
+	_, err = Decode(append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 32)...), 100)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestThumbnail(t *testing.T) {
+	img, _ := Decode(testPNG(60, 20), 100)
+
+	for _, size := range []int{8, 64} {
+		thumb := Thumbnail(img, size)
+		assert.Equal(t, image.Rect(0, 0, size, size), thumb.Bounds())
+
+		// Center square of 20x20 is split between red and blue
+		assert.Equal(t, uint8(255), thumb.RGBAAt(0, 0).R)
+		assert.Equal(t, uint8(255), thumb.RGBAAt(size-1, size-1).B)
+	}
+}
+
+func TestEncodeJPEG(t *testing.T) {
+	transparent := image.NewRGBA(image.Rect(0, 0, 4, 4))
+
+	data, err := EncodeJPEG(transparent, 85)
+	assert.Nil(t, err)
+
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	assert.Nil(t, err)
+
+	r, g, b, _ := img.At(1, 1).RGBA()
+	assert.Greater(t, r>>8, uint32(250))
+	assert.Greater(t, g>>8, uint32(250))
+	assert.Greater(t, b>>8, uint32(250))
+}
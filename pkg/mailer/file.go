@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileMailer writes every message into separate .eml file inside of directory
+type FileMailer struct {
+	directory string
+	from      string
+}
+
+func NewFileMailer(directory string, from string) (*FileMailer, error) {
+	err := os.MkdirAll(directory, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileMailer{
+		directory: directory,
+		from:      from,
+	}, nil
+}
+
+func (m *FileMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), strings.NewReplacer("@", "_at_", "/", "_").Replace(to))
+
+	return os.WriteFile(filepath.Join(m.directory, name), buildMessage(m.from, to, subject, body), 0o644)
+}
@@ -0,0 +1,22 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// LogMailer prints messages instead of sending them, meant for local development
+type LogMailer struct {
+	logger *log.Logger
+}
+
+func NewLogMailer(logger *log.Logger) *LogMailer {
+	return &LogMailer{
+		logger: logger,
+	}
+}
+
+func (m *LogMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	m.logger.Printf("mail to %s: %s\n%s", to, subject, body)
+	return nil
+}
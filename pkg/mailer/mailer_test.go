@@ -0,0 +1,36 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildMessage(t *testing.T) {
+	message := string(buildMessage("from@example.com", "to@example.com", "Subject", "first line\nsecond line"))
+
+	assert.Contains(t, message, "From: from@example.com\r\n")
+	assert.Contains(t, message, "To: to@example.com\r\n")
+	assert.Contains(t, message, "Subject: Subject\r\n")
+	assert.True(t, strings.HasSuffix(message, "\r\n\r\nfirst line\r\nsecond line"), "body must follow headers")
+}
+
+func TestFileMailer(t *testing.T) {
+	directory := filepath.Join(t.TempDir(), "mail")
+
+	m, err := NewFileMailer(directory, "from@example.com")
+	assert.Nil(t, err, "must create directory")
+
+	err = m.Send(context.Background(), "to@example.com", "Subject", "body")
+	assert.Nil(t, err, "must be nil")
+
+	files, _ := os.ReadDir(directory)
+	assert.Len(t, files, 1, "must write one file")
+
+	content, _ := os.ReadFile(filepath.Join(directory, files[0].Name()))
+	assert.Contains(t, string(content), "To: to@example.com")
+}
@@ -0,0 +1,28 @@
+package mailer
+
+type Option func(*SMTPMailer)
+
+func WithHost(host string) Option {
+	return func(m *SMTPMailer) {
+		m.host = host
+	}
+}
+
+func WithPort(port string) Option {
+	return func(m *SMTPMailer) {
+		m.port = port
+	}
+}
+
+func WithCredentials(username string, password string) Option {
+	return func(m *SMTPMailer) {
+		m.username = username
+		m.password = password
+	}
+}
+
+func WithSender(from string) Option {
+	return func(m *SMTPMailer) {
+		m.from = from
+	}
+}
@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/mail"
+	"net/smtp"
+)
+
+type SMTPMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailer(opts ...Option) *SMTPMailer {
+	m := &SMTPMailer{
+		port: "587",
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+
+	sender, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return err
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- smtp.SendMail(net.JoinHostPort(m.host, m.port), auth, sender.Address, []string{to}, buildMessage(m.from, to, subject, body))
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,107 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"one-lab-final/pkg/oidc/oidctest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	server := oidctest.NewServer("client")
+	defer server.Close()
+
+	provider := NewProvider(server.Issuer(), "client", WithRedirectURL("http://localhost/callback"))
+	ctx := context.Background()
+
+	verifier, _ := GenerateVerifier()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", CodeChallenge(verifier))
+	assert.Nil(t, err)
+
+	parsed, _ := url.Parse(authURL)
+	assert.Equal(t, "S256", parsed.Query().Get("code_challenge_method"))
+	assert.Equal(t, "openid email profile", parsed.Query().Get("scope"))
+
+	code, state, err := server.Authorize(authURL)
+	assert.Nil(t, err)
+	assert.Equal(t, "state", state)
+
+	tokens, err := provider.Exchange(ctx, code, verifier)
+	assert.Nil(t, err)
+
+	claims, err := provider.VerifyIDToken(ctx, tokens.IDToken, "nonce")
+	assert.Nil(t, err)
+	assert.Equal(t, "subject", claims.Subject)
+	assert.Equal(t, "user@example.com", claims.Email)
+	assert.True(t, claims.EmailVerified)
+}
+
+func TestExchangeWrongVerifier(t *testing.T) {
+	server := oidctest.NewServer("client")
+	defer server.Close()
+
+	provider := NewProvider(server.Issuer(), "client", WithRedirectURL("http://localhost/callback"))
+	ctx := context.Background()
+
+	verifier, _ := GenerateVerifier()
+	authURL, _ := provider.AuthCodeURL(ctx, "state", "nonce", CodeChallenge(verifier))
+	code, _, _ := server.Authorize(authURL)
+
+	_, err := provider.Exchange(ctx, code, "other verifier")
+	assert.ErrorIs(t, err, ErrExchangeFailed)
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	server := oidctest.NewServer("client")
+	defer server.Close()
+
+	other := oidctest.NewServer("client")
+	defer other.Close()
+
+	user := oidctest.User{Subject: "subject"}
+	valid, _ := server.SignIDToken(user, "nonce", time.Now().Add(time.Hour))
+	expired, _ := server.SignIDToken(user, "nonce", time.Now().Add(-time.Hour))
+	foreign, _ := other.SignIDToken(user, "nonce", time.Now().Add(time.Hour))
+
+	tests := []struct {
+		Name      string
+		Token     string
+		ClientID  string
+		Nonce     string
+		ExpectErr bool
+	}{
+		{Name: "Valid token", Token: valid, ClientID: "client", Nonce: "nonce"},
+		{Name: "Expired token", Token: expired, ClientID: "client", Nonce: "nonce", ExpectErr: true},
+		{Name: "Nonce does not match", Token: valid, ClientID: "client", Nonce: "other", ExpectErr: true},
+		{Name: "Audience does not match", Token: valid, ClientID: "other", Nonce: "nonce", ExpectErr: true},
+		{Name: "Signed by other issuer", Token: foreign, ClientID: "client", Nonce: "nonce", ExpectErr: true},
+		{Name: "Malformed token", Token: "abc.def.ghi", ClientID: "client", Nonce: "nonce", ExpectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			provider := NewProvider(server.Issuer(), test.ClientID)
+
+			_, err := provider.VerifyIDToken(context.Background(), test.Token, test.Nonce)
+			if test.ExpectErr {
+				assert.ErrorIs(t, err, ErrInvalidIDToken)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	server := oidctest.NewServer("client")
+	defer server.Close()
+
+	provider := NewProvider(server.Issuer()+"/other", "client")
+
+	_, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "challenge")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,29 @@
+package oidc
+
+import "net/http"
+
+type Option func(*Provider)
+
+func WithClientSecret(secret string) Option {
+	return func(p *Provider) {
+		p.clientSecret = secret
+	}
+}
+
+func WithRedirectURL(url string) Option {
+	return func(p *Provider) {
+		p.redirectURL = url
+	}
+}
+
+func WithScopes(scopes ...string) Option {
+	return func(p *Provider) {
+		p.scopes = scopes
+	}
+}
+
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Provider) {
+		p.client = client
+	}
+}
@@ -0,0 +1,181 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidIDToken = errors.New("id token is invalid")
+	ErrExchangeFailed = errors.New("authorization code exchange failed")
+)
+
+// Provider performs authorization code flow with PKCE against OpenID Connect
+// provider. Configuration of the provider is discovered on first use
+type Provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+	client       *http.Client
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     *keySet
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Tokens is a response of the token endpoint
+type Tokens struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func NewProvider(issuer string, clientID string, opts ...Option) *Provider {
+	p := &Provider{
+		issuer:   strings.TrimSuffix(issuer, "/"),
+		clientID: clientID,
+		scopes:   []string{"openid", "email", "profile"},
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// AuthCodeURL returns URL of the provider the user is redirected to
+func (p *Provider) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.clientID)
+	query.Set("redirect_uri", p.redirectURL)
+	query.Set("scope", strings.Join(p.scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(md.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return md.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange trades authorization code for tokens, codeVerifier must match
+// the challenge sent in AuthCodeURL
+func (p *Provider) Exchange(ctx context.Context, code string, codeVerifier string) (*Tokens, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectURL)
+	form.Set("client_id", p.clientID)
+	form.Set("code_verifier", codeVerifier)
+	if p.clientSecret != "" {
+		form.Set("client_secret", p.clientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: token endpoint returned %s", ErrExchangeFailed, resp.Status)
+	}
+
+	tokens := new(Tokens)
+
+	err = json.NewDecoder(resp.Body).Decode(tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("%w: response has no id_token", ErrExchangeFailed)
+	}
+
+	return tokens, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	md := new(metadata)
+
+	err := p.getJSON(ctx, p.issuer+"/.well-known/openid-configuration", md)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(md.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("issuer %q in discovery document does not match %q", md.Issuer, p.issuer)
+	}
+
+	p.metadata = md
+
+	return md, nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
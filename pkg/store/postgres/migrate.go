@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// NewMigrate prepares migrations from directory at path to be applied to
+// the database
+func NewMigrate(path string, options ...Option) (*migrate.Migrate, error) {
+	s := new(Settings)
+
+	for _, option := range options {
+		option(s)
+	}
+
+	m, err := migrate.New("file://"+path, s.parseToMigrateDSN())
+	if err != nil {
+		return nil, fmt.Errorf("migrate init err: %w", err)
+	}
+
+	return m, nil
+}
@@ -17,11 +17,20 @@ type Settings struct {
 }
 
 func (s *Settings) parseToDSN() string {
+	return s.url("postgresql")
+}
+
+// parseToMigrateDSN uses scheme of golang-migrate pgx driver
+func (s *Settings) parseToMigrateDSN() string {
+	return s.url("pgx")
+}
+
+func (s *Settings) url(scheme string) string {
 	q := url.Values{}
 	q.Add("sslmode", "disable")
 
 	u := url.URL{
-		Scheme:   "postgresql",
+		Scheme:   scheme,
 		User:     url.UserPassword(s.username, s.password),
 		Host:     fmt.Sprintf("%s:%s", s.host, s.port),
 		Path:     s.db,
@@ -0,0 +1,90 @@
+package util
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const breachedPrefixLength = 5
+
+// BreachedPasswords keeps SHA-1 hashes of leaked passwords grouped by the
+// first 5 hex characters, the same layout used by k-anonymity range APIs.
+// Only a small bucket is scanned on lookup
+type BreachedPasswords struct {
+	ranges map[string]map[string]struct{}
+}
+
+// LoadBreachedPasswords reads one hash per line in format "HASH" or
+// "HASH:COUNT". Empty lines and lines starting with "#" are skipped
+func LoadBreachedPasswords(r io.Reader) (*BreachedPasswords, error) {
+	list := &BreachedPasswords{
+		ranges: make(map[string]map[string]struct{}),
+	}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(text, ":")
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("line %d: hash must be %d hex characters", line, sha1.Size*2)
+		}
+
+		hash = strings.ToUpper(hash)
+		prefix, suffix := hash[:breachedPrefixLength], hash[breachedPrefixLength:]
+
+		bucket, ok := list.ranges[prefix]
+		if !ok {
+			bucket = make(map[string]struct{})
+			list.ranges[prefix] = bucket
+		}
+		bucket[suffix] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func LoadBreachedPasswordsFile(path string) (*BreachedPasswords, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return LoadBreachedPasswords(file)
+}
+
+func (b *BreachedPasswords) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	bucket, ok := b.ranges[hash[:breachedPrefixLength]]
+	if !ok {
+		return false
+	}
+
+	_, ok = bucket[hash[breachedPrefixLength:]]
+	return ok
+}
+
+func (b *BreachedPasswords) Len() int {
+	count := 0
+	for _, bucket := range b.ranges {
+		count += len(bucket)
+	}
+
+	return count
+}
@@ -0,0 +1,28 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreachedPasswords(t *testing.T) {
+	// SHA-1 of "password" and "123456"
+	file := `# breached passwords
+5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824
+7c4a8d09ca3762af61e59520943dc26494f8941b
+
+`
+
+	list, err := LoadBreachedPasswords(strings.NewReader(file))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, list.Len())
+
+	assert.True(t, list.Contains("password"))
+	assert.True(t, list.Contains("123456"))
+	assert.False(t, list.Contains("correct horse battery staple"))
+
+	_, err = LoadBreachedPasswords(strings.NewReader("5BAA61E4:10"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrUnknownKeyID = errors.New("token is signed with unknown key")
+)
+
+type JWTClaims struct {
+	UserID int64  `json:"uid"`
+	Role   string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// GenerateJWT signs claims with HS256, keyID is put into "kid" header
+// so the token can be verified after the key was rotated
+func GenerateJWT(userID int64, role string, tokenID string, expiry time.Time, keyID string, key []byte) (string, error) {
+	claims := JWTClaims{
+		UserID: userID,
+		Role:   role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Subject:   fmt.Sprintf("%d", userID),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiry),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = keyID
+
+	return token.SignedString(key)
+}
+
+// ParseJWT verifies signature and expiry of the token. The key is selected
+// from keys by "kid" header, tokens without expiry are rejected
+func ParseJWT(tokenString string, keys map[string][]byte) (*JWTClaims, error) {
+	claims := new(JWTClaims)
+
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+
+		key, ok := keys[keyID]
+		if !ok {
+			return nil, ErrUnknownKeyID
+		}
+
+		return key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.ExpiresAt == nil {
+		return nil, jwt.ErrTokenRequiredClaimMissing
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateJWT(t *testing.T) {
+	token, err := GenerateJWT(1, "USER", "jti", time.Now().Add(time.Minute), "current", []byte("key"))
+	assert.Nil(t, err, "must be nil")
+	assert.NotEmpty(t, token, "must not be empty")
+}
+
+func TestParseJWT(t *testing.T) {
+	keys := map[string][]byte{
+		"current":  []byte("current key"),
+		"previous": []byte("previous key"),
+	}
+
+	current, _ := GenerateJWT(1, "USER", "jti", time.Now().Add(time.Minute), "current", keys["current"])
+	previous, _ := GenerateJWT(1, "USER", "jti", time.Now().Add(time.Minute), "previous", keys["previous"])
+	expired, _ := GenerateJWT(1, "USER", "jti", time.Now().Add(-time.Minute), "current", keys["current"])
+	unknown, _ := GenerateJWT(1, "USER", "jti", time.Now().Add(time.Minute), "removed", []byte("removed key"))
+	forged, _ := GenerateJWT(1, "ADMIN", "jti", time.Now().Add(time.Minute), "current", []byte("wrong key"))
+
+	claims, err := ParseJWT(current, keys)
+	assert.Nil(t, err, "must pass")
+	assert.Equal(t, int64(1), claims.UserID)
+	assert.Equal(t, "jti", claims.ID)
+
+	_, err = ParseJWT(previous, keys)
+	assert.Nil(t, err, "rotated key must still be accepted")
+
+	_, err = ParseJWT(expired, keys)
+	assert.NotNil(t, err, "expired token must fail")
+
+	_, err = ParseJWT(unknown, keys)
+	assert.ErrorIs(t, err, ErrUnknownKeyID)
+
+	_, err = ParseJWT(forged, keys)
+	assert.NotNil(t, err, "token with wrong signature must fail")
+
+	_, err = ParseJWT("not a token", keys)
+	assert.NotNil(t, err, "malformed token must fail")
+}
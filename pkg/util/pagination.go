@@ -26,10 +26,7 @@ func (f Filter) Offset() int {
 }
 
 func (f Filter) FormatSort() string {
-	if strings.HasPrefix(f.Sort, "-") {
-		return strings.ToLower(f.Sort)
-	}
-	return strings.ToLower(f.Sort)
+	return strings.ToLower(strings.TrimPrefix(f.Sort, "-"))
 }
 
 func (f Filter) SortDirection() string {
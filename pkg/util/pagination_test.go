@@ -0,0 +1,29 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterSort(t *testing.T) {
+	tests := []struct {
+		Sort              string
+		ExpectedColumn    string
+		ExpectedDirection string
+	}{
+		{Sort: "created_at", ExpectedColumn: "created_at", ExpectedDirection: "ASC"},
+		{Sort: "-created_at", ExpectedColumn: "created_at", ExpectedDirection: "DESC"},
+		{Sort: "Title", ExpectedColumn: "title", ExpectedDirection: "ASC"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Sort, func(t *testing.T) {
+			filter := NewFilter(1, 20, test.Sort)
+
+			assert.Equal(t, test.ExpectedColumn, filter.FormatSort())
+			assert.Equal(t, test.ExpectedDirection, filter.SortDirection())
+			assert.True(t, filter.ValidateSort([]string{test.ExpectedColumn}))
+		})
+	}
+}
@@ -1,25 +1,58 @@
 package util
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"strings"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
 var (
 	ErrMismatchedPassword = errors.New("password is incorrent")
+	ErrUnknownHashFormat  = errors.New("password hash has unknown format")
 )
 
-func HashPassword(password string) ([]byte, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		return nil, err
-	}
+const argon2idPrefix = "$argon2id$"
 
-	return hash, nil
+// PasswordHasher produces self-describing hashes, so the algorithm and its
+// parameters can be changed without invalidating already stored hashes
+type PasswordHasher interface {
+	Hash(password string) ([]byte, error)
+
+	// NeedsRehash reports whether hash was produced by another algorithm
+	// or with other parameters than the hasher would use now
+	NeedsRehash(hash []byte) bool
+}
+
+// LengthLimitedHasher is implemented by hashers which reject passwords
+// longer than MaxLength bytes
+type LengthLimitedHasher interface {
+	MaxLength() int
 }
 
+var DefaultPasswordHasher PasswordHasher = &Argon2idHasher{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func HashPassword(password string) ([]byte, error) {
+	return DefaultPasswordHasher.Hash(password)
+}
+
+// CheckPassword verifies password against hash made by any supported hasher
 func CheckPassword(password string, password_hash []byte) error {
+	if strings.HasPrefix(string(password_hash), argon2idPrefix) {
+		return checkArgon2id(password, password_hash)
+	}
+
 	err := bcrypt.CompareHashAndPassword(password_hash, []byte(password))
 	if err != nil {
 		switch {
@@ -32,3 +65,124 @@ func CheckPassword(password string, password_hash []byte) error {
 
 	return nil
 }
+
+// Argon2idHasher encodes hashes in PHC string format:
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+type Argon2idHasher struct {
+	// Memory in KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+func (h *Argon2idHasher) Hash(password string) ([]byte, error) {
+	salt := make([]byte, h.SaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+
+	encoded := fmt.Sprintf(
+		"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		h.Memory,
+		h.Iterations,
+		h.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+
+	return []byte(encoded), nil
+}
+
+func (h *Argon2idHasher) NeedsRehash(hash []byte) bool {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return true
+	}
+
+	return params.Memory != h.Memory ||
+		params.Iterations != h.Iterations ||
+		params.Parallelism != h.Parallelism ||
+		uint32(len(salt)) != h.SaltLength ||
+		uint32(len(key)) != h.KeyLength
+}
+
+func checkArgon2id(password string, hash []byte) error {
+	params, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrMismatchedPassword
+	}
+
+	return nil
+}
+
+func decodeArgon2id(hash []byte) (*Argon2idHasher, []byte, []byte, error) {
+	parts := strings.Split(string(hash), "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	_, err := fmt.Sscanf(parts[2], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+
+	params := new(Argon2idHasher)
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	if err != nil {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, ErrUnknownHashFormat
+	}
+
+	return params, salt, key, nil
+}
+
+// BcryptHasher is kept for deployments that can not afford memory used by
+// argon2id. Passwords longer than 72 bytes are rejected
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h *BcryptHasher) Hash(password string) ([]byte, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return nil, err
+	}
+
+	return hash, nil
+}
+
+// MaxLength is the longest password in bytes bcrypt accepts
+func (h *BcryptHasher) MaxLength() int {
+	return 72
+}
+
+func (h *BcryptHasher) NeedsRehash(hash []byte) bool {
+	cost, err := bcrypt.Cost(hash)
+	if err != nil {
+		return true
+	}
+
+	return cost != h.Cost
+}
@@ -9,10 +9,15 @@ import (
 )
 
 func TestHashPassword(t *testing.T) {
-	_, err := HashPassword("password")
+	hash, err := HashPassword("password")
 	assert.Nil(t, err, "must be nil")
+	assert.True(t, strings.HasPrefix(string(hash), "$argon2id$v=19$m=65536,t=3,p=2$"))
 
 	_, err = HashPassword(strings.Repeat("a", 73))
+	assert.Nil(t, err, "argon2id must accept long passwords")
+
+	bcryptHasher := &BcryptHasher{Cost: bcrypt.MinCost}
+	_, err = bcryptHasher.Hash(strings.Repeat("a", 73))
 	assert.NotNil(t, err, "must not be nil")
 }
 
@@ -23,4 +28,27 @@ func TestCheckPassword(t *testing.T) {
 
 	assert.Empty(t, CheckPassword(password1, hash), "must pass")
 	assert.NotEmpty(t, CheckPassword(password2, hash), "must fail")
+
+	hash, _ = HashPassword(password1)
+
+	assert.Empty(t, CheckPassword(password1, hash), "must pass")
+	assert.ErrorIs(t, CheckPassword(password2, hash), ErrMismatchedPassword)
+	assert.ErrorIs(t, CheckPassword(password1, []byte("$argon2id$v=19$m=1$salt$key")), ErrUnknownHashFormat)
+}
+
+func TestNeedsRehash(t *testing.T) {
+	argon2id := &Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	stronger := &Argon2idHasher{Memory: 2048, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	bcryptHasher := &BcryptHasher{Cost: bcrypt.MinCost}
+
+	argon2idHash, _ := argon2id.Hash("password")
+	bcryptHash, _ := bcryptHasher.Hash("password")
+
+	assert.False(t, argon2id.NeedsRehash(argon2idHash))
+	assert.True(t, stronger.NeedsRehash(argon2idHash))
+	assert.True(t, argon2id.NeedsRehash(bcryptHash))
+
+	assert.False(t, bcryptHasher.NeedsRehash(bcryptHash))
+	assert.True(t, (&BcryptHasher{Cost: bcrypt.DefaultCost}).NeedsRehash(bcryptHash))
+	assert.True(t, bcryptHasher.NeedsRehash(argon2idHash))
 }
@@ -11,3 +11,9 @@ func TestGenerateToken(t *testing.T) {
 	assert.NotNil(t, token, "must not be empty")
 	assert.Nil(t, err, "must be nil")
 }
+
+func TestHashToken(t *testing.T) {
+	token, _ := GenerateToken()
+	assert.Equal(t, token.Hash, HashToken(token.Plaintext), "must match generated hash")
+	assert.NotEqual(t, HashToken("first"), HashToken("second"), "must differ")
+}
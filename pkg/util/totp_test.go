@@ -0,0 +1,79 @@
+package util
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Secret "12345678901234567890" from RFC 6238 test vectors
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTOTP(t *testing.T) {
+	tests := []struct {
+		Time     int64
+		Expected string
+	}{
+		{Time: 59, Expected: "287082"},
+		{Time: 1111111109, Expected: "081804"},
+		{Time: 1234567890, Expected: "005924"},
+		{Time: 2000000000, Expected: "279037"},
+	}
+
+	for _, test := range tests {
+		code, err := GenerateTOTP(rfcSecret, time.Unix(test.Time, 0))
+		assert.Nil(t, err, "must be nil")
+		assert.Equal(t, test.Expected, code, "must match RFC 6238 test vector")
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	assert.Nil(t, err, "must be nil")
+
+	now := time.Now()
+	code, _ := GenerateTOTP(secret, now)
+
+	assert.True(t, ValidateTOTP(secret, code, now), "current code must be valid")
+	assert.True(t, ValidateTOTP(secret, code, now.Add(30*time.Second)), "previous code must be valid")
+	assert.False(t, ValidateTOTP(secret, code, now.Add(5*time.Minute)), "outdated code must be invalid")
+	assert.False(t, ValidateTOTP(secret, "abcdef", now), "malformed code must be invalid")
+	assert.False(t, ValidateTOTP("not base32!", code, now), "malformed secret must be rejected")
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri, err := url.Parse(TOTPURI("Library", "username", rfcSecret))
+	assert.Nil(t, err, "must be nil")
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Library:username", uri.Path)
+	assert.Equal(t, rfcSecret, uri.Query().Get("secret"))
+	assert.Equal(t, "Library", uri.Query().Get("issuer"))
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	assert.Nil(t, err, "must be nil")
+	assert.Len(t, codes, 10)
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, code)
+		assert.False(t, seen[code], "codes must be unique")
+		seen[code] = true
+	}
+}
+
+func TestMatchTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	assert.Nil(t, err, "must be nil")
+
+	now := time.Now()
+	code, _ := GenerateTOTP(secret, now)
+
+	step, ok := MatchTOTP(secret, code, now.Add(30*time.Second))
+	assert.True(t, ok, "previous code must be valid")
+	assert.Equal(t, now.Unix()/30, step, "step of the code must be returned, not the current one")
+}